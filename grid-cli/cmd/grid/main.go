@@ -1,20 +1,30 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/spf13/pflag"
 	"github.com/yourusername/grid-cli/internal/client"
 	gridCell "github.com/yourusername/grid-cli/internal/cell"
 	gridConfig "github.com/yourusername/grid-cli/internal/config"
+	gridDaemon "github.com/yourusername/grid-cli/internal/daemon"
+	gridDiagnostics "github.com/yourusername/grid-cli/internal/diagnostics"
 	gridFocus "github.com/yourusername/grid-cli/internal/focus"
 	gridLayout "github.com/yourusername/grid-cli/internal/layout"
 	"github.com/yourusername/grid-cli/internal/logging"
@@ -24,21 +34,32 @@ import (
 	gridServer "github.com/yourusername/grid-cli/internal/server"
 	gridState "github.com/yourusername/grid-cli/internal/state"
 	gridTypes "github.com/yourusername/grid-cli/internal/types"
+	gridVersion "github.com/yourusername/grid-cli/internal/version"
+	gridWatch "github.com/yourusername/grid-cli/internal/watch"
 	gridWindow "github.com/yourusername/grid-cli/internal/window"
 )
 
 var (
 	socketPath string
 	timeout    time.Duration
+	retries    int
 	jsonOutput bool
 	noColor    bool
 	debugMode  bool
+	useDaemon  bool
+	serverOnly bool
+
+	jsonEnvelopeOutput bool
 
 	// Color functions
 	successColor = color.New(color.FgGreen, color.Bold)
 	errorColor   = color.New(color.FgRed, color.Bold)
 	infoColor    = color.New(color.FgCyan)
 	keyColor     = color.New(color.FgYellow)
+
+	// terminalNoColor is color's own choice from the terminal (e.g. when
+	// piped), which --no-color can only add to
+	terminalNoColor = color.NoColor
 )
 
 // rootCmd is the base command
@@ -52,6 +73,58 @@ and move windows between spaces and displays.`,
 	Version: "0.1.0",
 }
 
+// versionCmd prints the CLI version and optionally checks it against the server
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the CLI version",
+	Long: `Prints the grid CLI version.
+
+With --check, also asks GridServer for its version and warns if the CLI is
+older than the server's minimum or recommended client version, or newer
+than the server itself.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+		if !check {
+			if jsonOutput {
+				return printJSON(map[string]interface{}{"client": rootCmd.Version})
+			}
+			fmt.Printf("grid %s\n", rootCmd.Version)
+			return nil
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		info, err := c.GetServerInfo(context.Background())
+		if err != nil {
+			printError(fmt.Sprintf("Failed to get server info: %v", err))
+			return err
+		}
+
+		result, err := gridVersion.CheckCompatibility(rootCmd.Version, info)
+		if err != nil {
+			return fmt.Errorf("failed to compare versions: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(result)
+		}
+
+		keyColor.Print("CLI: ")
+		fmt.Println(result.Client)
+		keyColor.Print("Server: ")
+		fmt.Println(result.Server)
+		if result.Status == gridVersion.StatusCompatible {
+			successColor.Println("✓ CLI and server are compatible")
+		} else {
+			errorColor.Fprint(os.Stderr, "Warning: ")
+			fmt.Fprintln(os.Stderr, result.Message)
+		}
+		return nil
+	},
+}
+
 // pingCmd tests server connectivity
 var pingCmd = &cobra.Command{
 	Use:   "ping",
@@ -150,6 +223,369 @@ var dumpCmd = &cobra.Command{
 	},
 }
 
+// manCmd writes man pages for the whole command tree
+var manCmd = &cobra.Command{
+	Use:   "man <dir>",
+	Short: "Generate man pages for every command",
+	Long: `Writes a man page for grid and each of its subcommands into <dir> (grid.1,
+grid-window-move.1, ...). Copy them into a man1 directory on your MANPATH,
+or run make man.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		header := &doc.GenManHeader{Section: "1", Source: "grid " + rootCmd.Version}
+		if err := doc.GenManTree(rootCmd, header, dir); err != nil {
+			return fmt.Errorf("failed to generate man pages: %w", err)
+		}
+		successColor.Printf("✓ Man pages written to %s\n", dir)
+		return nil
+	},
+}
+
+// helpAllCmd prints the whole command tree
+var helpAllCmd = &cobra.Command{
+	Use:   "help-all",
+	Short: "Show every command with its flags and args",
+	Long: `Walks the full command tree and prints every command with its args and flags.
+
+With --json, emits the tree as structured JSON (flag types, defaults and
+descriptions included) for building completions or docs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tree := describeCommand(rootCmd)
+		if jsonOutput {
+			return printJSON(tree)
+		}
+		printCommandTree(os.Stdout, tree)
+		return nil
+	},
+}
+
+// commandDoc describes a command for help-all
+type commandDoc struct {
+	Name     string       `json:"name"`
+	Path     string       `json:"path"`
+	Args     string       `json:"args,omitempty"`
+	Aliases  []string     `json:"aliases,omitempty"`
+	Short    string       `json:"short,omitempty"`
+	Long     string       `json:"long,omitempty"`
+	Flags    []flagDoc    `json:"flags,omitempty"`
+	Commands []commandDoc `json:"commands,omitempty"`
+}
+
+// flagDoc describes a command flag for help-all
+type flagDoc struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Persistent bool   `json:"persistent,omitempty"` // Inherited by subcommands
+}
+
+// describeCommand builds the help-all tree for cmd and its available
+// subcommands. Flags are the ones defined on each command itself; persistent
+// flags appear once, on the command that defines them.
+func describeCommand(cmd *cobra.Command) commandDoc {
+	doc := commandDoc{
+		Name:    cmd.Name(),
+		Path:    cmd.CommandPath(),
+		Aliases: cmd.Aliases,
+		Short:   cmd.Short,
+		Long:    cmd.Long,
+	}
+	if fields := strings.Fields(cmd.Use); len(fields) > 1 {
+		doc.Args = strings.Join(fields[1:], " ")
+	}
+
+	persistent := cmd.PersistentFlags()
+	cmd.NonInheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		doc.Flags = append(doc.Flags, flagDoc{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Persistent: persistent.Lookup(f.Name) != nil,
+		})
+	})
+
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		doc.Commands = append(doc.Commands, describeCommand(sub))
+	}
+	return doc
+}
+
+// printCommandTree prints a help-all tree as indented text
+func printCommandTree(w io.Writer, doc commandDoc) {
+	var walk func(doc commandDoc, depth int)
+	walk = func(doc commandDoc, depth int) {
+		indent := strings.Repeat("  ", depth)
+		line := doc.Path
+		if doc.Args != "" {
+			line += " " + doc.Args
+		}
+		fmt.Fprintf(w, "%s%s", indent, line)
+		if doc.Short != "" {
+			fmt.Fprintf(w, "  - %s", doc.Short)
+		}
+		fmt.Fprintln(w)
+		for _, f := range doc.Flags {
+			name := "--" + f.Name
+			if f.Shorthand != "" {
+				name = "-" + f.Shorthand + ", " + name
+			}
+			fmt.Fprintf(w, "%s    %s %s  %s", indent, name, f.Type, f.Usage)
+			if f.Default != "" && f.Default != "false" && f.Default != "0" && f.Default != "[]" {
+				fmt.Fprintf(w, " (default %s)", f.Default)
+			}
+			fmt.Fprintln(w)
+		}
+		for _, sub := range doc.Commands {
+			walk(sub, depth+1)
+		}
+	}
+	walk(doc, 0)
+}
+
+// watchCmd runs the daemon that executes commands sent with --daemon
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Run a daemon that executes grid commands sent with --daemon",
+	Long: `Listens on the control socket ($GRID_CONTROL_SOCKET, default /tmp/grid-control.sock)
+and runs the commands other grid invocations forward with --daemon, one at a time,
+in this process. Bind held-key actions as e.g. "grid --daemon window move right"
+to skip starting a new process per repeat; without a running daemon the command
+runs standalone. Each forwarded command still loads config and state afresh, and
+runs with the daemon's working directory and environment, not the caller's; pass
+absolute paths.
+
+With --reapply, it also polls the server every --interval and re-applies the
+active space's layout when tileable windows open or close there, once changes
+have settled. Window changes are printed as they're handled.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The envelope is printed when the command ends, which watch never does
+		if jsonEnvelopeOutput {
+			return fmt.Errorf("watch runs until stopped and can't be used with --json-envelope")
+		}
+
+		reapply, _ := cmd.Flags().GetBool("reapply")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		// Forwarded runs set their own --server-only; the daemon's comes back after each
+		daemonReadOnly = serverOnly
+
+		path := gridDaemon.ControlSocketPath()
+		ln, err := gridDaemon.Listen(path)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Forwarded commands and re-applies share the state file; run one at a time
+		var mu sync.Mutex
+		handle := func(args []string) gridDaemon.Response {
+			mu.Lock()
+			defer mu.Unlock()
+			return runForwarded(args)
+		}
+
+		if reapply {
+			poller := &gridWatch.Poller{
+				Interval: interval,
+				Debounce: gridWatch.DefaultDebounce,
+				Fetch: func(ctx context.Context) (*gridServer.Snapshot, error) {
+					c := client.NewClient(socketPath, timeout)
+					defer c.Close()
+					return gridServer.Fetch(ctx, c)
+				},
+				OnChange: func(ctx context.Context, snap *gridServer.Snapshot, ch gridWatch.Changes) {
+					mu.Lock()
+					defer mu.Unlock()
+					reapplyOnChange(ctx, snap, ch)
+				},
+			}
+			go poller.Run(ctx)
+			successColor.Printf("✓ Watching for window changes every %s\n", interval)
+		}
+
+		successColor.Printf("✓ Listening on %s\n", path)
+		return gridDaemon.Serve(ctx, ln, handle)
+	},
+}
+
+// reapplyOnChange re-applies the active space's layout after `watch --reapply`
+// sees windows open or close there
+func reapplyOnChange(ctx context.Context, snap *gridServer.Snapshot, ch gridWatch.Changes) {
+	infoColor.Printf("Space %s: opened %v, closed %v\n", ch.SpaceID, ch.Added, ch.Removed)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		printError(fmt.Sprintf("failed to load config: %v", err))
+		return
+	}
+
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		printError(fmt.Sprintf("failed to load state: %v", err))
+		return
+	}
+	// A failed sync or re-apply isn't saved; release the lock LoadState took
+	defer gridState.ReleaseLock()
+
+	if err := syncState(snap, runtimeState); err != nil {
+		printError(fmt.Sprintf("failed to reconcile state: %v", err))
+		return
+	}
+
+	space := runtimeState.GetSpaceReadOnly(snap.SpaceID)
+	if space == nil || space.CurrentLayoutID == "" {
+		logging.Debug().Str("space", snap.SpaceID).Msg("watch: no layout on space, not re-applying")
+		saveWatchState(runtimeState)
+		return
+	}
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	opts := gridLayout.DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	if err := gridLayout.ReapplyLayout(ctx, c, snap, cfg, runtimeState, opts); err != nil {
+		printError(fmt.Sprintf("failed to re-apply layout: %v", err))
+		return
+	}
+	if saveWatchState(runtimeState) {
+		successColor.Printf("✓ Re-applied layout %s\n", space.CurrentLayoutID)
+	}
+}
+
+// saveWatchState saves state after a watch re-apply, reporting a failure
+func saveWatchState(rs *gridState.RuntimeState) bool {
+	if err := rs.Save(); err != nil {
+		printError(fmt.Sprintf("failed to save state: %v", err))
+		return false
+	}
+	return true
+}
+
+// daemonReadOnly is the daemon's own --server-only, which each forwarded run
+// puts back once it's done
+var daemonReadOnly bool
+
+// forwarded is set while a forwarded command runs. Its stdin is the
+// daemon's, so it must not prompt.
+var forwarded bool
+
+// runForwarded executes a command forwarded to the daemon in this process,
+// capturing what it prints
+func runForwarded(args []string) gridDaemon.Response {
+	if len(args) > 0 && args[0] == "watch" {
+		return gridDaemon.Response{Error: "cannot run watch through the daemon"}
+	}
+
+	// Read-only commands load state without saving it; don't keep its lock
+	defer gridState.ReleaseLock()
+	// A forwarded --server-only must not carry over to the daemon's own
+	// reapplies, nor may a forwarded run turn off the daemon's
+	defer gridState.SetReadOnly(daemonReadOnly)
+
+	stdout, stderr := new(strings.Builder), new(strings.Builder)
+	restore, err := captureOutput(stdout, stderr)
+	if err != nil {
+		return gridDaemon.Response{Error: err.Error()}
+	}
+
+	// Flag values stick to the command tree between runs
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	forwarded = true
+	runErr := execute()
+	forwarded = false
+	restore()
+
+	resp := gridDaemon.Response{Stdout: stdout.String(), Stderr: stderr.String()}
+	if runErr != nil {
+		resp.Error = runErr.Error()
+	}
+	return resp
+}
+
+// captureOutput redirects stdout and stderr (including colored output) into
+// the given writers until the returned restore function is called
+func captureOutput(stdout, stderr io.Writer) (func(), error) {
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		outR.Close()
+		outW.Close()
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	done := make(chan struct{}, 2)
+	copyTo := func(w io.Writer, r *os.File) {
+		io.Copy(w, r)
+		r.Close()
+		done <- struct{}{}
+	}
+	go copyTo(stdout, outR)
+	go copyTo(stderr, errR)
+
+	origOut, origErr, origColor := os.Stdout, os.Stderr, color.Output
+	os.Stdout, os.Stderr, color.Output = outW, errW, outW
+	rootCmd.SetOut(outW)
+	rootCmd.SetErr(errW)
+
+	return func() {
+		os.Stdout, os.Stderr, color.Output = origOut, origErr, origColor
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		outW.Close()
+		errW.Close()
+		<-done
+		<-done
+	}, nil
+}
+
+// resetFlags puts every flag in the command tree back to its default
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			def := strings.Trim(f.DefValue, "[]")
+			if def == "" {
+				sv.Replace(nil)
+			} else {
+				sv.Replace(strings.Split(def, ","))
+			}
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
 // showCmd is the parent command for visualization subcommands
 var showCmd = &cobra.Command{
 	Use:   "show",
@@ -159,11 +595,13 @@ var showCmd = &cobra.Command{
 
 // Visualization flags
 var (
-	showASCII     bool
-	showUnicode   bool
-	showNoIDs     bool
-	showWidth     int
-	showHeight    int
+	showASCII   bool
+	showUnicode bool
+	showNoIDs   bool
+	showWidth   int
+	showHeight  int
+	showFormat  string
+	showOut     string
 )
 
 // showLayoutCmd visualizes all displays
@@ -178,8 +616,7 @@ Windows are shown as boxes with their ID, application name, and size.`,
 			return err
 		}
 
-		opts := getVisualizationOptions()
-		return output.PrintVisualization(state, -1, opts)
+		return printLayout(state, -1)
 	},
 }
 
@@ -202,8 +639,7 @@ Windows are shown as boxes with their ID, application name, and size.`,
 			return err
 		}
 
-		opts := getVisualizationOptions()
-		return output.PrintVisualization(state, displayIndex, opts)
+		return printLayout(state, displayIndex)
 	},
 }
 
@@ -221,15 +657,25 @@ var listWindowsCmd = &cobra.Command{
 	Long: `Lists all windows with their IDs, titles, applications, and positions.
 
 By default, filters out system UI, utility windows, and borders (yabai-style filtering).
-Use --all to show all windows including system components.`,
+Use --all to show all windows including system components.
+Use --stale to list windows tracked in grid state that no longer exist on the server.
+Use --tree to group windows by display, space and cell; windows not in a cell
+are listed as floating under their space.
+
+--app, --title and --space narrow the list further: app name and title match
+case-insensitive substrings, and a window must match every filter given.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if stale, _ := cmd.Flags().GetBool("stale"); stale {
+			return listStaleWindows()
+		}
+
 		state, err := getState()
 		if err != nil {
 			return err
 		}
 
 		windows := state.GetWindows()
-		if len(windows) == 0 {
+		if len(windows) == 0 && !jsonOutput {
 			fmt.Println("No windows found")
 			return nil
 		}
@@ -239,18 +685,48 @@ Use --all to show all windows including system components.`,
 		if !showAll {
 			windows = filterWindows(windows)
 		}
+		filter := windowFilterFromFlags(cmd)
+		windows = filter.Apply(windows)
 
-		if len(windows) == 0 {
+		// With --json an empty result is an empty list, not a message
+		if len(windows) == 0 && !jsonOutput {
+			if !filter.IsZero() {
+				fmt.Printf("No windows found matching %s\n", filter)
+				return nil
+			}
 			fmt.Println("No windows found (try --all to show system windows)")
 			return nil
 		}
 
+		if tree, _ := cmd.Flags().GetBool("tree"); tree {
+			runtimeState, err := gridState.LoadState()
+			if err != nil {
+				return fmt.Errorf("failed to load state: %w", err)
+			}
+			spaceNames := make(map[string]string)
+			if cfg, err := loadConfig(); err == nil {
+				for spaceID, sc := range cfg.Spaces {
+					spaceNames[spaceID] = sc.Name
+				}
+			}
+
+			windowTree := output.BuildWindowTree(state, windows, runtimeState, spaceNames)
+			if jsonOutput {
+				return printJSON(windowTree)
+			}
+			output.PrintWindowTree(os.Stdout, windowTree)
+			return nil
+		}
+
 		if jsonOutput {
 			return printJSON(windows)
 		}
 
-		output.PrintWindowsTable(windows)
+		output.PrintWindowsTable(windows, tableOptions(cmd))
 		fmt.Printf("\nTotal: %d windows", len(windows))
+		if !filter.IsZero() {
+			fmt.Printf(" matching %s", filter)
+		}
 		if !showAll {
 			fmt.Printf(" (filtered, use --all to show all windows)")
 		}
@@ -259,6 +735,55 @@ Use --all to show all windows including system components.`,
 	},
 }
 
+// windowFilterFromFlags reads the --app, --title and --space filters of
+// list windows
+func windowFilterFromFlags(cmd *cobra.Command) models.WindowFilter {
+	var filter models.WindowFilter
+	filter.App, _ = cmd.Flags().GetString("app")
+	filter.Title, _ = cmd.Flags().GetString("title")
+	filter.Space, _ = cmd.Flags().GetString("space")
+	return filter
+}
+
+// listStaleWindows prints windows tracked in state for the active space
+// that are missing from the server (what reconciliation would prune).
+func listStaleWindows() error {
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	snap, err := gridServer.Fetch(context.Background(), c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch server state: %w", err)
+	}
+
+	// Deliberately no reconcile here: that would prune what we want to show
+	stale := gridReconcile.StaleWindows(snap, runtimeState)
+
+	if jsonOutput {
+		if stale == nil {
+			stale = []gridReconcile.StaleWindow{}
+		}
+		return printJSON(stale)
+	}
+
+	if len(stale) == 0 {
+		fmt.Printf("No stale windows on space %s\n", snap.SpaceID)
+		return nil
+	}
+
+	keyColor.Printf("Stale windows on space %s:\n", snap.SpaceID)
+	for _, w := range stale {
+		fmt.Printf("  %d (last in cell %s)\n", w.WindowID, w.CellID)
+	}
+	fmt.Printf("\nTotal: %d stale windows\n", len(stale))
+	return nil
+}
+
 // listSpacesCmd lists all spaces
 var listSpacesCmd = &cobra.Command{
 	Use:   "spaces",
@@ -270,7 +795,7 @@ var listSpacesCmd = &cobra.Command{
 			return err
 		}
 
-		if len(state.Spaces) == 0 {
+		if len(state.Spaces) == 0 && !jsonOutput {
 			fmt.Println("No spaces found")
 			return nil
 		}
@@ -285,7 +810,7 @@ var listSpacesCmd = &cobra.Command{
 			return printJSON(spaces)
 		}
 
-		output.PrintSpacesTable(spaces)
+		output.PrintSpacesTable(spaces, tableOptions(cmd))
 		fmt.Printf("\nTotal: %d spaces\n", len(spaces))
 		return nil
 	},
@@ -302,16 +827,19 @@ var listDisplaysCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonOutput {
+			if state.Displays == nil {
+				return printJSON([]*models.Display{})
+			}
+			return printJSON(state.Displays)
+		}
+
 		if len(state.Displays) == 0 {
 			fmt.Println("No displays found")
 			return nil
 		}
 
-		if jsonOutput {
-			return printJSON(state.Displays)
-		}
-
-		output.PrintDisplaysTable(state.Displays)
+		output.PrintDisplaysTable(state.Displays, tableOptions(cmd))
 		fmt.Printf("\nTotal: %d displays\n", len(state.Displays))
 		return nil
 	},
@@ -329,7 +857,7 @@ var listAppsCmd = &cobra.Command{
 		}
 
 		apps := state.GetApplications()
-		if len(apps) == 0 {
+		if len(apps) == 0 && !jsonOutput {
 			fmt.Println("No applications found")
 			return nil
 		}
@@ -338,7 +866,7 @@ var listAppsCmd = &cobra.Command{
 			return printJSON(apps)
 		}
 
-		output.PrintApplicationsTable(apps)
+		output.PrintApplicationsTable(apps, tableOptions(cmd))
 		fmt.Printf("\nTotal: %d applications\n", len(apps))
 		return nil
 	},
@@ -373,12 +901,116 @@ var windowGetCmd = &cobra.Command{
 			return fmt.Errorf("window %d not found", windowID)
 		}
 
+		var gridInfo *gridLayout.WindowGridInfo
+		if showGrid, _ := cmd.Flags().GetBool("grid"); showGrid {
+			gridInfo, err = inspectWindowGrid(uint32(windowID))
+			if err != nil {
+				return err
+			}
+		}
+
 		if jsonOutput {
+			if gridInfo != nil {
+				return printJSON(struct {
+					*models.Window
+					Grid *gridLayout.WindowGridInfo `json:"grid"`
+				}{window, gridInfo})
+			}
 			return printJSON(window)
 		}
 
 		app := state.FindApplicationByPID(window.PID)
 		output.PrintWindowDetail(window, app)
+		if gridInfo != nil {
+			printWindowGridInfo(gridInfo)
+		}
+		return nil
+	},
+}
+
+// inspectWindowGrid loads config and state and computes how the grid sees a window
+func inspectWindowGrid(windowID uint32) (*gridLayout.WindowGridInfo, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	snap, err := gridServer.Fetch(context.Background(), c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server state: %w", err)
+	}
+
+	return gridLayout.InspectWindow(snap, cfg, runtimeState, windowID)
+}
+
+// printWindowGridInfo prints the grid section of window get --grid
+func printWindowGridInfo(info *gridLayout.WindowGridInfo) {
+	formatRect := func(r gridTypes.Rect) string {
+		return fmt.Sprintf("(%.0f, %.0f) %.0fx%.0f", r.X, r.Y, r.Width, r.Height)
+	}
+
+	fmt.Println()
+	keyColor.Println("Grid:")
+	fmt.Printf("  Space: %s (layout %s)\n", info.SpaceID, info.LayoutID)
+	fmt.Printf("  Cell: %s (index %d, %s)\n", info.CellID, info.Index, info.StackMode)
+	fmt.Printf("  Split Ratio: %.2f\n", info.SplitRatio)
+	fmt.Printf("  Target Frame: %s\n", formatRect(info.TargetFrame))
+	fmt.Printf("  Actual Frame: %s\n", formatRect(info.ActualFrame))
+	if info.InPlace {
+		successColor.Println("  Drift: none")
+	} else {
+		infoColor.Printf("  Drift: %+.0f, %+.0f, %+.0fx%+.0f\n", info.Drift.X, info.Drift.Y, info.Drift.Width, info.Drift.Height)
+	}
+}
+
+// windowQueryCmd prints a single field of a window
+var windowQueryCmd = &cobra.Command{
+	Use:   "query <window-id> <field>",
+	Short: "Print a single window field",
+	Long: `Prints one field of a window's JSON as a raw value, for scripting.
+Fields are dotted paths, e.g. appName, level, frame.width, metadata.<key>, spaces.0.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		windowID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid window ID: %v", err)
+		}
+
+		state, err := getState()
+		if err != nil {
+			return err
+		}
+
+		window := state.FindWindowByID(windowID)
+		if window == nil {
+			return fmt.Errorf("window %d not found", windowID)
+		}
+
+		value, ok := window.Field(args[1])
+		if !ok || value == nil {
+			if cmd.Flags().Changed("default") {
+				def, _ := cmd.Flags().GetString("default")
+				fmt.Println(def)
+				return nil
+			}
+			if !ok {
+				return fmt.Errorf("field %q not found on window %d", args[1], windowID)
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(value)
+		}
+
+		fmt.Println(models.FormatFieldValue(value))
 		return nil
 	},
 }
@@ -387,31 +1019,46 @@ var windowGetCmd = &cobra.Command{
 var windowFindCmd = &cobra.Command{
 	Use:   "find <pattern>",
 	Short: "Find windows by title pattern",
-	Long:  `Searches for windows whose title contains the given pattern (case-insensitive).`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Searches for windows whose title or app name contains the given pattern
+(case-insensitive).
+
+--on-display keeps windows on the spaces of a display (index as in
+'list displays', or UUID); --on-space keeps windows on one space. Given
+both, a window must be on that space and the space on that display.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		pattern := strings.ToLower(args[0])
+		onDisplay, _ := cmd.Flags().GetString("on-display")
+		onSpace, _ := cmd.Flags().GetString("on-space")
 
 		state, err := getState()
 		if err != nil {
 			return err
 		}
 
-		// Filter windows by title pattern
-		var matches []*models.Window
-		for _, win := range state.Windows {
-			title := ""
-			if win.Title != nil {
-				title = *win.Title
+		// Restrict to a display's and/or a space's windows
+		var spaceIDs map[string]bool
+		if onDisplay != "" {
+			display, err := state.FindDisplay(onDisplay)
+			if err != nil {
+				return err
 			}
-			appName := ""
-			if win.AppName != nil {
-				appName = *win.AppName
+			spaceIDs = make(map[string]bool)
+			for _, id := range display.GetSpaceIDs() {
+				if onSpace == "" || id == onSpace {
+					spaceIDs[id] = true
+				}
 			}
-			if strings.Contains(strings.ToLower(title), pattern) ||
-			   strings.Contains(strings.ToLower(appName), pattern) {
-				matches = append(matches, win)
+		} else if onSpace != "" {
+			spaceIDs = map[string]bool{onSpace: true}
+		}
+
+		matches := state.FindWindows(args[0], spaceIDs)
+
+		if jsonOutput {
+			if matches == nil {
+				matches = []*models.Window{}
 			}
+			return printJSON(matches)
 		}
 
 		if len(matches) == 0 {
@@ -419,11 +1066,7 @@ var windowFindCmd = &cobra.Command{
 			return nil
 		}
 
-		if jsonOutput {
-			return printJSON(matches)
-		}
-
-		output.PrintWindowsTable(matches)
+		output.PrintWindowsTable(matches, tableOptions(cmd))
 		fmt.Printf("\nFound %d windows matching '%s'\n", len(matches), args[0])
 		return nil
 	},
@@ -492,8 +1135,14 @@ var windowUpdateCmd = &cobra.Command{
 var windowToSpaceCmd = &cobra.Command{
 	Use:   "to-space <window-id> <space-id>",
 	Short: "Move a window to a specific space",
-	Long:  `Moves a window to the specified space ID.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Moves a window to the specified space ID.
+
+With --tile, the window is also assigned into the target space's layout
+(the --cell given, or the least-populated cell) and that cell is re-placed.
+
+When the current space tracks the window, the windows left in its cell are
+re-placed to fill the gap (disable with --reflow-source=false).`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		windowID, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -502,6 +1151,12 @@ var windowToSpaceCmd = &cobra.Command{
 
 		spaceID := args[1]
 
+		cellID, _ := cmd.Flags().GetString("cell")
+		reflowSource, _ := cmd.Flags().GetBool("reflow-source")
+		if tile, _ := cmd.Flags().GetBool("tile"); tile || cellID != "" {
+			return tileWindowToSpace(uint32(windowID), spaceID, cellID, reflowSource)
+		}
+
 		updates := map[string]interface{}{
 			"spaceId": spaceID,
 		}
@@ -509,12 +1164,24 @@ var windowToSpaceCmd = &cobra.Command{
 		c := client.NewClient(socketPath, timeout)
 		defer c.Close()
 
-		result, err := c.UpdateWindow(context.Background(), windowID, updates)
+		ctx := context.Background()
+
+		var reflow func() int
+		if reflowSource {
+			reflow = prepareSourceReflow(ctx, c, uint32(windowID))
+		}
+
+		result, err := c.UpdateWindow(ctx, windowID, updates)
 		if err != nil {
 			printError(fmt.Sprintf("Failed to move window to space: %v", err))
 			return err
 		}
 
+		reflowed := 0
+		if reflow != nil {
+			reflowed = reflow()
+		}
+
 		if jsonOutput {
 			return printJSON(result)
 		}
@@ -523,10 +1190,399 @@ var windowToSpaceCmd = &cobra.Command{
 		if updates, ok := result["updatesApplied"].([]interface{}); ok && len(updates) > 0 {
 			fmt.Printf("  Applied: %v\n", updates)
 		}
+		if reflowed > 0 {
+			infoColor.Printf("  Reflowed %d window(s) left in the source cell\n", reflowed)
+		}
+		return nil
+	},
+}
+
+// prepareSourceReflow loads config and state before a window leaves the
+// current space, and returns a function that reflows the cell it left once
+// it's gone. Returns nil if anything can't be loaded; the move doesn't need it.
+func prepareSourceReflow(ctx context.Context, c *client.Client, windowID uint32) func() int {
+	cfg, err := loadConfig()
+	if err != nil {
+		logging.Debug().Err(err).Msg("skipping source reflow: failed to load config")
+		return nil
+	}
+
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		logging.Debug().Err(err).Msg("skipping source reflow: failed to load state")
+		return nil
+	}
+
+	snap, err := gridServer.Fetch(ctx, c)
+	if err != nil {
+		logging.Debug().Err(err).Msg("skipping source reflow: failed to fetch server state")
+		return nil
+	}
+	if err := syncState(snap, runtimeState); err != nil {
+		logging.Debug().Err(err).Msg("skipping source reflow: failed to reconcile state")
+		return nil
+	}
+
+	return func() int {
+		reflowed := gridWindow.ReflowAfterDeparture(ctx, c, snap, cfg, runtimeState, windowID)
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			logging.Warn().Err(err).Msg("failed to save state")
+		}
+		return reflowed
+	}
+}
+
+// tileWindowToSpace moves a window to a space and tiles it into that space's layout
+func tileWindowToSpace(windowID uint32, spaceID, cellID string, reflowSource bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	// 1. Fetch server state ONCE
+	snap, err := gridServer.Fetch(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch server state: %w", err)
+	}
+
+	// 2. Reconcile local state with server
+	if err := syncState(snap, runtimeState); err != nil {
+		return fmt.Errorf("failed to reconcile state: %w", err)
+	}
+
+	// 3. Move and tile
+	result, err := gridWindow.MoveWindowToSpace(ctx, c, snap, cfg, runtimeState, windowID, spaceID, cellID, reflowSource)
+	if err != nil {
+		return fmt.Errorf("failed to move window: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(result)
+	}
+
+	successColor.Printf("✓ Window %d moved to space %s (cell %s)\n", result.WindowID, result.TargetSpace, result.TargetCell)
+	if result.SourceReflowed > 0 {
+		infoColor.Printf("  Reflowed %d window(s) left in source cell %s\n", result.SourceReflowed, result.SourceCell)
+	}
+	return nil
+}
+
+// windowAssignCmd records a window in a cell of a space's state
+var windowAssignCmd = &cobra.Command{
+	Use:   "assign <window-id> <space-id> <cell-id>",
+	Short: "Record a window in a cell of a space's layout",
+	Long: `Records a window in a cell of the given space in runtime state, creating the
+space and cell state if needed. The window doesn't have to be focused or on
+the active space, which makes this useful for scripting an arrangement.
+
+If the space has a layout, the cell must be one of its cells. With --apply,
+the cell's windows are also re-placed on the display showing the space.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		windowID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid window ID: %v", err)
+		}
+		spaceID, cellID := args[1], args[2]
+		apply, _ := cmd.Flags().GetBool("apply")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		var c *client.Client
+		var snap *gridServer.Snapshot
+		ctx := context.Background()
+		if apply {
+			c = client.NewClient(socketPath, timeout)
+			defer c.Close()
+
+			snap, err = gridServer.Fetch(ctx, c)
+			if err != nil {
+				return fmt.Errorf("failed to fetch server state: %w", err)
+			}
+			if err := syncState(snap, runtimeState); err != nil {
+				return fmt.Errorf("failed to reconcile state: %w", err)
+			}
+		}
+
+		if err := gridWindow.AssignWindow(cfg, runtimeState, uint32(windowID), spaceID, cellID); err != nil {
+			return err
+		}
+
+		placed := 0
+		if apply {
+			placed, err = gridWindow.ApplyAssignedCell(ctx, c, snap, cfg, runtimeState, spaceID, cellID)
+			if err != nil {
+				return fmt.Errorf("failed to apply placement: %w", err)
+			}
+		}
+
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"windowId": windowID,
+				"spaceId":  spaceID,
+				"cellId":   cellID,
+				"placed":   placed,
+			})
+		}
+
+		successColor.Printf("✓ Window %d assigned to cell %s on space %s\n", windowID, cellID, spaceID)
+		if apply {
+			infoColor.Printf("  Placed %d window(s) in cell %s\n", placed, cellID)
+		}
+		return nil
+	},
+}
+
+// windowPinCmd pins a window to a cell of the current layout
+var windowPinCmd = &cobra.Command{
+	Use:   "pin <window-id> <cell>",
+	Short: "Pin a window to a cell across layout reapplies",
+	Long: `Pins a window on the active space to a cell of the space's current layout.
+Every apply and reapply puts the window in that cell before the assignment
+strategy runs, and ahead of app rules. The pin is dropped when the window
+closes, or with 'grid window unpin'.
+
+With --apply, the layout is reapplied right away.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		windowID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid window ID: %v", err)
+		}
+		cellID := args[1]
+		apply, _ := cmd.Flags().GetBool("apply")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		if err := gridWindow.PinWindow(snap, cfg, runtimeState, uint32(windowID), cellID); err != nil {
+			return err
+		}
+
+		if apply {
+			opts := gridLayout.DefaultApplyOptions()
+			opts.Gap = float64(cfg.Settings.CellPadding)
+			if err := gridLayout.ReapplyLayout(ctx, c, snap, cfg, runtimeState, opts); err != nil {
+				return fmt.Errorf("failed to reapply layout: %w", err)
+			}
+		}
+
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"windowId": windowID,
+				"spaceId":  snap.SpaceID,
+				"cellId":   cellID,
+				"applied":  apply,
+			})
+		}
+
+		successColor.Printf("✓ Window %d pinned to cell %s\n", windowID, cellID)
+		return nil
+	},
+}
+
+// windowUnpinCmd removes a window's pin
+var windowUnpinCmd = &cobra.Command{
+	Use:   "unpin <window-id>",
+	Short: "Remove a window's pin",
+	Long: `Removes the pin set by 'grid window pin' on the active space. The window
+stays where it is; later applies assign it with the normal strategy.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		windowID, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid window ID: %v", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		snap, err := gridServer.Fetch(context.Background(), c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		if err := gridWindow.UnpinWindow(runtimeState, snap.SpaceID, uint32(windowID)); err != nil {
+			return err
+		}
+
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"windowId": windowID,
+				"spaceId":  snap.SpaceID,
+			})
+		}
+
+		successColor.Printf("✓ Window %d unpinned\n", windowID)
 		return nil
 	},
 }
 
+// windowFloatCmd takes a window out of tiling
+var windowFloatCmd = &cobra.Command{
+	Use:   "float <window-id>",
+	Short: "Float a tiled window, leaving it where it is",
+	Long: `Floats a window on the active space: it leaves its cell and stays where it
+is through every apply and reapply, like a window an app rule floats. The
+float is dropped when the window closes, or with 'grid window unfloat'.
+
+With --apply, the layout is reapplied right away so the window's old cell
+closes the gap.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWindowFloat(cmd, args, true)
+	},
+}
+
+// windowUnfloatCmd returns a floated window to tiling
+var windowUnfloatCmd = &cobra.Command{
+	Use:   "unfloat <window-id>",
+	Short: "Return a floated window to tiling",
+	Long: `Returns a window floated with 'grid window float' to tiling. The next apply
+or reapply puts it in the cell it overlaps most, whatever the assignment
+strategy.
+
+With --apply, the layout is reapplied right away.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWindowFloat(cmd, args, false)
+	},
+}
+
+// runWindowFloat floats or unfloats the window in args[0] on the active space
+func runWindowFloat(cmd *cobra.Command, args []string, float bool) error {
+	windowID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid window ID: %v", err)
+	}
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runtimeState, err := gridState.LoadState()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	ctx := context.Background()
+	snap, err := gridServer.Fetch(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch server state: %w", err)
+	}
+	if err := syncState(snap, runtimeState); err != nil {
+		return fmt.Errorf("failed to reconcile state: %w", err)
+	}
+
+	cellID := ""
+	if float {
+		cellID, err = gridWindow.FloatWindow(snap, runtimeState, uint32(windowID))
+	} else {
+		err = gridWindow.UnfloatWindow(runtimeState, snap.SpaceID, uint32(windowID))
+	}
+	if err != nil {
+		return err
+	}
+
+	if apply {
+		opts := gridLayout.DefaultApplyOptions()
+		opts.Gap = float64(cfg.Settings.CellPadding)
+		if err := gridLayout.ReapplyLayout(ctx, c, snap, cfg, runtimeState, opts); err != nil {
+			return fmt.Errorf("failed to reapply layout: %w", err)
+		}
+	}
+
+	runtimeState.MarkUpdated()
+	if err := runtimeState.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(map[string]interface{}{
+			"windowId": windowID,
+			"spaceId":  snap.SpaceID,
+			"floating": float,
+			"applied":  apply,
+		})
+	}
+
+	switch {
+	case !float:
+		successColor.Printf("✓ Window %d returned to tiling\n", windowID)
+	case cellID != "":
+		successColor.Printf("✓ Window %d floated (left cell %s)\n", windowID, cellID)
+	default:
+		successColor.Printf("✓ Window %d floated\n", windowID)
+	}
+	return nil
+}
+
 // windowToDisplayCmd moves a window to a specific display
 var windowToDisplayCmd = &cobra.Command{
 	Use:   "to-display <window-id> <display-uuid>",
@@ -918,8 +1974,8 @@ var windowIsMinimizedCmd = &cobra.Command{
 // spaceCmd is the parent command for space subcommands
 var spaceCmd = &cobra.Command{
 	Use:   "space",
-	Short: "Manage spaces (requires MSS)",
-	Long:  `Commands for creating, destroying, and focusing spaces. Requires MSS.`,
+	Short: "Manage spaces (create, destroy and focus require MSS)",
+	Long:  `Commands for creating, destroying, and focusing spaces, which require MSS, and for listing their layout cycles.`,
 }
 
 // spaceCreateCmd creates a new space
@@ -1009,33 +2065,110 @@ var spaceFocusCmd = &cobra.Command{
 	},
 }
 
-// MARK: - Layout Commands
-
-// layoutCmd is the parent command for layout subcommands
-var gridLayoutCmd = &cobra.Command{
-	Use:   "layout",
-	Short: "Manage window layouts",
-	Long:  `Commands for listing, applying, and cycling window layouts.`,
-}
-
-// layoutListCmd lists available layouts
-var layoutListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List available layouts",
+// spaceListLayoutsCmd shows each space's layout cycle
+var spaceListLayoutsCmd = &cobra.Command{
+	Use:   "list-layouts",
+	Short: "Show each space's layout cycle and current layout",
+	Long: `Lists the layouts each space cycles through (its own, its display's, or
+every enabled layout), marking the one applied and naming the layout
+'grid layout cycle' switches to next. Spaces come from the state, the config
+and, when GridServer is reachable, the displays.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		// The snapshot only resolves per-display cycles, so go without it
+		// when the server is down
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+		snap, err := gridServer.Fetch(context.Background(), c)
+		if err != nil {
+			logging.Debug().Err(err).Msg("space list-layouts: no server snapshot, skipping display cycles")
+			snap = nil
+		}
+
+		cycles := gridLayout.SpaceCycles(cfg, runtimeState, snap)
+
 		if jsonOutput {
-			return printJSON(cfg.Layouts)
+			return printJSON(cycles)
 		}
 
-		fmt.Println("Available Layouts:")
-		fmt.Println()
+		if len(cycles) == 0 {
+			infoColor.Println("No spaces in state or config")
+			return nil
+		}
+		for i, cycle := range cycles {
+			if i > 0 {
+				fmt.Println()
+			}
+			keyColor.Printf("Space %s:\n", cycle.SpaceID)
+			for j, layoutID := range cycle.Layouts {
+				if j == cycle.CurrentIndex {
+					successColor.Printf("  * %d. %s\n", j+1, layoutID)
+				} else {
+					fmt.Printf("    %d. %s\n", j+1, layoutID)
+				}
+			}
+			if cycle.CurrentLayout != "" && cycle.CurrentIndex < 0 {
+				infoColor.Printf("  Current layout %s is not in the cycle\n", cycle.CurrentLayout)
+			}
+			if cycle.NextLayout != "" {
+				fmt.Printf("  Next: %s\n", cycle.NextLayout)
+			}
+		}
+		return nil
+	},
+}
+
+// MARK: - Layout Commands
+
+// layoutCmd is the parent command for layout subcommands
+var gridLayoutCmd = &cobra.Command{
+	Use:   "layout",
+	Short: "Manage window layouts",
+	Long:  `Commands for listing, applying, and cycling window layouts.`,
+}
+
+// layoutListCmd lists available layouts
+var layoutListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available layouts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Disabled layouts are hidden unless --all, then marked
+		showAll, _ := cmd.Flags().GetBool("all")
+		var layouts []gridConfig.LayoutConfig
 		for _, l := range cfg.Layouts {
-			keyColor.Printf("  %s\n", l.ID)
+			if !l.Disabled || showAll {
+				layouts = append(layouts, l)
+			}
+		}
+
+		if jsonOutput {
+			return printJSON(layouts)
+		}
+
+		fmt.Println("Available Layouts:")
+		fmt.Println()
+		for _, l := range layouts {
+			if l.Disabled {
+				keyColor.Printf("  %s", l.ID)
+				infoColor.Println(" (disabled)")
+			} else {
+				keyColor.Printf("  %s\n", l.ID)
+			}
 			if l.Name != "" {
 				fmt.Printf("    Name: %s\n", l.Name)
 			}
@@ -1059,7 +2192,7 @@ var layoutShowCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		layoutID := args[0]
 
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1080,6 +2213,9 @@ var layoutShowCmd = &cobra.Command{
 		if l.Description != "" {
 			fmt.Printf("Description: %s\n", l.Description)
 		}
+		if cfg.LayoutDisabled(l.ID) {
+			infoColor.Println("Disabled: skipped by layout cycle")
+		}
 		fmt.Println()
 
 		fmt.Println("Grid:")
@@ -1099,13 +2235,27 @@ var layoutShowCmd = &cobra.Command{
 
 // layoutApplyCmd applies a layout
 var layoutApplyCmd = &cobra.Command{
-	Use:   "apply <layout-id>",
+	Use:   "apply [layout-id]",
 	Short: "Apply a layout to the current space",
-	Args:  cobra.ExactArgs(1),
+	Long: `Applies a layout to the current space. Without a layout ID, the space's
+defaultLayout is used, falling back to settings.defaultLayout.
+
+With --all-spaces, the space showing on every display gets its default layout
+in one go, and each space's outcome is reported.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		layoutID := args[0]
+		var layoutID string
+		if len(args) > 0 {
+			layoutID = args[0]
+		}
+
+		allSpaces, _ := cmd.Flags().GetBool("all-spaces")
+		displayRef, _ := cmd.Flags().GetString("display")
+		if allSpaces && (layoutID != "" || displayRef != "" || cmd.Flags().Changed("place")) {
+			return fmt.Errorf("--all-spaces applies each space's default layout and can't be combined with a layout ID, --display or --place")
+		}
 
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1120,36 +2270,156 @@ var layoutApplyCmd = &cobra.Command{
 
 		ctx := context.Background()
 
-		// 1. Fetch server state ONCE
-		snap, err := gridServer.Fetch(ctx, c)
+		opts, err := applyOptionsFromFlags(cmd, cfg)
+		if err != nil {
+			return err
+		}
+
+		if allSpaces {
+			return applyAllSpaces(ctx, c, cfg, runtimeState, opts)
+		}
+
+		// 1. Fetch server state ONCE (for another display's space if requested)
+		snap, err := fetchSnapshot(ctx, c, displayRef)
 		if err != nil {
 			return fmt.Errorf("failed to fetch server state: %w", err)
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
 		// 3. Apply layout using snapshot
-		opts := gridLayout.DefaultApplyOptions()
-		opts.Gap = float64(cfg.Settings.CellPadding)
+		layoutID, err = cfg.ResolveLayoutID(snap.SpaceID, gridLayout.SpaceDisplay(snap, snap.SpaceID), layoutID)
+		if err != nil {
+			return err
+		}
 
-		if err := gridLayout.ApplyLayout(ctx, c, snap, cfg, runtimeState, layoutID, opts); err != nil {
+		placeFlags, _ := cmd.Flags().GetStringArray("place")
+		placements, err := parsePlacements(placeFlags)
+		if err != nil {
+			return err
+		}
+		opts.Placements = placements
+
+		opts.Launch.Fetch = func(ctx context.Context) (*gridServer.Snapshot, error) {
+			return fetchSnapshot(ctx, c, displayRef)
+		}
+
+		result, err := gridLayout.ApplyLayoutWithResult(ctx, c, snap, cfg, runtimeState, layoutID, opts)
+		if err != nil {
 			return fmt.Errorf("failed to apply layout: %w", err)
 		}
 
 		successColor.Printf("✓ Applied layout: %s\n", layoutID)
+		printApplyDetails(result, "  ")
 		return nil
 	},
 }
 
+// applyOptionsFromFlags builds the apply options layout apply's flags share
+// between a single space and --all-spaces
+func applyOptionsFromFlags(cmd *cobra.Command, cfg *gridConfig.Config) (gridLayout.ApplyLayoutOptions, error) {
+	opts := gridLayout.DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+
+	opts.OrientationAware, _ = cmd.Flags().GetBool("orientation-aware-stack")
+
+	opts.PlaceNewAtFocus, _ = cmd.Flags().GetBool("place-new-at-focus")
+	if assignment, _ := cmd.Flags().GetString("assignment"); assignment != "" {
+		strategy, ok := gridTypes.ParseAssignmentStrategy(assignment)
+		if !ok {
+			return opts, fmt.Errorf("invalid assignment %q (use position, preserve, autoflow, pinned, or bsp)", assignment)
+		}
+		if opts.PlaceNewAtFocus && strategy != gridTypes.AssignPreserve {
+			return opts, fmt.Errorf("--place-new-at-focus uses the preserve strategy and can't be combined with --assignment %s", assignment)
+		}
+		opts.Strategy = strategy
+	} else if opts.PlaceNewAtFocus || cfg.Settings.PlaceNewAtFocus {
+		// Placing new windows at focus keeps known windows in their cells
+		opts.Strategy = gridTypes.AssignPreserve
+	}
+
+	opts.LaunchEmpty, _ = cmd.Flags().GetBool("launch-empty")
+	return opts, nil
+}
+
+// applyAllSpaces applies the default layout of the space on every display
+// from a single dump, and reports how each went
+func applyAllSpaces(ctx context.Context, c *client.Client, cfg *gridConfig.Config, rs *gridState.RuntimeState, opts gridLayout.ApplyLayoutOptions) error {
+	// 1. Fetch server state ONCE for every display
+	snaps, err := gridServer.FetchAllDisplays(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch server state: %w", err)
+	}
+
+	// 2. Reconcile local state for each space
+	for _, snap := range snaps {
+		if err := syncState(snap, rs); err != nil {
+			return fmt.Errorf("failed to reconcile space %s: %w", snap.SpaceID, err)
+		}
+	}
+
+	// 3. Apply each space's default layout
+	results := gridLayout.ApplyDefaultLayouts(ctx, c, snaps, cfg, rs, func(snap *gridServer.Snapshot) gridLayout.ApplyLayoutOptions {
+		spaceOpts := opts
+		if display := gridLayout.SpaceDisplay(snap, snap.SpaceID); display != nil {
+			uuid := display.UUID
+			spaceOpts.Launch.Fetch = func(ctx context.Context) (*gridServer.Snapshot, error) {
+				return gridServer.FetchForDisplay(ctx, c, uuid)
+			}
+		}
+		return spaceOpts
+	})
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	if jsonOutput {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				errorColor.Printf("✗ Space %s: %s\n", r.SpaceID, r.Error)
+				continue
+			}
+			successColor.Printf("✓ Space %s: applied layout %s\n", r.SpaceID, r.LayoutID)
+			printApplyDetails(r.Result, "    ")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to apply layouts on %d of %d spaces", failed, len(results))
+	}
+	return nil
+}
+
+// printApplyDetails prints what an apply launched, skipped and left untiled
+func printApplyDetails(result *gridLayout.ApplyResult, indent string) {
+	for _, l := range result.Launched {
+		infoColor.Printf("%sLaunched %s into %s (window %d)\n", indent, l.App, l.CellID, l.Window.ID)
+	}
+	if result.Skipped > 0 {
+		infoColor.Printf("%s%d window(s) already in place, %d moved\n", indent, result.Skipped, result.Applied)
+	}
+	if len(result.Sticky) > 0 {
+		infoColor.Printf("%s%d sticky window(s) left untiled (settings.tileStickyWindows)\n", indent, len(result.Sticky))
+	}
+}
+
 // layoutCycleCmd cycles to the next layout
 var layoutCycleCmd = &cobra.Command{
 	Use:   "cycle",
 	Short: "Cycle to the next layout",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1171,7 +2441,7 @@ var layoutCycleCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
@@ -1213,11 +2483,8 @@ var layoutCurrentCmd = &cobra.Command{
 		}
 
 		layoutID := runtimeState.GetCurrentLayoutForSpace(spaceID)
-		if layoutID == "" {
-			fmt.Println("No layout currently applied")
-			return nil
-		}
 
+		// An empty layoutId means none is applied
 		if jsonOutput {
 			return printJSON(map[string]string{
 				"spaceId":  spaceID,
@@ -1225,6 +2492,11 @@ var layoutCurrentCmd = &cobra.Command{
 			})
 		}
 
+		if layoutID == "" {
+			fmt.Println("No layout currently applied")
+			return nil
+		}
+
 		fmt.Printf("Current layout for space %s: %s\n", spaceID, layoutID)
 		return nil
 	},
@@ -1235,7 +2507,7 @@ var layoutReapplyCmd = &cobra.Command{
 	Use:   "reapply",
 	Short: "Reapply the current layout",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1257,7 +2529,7 @@ var layoutReapplyCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
@@ -1274,6 +2546,218 @@ var layoutReapplyCmd = &cobra.Command{
 	},
 }
 
+// layoutSaveCmd saves the window arrangement on the active space as a layout
+var layoutSaveCmd = &cobra.Command{
+	Use:   "save <layout-id>",
+	Short: "Save the current window arrangement as a layout",
+	Long: `Infers a grid from the window frames on the active space and adds it to the
+config file as a new layout. Windows sharing an edge (within the cell padding)
+share a grid line; windows on the same rectangle share a cell. Cells are named
+cell1, cell2, ... in reading order. The generated YAML is printed so you can
+rename cells or tweak tracks. An existing layout ID in the config file is only
+replaced with --force; one defined in an included file is overridden.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		layoutID := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Check the file the layout is written to; one from an include is
+		// overridden by it
+		path, err := gridConfig.FindConfigFile()
+		if err != nil {
+			return err
+		}
+		if exists, err := gridConfig.FileHasLayout(path, layoutID); err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		} else if exists && !force {
+			return fmt.Errorf("layout %s already exists (use --force to overwrite)", layoutID)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(context.Background(), c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Infer a grid from the tileable windows
+		var frames []gridTypes.Rect
+		for _, w := range snap.Windows {
+			if w.IsTileable() {
+				frames = append(frames, w.Frame)
+			}
+		}
+		tolerance := gridLayout.DefaultInferTolerance + 2*float64(cfg.Settings.CellPadding)
+		lc, err := gridLayout.InferLayout(layoutID, frames, snap.DisplayBounds, tolerance)
+		if err != nil {
+			return fmt.Errorf("failed to infer layout: %w", err)
+		}
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			lc.Name = name
+		}
+		lc.Description = fmt.Sprintf("Saved from space %s", snap.SpaceID)
+
+		// 3. Write it to the config file
+		if err := gridConfig.SaveLayout(path, *lc, force); err != nil {
+			return fmt.Errorf("failed to save layout: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(lc)
+		}
+
+		data, err := gridConfig.MarshalLayoutYAML(*lc)
+		if err != nil {
+			return err
+		}
+		successColor.Printf("✓ Saved layout %s to %s\n", layoutID, path)
+		fmt.Println()
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+// commandConfig is the config the running command loaded with loadConfig,
+// nil until it does
+var commandConfig *gridConfig.Config
+
+// loadConfig loads the default config and keeps it for the command's
+// automatic state backups (see backupPolicy)
+func loadConfig() (*gridConfig.Config, error) {
+	cfg, err := gridConfig.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	commandConfig = cfg
+	return cfg, nil
+}
+
+// backupPolicy reads settings.autoBackupState and stateBackups when a save
+// first backs up the state file, from the command's config. Commands that
+// save without loading one load it here.
+func backupPolicy() (bool, int) {
+	cfg := commandConfig
+	if cfg == nil {
+		loaded, err := gridConfig.LoadConfig("")
+		if err != nil {
+			return false, 0
+		}
+		cfg = loaded
+	}
+	return cfg.Settings.AutoBackupState, cfg.Settings.StateBackups
+}
+
+// syncState reconciles local state with the server snapshot. In read-only
+// mode (--server-only) state starts empty on every run, so the space's
+// assignments are first rebuilt from where its windows are.
+func syncState(snap *gridServer.Snapshot, rs *gridState.RuntimeState) error {
+	if gridState.ReadOnly() {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if _, err := gridLayout.AdoptWindowPositions(snap, cfg, rs); err != nil {
+			return err
+		}
+	}
+	return gridReconcile.Sync(snap, rs)
+}
+
+// errReadOnlyState is returned by commands that only change local state
+var errReadOnlyState = fmt.Errorf("state is read-only (--server-only or %s)", gridState.ReadOnlyEnv)
+
+// fetchSnapshot fetches a snapshot for the active display, or for the given
+// display (index or UUID) when displayRef is set.
+func fetchSnapshot(ctx context.Context, c *client.Client, displayRef string) (*gridServer.Snapshot, error) {
+	if displayRef == "" {
+		return gridServer.Fetch(ctx, c)
+	}
+	return gridServer.FetchForDisplay(ctx, c, displayRef)
+}
+
+// MARK: - Display Commands
+
+// displayCmd is the parent command for display subcommands
+var displayCmd = &cobra.Command{
+	Use:   "display",
+	Short: "Manage layouts per display",
+}
+
+// displayLayoutCmd sets or applies a layout on a display's current space
+var displayLayoutCmd = &cobra.Command{
+	Use:   "layout <display> <layout-id>",
+	Short: "Set the layout for a display's current space",
+	Long: `Sets the layout for the space currently shown on a display, without needing
+its space ID. The display is given by index (as in 'show display') or UUID.
+
+By default the layout is only recorded and takes effect on the next apply;
+use --apply to arrange the display's windows immediately.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		displayRef, layoutID := args[0], args[1]
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE for the display's current space
+		snap, err := gridServer.FetchForDisplay(ctx, c, displayRef)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Set or apply the layout
+		apply, _ := cmd.Flags().GetBool("apply")
+		if !apply {
+			if err := gridLayout.SetSpaceLayout(cfg, runtimeState, snap.SpaceID, layoutID); err != nil {
+				return fmt.Errorf("failed to set layout: %w", err)
+			}
+			successColor.Printf("✓ Set layout %s for display %s (space %s)\n", layoutID, displayRef, snap.SpaceID)
+			return nil
+		}
+
+		opts := gridLayout.DefaultApplyOptions()
+		opts.Gap = float64(cfg.Settings.CellPadding)
+
+		result, err := gridLayout.ApplyLayoutWithResult(ctx, c, snap, cfg, runtimeState, layoutID, opts)
+		if err != nil {
+			return fmt.Errorf("failed to apply layout: %w", err)
+		}
+
+		successColor.Printf("✓ Applied layout %s to display %s (space %s)\n", layoutID, displayRef, snap.SpaceID)
+		if result.Skipped > 0 {
+			infoColor.Printf("  %d window(s) already in place, %d moved\n", result.Skipped, result.Applied)
+		}
+		if len(result.Sticky) > 0 {
+			infoColor.Printf("  %d sticky window(s) left untiled (settings.tileStickyWindows)\n", len(result.Sticky))
+		}
+		return nil
+	},
+}
+
 // MARK: - Config Commands
 
 // gridConfigCmd is the parent command for config subcommands
@@ -1288,7 +2772,7 @@ var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1297,11 +2781,31 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+// configSchemaCmd prints a JSON Schema for the config file
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema for the configuration file",
+	Long: `Prints a JSON Schema describing config.yaml, for editors that offer completion
+and inline validation (e.g. yaml-language-server). It is generated from the
+config types, so it always matches this version of grid.
+
+Checks that span several fields, such as layout IDs referenced by spaces,
+still need 'grid config validate'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printJSON(gridConfig.Schema())
+	},
+}
+
 // configValidateCmd validates config file
 var configValidateCmd = &cobra.Command{
 	Use:   "validate [path]",
 	Short: "Validate configuration file",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Validates a config file, stopping at the first error.
+
+When the server is running, it also warns about layouts whose fixed tracks
+(px sizes and minmax minimums) don't fit a connected display.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := ""
 		if len(args) > 0 {
@@ -1320,32 +2824,132 @@ var configValidateCmd = &cobra.Command{
 		successColor.Println("✓ Configuration is valid")
 		fmt.Printf("  Layouts: %d\n", len(cfg.Layouts))
 		fmt.Printf("  Spaces: %d\n", len(cfg.Spaces))
+		fmt.Printf("  Displays: %d\n", len(cfg.Displays))
 		fmt.Printf("  App Rules: %d\n", len(cfg.AppRules))
 
+		for _, issue := range cfg.CheckFit(liveFitDisplays()) {
+			keyColor.Printf("  %-7s ", issue.Severity)
+			fmt.Printf("%s: %s\n", issue.Scope, issue.Message)
+		}
+
 		return nil
 	},
 }
 
-// configInitCmd creates default config
-var configInitCmd = &cobra.Command{
-	Use:   "init",
-	Short: "Create default configuration file",
+// configWatchValidateCmd runs validation and lint checks once, for pre-commit hooks
+var configWatchValidateCmd = &cobra.Command{
+	Use:   "watch-validate <path>",
+	Short: "Validate and lint a config file in one pass (for pre-commit hooks)",
+	Long: `Runs validation plus lint checks against a config file and reports the
+validation error, if any, along with every lint warning. When the server is running, layouts whose
+fixed tracks don't fit a connected display are reported as warnings too. Exits
+non-zero if any errors are found; warnings alone do not fail. Runs once; it is
+not a daemon.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		path := gridConfig.GetConfigPath()
+		// Failing checks aren't usage errors; keep hook output to the report
+		cmd.SilenceUsage = true
 
-		// Check if file exists
-		if _, err := os.Stat(path); err == nil {
-			return fmt.Errorf("config file already exists at %s", path)
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" {
+			return fmt.Errorf("invalid format %q (must be text or json)", format)
 		}
 
-		defaultConfig := `# Grid Layout Configuration
-settings:
-  defaultStackMode: vertical
-  cellPadding: 8
-  animationDuration: 0.2
-  focusFollowsMouse: false
+		report := gridConfig.Report{Path: args[0], Issues: []gridConfig.Issue{}}
+		cfg, err := gridConfig.ParseConfigFile(args[0])
+		if err != nil {
+			report.Issues = append(report.Issues, gridConfig.Issue{
+				Severity: gridConfig.SeverityError,
+				Scope:    "file",
+				Message:  err.Error(),
+			})
+		} else {
+			report.Issues = append(report.Issues, cfg.Check()...)
+			report.Issues = append(report.Issues, cfg.CheckFit(liveFitDisplays())...)
+		}
 
-layouts:
+		if format == "json" || jsonOutput {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printConfigReport(&report)
+		}
+
+		if n := report.Errors(); n > 0 {
+			return fmt.Errorf("%s: %d error(s) found", args[0], n)
+		}
+		return nil
+	},
+}
+
+// liveFitDisplays returns the usable size of each connected display, named
+// by index, for config fit checks. Returns nil when the server isn't
+// reachable, so checks still work offline (e.g. in CI).
+func liveFitDisplays() []gridConfig.FitDisplay {
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	displays, err := gridServer.FetchDisplays(context.Background(), c)
+	if err != nil {
+		logging.Debug().Err(err).Msg("server unavailable, skipping display fit checks")
+		return nil
+	}
+	fit := make([]gridConfig.FitDisplay, 0, len(displays))
+	for i, d := range displays {
+		fit = append(fit, gridConfig.FitDisplay{
+			Name:   strconv.Itoa(i),
+			Width:  d.VisibleFrame.Width,
+			Height: d.VisibleFrame.Height,
+		})
+	}
+	return fit
+}
+
+// printConfigReport prints a config check report in human-readable form
+func printConfigReport(report *gridConfig.Report) {
+	if len(report.Issues) == 0 {
+		successColor.Printf("✓ %s: no issues\n", report.Path)
+		return
+	}
+
+	if report.Errors() > 0 {
+		errorColor.Printf("✗ %s: %d error(s), %d warning(s)\n", report.Path, report.Errors(), report.Warnings())
+	} else {
+		successColor.Printf("✓ %s: %d warning(s)\n", report.Path, report.Warnings())
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Severity == gridConfig.SeverityError {
+			errorColor.Printf("  %-7s ", issue.Severity)
+		} else {
+			keyColor.Printf("  %-7s ", issue.Severity)
+		}
+		fmt.Printf("%s: %s\n", issue.Scope, issue.Message)
+	}
+}
+
+// configInitCmd creates default config
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create default configuration file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := gridConfig.GetConfigPath()
+
+		// Check if file exists
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("config file already exists at %s", path)
+		}
+
+		defaultConfig := `# Grid Layout Configuration
+settings:
+  defaultStackMode: vertical
+  cellPadding: 8
+  animationDuration: 0.2
+  focusFollowsMouse: false
+  mouseFollowsFocus: false
+
+layouts:
   - id: two-column
     name: Two Column
     description: Equal two-column split
@@ -1402,13 +3006,119 @@ appRules:
 	},
 }
 
+// configGenerateCmd builds a starter config from grid dimensions
+var configGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a configuration file from grid dimensions",
+	Long: `Builds a config with an equal-track grid layout (cells cell1, cell2, … in
+reading order) and a single-cell "full" layout, and configures the given
+spaces to cycle between them.
+
+Without --columns, --rows or --spaces, asks for each value; press enter to take
+the default, or pass --yes to take all of them. Sent through the daemon
+(--daemon) it can't ask, and needs one of those flags. The config is written to the default config path unless it
+already exists (--force overwrites it), or printed with --stdout.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := gridConfig.GenerateOptions{}
+		opts.Columns, _ = cmd.Flags().GetInt("columns")
+		opts.Rows, _ = cmd.Flags().GetInt("rows")
+		opts.Spaces, _ = cmd.Flags().GetStringSlice("spaces")
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes && !cmd.Flags().Changed("columns") && !cmd.Flags().Changed("rows") && !cmd.Flags().Changed("spaces") {
+			if forwarded {
+				return fmt.Errorf("config generate can't prompt through the daemon; pass --columns, --rows, --spaces or --yes")
+			}
+			if err := promptGenerateOptions(cmd.InOrStdin(), cmd.ErrOrStderr(), &opts); err != nil {
+				return err
+			}
+		}
+
+		cfg, err := gridConfig.Generate(opts)
+		if err != nil {
+			return err
+		}
+		data, err := gridConfig.MarshalConfigYAML(cfg)
+		if err != nil {
+			return err
+		}
+
+		if toStdout, _ := cmd.Flags().GetBool("stdout"); toStdout {
+			_, err := cmd.OutOrStdout().Write(data)
+			return err
+		}
+
+		path := gridConfig.GetConfigPath()
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("config file already exists at %s (use --force to overwrite)", path)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+
+		successColor.Printf("✓ Generated config at: %s\n", path)
+		return nil
+	},
+}
+
+// promptGenerateOptions asks for config generate's values on out, reading
+// answers from in. An empty answer keeps the value already in opts.
+func promptGenerateOptions(in io.Reader, out io.Writer, opts *gridConfig.GenerateOptions) error {
+	reader := bufio.NewReader(in)
+	ask := func(question, def string) (string, error) {
+		fmt.Fprintf(out, "%s [%s]: ", question, def)
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+		return def, nil
+	}
+	askInt := func(question string, value *int) error {
+		answer, err := ask(question, strconv.Itoa(*value))
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil {
+			return fmt.Errorf("invalid number for %s: %q", strings.ToLower(question), answer)
+		}
+		*value = n
+		return nil
+	}
+
+	if err := askInt("Columns", &opts.Columns); err != nil {
+		return err
+	}
+	if err := askInt("Rows", &opts.Rows); err != nil {
+		return err
+	}
+	answer, err := ask("Spaces to configure (comma-separated IDs, - for none)", strings.Join(opts.Spaces, ","))
+	if err != nil {
+		return err
+	}
+	opts.Spaces = nil
+	if answer != "-" {
+		opts.Spaces = strings.Split(answer, ",")
+	}
+	return nil
+}
+
 // MARK: - State Commands
 
 // gridStateCmd is the parent command for state subcommands
 var gridStateCmd = &cobra.Command{
 	Use:   "state",
 	Short: "Manage runtime state",
-	Long:  `Commands for showing and resetting grid runtime state.`,
+	Long:  `Commands for showing, resetting, backing up, and restoring grid runtime state.`,
 }
 
 // stateShowCmd shows runtime state
@@ -1421,6 +3131,18 @@ var stateShowCmd = &cobra.Command{
 			return fmt.Errorf("failed to load state: %w", err)
 		}
 
+		if spaceID, _ := cmd.Flags().GetString("space"); spaceID != "" {
+			summary, ok := runtimeState.SpaceSummary(spaceID)
+			if !ok {
+				return fmt.Errorf("no state for space %s", spaceID)
+			}
+			if jsonOutput {
+				return printJSON(runtimeState.GetSpaceReadOnly(spaceID))
+			}
+			printSpaceState(os.Stdout, summary)
+			return nil
+		}
+
 		if jsonOutput {
 			return printJSON(runtimeState)
 		}
@@ -1450,11 +3172,50 @@ var stateShowCmd = &cobra.Command{
 	},
 }
 
+// printSpaceState prints a detailed SpaceSummary for `state show --space`
+func printSpaceState(w io.Writer, summary map[string]interface{}) {
+	keyColor.Fprintf(w, "Space %v:\n", summary["spaceId"])
+	fmt.Fprintf(w, "  Current Layout: %v (index %v)\n", summary["currentLayout"], summary["layoutIndex"])
+	fmt.Fprintf(w, "  Focused Cell: %v (window index %v)\n", summary["focusedCell"], summary["focusedWindow"])
+	if master, _ := summary["masterWindow"].(uint32); master != 0 {
+		fmt.Fprintf(w, "  Master Window: %d\n", master)
+	}
+	if tracks, _ := summary["tracks"].(*gridState.TrackOverrides); tracks != nil {
+		fmt.Fprintf(w, "  Track Overrides: columns %v, rows %v\n", tracks.Columns, tracks.Rows)
+	}
+	if mode, _ := summary["defaultStackMode"].(gridTypes.StackMode); mode != "" {
+		fmt.Fprintf(w, "  Default Stack Mode: %s\n", mode)
+	}
+	fmt.Fprintf(w, "  Windows: %v\n", summary["windowCount"])
+
+	cells, _ := summary["cells"].([]map[string]interface{})
+	fmt.Fprintf(w, "  Cells: %d\n", len(cells))
+	for _, cell := range cells {
+		fmt.Fprintln(w)
+		keyColor.Fprintf(w, "  Cell %v:\n", cell["cellId"])
+		fmt.Fprintf(w, "    Windows: %v\n", cell["windows"])
+		ratios := fmt.Sprintf("%v", cell["splitRatios"])
+		if userSet, _ := cell["ratiosUserSet"].(bool); userSet {
+			ratios += " (user set)"
+		}
+		fmt.Fprintf(w, "    Split Ratios: %s\n", ratios)
+		stackMode := fmt.Sprintf("%v", cell["stackMode"])
+		if stackMode == "" {
+			stackMode = "(default)"
+		}
+		fmt.Fprintf(w, "    Stack Mode: %s\n", stackMode)
+		fmt.Fprintf(w, "    Last Focused Index: %v\n", cell["lastFocusedIdx"])
+	}
+}
+
 // stateResetCmd resets runtime state
 var stateResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Clear all runtime state",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if gridState.ReadOnly() {
+			return errReadOnlyState
+		}
 		runtimeState, err := gridState.LoadState()
 		if err != nil {
 			return fmt.Errorf("failed to load state: %w", err)
@@ -1469,6 +3230,214 @@ var stateResetCmd = &cobra.Command{
 	},
 }
 
+// stateSetStackModeCmd sets a space's default stack mode
+var stateSetStackModeCmd = &cobra.Command{
+	Use:   "set-stack-mode <space-id> <mode>",
+	Short: "Set the stack mode for a space's cells (vertical, horizontal, tabs or grid)",
+	Long: `Set the stack mode a space's cells use when neither the cell nor the
+layout sets one. With --all-cells, every existing cell of the space is
+switched to the mode too.
+
+The mode takes effect on the next layout apply or reapply.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if gridState.ReadOnly() {
+			return errReadOnlyState
+		}
+		spaceID := args[0]
+		mode, ok := gridTypes.ParseStackMode(args[1])
+		if !ok {
+			return fmt.Errorf("invalid stack mode %q (use vertical, horizontal, tabs or grid)", args[1])
+		}
+		allCells, _ := cmd.Flags().GetBool("all-cells")
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		runtimeState.SetSpaceStackMode(spaceID, mode, allCells)
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+
+		if allCells {
+			cellCount := len(runtimeState.GetSpaceReadOnly(spaceID).Cells)
+			successColor.Printf("✓ Space %s stack mode set to %s (%d cells)\n", spaceID, mode, cellCount)
+		} else {
+			successColor.Printf("✓ Space %s default stack mode set to %s\n", spaceID, mode)
+		}
+		return nil
+	},
+}
+
+// stateBackupCmd writes a backup of the current state file
+var stateBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the current runtime state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keep := gridState.DefaultBackupCount
+		if cfg, err := loadConfig(); err == nil && cfg.Settings.StateBackups > 0 {
+			keep = cfg.Settings.StateBackups
+		}
+
+		name, err := gridState.BackupStateFile(gridState.GetStatePath(), keep)
+		if err != nil {
+			return fmt.Errorf("failed to back up state: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{"backup": name})
+		}
+
+		successColor.Printf("✓ State backed up to %s\n", name)
+		return nil
+	},
+}
+
+// stateListBackupsCmd lists available state backups
+var stateListBackupsCmd = &cobra.Command{
+	Use:   "list-backups",
+	Short: "List runtime state backups",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		statePath := gridState.GetStatePath()
+		names, err := gridState.ListBackups(statePath)
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(names)
+		}
+
+		if len(names) == 0 {
+			infoColor.Println("No state backups found")
+			return nil
+		}
+
+		keyColor.Printf("Backups in %s:\n", gridState.GetBackupDir(statePath))
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+		}
+		return nil
+	},
+}
+
+// stateRestoreCmd restores runtime state from a backup
+var stateRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore runtime state from a backup (latest by default)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if gridState.ReadOnly() {
+			return errReadOnlyState
+		}
+		name, _ := cmd.Flags().GetString("backup")
+
+		if _, err := gridState.RestoreBackup(gridState.GetStatePath(), name); err != nil {
+			return fmt.Errorf("failed to restore state: %w", err)
+		}
+
+		if name == "" {
+			name = "latest backup"
+		}
+		successColor.Printf("✓ State restored from %s\n", name)
+		return nil
+	},
+}
+
+// reconcileCmd is the parent command for inspecting state against the server
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Compare local state with the server",
+}
+
+// reconcileStatusCmd reports what reconciliation would change, without saving
+var reconcileStatusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"dry-run"},
+	Short:   "Show how local state has drifted from the server",
+	Long: `Compares the state of the current space (or --display's) with the server and
+reports the differences without changing or saving anything.
+
+Every command reconciles first, silently: windows in cells that are gone from
+the server are removed, pins and floats of closed windows are dropped, and focus
+follows the focused window. This shows what that would change, along with
+drift it leaves for the next layout apply: windows the apply would tile that
+are in no cell, a layout missing from the config, and cells the layout doesn't
+define.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		displayRef, _ := cmd.Flags().GetString("display")
+		snap, err := fetchSnapshot(context.Background(), c, displayRef)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		drift := gridReconcile.Status(context.Background(), snap, cfg, runtimeState, gridLayout.NewStickyChecker(c))
+		if jsonOutput {
+			return printJSON(drift)
+		}
+		printDrift(os.Stdout, drift)
+		return nil
+	},
+}
+
+// printDrift prints a reconcile status report
+func printDrift(w io.Writer, drift *gridReconcile.Drift) {
+	layoutID := drift.LayoutID
+	if layoutID == "" {
+		layoutID = "none applied"
+	}
+	keyColor.Fprintf(w, "Space %s (layout %s):\n", drift.SpaceID, layoutID)
+	if drift.InSync() {
+		successColor.Fprintln(w, "✓ State matches the server")
+		return
+	}
+
+	if len(drift.Stale) > 0 || len(drift.StalePins) > 0 || len(drift.StaleFloats) > 0 || drift.FocusedCell != "" {
+		fmt.Fprintln(w, "  Reconcile would:")
+		for _, s := range drift.Stale {
+			fmt.Fprintf(w, "    - remove window %d from cell %s (gone from the server)\n", s.WindowID, s.CellID)
+		}
+		for _, id := range drift.StalePins {
+			fmt.Fprintf(w, "    - drop the pin of closed window %d\n", id)
+		}
+		for _, id := range drift.StaleFloats {
+			fmt.Fprintf(w, "    - drop the float of closed window %d\n", id)
+		}
+		if drift.FocusedCell != "" {
+			fmt.Fprintf(w, "    ~ move focus to cell %s\n", drift.FocusedCell)
+		}
+	}
+
+	if len(drift.Unassigned) > 0 || drift.MissingLayout || len(drift.UnknownCells) > 0 {
+		fmt.Fprintln(w, "  Left for the next layout apply:")
+		for _, id := range drift.Unassigned {
+			fmt.Fprintf(w, "    + window %d is in no cell\n", id)
+		}
+		if drift.MissingLayout {
+			fmt.Fprintf(w, "    ! layout %s is not in the config\n", drift.LayoutID)
+		}
+		for _, cellID := range drift.UnknownCells {
+			fmt.Fprintf(w, "    ! cell %s is not in layout %s\n", cellID, drift.LayoutID)
+		}
+	}
+}
+
 // MARK: - the-grid Focus Commands
 
 // focusCmd is the parent command for focus subcommands
@@ -1479,12 +3448,18 @@ var focusCmd = &cobra.Command{
 }
 
 // focusDirectionHelper is a helper function for directional focus commands
-func focusDirectionHelper(direction gridTypes.Direction, wrapAround bool, extend bool) error {
-	cfg, err := gridConfig.LoadConfig("")
+func focusDirectionHelper(direction gridTypes.Direction, cmd *cobra.Command, extend bool) error {
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	// An explicit --wrap overrides settings.focus.wrap for the direction's axis
+	wrapAround := cfg.Settings.Focus.Wrap.Wraps(direction)
+	if cmd.Flags().Changed("wrap") {
+		wrapAround, _ = cmd.Flags().GetBool("wrap")
+	}
+
 	runtimeState, err := gridState.LoadState()
 	if err != nil {
 		return fmt.Errorf("failed to load state: %w", err)
@@ -1502,14 +3477,15 @@ func focusDirectionHelper(direction gridTypes.Direction, wrapAround bool, extend
 	}
 
 	// 2. Reconcile local state with server
-	if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+	if err := syncState(snap, runtimeState); err != nil {
 		return fmt.Errorf("failed to reconcile state: %w", err)
 	}
 
 	// 3. Move focus
 	opts := gridFocus.MoveFocusOpts{
-		WrapAround: wrapAround,
-		Extend:     extend,
+		WrapAround:        wrapAround,
+		Extend:            extend,
+		MouseFollowsFocus: cfg.Settings.MouseFollowsFocus,
 	}
 	windowID, err := gridFocus.MoveFocus(ctx, c, snap, cfg, runtimeState, direction, opts)
 	if err != nil {
@@ -1526,12 +3502,11 @@ var focusLeftCmd = &cobra.Command{
 	Short: "Move focus to left cell",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
 		extend, _ := cmd.Flags().GetBool("extend")
 		if extend {
 			logging.Debug().Bool("extend", extend).Msg("cross-monitor focus enabled")
 		}
-		return focusDirectionHelper(gridTypes.DirLeft, wrap, extend)
+		return focusDirectionHelper(gridTypes.DirLeft, cmd, extend)
 	},
 }
 
@@ -1541,12 +3516,11 @@ var focusRightCmd = &cobra.Command{
 	Short: "Move focus to right cell",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
 		extend, _ := cmd.Flags().GetBool("extend")
 		if extend {
 			logging.Debug().Bool("extend", extend).Msg("cross-monitor focus enabled")
 		}
-		return focusDirectionHelper(gridTypes.DirRight, wrap, extend)
+		return focusDirectionHelper(gridTypes.DirRight, cmd, extend)
 	},
 }
 
@@ -1556,12 +3530,11 @@ var focusUpCmd = &cobra.Command{
 	Short: "Move focus to cell above",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
 		extend, _ := cmd.Flags().GetBool("extend")
 		if extend {
 			logging.Debug().Bool("extend", extend).Msg("cross-monitor focus enabled")
 		}
-		return focusDirectionHelper(gridTypes.DirUp, wrap, extend)
+		return focusDirectionHelper(gridTypes.DirUp, cmd, extend)
 	},
 }
 
@@ -1571,18 +3544,95 @@ var focusDownCmd = &cobra.Command{
 	Short: "Move focus to cell below",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
 		extend, _ := cmd.Flags().GetBool("extend")
 		if extend {
 			logging.Debug().Bool("extend", extend).Msg("cross-monitor focus enabled")
 		}
-		return focusDirectionHelper(gridTypes.DirDown, wrap, extend)
+		return focusDirectionHelper(gridTypes.DirDown, cmd, extend)
 	},
 }
 
+// moveWindowOptsFromFlags builds window move options from the shared move flags
+func moveWindowOptsFromFlags(cmd *cobra.Command) gridWindow.MoveWindowOpts {
+	wrap, _ := cmd.Flags().GetBool("wrap")
+	extend, _ := cmd.Flags().GetBool("extend")
+	windowID, _ := cmd.Flags().GetUint32("window-id")
+	withSiblings, _ := cmd.Flags().GetBool("with-app-siblings")
+	targetSpace, _ := cmd.Flags().GetString("target-space")
+	autoLayout, _ := cmd.Flags().GetBool("auto-layout")
+	follow, _ := cmd.Flags().GetBool("follow")
+	keepRelative, _ := cmd.Flags().GetBool("keep-relative")
+	autoExpand, _ := cmd.Flags().GetBool("auto-expand")
+	reflowSource, _ := cmd.Flags().GetBool("reflow-source")
+	balanceTarget, _ := cmd.Flags().GetBool("balance-target")
+	if noBalance, _ := cmd.Flags().GetBool("no-balance-target"); noBalance {
+		balanceTarget = false
+	}
+	split, _ := cmd.Flags().GetString("split")
+	app, _ := cmd.Flags().GetString("app")
+	nth, _ := cmd.Flags().GetInt("nth")
+	// A fallback space only makes sense when crossing displays
+	if targetSpace != "" {
+		extend = true
+	}
+	if extend {
+		logging.Debug().Bool("extend", extend).Msg("cross-monitor window move enabled")
+	}
+	return gridWindow.MoveWindowOpts{
+		WrapAround:      wrap,
+		Extend:          extend,
+		WindowID:        windowID,
+		WithAppSiblings: withSiblings,
+		TargetSpace:     targetSpace,
+		AutoLayout:      autoLayout,
+		Follow:          follow,
+		KeepRelative:    keepRelative,
+		AutoExpand:      autoExpand,
+		ReflowSource:    reflowSource,
+		Split:           gridTypes.StackMode(split),
+
+		KeepTargetRatios: !balanceTarget,
+		FocusFollow:      focusFollowFromFlags(cmd),
+
+		App: app,
+		Nth: nth,
+	}
+}
+
+// addFocusFollowFlags registers the flags overriding settings.focusFollowsMove
+func addFocusFollowFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("then-focus", false, "Focus the moved window (overrides settings.focusFollowsMove)")
+	cmd.Flags().Bool("no-focus", false, "Keep focus on the window that had it (overrides settings.focusFollowsMove)")
+	cmd.MarkFlagsMutuallyExclusive("then-focus", "no-focus")
+}
+
+// focusFollowFromFlags returns the focus behaviour --then-focus or
+// --no-focus asks for, or nil to use settings.focusFollowsMove
+func focusFollowFromFlags(cmd *cobra.Command) *bool {
+	follow := true
+	switch {
+	case cmd.Flags().Changed("then-focus"):
+		follow, _ = cmd.Flags().GetBool("then-focus")
+	case cmd.Flags().Changed("no-focus"):
+		noFocus, _ := cmd.Flags().GetBool("no-focus")
+		follow = !noFocus
+	default:
+		return nil
+	}
+	return &follow
+}
+
 // moveWindowDirectionHelper is a helper function for directional window move commands
-func moveWindowDirectionHelper(direction gridTypes.Direction, wrapAround bool, extend bool, windowID uint32) error {
-	cfg, err := gridConfig.LoadConfig("")
+func moveWindowDirectionHelper(direction gridTypes.Direction, opts gridWindow.MoveWindowOpts) error {
+	return runWindowMove(opts, func(ctx context.Context, c *client.Client, snap *gridServer.Snapshot, cfg *gridConfig.Config, rs *gridState.RuntimeState) (*gridWindow.MoveResult, error) {
+		return gridWindow.MoveWindow(ctx, c, snap, cfg, rs, direction, opts)
+	})
+}
+
+// runWindowMove loads config and state, syncs with the server, runs move and
+// reports the result
+func runWindowMove(opts gridWindow.MoveWindowOpts, move func(context.Context, *client.Client, *gridServer.Snapshot, *gridConfig.Config, *gridState.RuntimeState) (*gridWindow.MoveResult, error)) error {
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -1604,67 +3654,228 @@ func moveWindowDirectionHelper(direction gridTypes.Direction, wrapAround bool, e
 	}
 
 	// 2. Reconcile local state with server
-	if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+	if err := syncState(snap, runtimeState); err != nil {
 		return fmt.Errorf("failed to reconcile state: %w", err)
 	}
 
 	// 3. Move window
-	opts := gridWindow.MoveWindowOpts{
-		WrapAround: wrapAround,
-		Extend:     extend,
-		WindowID:   windowID,
-	}
-	result, err := gridWindow.MoveWindow(ctx, c, snap, cfg, runtimeState, direction, opts)
+	result, err := move(ctx, c, snap, cfg, runtimeState)
 	if err != nil {
 		return fmt.Errorf("failed to move window: %w", err)
 	}
 
-	if result.CrossDisplay {
+	if result.Unchanged != "" {
+		infoColor.Printf("Window %d not moved: %s\n", result.WindowID, result.Unchanged)
+	} else if result.SwappedWith != 0 {
+		successColor.Printf("Swapped window %d (%s) with window %d (%s)\n",
+			result.WindowID, result.TargetCell, result.SwappedWith, result.SourceCell)
+	} else if result.Reordered {
+		successColor.Printf("Moved window %d within %s to position %d\n",
+			result.WindowID, result.SourceCell, result.StackIndex)
+	} else if result.CrossDisplay {
 		successColor.Printf("Moved window %d: %s -> %s (cross-display to space %s)\n",
 			result.WindowID, result.SourceCell, result.TargetCell, result.TargetSpace)
+		if result.Followed {
+			infoColor.Printf("  Switched to space %s\n", result.TargetSpace)
+		} else if note := gridWindow.InactiveSpaceNote(result); note != "" {
+			infoColor.Printf("  Note: %s\n", note)
+		}
+	} else if result.ExpandedLayout != "" {
+		successColor.Printf("Moved window %d: %s -> %s (switched to layout %s)\n",
+			result.WindowID, result.SourceCell, result.TargetCell, result.ExpandedLayout)
 	} else {
 		successColor.Printf("Moved window %d: %s -> %s\n",
 			result.WindowID, result.SourceCell, result.TargetCell)
 	}
+	if len(result.Siblings) > 0 {
+		fmt.Printf("  With app siblings: %v\n", result.Siblings)
+	}
+	if opts.Split != "" && result.ExpandedLayout == "" {
+		infoColor.Printf("  Cell %s now stacks %s\n", result.TargetCell, opts.Split)
+	}
 	return nil
 }
 
-// windowMoveCmd is the parent command for window move operations
-var windowMoveCmd = &cobra.Command{
-	Use:   "move",
-	Short: "Move window to adjacent cell",
-	Long:  `Commands for moving windows between cells in the layout grid.`,
+// windowThrowCmd moves a window to another display by index or UUID
+var windowThrowCmd = &cobra.Command{
+	Use:   "throw <display>",
+	Short: "Move window to another display's current space",
+	Long: `Moves the focused window (or --window-id) to the current space of the display
+with the given index, as numbered by 'list displays', or UUID. The window lands
+in the cell closest to where it sat on its current display.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := moveWindowOptsFromFlags(cmd)
+		return runWindowMove(opts, func(ctx context.Context, c *client.Client, snap *gridServer.Snapshot, cfg *gridConfig.Config, rs *gridState.RuntimeState) (*gridWindow.MoveResult, error) {
+			return gridWindow.ThrowWindow(ctx, c, snap, cfg, rs, args[0], opts)
+		})
+	},
 }
 
-// windowMoveLeftCmd moves window to the left cell
-var windowMoveLeftCmd = &cobra.Command{
-	Use:   "left",
-	Short: "Move window to left cell",
-	Args:  cobra.NoArgs,
+// windowSwapCmd swaps the focused window with the window in an adjacent cell
+var windowSwapCmd = &cobra.Command{
+	Use:   "swap <direction>",
+	Short: "Swap window with the window in an adjacent cell",
+	Long: `Swaps the focused window (or --window-id) with the top window of the adjacent
+cell in the given direction (left, right, up, down or a diagonal). Each window
+takes the other's cell and stack position, so split positions are kept, and
+focus stays with the swapped window (see settings.focusFollowsMove and
+--no-focus). An empty target cell makes this a move.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		direction, ok := gridTypes.ParseDirection(args[0])
+		if !ok {
+			return fmt.Errorf("invalid direction: %s (use left, right, up, down or a diagonal)", args[0])
+		}
 		wrap, _ := cmd.Flags().GetBool("wrap")
-		extend, _ := cmd.Flags().GetBool("extend")
 		windowID, _ := cmd.Flags().GetUint32("window-id")
-		if extend {
-			logging.Debug().Bool("extend", extend).Msg("cross-monitor window move enabled")
-		}
-		return moveWindowDirectionHelper(gridTypes.DirLeft, wrap, extend, windowID)
+		opts := gridWindow.MoveWindowOpts{WrapAround: wrap, WindowID: windowID, FocusFollow: focusFollowFromFlags(cmd)}
+		return runWindowMove(opts, func(ctx context.Context, c *client.Client, snap *gridServer.Snapshot, cfg *gridConfig.Config, rs *gridState.RuntimeState) (*gridWindow.MoveResult, error) {
+			return gridWindow.SwapWindow(ctx, c, snap, cfg, rs, direction, opts)
+		})
 	},
 }
 
-// windowMoveRightCmd moves window to the right cell
-var windowMoveRightCmd = &cobra.Command{
-	Use:   "right",
-	Short: "Move window to right cell",
-	Args:  cobra.NoArgs,
+// windowPromoteCmd moves the focused window into the main cell
+var windowPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Move window to the main cell",
+	Long: `Swaps the focused window (or --window-id) with the top window of the main
+cell: settings.mainCell if the layout has it, else a cell named "main", else
+the largest cell. Only the two cells are re-placed. A window already on top of
+the main cell, or a layout with a single cell, leaves everything as is.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
-		extend, _ := cmd.Flags().GetBool("extend")
 		windowID, _ := cmd.Flags().GetUint32("window-id")
-		if extend {
-			logging.Debug().Bool("extend", extend).Msg("cross-monitor window move enabled")
+		return runWindowMove(gridWindow.MoveWindowOpts{}, func(ctx context.Context, c *client.Client, snap *gridServer.Snapshot, cfg *gridConfig.Config, rs *gridState.RuntimeState) (*gridWindow.MoveResult, error) {
+			return gridWindow.PromoteWindow(ctx, c, snap, cfg, rs, windowID)
+		})
+	},
+}
+
+// windowDemoteCmd moves the focused window out of the main cell
+var windowDemoteCmd = &cobra.Command{
+	Use:   "demote",
+	Short: "Move window out of the main cell",
+	Long: `The inverse of 'window promote': swaps the focused window (or --window-id),
+which must be in the main cell, with the top window of the first other cell
+(top-left first).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		windowID, _ := cmd.Flags().GetUint32("window-id")
+		return runWindowMove(gridWindow.MoveWindowOpts{}, func(ctx context.Context, c *client.Client, snap *gridServer.Snapshot, cfg *gridConfig.Config, rs *gridState.RuntimeState) (*gridWindow.MoveResult, error) {
+			return gridWindow.DemoteWindow(ctx, c, snap, cfg, rs, windowID)
+		})
+	},
+}
+
+// windowUndoCmd reverts the latest window move between cells
+var windowUndoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last window move",
+	Long: `Moves the window of the latest recorded move (see 'window history') back to
+its old position in the cell it came from, bringing it back from another
+display's space if it moved there, and reapplies the layout. Moves of windows
+that have since closed are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWindowMove(gridWindow.MoveWindowOpts{}, gridWindow.UndoMove)
+	},
+}
+
+// windowHistoryCmd lists the recorded window moves of a space
+var windowHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded window moves",
+	Long: `Lists the window moves between cells recorded for a space (the current
+space unless --space is given), newest first. These are what 'window undo'
+reverts. --frames adds each window's frame before and after the move.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		spaceID, _ := cmd.Flags().GetString("space")
+		if spaceID == "" {
+			c := client.NewClient(socketPath, timeout)
+			defer c.Close()
+			snap, err := gridServer.Fetch(context.Background(), c)
+			if err != nil {
+				return fmt.Errorf("failed to fetch server state: %w", err)
+			}
+			spaceID = snap.SpaceID
+		}
+
+		var entries []gridState.MoveEntry
+		if space := runtimeState.GetSpaceReadOnly(spaceID); space != nil {
+			entries = space.MoveHistory
+		}
+		if jsonOutput {
+			if entries == nil {
+				entries = []gridState.MoveEntry{}
+			}
+			return printJSON(entries)
 		}
-		return moveWindowDirectionHelper(gridTypes.DirRight, wrap, extend, windowID)
+
+		frames, _ := cmd.Flags().GetBool("frames")
+		printMoveHistory(os.Stdout, spaceID, entries, frames)
+		return nil
+	},
+}
+
+// printMoveHistory prints a space's window moves for `window history`,
+// newest first
+func printMoveHistory(w io.Writer, spaceID string, entries []gridState.MoveEntry, frames bool) {
+	if len(entries) == 0 {
+		infoColor.Fprintf(w, "No window moves recorded for space %s\n", spaceID)
+		return
+	}
+	formatRect := func(r gridTypes.Rect) string {
+		return fmt.Sprintf("(%.0f, %.0f) %.0fx%.0f", r.X, r.Y, r.Width, r.Height)
+	}
+
+	keyColor.Fprintf(w, "Space %s:\n", spaceID)
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		target := entry.TargetCell
+		if entry.TargetSpace != "" {
+			target = fmt.Sprintf("space %s %s", entry.TargetSpace, entry.TargetCell)
+		}
+		fmt.Fprintf(w, "  %s  window %d: %s[%d] -> %s\n",
+			entry.Time.Format("15:04:05"), entry.WindowID, entry.SourceCell, entry.SourceIndex, target)
+		if frames {
+			fmt.Fprintf(w, "    before: %s\n", formatRect(entry.Before))
+			fmt.Fprintf(w, "    after:  %s\n", formatRect(entry.After))
+		}
+	}
+}
+
+// windowMoveCmd is the parent command for window move operations
+var windowMoveCmd = &cobra.Command{
+	Use:   "move",
+	Short: "Move window to adjacent cell",
+	Long:  `Commands for moving windows between cells in the layout grid.`,
+}
+
+// windowMoveLeftCmd moves window to the left cell
+var windowMoveLeftCmd = &cobra.Command{
+	Use:   "left",
+	Short: "Move window to left cell",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirLeft, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowMoveRightCmd moves window to the right cell
+var windowMoveRightCmd = &cobra.Command{
+	Use:   "right",
+	Short: "Move window to right cell",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirRight, moveWindowOptsFromFlags(cmd))
 	},
 }
 
@@ -1674,13 +3885,7 @@ var windowMoveUpCmd = &cobra.Command{
 	Short: "Move window to cell above",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
-		extend, _ := cmd.Flags().GetBool("extend")
-		windowID, _ := cmd.Flags().GetUint32("window-id")
-		if extend {
-			logging.Debug().Bool("extend", extend).Msg("cross-monitor window move enabled")
-		}
-		return moveWindowDirectionHelper(gridTypes.DirUp, wrap, extend, windowID)
+		return moveWindowDirectionHelper(gridTypes.DirUp, moveWindowOptsFromFlags(cmd))
 	},
 }
 
@@ -1690,13 +3895,204 @@ var windowMoveDownCmd = &cobra.Command{
 	Short: "Move window to cell below",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		wrap, _ := cmd.Flags().GetBool("wrap")
-		extend, _ := cmd.Flags().GetBool("extend")
-		windowID, _ := cmd.Flags().GetUint32("window-id")
-		if extend {
-			logging.Debug().Bool("extend", extend).Msg("cross-monitor window move enabled")
+		return moveWindowDirectionHelper(gridTypes.DirDown, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowMoveUpLeftCmd moves window to the cell diagonally up and to the left
+var windowMoveUpLeftCmd = &cobra.Command{
+	Use:   "up-left",
+	Short: "Move window to cell diagonally up and to the left",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirUpLeft, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowMoveUpRightCmd moves window to the cell diagonally up and to the right
+var windowMoveUpRightCmd = &cobra.Command{
+	Use:   "up-right",
+	Short: "Move window to cell diagonally up and to the right",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirUpRight, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowMoveDownLeftCmd moves window to the cell diagonally down and to the left
+var windowMoveDownLeftCmd = &cobra.Command{
+	Use:   "down-left",
+	Short: "Move window to cell diagonally down and to the left",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirDownLeft, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowMoveDownRightCmd moves window to the cell diagonally down and to the right
+var windowMoveDownRightCmd = &cobra.Command{
+	Use:   "down-right",
+	Short: "Move window to cell diagonally down and to the right",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveWindowDirectionHelper(gridTypes.DirDownRight, moveWindowOptsFromFlags(cmd))
+	},
+}
+
+// windowCenterCmd centers one window on its display
+var windowCenterCmd = &cobra.Command{
+	Use:   "center",
+	Short: "Center a window on its display",
+	Long: `Centers the focused window (or --window-id) in the visible frame of the display
+containing it, keeping its size. The window is shrunk only if it doesn't fit.
+--padding insets the visible frame on every side. Meant for floating windows:
+tiling state isn't changed, so a tiled window goes back to its cell on the next
+apply.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWindowOnDisplay(cmd, "Centered", gridLayout.CenterWindow)
+	},
+}
+
+// windowMaximizeCmd sizes one window to its display
+var windowMaximizeCmd = &cobra.Command{
+	Use:   "maximize",
+	Short: "Size a window to fill its display",
+	Long: `Sizes the focused window (or --window-id) to the visible frame of the display
+containing it (the screen minus the menu bar and Dock). --padding insets the
+frame on every side. Tiling state isn't changed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWindowOnDisplay(cmd, "Maximized", gridLayout.MaximizeWindow)
+	},
+}
+
+// runWindowOnDisplay runs window center or maximize with the command's
+// --window-id and --padding
+func runWindowOnDisplay(
+	cmd *cobra.Command,
+	verb string,
+	position func(context.Context, *client.Client, *gridServer.Snapshot, uint32, float64) (uint32, gridTypes.Rect, error),
+) error {
+	windowID, _ := cmd.Flags().GetUint32("window-id")
+	padding, _ := cmd.Flags().GetFloat64("padding")
+
+	c := client.NewClient(socketPath, timeout)
+	defer c.Close()
+
+	ctx := context.Background()
+
+	snap, err := gridServer.Fetch(ctx, c)
+	if err != nil {
+		return fmt.Errorf("failed to fetch server state: %w", err)
+	}
+
+	windowID, bounds, err := position(ctx, c, snap, windowID, padding)
+	if err != nil {
+		return fmt.Errorf("failed to position window: %w", err)
+	}
+
+	if jsonOutput {
+		return printJSON(map[string]interface{}{"windowId": windowID, "frame": bounds})
+	}
+	successColor.Printf("✓ %s window %d at (%.0f, %.0f) size %.0fx%.0f\n",
+		verb, windowID, bounds.X, bounds.Y, bounds.Width, bounds.Height)
+	return nil
+}
+
+// windowCenterFloatingCmd centers floating windows on their displays
+var windowCenterFloatingCmd = &cobra.Command{
+	Use:     "center-floating",
+	Aliases: []string{"center-all-floating"},
+	Short:   "Center floating windows on their displays",
+	Long: `Centers every floating window on the current space (dialogs, float app rules).
+Tiled windows are not touched. Use --cascade to offset each window so they don't fully overlap.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		displayIndex, _ := cmd.Flags().GetInt("display")
+		cascade, _ := cmd.Flags().GetFloat64("cascade")
+		if cascade < 0 {
+			return fmt.Errorf("cascade cannot be negative")
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		count, err := gridLayout.CenterFloating(ctx, c, snap, cfg, displayIndex, cascade)
+		if err != nil {
+			return fmt.Errorf("failed to center floating windows: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{"centered": count})
+		}
+
+		if count == 0 {
+			infoColor.Println("No floating windows to center")
+			return nil
+		}
+		successColor.Printf("✓ Centered %d floating window(s)\n", count)
+		return nil
+	},
+}
+
+// windowCascadeCmd arranges specific windows in an overlapping cascade
+var windowCascadeCmd = &cobra.Command{
+	Use:     "cascade <id> [id...]",
+	Aliases: []string{"arrange-cascade"},
+	Short:   "Arrange windows in a cascade",
+	Long: `Positions the listed windows in an overlapping cascade from the top-left corner
+of the display, each offset from the previous one and sized to a fraction of the display.
+Tiling state is not changed; a later layout apply retiles the windows.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		displayIndex, _ := cmd.Flags().GetInt("display")
+		offset, _ := cmd.Flags().GetFloat64("offset")
+		if offset < 0 {
+			return fmt.Errorf("offset cannot be negative")
+		}
+
+		windowIDs := make([]uint32, 0, len(args))
+		for _, arg := range args {
+			windowID, err := strconv.ParseUint(arg, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid window ID: %v", err)
+			}
+			windowIDs = append(windowIDs, uint32(windowID))
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
 		}
-		return moveWindowDirectionHelper(gridTypes.DirDown, wrap, extend, windowID)
+
+		if err := gridLayout.CascadeWindows(ctx, c, snap, windowIDs, displayIndex, offset); err != nil {
+			return fmt.Errorf("failed to cascade windows: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{"cascaded": windowIDs})
+		}
+
+		successColor.Printf("✓ Cascaded %d window(s)\n", len(windowIDs))
+		return nil
 	},
 }
 
@@ -1708,6 +4104,12 @@ var focusNextCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logging.Info().Str("cmd", "focus-next").Msg("starting")
 
+		orderFlag, _ := cmd.Flags().GetString("order")
+		order, ok := gridFocus.ParseCycleOrder(orderFlag)
+		if !ok {
+			return fmt.Errorf("invalid order: %s (use position or mru)", orderFlag)
+		}
+
 		runtimeState, err := gridState.LoadState()
 		if err != nil {
 			logging.Error().Str("cmd", "focus-next").Err(err).Msg("failed to load state")
@@ -1727,13 +4129,13 @@ var focusNextCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			logging.Error().Str("cmd", "focus-next").Err(err).Msg("failed to reconcile")
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
 		// 3. Cycle focus using local state
-		windowID, err := gridFocus.CycleFocus(ctx, c, runtimeState, snap.SpaceID, true)
+		windowID, err := gridFocus.CycleFocus(ctx, c, runtimeState, snap.SpaceID, true, order)
 		if err != nil {
 			logging.Error().Str("cmd", "focus-next").Err(err).Msg("failed to cycle")
 			return fmt.Errorf("failed to cycle focus: %w", err)
@@ -1750,6 +4152,47 @@ var focusNextCmd = &cobra.Command{
 	},
 }
 
+// focusBackCmd returns focus to the previously focused window
+var focusBackCmd = &cobra.Command{
+	Use:   "back",
+	Short: "Focus the previously focused window",
+	Long: `Returns focus to the window focused before the current one on this space.
+Repeating it walks further back through the focus history (up to 16 entries),
+skipping windows that have closed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Pop focus history
+		windowID, err := gridFocus.FocusBack(ctx, c, runtimeState, snap.SpaceID)
+		if err != nil {
+			return fmt.Errorf("failed to focus back: %w", err)
+		}
+
+		successColor.Printf("✓ Focused window: %d\n", windowID)
+		return nil
+	},
+}
+
 // focusPrevCmd cycles focus to previous window in cell
 var focusPrevCmd = &cobra.Command{
 	Use:   "prev",
@@ -1758,6 +4201,12 @@ var focusPrevCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logging.Info().Str("cmd", "focus-prev").Msg("starting")
 
+		orderFlag, _ := cmd.Flags().GetString("order")
+		order, ok := gridFocus.ParseCycleOrder(orderFlag)
+		if !ok {
+			return fmt.Errorf("invalid order: %s (use position or mru)", orderFlag)
+		}
+
 		runtimeState, err := gridState.LoadState()
 		if err != nil {
 			logging.Error().Str("cmd", "focus-prev").Err(err).Msg("failed to load state")
@@ -1777,13 +4226,13 @@ var focusPrevCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			logging.Error().Str("cmd", "focus-prev").Err(err).Msg("failed to reconcile")
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
 		// 3. Cycle focus using local state
-		windowID, err := gridFocus.CycleFocus(ctx, c, runtimeState, snap.SpaceID, false)
+		windowID, err := gridFocus.CycleFocus(ctx, c, runtimeState, snap.SpaceID, false, order)
 		if err != nil {
 			logging.Error().Str("cmd", "focus-prev").Err(err).Msg("failed to cycle")
 			return fmt.Errorf("failed to cycle focus: %w", err)
@@ -1808,6 +4257,413 @@ var focusCellCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cellID := args[0]
 
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Focus the cell
+		windowID, err := gridFocus.FocusCell(ctx, c, runtimeState, snap.SpaceID, cellID)
+		if err != nil {
+			return fmt.Errorf("failed to focus cell: %w", err)
+		}
+		if cfg.Settings.MouseFollowsFocus {
+			gridFocus.WarpMouseToWindow(ctx, c, snap, windowID)
+		}
+
+		successColor.Printf("✓ Focused cell %s (window: %d)\n", cellID, windowID)
+		return nil
+	},
+}
+
+// focusMasterCmd focuses the space's master window
+var focusMasterCmd = &cobra.Command{
+	Use:   "master",
+	Short: "Focus the master window of the current space",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server (promotes a new master if it closed)
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Focus the master
+		windowID, err := gridFocus.FocusMaster(ctx, c, snap, cfg, runtimeState)
+		if err != nil {
+			return fmt.Errorf("failed to focus master: %w", err)
+		}
+
+		successColor.Printf("✓ Focused master window: %d\n", windowID)
+		return nil
+	},
+}
+
+// focusUrgentCmd focuses the window that most recently requested attention
+var focusUrgentCmd = &cobra.Command{
+	Use:   "urgent",
+	Short: "Focus the window that most recently requested attention",
+	Long: `Focuses the most recently urgent window on any space, switching to its
+space first if needed (requires MSS). Exits successfully when no window is
+requesting attention.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Focus the urgent window
+		urgent, err := gridFocus.FocusUrgent(ctx, c, snap, runtimeState)
+		if err != nil {
+			return fmt.Errorf("failed to focus urgent window: %w", err)
+		}
+		if urgent == nil {
+			infoColor.Println("No window is requesting attention")
+			return nil
+		}
+
+		successColor.Printf("✓ Focused urgent window: %d (%s)\n", urgent.ID, urgent.AppName)
+		if urgent.SpaceID != snap.SpaceID {
+			infoColor.Printf("  Switched to space %s\n", urgent.SpaceID)
+		}
+		return nil
+	},
+}
+
+// focusSetMasterCmd designates the master window of the current space
+var focusSetMasterCmd = &cobra.Command{
+	Use:   "set-master",
+	Short: "Make the focused (or given) window the master of the current space",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Record the master
+		spaceState := runtimeState.GetSpaceReadOnly(snap.SpaceID)
+		if spaceState == nil {
+			return fmt.Errorf("no layout applied to space %s", snap.SpaceID)
+		}
+
+		windowID, _ := cmd.Flags().GetUint32("window-id")
+		if windowID == 0 {
+			windowID = spaceState.GetFocusedWindow()
+			if windowID == 0 {
+				return fmt.Errorf("no focused window")
+			}
+		}
+
+		if !runtimeState.GetSpace(snap.SpaceID).SetMaster(windowID) {
+			return fmt.Errorf("window %d is not assigned to any cell", windowID)
+		}
+		runtimeState.MarkUpdated()
+		if err := runtimeState.Save(); err != nil {
+			return fmt.Errorf("failed to save state: %w", err)
+		}
+
+		successColor.Printf("✓ Master window set: %d\n", windowID)
+		return nil
+	},
+}
+
+// MARK: - the-grid Resize Commands
+
+// resizeCmd is the parent command for resize subcommands
+var gridResizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Resize windows in layout",
+	Long:  `Commands for growing, shrinking, or resetting window splits.`,
+}
+
+// resizeAdjustCmd grows or shrinks focused window
+var resizeAdjustCmd = &cobra.Command{
+	Use:       "grow|shrink [amount]",
+	Short:     "Grow or shrink focused window",
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"grow", "shrink"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := args[0]
+		if action != "grow" && action != "shrink" {
+			return fmt.Errorf("invalid action: %s (use 'grow' or 'shrink')", action)
+		}
+
+		delta := gridLayout.DefaultResizeAmount
+		if len(args) > 1 {
+			parsed, err := strconv.ParseFloat(args[1], 64)
+			if err != nil {
+				return fmt.Errorf("invalid amount: %w", err)
+			}
+			delta = parsed
+		}
+		if action == "shrink" {
+			delta = -delta
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Tabbed cells have nothing to resize: optionally switch tabs instead
+		if gridLayout.ResizeCyclesTabs(cfg, runtimeState.GetSpaceReadOnly(snap.SpaceID)) {
+			windowID, err := gridFocus.CycleFocus(ctx, c, runtimeState, snap.SpaceID, delta > 0, gridFocus.CycleByPosition)
+			if err != nil {
+				return fmt.Errorf("failed to switch tab: %w", err)
+			}
+
+			if jsonOutput {
+				return printJSON(map[string]interface{}{
+					"action":   action,
+					"changed":  true,
+					"tab":      true,
+					"windowId": windowID,
+				})
+			}
+
+			successColor.Printf("✓ Switched tab to window %d (%s)\n", windowID, action)
+			return nil
+		}
+
+		// 4. Adjust split, optionally snapping to common ratios
+		var snapPoints []float64
+		if snapEnabled, _ := cmd.Flags().GetBool("snap"); snapEnabled {
+			snapPoints = cfg.Settings.ResizeSnapPoints
+			if len(snapPoints) == 0 {
+				snapPoints = gridLayout.DefaultSnapPoints
+			}
+		}
+		changed, err := gridLayout.AdjustFocusedSplit(ctx, c, snap, cfg, runtimeState, delta, snapPoints)
+		if err != nil {
+			return fmt.Errorf("failed to resize: %w", err)
+		}
+
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		if jsonOutput {
+			if err := printJSON(map[string]interface{}{
+				"action":  action,
+				"changed": changed,
+			}); err != nil {
+				return err
+			}
+			if !changed && strict {
+				return fmt.Errorf("at minimum, no change")
+			}
+			return nil
+		}
+
+		if !changed {
+			if strict {
+				return fmt.Errorf("at minimum, no change")
+			}
+			infoColor.Printf("Window already at minimum split, no change (%s)\n", action)
+			return nil
+		}
+
+		successColor.Printf("✓ Resized window (%s)\n", action)
+		return nil
+	},
+}
+
+// resizeCellCmd grows or shrinks the focused cell's grid tracks
+var resizeCellCmd = &cobra.Command{
+	Use:   "cell <grow|shrink> <direction> [amount]",
+	Short: "Grow or shrink the focused cell's column or row",
+	Long: `Move the focused cell's edge on the given side by amount fr (default 0.1).
+The whole column or row resizes, so every cell sharing it stays aligned.
+The new track sizes are kept for the space until the layout changes or
+'grid resize reset --all'.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := args[0]
+		if action != "grow" && action != "shrink" {
+			return fmt.Errorf("invalid action: %s (use 'grow' or 'shrink')", action)
+		}
+
+		direction, ok := gridTypes.ParseDirection(args[1])
+		if !ok || direction.IsDiagonal() {
+			return fmt.Errorf("invalid direction: %s (use left, right, up, down)", args[1])
+		}
+
+		delta := gridLayout.DefaultTrackStep
+		if len(args) > 2 {
+			parsed, err := strconv.ParseFloat(args[2], 64)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid amount: %s", args[2])
+			}
+			delta = parsed
+		}
+		if action == "shrink" {
+			delta = -delta
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Adjust tracks and reapply
+		changed, err := gridLayout.ResizeFocusedCell(ctx, c, snap, cfg, runtimeState, direction, delta)
+		if err != nil {
+			return fmt.Errorf("failed to resize cell: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"action":    action,
+				"direction": direction.String(),
+				"changed":   changed,
+			})
+		}
+
+		if !changed {
+			infoColor.Printf("Track already at minimum size, no change (%s %s)\n", action, direction)
+			return nil
+		}
+
+		successColor.Printf("✓ Resized cell (%s %s)\n", action, direction)
+		return nil
+	},
+}
+
+// resizeAbsorbCmd grows the focused cell over an empty neighbour
+var resizeAbsorbCmd = &cobra.Command{
+	Use:   "absorb <direction>",
+	Short: "Grow the focused cell over the empty cell next to it",
+	Long: `Extend the focused cell's windows over the adjacent cell in the given
+direction (left, right, up, down), which must be empty and line up with the
+focused cell's edge. The span is kept for the space until a window is placed
+in the absorbed cell, the layout changes or 'grid resize reset --all'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		direction, ok := gridTypes.ParseDirection(args[0])
+		if !ok || direction.IsDiagonal() {
+			return fmt.Errorf("invalid direction: %s (use left, right, up, down)", args[0])
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
 		runtimeState, err := gridState.LoadState()
 		if err != nil {
 			return fmt.Errorf("failed to load state: %w", err)
@@ -1825,47 +4681,48 @@ var focusCellCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
-		// 3. Focus the cell
-		windowID, err := gridFocus.FocusCell(ctx, c, runtimeState, snap.SpaceID, cellID)
+		// 3. Record the span and reapply
+		absorbed, err := gridLayout.AbsorbAdjacentCell(ctx, c, snap, cfg, runtimeState, direction)
 		if err != nil {
-			return fmt.Errorf("failed to focus cell: %w", err)
+			return fmt.Errorf("failed to absorb cell: %w", err)
 		}
 
-		successColor.Printf("✓ Focused cell %s (window: %d)\n", cellID, windowID)
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"direction": direction.String(),
+				"absorbed":  absorbed,
+			})
+		}
+
+		successColor.Printf("✓ Focused cell now spans cell %s (%s)\n", absorbed, direction)
 		return nil
 	},
 }
 
-// MARK: - the-grid Resize Commands
-
-// resizeCmd is the parent command for resize subcommands
-var gridResizeCmd = &cobra.Command{
-	Use:   "resize",
-	Short: "Resize windows in layout",
-	Long:  `Commands for growing, shrinking, or resetting window splits.`,
-}
-
-// resizeAdjustCmd grows or shrinks focused window
-var resizeAdjustCmd = &cobra.Command{
-	Use:       "grow|shrink [amount]",
-	Short:     "Grow or shrink focused window",
-	Args:      cobra.RangeArgs(1, 2),
-	ValidArgs: []string{"grow", "shrink"},
+// resizeMasterCmd grows or shrinks the master cell against the stack area
+var resizeMasterCmd = &cobra.Command{
+	Use:   "master <grow|shrink> [amount]",
+	Short: "Grow or shrink the master area",
+	Long: `Resize the cell holding the space's master window (see 'grid focus set-master')
+by amount fr (default 0.1), on its side facing the rest of the grid. The
+tracks beyond it, the stack area, give up or take the same space in
+proportion to their sizes. Sizes are kept for the space like 'resize cell'.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		action := args[0]
 		if action != "grow" && action != "shrink" {
 			return fmt.Errorf("invalid action: %s (use 'grow' or 'shrink')", action)
 		}
 
-		delta := gridLayout.DefaultResizeAmount
+		delta := gridLayout.DefaultTrackStep
 		if len(args) > 1 {
 			parsed, err := strconv.ParseFloat(args[1], 64)
-			if err != nil {
-				return fmt.Errorf("invalid amount: %w", err)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid amount: %s", args[1])
 			}
 			delta = parsed
 		}
@@ -1873,7 +4730,84 @@ var resizeAdjustCmd = &cobra.Command{
 			delta = -delta
 		}
 
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Adjust the master and stack tracks and reapply
+		changed, err := gridLayout.ResizeMaster(ctx, c, snap, cfg, runtimeState, delta)
+		if err != nil {
+			return fmt.Errorf("failed to resize master: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"action":  action,
+				"changed": changed,
+			})
+		}
+
+		if !changed {
+			infoColor.Printf("Master area already at its limit, no change (%s)\n", action)
+			return nil
+		}
+
+		successColor.Printf("✓ Resized master area (%s)\n", action)
+		return nil
+	},
+}
+
+// resizeDragCmd turns a pointer drag into a split adjustment
+var resizeDragCmd = &cobra.Command{
+	Use:   "drag --boundary <cellID:index> --from <x,y> --to <x,y>",
+	Short: "Move a split boundary by a pointer drag",
+	Long: `Move the boundary after window <index> in a cell by the distance between
+two screen points, so external tools can map mouse drags to resizes. Only
+the drag along the cell's stack axis counts (y for vertical stacks, x for
+horizontal ones), converted to a ratio using the cell's size. Windows stop
+at the minimum split.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		boundaryFlag, _ := cmd.Flags().GetString("boundary")
+		fromFlag, _ := cmd.Flags().GetString("from")
+		toFlag, _ := cmd.Flags().GetString("to")
+
+		cellID, boundary, err := parseBoundary(boundaryFlag)
+		if err != nil {
+			return err
+		}
+		from, err := parsePoint(fromFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+		to, err := parsePoint(toFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1895,16 +4829,30 @@ var resizeAdjustCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
-		// 3. Adjust split
-		if err := gridLayout.AdjustFocusedSplit(ctx, c, snap, cfg, runtimeState, delta); err != nil {
+		// 3. Move the boundary and reapply
+		changed, err := gridLayout.DragSplitBoundary(ctx, c, snap, cfg, runtimeState, cellID, boundary, from, to)
+		if err != nil {
 			return fmt.Errorf("failed to resize: %w", err)
 		}
 
-		successColor.Printf("✓ Resized window (%s)\n", action)
+		if jsonOutput {
+			return printJSON(map[string]interface{}{
+				"cellId":   cellID,
+				"boundary": boundary,
+				"changed":  changed,
+			})
+		}
+
+		if !changed {
+			infoColor.Printf("Split %s:%d already at its limit, no change\n", cellID, boundary)
+			return nil
+		}
+
+		successColor.Printf("✓ Moved split %s:%d\n", cellID, boundary)
 		return nil
 	},
 }
@@ -1914,7 +4862,7 @@ var resizeResetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset splits to equal",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -1936,7 +4884,7 @@ var resizeResetCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
@@ -1971,7 +4919,7 @@ var cellCmd = &cobra.Command{
 var cellSendCmd = &cobra.Command{
 	Use:   "send <direction>",
 	Short: "Send focused window to adjacent cell",
-	Long:  `Move the focused window to an adjacent cell in the specified direction (left, right, up, down).`,
+	Long:  `Move the focused window (or --window-id) to an adjacent cell in the specified direction (left, right, up, down).`,
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		direction, ok := gridTypes.ParseDirection(args[0])
@@ -1979,7 +4927,7 @@ var cellSendCmd = &cobra.Command{
 			return fmt.Errorf("invalid direction: %s (use left, right, up, or down)", args[0])
 		}
 
-		cfg, err := gridConfig.LoadConfig("")
+		cfg, err := loadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
@@ -2001,12 +4949,13 @@ var cellSendCmd = &cobra.Command{
 		}
 
 		// 2. Reconcile local state with server
-		if err := gridReconcile.Sync(snap, runtimeState); err != nil {
+		if err := syncState(snap, runtimeState); err != nil {
 			return fmt.Errorf("failed to reconcile state: %w", err)
 		}
 
 		// 3. Send window
-		if err := gridCell.SendWindow(ctx, c, snap, cfg, runtimeState, direction); err != nil {
+		windowID, _ := cmd.Flags().GetUint32("window-id")
+		if err := gridCell.SendWindow(ctx, c, snap, cfg, runtimeState, direction, windowID); err != nil {
 			return fmt.Errorf("failed to send window: %w", err)
 		}
 
@@ -2015,6 +4964,120 @@ var cellSendCmd = &cobra.Command{
 	},
 }
 
+// cellPullCmd pulls a window from an adjacent cell into the focused cell
+var cellPullCmd = &cobra.Command{
+	Use:   "pull <direction>",
+	Short: "Pull window from adjacent cell into focused cell",
+	Long:  `Move the focused window of the adjacent cell in the specified direction (left, right, up, down) into the focused cell, and focus it.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		direction, ok := gridTypes.ParseDirection(args[0])
+		if !ok {
+			return fmt.Errorf("invalid direction: %s (use left, right, up, or down)", args[0])
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Pull window
+		windowID, sourceCell, err := gridCell.PullWindow(ctx, c, snap, cfg, runtimeState, direction)
+		if err != nil {
+			return fmt.Errorf("failed to pull window: %w", err)
+		}
+
+		successColor.Printf("✓ Pulled window %d from %s\n", windowID, sourceCell)
+		return nil
+	},
+}
+
+// cellStackCmd sets or cycles the focused cell's stack mode
+var cellStackCmd = &cobra.Command{
+	Use:   "stack <vertical|horizontal|tabs|grid|cycle>",
+	Short: "Set the focused cell's stack mode",
+	Long: `Set how the focused cell stacks its windows and re-place them right away.
+Other cells are left alone and the focused window keeps focus.
+'cycle' switches to the next mode: vertical, horizontal, tabs, grid.`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"vertical", "horizontal", "tabs", "grid", "cycle"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var mode gridTypes.StackMode
+		if args[0] != "cycle" {
+			var ok bool
+			mode, ok = gridTypes.ParseStackMode(args[0])
+			if !ok {
+				return fmt.Errorf("invalid stack mode: %s (use vertical, horizontal, tabs, grid, or cycle)", args[0])
+			}
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		runtimeState, err := gridState.LoadState()
+		if err != nil {
+			return fmt.Errorf("failed to load state: %w", err)
+		}
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		ctx := context.Background()
+
+		// 1. Fetch server state ONCE
+		snap, err := gridServer.Fetch(ctx, c)
+		if err != nil {
+			return fmt.Errorf("failed to fetch server state: %w", err)
+		}
+
+		// 2. Reconcile local state with server
+		if err := syncState(snap, runtimeState); err != nil {
+			return fmt.Errorf("failed to reconcile state: %w", err)
+		}
+
+		// 3. Switch the mode and re-place the cell
+		var result *gridWindow.StackModeResult
+		if mode == "" {
+			result, err = gridWindow.CycleCellStackMode(ctx, c, snap, cfg, runtimeState)
+		} else {
+			result, err = gridWindow.SetCellStackMode(ctx, c, snap, cfg, runtimeState, mode)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set stack mode: %w", err)
+		}
+
+		if jsonOutput {
+			return printJSON(result)
+		}
+
+		successColor.Printf("✓ Cell %s now stacks %s\n", result.Cell, result.Mode)
+		return nil
+	},
+}
+
 // Helper function for formatting track sizes
 func formatTrackSizes(tracks []gridTypes.TrackSize) string {
 	var parts []string
@@ -2024,6 +5087,111 @@ func formatTrackSizes(tracks []gridTypes.TrackSize) string {
 	return "[" + strings.Join(parts, ", ") + "]"
 }
 
+// parsePlacements parses repeated --place <windowID>=<cellID> values
+func parsePlacements(values []string) (map[uint32]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	placements := make(map[uint32]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid --place %q (expected <windowID>=<cellID>)", v)
+		}
+		windowID, err := strconv.ParseUint(parts[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window ID in --place %q: %v", v, err)
+		}
+		placements[uint32(windowID)] = parts[1]
+	}
+	return placements, nil
+}
+
+// parseBoundary parses a --boundary <cellID:index> value
+func parseBoundary(value string) (string, int, error) {
+	i := strings.LastIndex(value, ":")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid --boundary %q (expected <cellID:index>)", value)
+	}
+	index, err := strconv.Atoi(value[i+1:])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid index in --boundary %q", value)
+	}
+	return value[:i], index, nil
+}
+
+// parsePoint parses an <x,y> screen point
+func parsePoint(value string) (gridTypes.Point, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return gridTypes.Point{}, fmt.Errorf("expected <x,y>, got %q", value)
+	}
+	x, errX := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errX != nil || errY != nil {
+		return gridTypes.Point{}, fmt.Errorf("expected <x,y>, got %q", value)
+	}
+	return gridTypes.Point{X: x, Y: y}, nil
+}
+
+// MARK: - Diagnostics Command
+
+// diagnosticsCmd collects a bug report bundle
+var diagnosticsCmd = &cobra.Command{
+	Use:   "diagnostics",
+	Short: "Collect a diagnostics bundle for bug reports",
+	Long: `Collects server info, a server dump, the runtime state and the loaded config,
+along with the CLI version and socket path, into a single bundle.
+
+Writes JSON to stdout, or to --out (a .zip path writes one file per section).
+Use --redact to strip window titles and application paths from the dump.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outPath, _ := cmd.Flags().GetString("out")
+		redact, _ := cmd.Flags().GetBool("redact")
+
+		c := client.NewClient(socketPath, timeout)
+		defer c.Close()
+
+		bundle := gridDiagnostics.Collect(context.Background(), c, gridDiagnostics.Options{
+			Version:    rootCmd.Version,
+			SocketPath: socketPath,
+			Redact:     redact,
+		})
+
+		if outPath == "" {
+			return bundle.WriteJSON(os.Stdout)
+		}
+
+		f, err := os.Create(outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", outPath, err)
+		}
+		defer f.Close()
+
+		if strings.HasSuffix(strings.ToLower(outPath), ".zip") {
+			err = bundle.WriteZip(f)
+		} else {
+			err = bundle.WriteJSON(f)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write diagnostics: %w", err)
+		}
+
+		successColor.Printf("✓ Diagnostics written to %s\n", outPath)
+		sections := make([]string, 0, len(bundle.Errors))
+		for section := range bundle.Errors {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		for _, section := range sections {
+			infoColor.Printf("  %s not collected: %s\n", section, bundle.Errors[section])
+		}
+		return nil
+	},
+}
+
 // MARK: - Render Command
 
 // RenderWindow represents a window with normalized coordinates
@@ -2195,44 +5363,94 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&socketPath, "socket", client.DefaultSocketPath, "Unix socket path")
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", client.DefaultTimeout, "Request timeout")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", client.DefaultRetries, "Attempts for requests that hit a dropped or refused connection")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().BoolVar(&jsonEnvelopeOutput, "json-envelope", false, "Output JSON wrapped in {ok, command, data, error} (implies --json)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	rootCmd.PersistentFlags().BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().BoolVar(&useDaemon, "daemon", false, "Run through a running grid watch daemon if there is one")
+	rootCmd.PersistentFlags().BoolVar(&serverOnly, "server-only", false, "Never read or write state.json; derive cell assignments from window positions (also GRID_READ_ONLY=1)")
 
 	// Add top-level commands
 	rootCmd.AddCommand(pingCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().Bool("check", false, "Compare the CLI version with the server's")
 	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(helpAllCmd)
+	rootCmd.AddCommand(manCmd)
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().Bool("reapply", false, "Re-apply the active space's layout when windows open or close")
+	watchCmd.Flags().Duration("interval", gridWatch.DefaultInterval, "How often to poll the server with --reapply")
 	rootCmd.AddCommand(showCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(windowCmd)
 	rootCmd.AddCommand(spaceCmd)
 	rootCmd.AddCommand(renderCmd)
+	rootCmd.AddCommand(diagnosticsCmd)
+	diagnosticsCmd.Flags().String("out", "", "Write the bundle to a file (.zip for an archive, otherwise JSON)")
+	diagnosticsCmd.Flags().Bool("redact", false, "Strip window titles and application paths")
 
 	// Add the-grid layout commands
 	rootCmd.AddCommand(gridLayoutCmd)
 	gridLayoutCmd.AddCommand(layoutListCmd)
+	layoutListCmd.Flags().Bool("all", false, "Include disabled layouts (marked as such)")
 	gridLayoutCmd.AddCommand(layoutShowCmd)
 	gridLayoutCmd.AddCommand(layoutApplyCmd)
 	gridLayoutCmd.AddCommand(layoutCycleCmd)
 	gridLayoutCmd.AddCommand(layoutCurrentCmd)
 	gridLayoutCmd.AddCommand(layoutReapplyCmd)
+	gridLayoutCmd.AddCommand(layoutSaveCmd)
 
 	// Add layout command flags
 	layoutApplyCmd.Flags().String("space", "", "Space ID to apply layout to")
+	layoutApplyCmd.Flags().StringArray("place", nil, "Pin a window to a cell for this apply (<windowID>=<cellID>, repeatable)")
+	layoutApplyCmd.Flags().Bool("orientation-aware-stack", false, "Swap vertical/horizontal default stacking on portrait displays")
+	layoutApplyCmd.Flags().Bool("place-new-at-focus", false, "Keep windows in their cells and put new windows in the focused cell")
+	layoutApplyCmd.Flags().String("assignment", "", "Window assignment strategy: position (default), preserve, autoflow, pinned, or bsp")
+	layoutApplyCmd.Flags().String("display", "", "Apply to the current space of this display (index or UUID)")
+	layoutApplyCmd.Flags().Bool("launch-empty", false, "Launch each empty cell's configured app (cells[].launch) and tile its window there")
+	layoutApplyCmd.Flags().Bool("all-spaces", false, "Apply each display's current space its default layout")
+	layoutSaveCmd.Flags().Bool("force", false, "Replace an existing layout with the same ID")
+	layoutSaveCmd.Flags().String("name", "", "Human-readable layout name (default: the layout ID)")
 	layoutCycleCmd.Flags().String("space", "", "Space ID to cycle layout for")
 	layoutCurrentCmd.Flags().String("space", "", "Space ID to check")
 
+	// Add display commands
+	rootCmd.AddCommand(displayCmd)
+	displayCmd.AddCommand(displayLayoutCmd)
+	displayLayoutCmd.Flags().Bool("apply", false, "Arrange the display's windows now instead of only recording the layout")
+
 	// Add the-grid config commands
 	rootCmd.AddCommand(gridConfigCmd)
 	gridConfigCmd.AddCommand(configShowCmd)
+	gridConfigCmd.AddCommand(configSchemaCmd)
 	gridConfigCmd.AddCommand(configValidateCmd)
+	gridConfigCmd.AddCommand(configWatchValidateCmd)
+	configWatchValidateCmd.Flags().String("format", "text", "Output format: text or json")
 	gridConfigCmd.AddCommand(configInitCmd)
+	gridConfigCmd.AddCommand(configGenerateCmd)
+	configGenerateCmd.Flags().Int("columns", 2, "Columns in the generated grid layout")
+	configGenerateCmd.Flags().Int("rows", 1, "Rows in the generated grid layout")
+	configGenerateCmd.Flags().StringSlice("spaces", []string{"1"}, "Space IDs to configure (comma-separated)")
+	configGenerateCmd.Flags().Bool("force", false, "Overwrite an existing config file")
+	configGenerateCmd.Flags().Bool("yes", false, "Take the default values instead of asking")
+	configGenerateCmd.Flags().Bool("stdout", false, "Print the config instead of writing it")
 
 	// Add the-grid state commands
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.AddCommand(reconcileStatusCmd)
+	reconcileStatusCmd.Flags().String("display", "", "Check the current space of this display (index or UUID)")
 	rootCmd.AddCommand(gridStateCmd)
 	gridStateCmd.AddCommand(stateShowCmd)
 	gridStateCmd.AddCommand(stateResetCmd)
+	gridStateCmd.AddCommand(stateSetStackModeCmd)
+	stateSetStackModeCmd.Flags().Bool("all-cells", false, "Also switch every existing cell of the space to the mode")
+	gridStateCmd.AddCommand(stateBackupCmd)
+	gridStateCmd.AddCommand(stateListBackupsCmd)
+	gridStateCmd.AddCommand(stateRestoreCmd)
+	stateRestoreCmd.Flags().String("backup", "", "Backup name to restore (see 'grid state list-backups')")
+	stateShowCmd.Flags().String("space", "", "Show one space's state in detail")
 
 	// Add the-grid focus commands
 	rootCmd.AddCommand(focusCmd)
@@ -2242,13 +5460,20 @@ func init() {
 	focusCmd.AddCommand(focusDownCmd)
 	focusCmd.AddCommand(focusNextCmd)
 	focusCmd.AddCommand(focusPrevCmd)
+	focusCmd.AddCommand(focusBackCmd)
 	focusCmd.AddCommand(focusCellCmd)
+	focusCmd.AddCommand(focusMasterCmd)
+	focusCmd.AddCommand(focusSetMasterCmd)
+	focusCmd.AddCommand(focusUrgentCmd)
+	focusSetMasterCmd.Flags().Uint32("window-id", 0, "Window ID to make master (default: focused window)")
 
 	// Add focus command flags
-	focusLeftCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
-	focusRightCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
-	focusUpCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
-	focusDownCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
+	focusLeftCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge (default: settings.focus.wrap)")
+	focusRightCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge (default: settings.focus.wrap)")
+	focusUpCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge (default: settings.focus.wrap)")
+	focusDownCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge (default: settings.focus.wrap)")
+	focusNextCmd.Flags().String("order", string(gridFocus.CycleByPosition), "Cycle order: position (stack order) or mru (most recently focused first)")
+	focusPrevCmd.Flags().String("order", string(gridFocus.CycleByPosition), "Cycle order: position (stack order) or mru (most recently focused first)")
 
 	focusLeftCmd.Flags().Bool("extend", false, "Extend focus to adjacent monitors when no cell exists in direction")
 	focusRightCmd.Flags().Bool("extend", false, "Extend focus to adjacent monitors when no cell exists in direction")
@@ -2259,13 +5484,28 @@ func init() {
 	rootCmd.AddCommand(gridResizeCmd)
 	gridResizeCmd.AddCommand(resizeAdjustCmd)
 	gridResizeCmd.AddCommand(resizeResetCmd)
+	gridResizeCmd.AddCommand(resizeCellCmd)
+	gridResizeCmd.AddCommand(resizeAbsorbCmd)
+	gridResizeCmd.AddCommand(resizeMasterCmd)
+	gridResizeCmd.AddCommand(resizeDragCmd)
 
 	// Add resize command flags
-	resizeResetCmd.Flags().Bool("all", false, "Reset all cells, not just focused cell")
+	resizeAdjustCmd.Flags().Bool("strict", false, "Exit non-zero when the split is already at its minimum")
+	resizeAdjustCmd.Flags().Bool("snap", false, "Snap to settings.resizeSnapPoints (default 1/3, 1/2, 2/3) when close")
+	resizeResetCmd.Flags().Bool("all", false, "Reset all cells and track sizes, not just focused cell")
+	resizeDragCmd.Flags().String("boundary", "", "Split to move, as <cellID:index> (boundary after window index)")
+	resizeDragCmd.Flags().String("from", "", "Drag start point <x,y>")
+	resizeDragCmd.Flags().String("to", "", "Drag end point <x,y>")
+	resizeDragCmd.MarkFlagRequired("boundary")
+	resizeDragCmd.MarkFlagRequired("from")
+	resizeDragCmd.MarkFlagRequired("to")
 
 	// Add the-grid cell commands
 	rootCmd.AddCommand(cellCmd)
 	cellCmd.AddCommand(cellSendCmd)
+	cellSendCmd.Flags().Uint32("window-id", 0, "Window ID to send (default: focused window)")
+	cellCmd.AddCommand(cellPullCmd)
+	cellCmd.AddCommand(cellStackCmd)
 
 	// Add show subcommands
 	showCmd.AddCommand(showLayoutCmd)
@@ -2277,21 +5517,47 @@ func init() {
 	showCmd.PersistentFlags().BoolVar(&showNoIDs, "no-ids", false, "Hide window IDs")
 	showCmd.PersistentFlags().IntVar(&showWidth, "width", 0, "Override terminal width")
 	showCmd.PersistentFlags().IntVar(&showHeight, "height", 0, "Override terminal height")
+	showCmd.PersistentFlags().StringVar(&showFormat, "format", "ascii", "Output format: ascii, svg, png")
+	showCmd.PersistentFlags().StringVarP(&showOut, "out", "o", "", "Write the image to a file (required for png)")
 
 	// Add list subcommands
 	listCmd.AddCommand(listWindowsCmd)
 	listCmd.AddCommand(listSpacesCmd)
 	listCmd.AddCommand(listDisplaysCmd)
 	listCmd.AddCommand(listAppsCmd)
+	listCmd.PersistentFlags().Bool("wide", false, "Don't truncate columns to fit the terminal")
 
 	// Add list windows flags
 	listWindowsCmd.Flags().Bool("all", false, "Show all windows including system UI and utility windows")
+	listWindowsCmd.Flags().Bool("stale", false, "Show windows tracked in state but gone from the server")
+	listWindowsCmd.Flags().Bool("tree", false, "Group windows by display, space and cell")
+	listWindowsCmd.Flags().String("app", "", "Only windows whose app name contains this (case-insensitive)")
+	listWindowsCmd.Flags().String("title", "", "Only windows whose title contains this (case-insensitive)")
+	listWindowsCmd.Flags().String("space", "", "Only windows on this space ID")
 
 	// Add window subcommands
 	windowCmd.AddCommand(windowGetCmd)
+	windowGetCmd.Flags().Bool("grid", false, "Also show the window's cell, split ratio and target frame drift")
+	windowCmd.AddCommand(windowQueryCmd)
+	windowQueryCmd.Flags().String("default", "", "Value to print when the field is missing")
 	windowCmd.AddCommand(windowFindCmd)
+	windowFindCmd.Flags().Bool("wide", false, "Don't truncate columns to fit the terminal")
+	windowFindCmd.Flags().String("on-display", "", "Only windows on this display's spaces (index or UUID)")
+	windowFindCmd.Flags().String("on-space", "", "Only windows on this space")
 	windowCmd.AddCommand(windowUpdateCmd)
 	windowCmd.AddCommand(windowToSpaceCmd)
+	windowToSpaceCmd.Flags().Bool("tile", false, "Tile the window into the target space's layout")
+	windowToSpaceCmd.Flags().String("cell", "", "Cell to tile into (implies --tile; default: least-populated cell)")
+	windowToSpaceCmd.Flags().Bool("reflow-source", true, "Re-place the windows left in the source cell to fill the gap")
+	windowCmd.AddCommand(windowAssignCmd)
+	windowAssignCmd.Flags().Bool("apply", false, "Also re-place the cell's windows")
+	windowCmd.AddCommand(windowPinCmd)
+	windowPinCmd.Flags().Bool("apply", false, "Reapply the layout right away")
+	windowCmd.AddCommand(windowUnpinCmd)
+	windowCmd.AddCommand(windowFloatCmd)
+	windowFloatCmd.Flags().Bool("apply", false, "Reapply the layout right away")
+	windowCmd.AddCommand(windowUnfloatCmd)
+	windowUnfloatCmd.Flags().Bool("apply", false, "Reapply the layout right away")
 	windowCmd.AddCommand(windowToDisplayCmd)
 	windowCmd.AddCommand(windowSetOpacityCmd)
 	windowCmd.AddCommand(windowFadeOpacityCmd)
@@ -2304,24 +5570,78 @@ func init() {
 	windowCmd.AddCommand(windowUnminimizeCmd)
 	windowCmd.AddCommand(windowIsMinimizedCmd)
 	windowCmd.AddCommand(windowMoveCmd)
+	windowCmd.AddCommand(windowThrowCmd)
+	windowCmd.AddCommand(windowSwapCmd)
+	windowCmd.AddCommand(windowUndoCmd)
+	windowCmd.AddCommand(windowHistoryCmd)
+	windowHistoryCmd.Flags().Bool("frames", false, "Show each window's frame before and after the move")
+	windowHistoryCmd.Flags().String("space", "", "Space ID (default: current space)")
+	windowCmd.AddCommand(windowPromoteCmd)
+	windowPromoteCmd.Flags().Uint32("window-id", 0, "Window ID to promote (default: focused window)")
+	windowCmd.AddCommand(windowDemoteCmd)
+	windowDemoteCmd.Flags().Uint32("window-id", 0, "Window ID to demote (default: focused window)")
+	windowSwapCmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
+	windowSwapCmd.Flags().Uint32("window-id", 0, "Window ID to swap (default: focused window)")
+	windowThrowCmd.Flags().Uint32("window-id", 0, "Window ID to move (default: focused window)")
+	windowThrowCmd.Flags().Bool("with-app-siblings", false, "Also move the window's same-app siblings")
+	windowThrowCmd.Flags().Bool("keep-relative", false, "Keep the window's share of its cell in the target cell")
+	windowThrowCmd.Flags().Bool("reflow-source", true, "Re-place the windows left in the source cell")
+	windowThrowCmd.Flags().Bool("balance-target", true, "Equalize the target cell's windows after the move")
+	windowThrowCmd.Flags().Bool("no-balance-target", false, "Keep the target cell's ratios, giving the window a proportional share")
+	windowThrowCmd.Flags().String("split", "", "Set the target cell's stack mode as part of the move: vertical or horizontal")
+	windowCmd.AddCommand(windowCenterFloatingCmd)
+	windowCenterFloatingCmd.Flags().Int("display", -1, "Display index to center on (default: each window's own display)")
+	windowCenterFloatingCmd.Flags().Float64("cascade", 0, "Offset in pixels between successive windows")
+	windowCmd.AddCommand(windowCenterCmd)
+	windowCenterCmd.Flags().Uint32("window-id", 0, "Window ID to center (default: focused window)")
+	windowCenterCmd.Flags().Float64("padding", 0, "Inset from the display's visible frame in pixels")
+	windowCmd.AddCommand(windowMaximizeCmd)
+	windowMaximizeCmd.Flags().Uint32("window-id", 0, "Window ID to maximize (default: focused window)")
+	windowMaximizeCmd.Flags().Float64("padding", 0, "Inset from the display's visible frame in pixels")
+	windowCmd.AddCommand(windowCascadeCmd)
+	windowCascadeCmd.Flags().Int("display", -1, "Display index to cascade on (default: the first window's display)")
+	windowCascadeCmd.Flags().Float64("offset", 30, "Offset in pixels between successive windows")
 
 	// Add window move subcommands
 	windowMoveCmd.AddCommand(windowMoveLeftCmd)
 	windowMoveCmd.AddCommand(windowMoveRightCmd)
 	windowMoveCmd.AddCommand(windowMoveUpCmd)
 	windowMoveCmd.AddCommand(windowMoveDownCmd)
+	windowMoveCmd.AddCommand(windowMoveUpLeftCmd)
+	windowMoveCmd.AddCommand(windowMoveUpRightCmd)
+	windowMoveCmd.AddCommand(windowMoveDownLeftCmd)
+	windowMoveCmd.AddCommand(windowMoveDownRightCmd)
 
 	// Add flags for window move commands
-	for _, cmd := range []*cobra.Command{windowMoveLeftCmd, windowMoveRightCmd, windowMoveUpCmd, windowMoveDownCmd} {
+	for _, cmd := range []*cobra.Command{
+		windowMoveLeftCmd, windowMoveRightCmd, windowMoveUpCmd, windowMoveDownCmd,
+		windowMoveUpLeftCmd, windowMoveUpRightCmd, windowMoveDownLeftCmd, windowMoveDownRightCmd,
+	} {
 		cmd.Flags().Bool("wrap", true, "Wrap around to opposite edge")
 		cmd.Flags().Bool("extend", false, "Extend to adjacent monitors")
 		cmd.Flags().Uint32("window-id", 0, "Window ID to move (default: focused window)")
+		cmd.Flags().Bool("with-app-siblings", false, "Also move the window's same-app siblings into the target cell")
+		cmd.Flags().String("target-space", "", "Fallback space when no adjacent display exists (implies --extend)")
+		cmd.Flags().Bool("auto-layout", false, "Apply the target space's default layout if it has none")
+		cmd.Flags().Bool("follow", false, "Switch to the target space if a cross-display move leaves it inactive")
+		cmd.Flags().Bool("keep-relative", false, "Keep the window's share of its cell in the target cell")
+		cmd.Flags().Bool("auto-expand", false, "Switch to a larger layout in the cycle instead of overstacking the target cell (not with --split)")
+		cmd.Flags().Bool("reflow-source", true, "Re-place the windows left in the source cell after a cross-display move")
+		cmd.Flags().Bool("balance-target", true, "Equalize the target cell's windows after the move")
+		cmd.Flags().Bool("no-balance-target", false, "Keep the target cell's ratios, giving the window a proportional share")
+		cmd.Flags().String("split", "", "Set the target cell's stack mode as part of the move: vertical or horizontal")
+		cmd.Flags().String("app", "", "Move a window of this app (name or bundle ID) instead of the focused one")
+		cmd.Flags().Int("nth", 1, "With --app, which of the app's windows to move, counting by window ID")
+		addFocusFollowFlags(cmd)
 	}
+	addFocusFollowFlags(windowThrowCmd)
+	addFocusFollowFlags(windowSwapCmd)
 
 	// Add space subcommands
 	spaceCmd.AddCommand(spaceCreateCmd)
 	spaceCmd.AddCommand(spaceDestroyCmd)
 	spaceCmd.AddCommand(spaceFocusCmd)
+	spaceCmd.AddCommand(spaceListLayoutsCmd)
 
 	// Add flags for window update command
 	windowUpdateCmd.Flags().Float64Var(&updateX, "x", 0, "X position (optional)")
@@ -2331,12 +5651,23 @@ func init() {
 
 	// Disable color if requested, enable debug logging if requested
 	cobra.OnInitialize(func() {
-		if noColor {
-			color.NoColor = true
-		}
-		if debugMode {
-			logging.SetDebug(true)
-		}
+		// Set both ways so a daemon run doesn't inherit the previous run's flags
+		color.NoColor = noColor || terminalNoColor
+		logging.SetDebug(debugMode)
+		gridState.SetReadOnly(serverOnly)
+		// Set through the flag so the daemon's flag reset clears it again;
+		// usage text would break the envelope on stdout
+		if jsonEnvelopeOutput {
+			rootCmd.PersistentFlags().Set("json", "true")
+			rootCmd.SilenceUsage = true
+			suppressStdout()
+		}
+		client.SetDefaultRetries(retries)
+		// Automatic state backups are opt-in via settings.autoBackupState,
+		// read from this command's config and taken once per command
+		commandConfig = nil
+		gridState.SetBackupPolicyFunc(backupPolicy)
+		gridState.ResetBackups()
 	})
 }
 
@@ -2345,19 +5676,132 @@ func main() {
 	logging.Init()
 	defer logging.Close()
 
-	if err := rootCmd.Execute(); err != nil {
+	// With --daemon, a running `grid watch` executes the command; otherwise
+	// it runs here as usual
+	if hasDaemonFlag(os.Args[1:]) {
+		handled, err := gridDaemon.Route(context.Background(), gridDaemon.ControlSocketPath(), os.Args[1:], os.Stdout, os.Stderr)
+		if handled {
+			if err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+		logging.Debug().Msg("no grid daemon running, executing standalone")
+	}
+
+	if err := execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
+// jsonEnvelope wraps a command's JSON output under --json-envelope
+type jsonEnvelope struct {
+	OK      bool        `json:"ok"`
+	Command string      `json:"command"`
+	Data    interface{} `json:"data"`
+	Error   *string     `json:"error"`
+}
+
+// pendingJSON holds what the running command passed to printJSON while
+// --json-envelope is set, until execute knows whether the command failed
+var pendingJSON []interface{}
+
+// restoreStdout undoes suppressStdout once the command has run
+var restoreStdout func()
+
+// suppressStdout discards what the command prints to stdout, so that under
+// --json-envelope only the envelope reaches it. Human-readable messages of
+// commands without a JSON result would otherwise break the envelope.
+func suppressStdout() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		logging.Warn().Err(err).Msg("failed to open null device")
+		return
+	}
+	origOut, origColor := os.Stdout, color.Output
+	os.Stdout, color.Output = devNull, devNull
+	restoreStdout = func() {
+		os.Stdout, color.Output = origOut, origColor
+		devNull.Close()
+	}
+}
+
+// execute runs the root command. With --json-envelope, the command's JSON
+// output is printed afterwards inside an envelope that also reports the
+// command's error.
+func execute() error {
+	pendingJSON = nil
+	cmd, err := rootCmd.ExecuteC()
+	rootCmd.SilenceUsage = false
+	if restoreStdout != nil {
+		restoreStdout()
+		restoreStdout = nil
+	}
+	if !jsonEnvelopeOutput {
+		return err
+	}
+
+	env := jsonEnvelope{OK: err == nil}
+	switch len(pendingJSON) {
+	case 0:
+	case 1:
+		env.Data = pendingJSON[0]
+	default:
+		env.Data = pendingJSON
+	}
+	pendingJSON = nil
+	if cmd != nil {
+		env.Command = cmd.CommandPath()
+	}
+	if err != nil {
+		msg := err.Error()
+		env.Error = &msg
+	}
+	if encErr := encodeJSON(env); encErr != nil {
+		return encErr
+	}
+	return err
+}
+
+// hasDaemonFlag reports whether args ask to run through the daemon. It runs
+// before cobra parses flags, so only the flag's plain forms are recognised.
+func hasDaemonFlag(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "--daemon", "--daemon=true":
+			return true
+		}
+	}
+	return false
+}
+
 // Helper functions
 
+// printJSON prints a command's JSON result. Under --json-envelope it is held
+// for execute to wrap; a command printing several results gets them as a
+// list in the envelope's data.
 func printJSON(data interface{}) error {
+	if !jsonEnvelopeOutput {
+		return encodeJSON(data)
+	}
+	pendingJSON = append(pendingJSON, data)
+	return nil
+}
+
+func encodeJSON(data interface{}) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	return enc.Encode(data)
 }
 
+// tableOptions returns table options for a command with a --wide flag
+func tableOptions(cmd *cobra.Command) output.TableOptions {
+	wide, _ := cmd.Flags().GetBool("wide")
+	return output.TableOptions{Wide: wide}
+}
+
 func printError(msg string) {
 	if noColor {
 		fmt.Fprintln(os.Stderr, "Error:", msg)
@@ -2508,6 +5952,44 @@ func getState() (*models.State, error) {
 	return state, nil
 }
 
+// printLayout renders a display (or all if displayIndex < 0) in the
+// format chosen by --format, writing images to --out or stdout
+func printLayout(state *models.State, displayIndex int) error {
+	var render func(io.Writer, *models.State, int) error
+	switch showFormat {
+	case "", "ascii":
+		return output.PrintVisualization(state, displayIndex, getVisualizationOptions())
+	case "svg":
+		render = output.WriteSVG
+	case "png":
+		if showOut == "" {
+			return fmt.Errorf("png output requires --out")
+		}
+		render = output.WritePNG
+	default:
+		return fmt.Errorf("invalid format %q: use ascii, svg, or png", showFormat)
+	}
+
+	if showOut == "" {
+		return render(os.Stdout, state, displayIndex)
+	}
+
+	f, err := os.Create(showOut)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", showOut, err)
+	}
+	if err := render(f, state, displayIndex); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render layout: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", showOut, err)
+	}
+
+	successColor.Printf("✓ Layout written to %s\n", showOut)
+	return nil
+}
+
 // getVisualizationOptions builds options from flags
 func getVisualizationOptions() output.VisualizationOptions {
 	opts := output.DefaultVisualizationOptions()
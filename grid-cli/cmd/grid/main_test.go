@@ -0,0 +1,598 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/rs/zerolog"
+	gridConfig "github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/models"
+	gridReconcile "github.com/yourusername/grid-cli/internal/reconcile"
+	gridServer "github.com/yourusername/grid-cli/internal/server"
+	gridState "github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	gridTypes "github.com/yourusername/grid-cli/internal/types"
+	gridWatch "github.com/yourusername/grid-cli/internal/watch"
+)
+
+// findCommand looks up a command in a help-all tree by its path
+func findCommand(doc commandDoc, path string) *commandDoc {
+	if doc.Path == path {
+		return &doc
+	}
+	for _, sub := range doc.Commands {
+		if found := findCommand(sub, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func TestDescribeCommand_JSON(t *testing.T) {
+	data, err := json.Marshal(describeCommand(rootCmd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree commandDoc
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	update := findCommand(tree, "grid window update")
+	if update == nil {
+		t.Fatal("grid window update missing from the tree")
+	}
+	if update.Args != "<window-id>" {
+		t.Errorf("args = %q, want <window-id>", update.Args)
+	}
+
+	var x *flagDoc
+	for i := range update.Flags {
+		if update.Flags[i].Name == "x" {
+			x = &update.Flags[i]
+		}
+	}
+	if x == nil {
+		t.Fatalf("--x flag missing from window update: %+v", update.Flags)
+	}
+	if x.Type != "float64" || x.Usage == "" {
+		t.Errorf("unexpected --x flag: %+v", x)
+	}
+
+	// Persistent flags appear only where they're defined
+	for _, f := range update.Flags {
+		if f.Name == "json" {
+			t.Error("inherited --json flag repeated on window update")
+		}
+	}
+	if len(tree.Flags) == 0 || !tree.Flags[0].Persistent {
+		t.Errorf("expected root persistent flags, got %+v", tree.Flags)
+	}
+}
+
+func TestPrintCommandTree(t *testing.T) {
+	var buf bytes.Buffer
+	printCommandTree(&buf, describeCommand(rootCmd))
+
+	out := buf.String()
+	if !strings.Contains(out, "grid window update <window-id>") {
+		t.Errorf("text tree missing window update:\n%s", out)
+	}
+	if !strings.Contains(out, "--x float64") {
+		t.Errorf("text tree missing --x flag:\n%s", out)
+	}
+}
+
+func TestMan_WritesPagePerCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "man")
+
+	if resp := runForwarded([]string{"man", dir}); resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	for _, page := range []string{"grid.1", "grid-window-move.1", "grid-layout-apply.1"} {
+		data, err := os.ReadFile(filepath.Join(dir, page))
+		if err != nil {
+			t.Errorf("expected man page %s: %v", page, err)
+			continue
+		}
+		if !strings.Contains(string(data), ".TH") {
+			t.Errorf("%s is not a man page: %.100q", page, data)
+		}
+	}
+}
+
+func TestRunForwarded_CapturesOutputAndResetsFlags(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	resp := runForwarded([]string{"help-all", "--json"})
+	if resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	if !strings.HasPrefix(resp.Stdout, "{") || !strings.Contains(resp.Stdout, `"grid window update"`) {
+		t.Errorf("expected JSON tree on stdout, got %.200q", resp.Stdout)
+	}
+
+	// --json from the previous run doesn't carry over
+	resp = runForwarded([]string{"help-all"})
+	if strings.HasPrefix(resp.Stdout, "{") || !strings.Contains(resp.Stdout, "grid window update <window-id>") {
+		t.Errorf("expected text tree after reset, got %.200q", resp.Stdout)
+	}
+
+	if resp := runForwarded([]string{"watch"}); resp.Error == "" {
+		t.Error("expected watch to be refused through the daemon")
+	}
+}
+
+func TestRunForwarded_ReleasesStateLock(t *testing.T) {
+	// Child process: take the state lock the way a standalone grid would
+	if os.Getenv("GRID_TEST_LOAD_STATE") == "1" {
+		if _, err := gridState.LoadStateFrom(gridState.GetStatePath()); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	// The state directory must exist for the lock to be taken
+	if err := gridState.NewRuntimeState().Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp := runForwarded([]string{"state", "show"}); resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRunForwarded_ReleasesStateLock$")
+	cmd.Env = append(os.Environ(), "GRID_TEST_LOAD_STATE=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("loading state after a forwarded run failed: %v\n%s", err, out)
+	}
+}
+
+func TestRunForwarded_ResetsReadOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(gridState.ReadOnlyEnv, "")
+
+	if resp := runForwarded([]string{"state", "show", "--server-only"}); resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	if gridState.ReadOnly() {
+		t.Error("read-only mode outlived the forwarded --server-only run")
+	}
+}
+
+func TestRunForwarded_KeepsDaemonReadOnly(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(gridState.ReadOnlyEnv, "")
+	daemonReadOnly = true
+	defer func() {
+		daemonReadOnly = false
+		gridState.SetReadOnly(false)
+	}()
+	gridState.SetReadOnly(true)
+
+	if resp := runForwarded([]string{"state", "show"}); resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	if !gridState.ReadOnly() {
+		t.Error("a forwarded run turned off the --server-only daemon's read-only mode")
+	}
+}
+
+func decodeEnvelope(t *testing.T, stdout string) jsonEnvelope {
+	t.Helper()
+	var env jsonEnvelope
+	if err := json.Unmarshal([]byte(stdout), &env); err != nil {
+		t.Fatalf("stdout is not one JSON envelope: %v\n%.300s", err, stdout)
+	}
+	return env
+}
+
+func TestJSONEnvelope_WrapsResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	resp := runForwarded([]string{"state", "show", "--json-envelope"})
+	if resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	env := decodeEnvelope(t, resp.Stdout)
+	if !env.OK || env.Error != nil {
+		t.Errorf("expected ok without error, got %+v", env)
+	}
+	if env.Command != "grid state show" {
+		t.Errorf("command = %q, want grid state show", env.Command)
+	}
+	data, ok := env.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the state as data, got %T", env.Data)
+	}
+	if _, ok := data["spaces"]; !ok {
+		t.Errorf("state data missing spaces: %v", data)
+	}
+
+	// The envelope doesn't carry over to the next run
+	resp = runForwarded([]string{"help-all", "--json"})
+	if strings.Contains(resp.Stdout, `"ok"`) {
+		t.Errorf("expected a bare tree after reset, got %.200q", resp.Stdout)
+	}
+}
+
+func TestJSONEnvelope_ReportsError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	resp := runForwarded([]string{"state", "set-stack-mode", "1", "bogus", "--json-envelope"})
+	if resp.Error == "" {
+		t.Fatal("expected the command to fail")
+	}
+	env := decodeEnvelope(t, resp.Stdout)
+	if env.OK {
+		t.Error("expected ok to be false")
+	}
+	if env.Error == nil || !strings.Contains(*env.Error, "invalid stack mode") {
+		t.Errorf("expected the error in the envelope, got %v", env.Error)
+	}
+	if env.Command != "grid state set-stack-mode" || env.Data != nil {
+		t.Errorf("unexpected envelope: %+v", env)
+	}
+}
+
+func TestHasDaemonFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--daemon", "window", "move", "right"}, true},
+		{[]string{"focus", "left", "--daemon=true"}, true},
+		{[]string{"focus", "left"}, false},
+		{[]string{"--daemon=false", "focus", "left"}, false},
+		{[]string{"window", "find", "--", "--daemon"}, false},
+	}
+	for _, tt := range tests {
+		if got := hasDaemonFlag(tt.args); got != tt.want {
+			t.Errorf("hasDaemonFlag(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestPrintSpaceState_ScopedToSpace(t *testing.T) {
+	rs := gridState.NewRuntimeState()
+	one := rs.GetSpace("1")
+	one.SetCurrentLayout("half", 0)
+	one.AssignWindow(101, "left")
+	one.AssignWindow(102, "left")
+	one.SetFocus("left", 1)
+	two := rs.GetSpace("2")
+	two.SetCurrentLayout("full", 0)
+	two.AssignWindow(201, "main")
+
+	summary, ok := rs.SpaceSummary("1")
+	if !ok {
+		t.Fatal("expected state for space 1")
+	}
+	var buf bytes.Buffer
+	printSpaceState(&buf, summary)
+	out := buf.String()
+
+	for _, want := range []string{"Space 1:", "half", "Cell left:", "[101 102]", "Split Ratios: [0.5 0.5]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"main", "201", "full"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q from another space:\n%s", unwanted, out)
+		}
+	}
+
+	if _, ok := rs.SpaceSummary("3"); ok {
+		t.Error("expected no summary for an unknown space")
+	}
+}
+
+func TestPromptGenerateOptions(t *testing.T) {
+	opts := gridConfig.GenerateOptions{Columns: 2, Rows: 1, Spaces: []string{"1"}}
+	var out bytes.Buffer
+
+	// Empty answers keep the defaults
+	if err := promptGenerateOptions(strings.NewReader("3\n\n2,3\n"), &out, &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Columns != 3 || opts.Rows != 1 || strings.Join(opts.Spaces, ",") != "2,3" {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+	if !strings.Contains(out.String(), "Columns [2]: ") {
+		t.Errorf("expected a columns prompt, got %q", out.String())
+	}
+
+	if err := promptGenerateOptions(strings.NewReader("two\n"), &out, &opts); err == nil {
+		t.Error("expected an error for a non-numeric answer")
+	}
+}
+
+func TestConfigGenerate_ForwardedDoesNotPrompt(t *testing.T) {
+	resp := runForwarded([]string{"config", "generate", "--stdout"})
+	if resp.Error == "" || !strings.Contains(resp.Error, "--yes") {
+		t.Errorf("expected a forwarded run without values to fail, got %+v", resp)
+	}
+
+	resp = runForwarded([]string{"config", "generate", "--stdout", "--yes"})
+	if resp.Error != "" {
+		t.Fatalf("forwarded run with --yes failed: %s", resp.Error)
+	}
+	if !strings.Contains(resp.Stdout, "grid-2x1") {
+		t.Errorf("expected the default 2x1 layout, got:\n%s", resp.Stdout)
+	}
+}
+
+func TestPrintDrift(t *testing.T) {
+	var buf bytes.Buffer
+	printDrift(&buf, &gridReconcile.Drift{
+		SpaceID:      "1",
+		LayoutID:     "half",
+		Stale:        []gridReconcile.StaleWindow{{WindowID: 101, CellID: "a"}},
+		FocusedCell:  "b",
+		Unassigned:   []uint32{600},
+		UnknownCells: []string{"old"},
+	})
+	out := buf.String()
+	for _, want := range []string{"Space 1 (layout half)", "remove window 101 from cell a", "move focus to cell b", "window 600 is in no cell", "cell old is not in layout half"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printDrift(&buf, &gridReconcile.Drift{SpaceID: "2"})
+	if out := buf.String(); !strings.Contains(out, "layout none applied") || !strings.Contains(out, "matches the server") {
+		t.Errorf("unexpected in-sync output:\n%s", out)
+	}
+}
+
+// startDumpServer serves dump on a fake server socket and answers every
+// other request with an empty result. Returns the socket path.
+func startDumpServer(t *testing.T, dump map[string]interface{}) string {
+	t.Helper()
+	s := &testutil.Server{
+		Handle: func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+			if req.Method == "dump" {
+				return dump, nil
+			}
+			return nil, nil
+		},
+	}
+	return s.Start(t)
+}
+
+func TestListWindows_EmptyJSON(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	socket := startDumpServer(t, map[string]interface{}{
+		"windows": map[string]interface{}{
+			"1": map[string]interface{}{"id": 1, "appName": "Safari", "title": "Docs", "isOrderedIn": true, "alpha": 1, "level": 0},
+		},
+	})
+
+	for _, args := range [][]string{
+		{"list", "windows", "--app", "nomatch", "--json"},
+		{"list", "windows", "--app", "nomatch", "--all", "--json"},
+	} {
+		resp := runForwarded(append(args, "--socket", socket))
+		if resp.Error != "" {
+			t.Fatalf("%v: unexpected error: %s", args, resp.Error)
+		}
+		if strings.TrimSpace(resp.Stdout) != "[]" {
+			t.Errorf("%v: stdout = %q, want []", args, resp.Stdout)
+		}
+	}
+}
+
+func TestJSONEnvelope_EmptyResult(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	socket := startDumpServer(t, map[string]interface{}{})
+
+	for _, args := range [][]string{
+		{"list", "spaces"},
+		{"list", "displays"},
+		{"list", "apps"},
+		{"list", "windows"},
+		{"window", "find", "nomatch"},
+	} {
+		resp := runForwarded(append(args, "--json-envelope", "--socket", socket))
+		if resp.Error != "" {
+			t.Fatalf("%v: unexpected error: %s", args, resp.Error)
+		}
+		env := decodeEnvelope(t, resp.Stdout)
+		if data, ok := env.Data.([]interface{}); !env.OK || !ok || len(data) != 0 {
+			t.Errorf("%v: expected ok with an empty list, got %+v", args, env)
+		}
+	}
+}
+
+func TestWatch_RejectsJSONEnvelope(t *testing.T) {
+	jsonEnvelopeOutput = true
+	defer func() { jsonEnvelopeOutput = false }()
+
+	err := watchCmd.RunE(watchCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--json-envelope") {
+		t.Errorf("expected watch to refuse --json-envelope, got %v", err)
+	}
+}
+
+func TestWatchValidate_WarnsAboutDisplayFit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	socket := startDumpServer(t, map[string]interface{}{
+		"displays": []interface{}{
+			map[string]interface{}{"uuid": "laptop", "visibleFrame": []interface{}{[]interface{}{0, 0}, []interface{}{1000, 800}}},
+		},
+	})
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := `layouts:
+  - id: sidebar
+    grid:
+      columns:
+        - 900px
+        - minmax(300px, 1fr)
+      rows: [1fr]
+    areas:
+      - [side, main]
+`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := runForwarded([]string{"config", "watch-validate", path, "--format", "json", "--socket", socket})
+	if resp.Error != "" {
+		t.Fatalf("warnings alone shouldn't fail: %s", resp.Error)
+	}
+	var report gridConfig.Report
+	if err := json.Unmarshal([]byte(resp.Stdout), &report); err != nil {
+		t.Fatalf("stdout is not a report: %v\n%s", err, resp.Stdout)
+	}
+	if report.Warnings() != 1 || !strings.Contains(report.Issues[0].Message, "display 0 is 1000px wide") {
+		t.Errorf("expected one fit warning, got %+v", report.Issues)
+	}
+}
+
+func TestRunForwarded_ResetsColorAndDebug(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer func() { color.NoColor = terminalNoColor }()
+
+	runForwarded([]string{"help-all", "--no-color", "--debug"})
+	if !color.NoColor || zerolog.GlobalLevel() != zerolog.DebugLevel {
+		t.Fatalf("expected --no-color and --debug to apply, got NoColor=%v level=%v", color.NoColor, zerolog.GlobalLevel())
+	}
+
+	runForwarded([]string{"help-all"})
+	if color.NoColor != terminalNoColor {
+		t.Error("--no-color outlived its forwarded run")
+	}
+	if zerolog.GlobalLevel() == zerolog.DebugLevel {
+		t.Error("--debug outlived its forwarded run")
+	}
+}
+
+func TestReapplyOnChange_FailedReapplyIsNotSaved(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "thegrid")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "layouts:\n  - id: full\n    grid: {columns: [1fr], rows: [1fr]}\n    areas: [[main]]\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rs := gridState.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("gone", 0) // not in the config
+	space.AssignWindow(100, "a")
+	if err := rs.Save(); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(gridState.GetStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := &gridServer.Snapshot{
+		SpaceID:   "1",
+		Windows:   []gridServer.WindowInfo{{ID: 100}, {ID: 101}},
+		WindowIDs: map[uint32]bool{100: true, 101: true},
+	}
+	stdout, stderr := new(strings.Builder), new(strings.Builder)
+	restore, err := captureOutput(stdout, stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reapplyOnChange(context.Background(), snap, gridWatch.Changes{SpaceID: "1", Added: []uint32{101}})
+	restore()
+
+	if !strings.Contains(stderr.String(), "failed to re-apply layout") {
+		t.Fatalf("expected the re-apply to fail, got stdout %q stderr %q", stdout, stderr)
+	}
+	after, err := os.ReadFile(gridState.GetStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("state was saved after a failed re-apply")
+	}
+}
+
+func TestJSONEnvelope_SuppressesHumanOutput(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// set-stack-mode has no JSON result, only a success message
+	resp := runForwarded([]string{"state", "set-stack-mode", "1", "tabs", "--json-envelope"})
+	if resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	env := decodeEnvelope(t, resp.Stdout)
+	if !env.OK || env.Command != "grid state set-stack-mode" || env.Data != nil {
+		t.Errorf("unexpected envelope: %+v", env)
+	}
+}
+
+func TestApplyOptionsFromFlags_AssignmentBeatsPlaceNewAtFocus(t *testing.T) {
+	defer resetFlags(rootCmd)
+	cfg := &gridConfig.Config{Settings: gridConfig.Settings{PlaceNewAtFocus: true}}
+
+	// The setting alone switches to preserve
+	opts, err := applyOptionsFromFlags(layoutApplyCmd, cfg)
+	if err != nil || opts.Strategy != gridTypes.AssignPreserve {
+		t.Fatalf("expected preserve from the setting, got %v (%v)", opts.Strategy, err)
+	}
+
+	// An explicit --assignment wins over the setting
+	layoutApplyCmd.Flags().Set("assignment", "autoflow")
+	opts, err = applyOptionsFromFlags(layoutApplyCmd, cfg)
+	if err != nil || opts.Strategy != gridTypes.AssignAutoFlow {
+		t.Errorf("expected the explicit autoflow strategy, got %v (%v)", opts.Strategy, err)
+	}
+
+	// ...and can't be combined with the flag
+	layoutApplyCmd.Flags().Set("place-new-at-focus", "true")
+	if _, err := applyOptionsFromFlags(layoutApplyCmd, cfg); err == nil {
+		t.Error("expected --place-new-at-focus with --assignment autoflow to be rejected")
+	}
+	layoutApplyCmd.Flags().Set("assignment", "preserve")
+	if opts, err := applyOptionsFromFlags(layoutApplyCmd, cfg); err != nil || !opts.PlaceNewAtFocus {
+		t.Errorf("expected --place-new-at-focus with preserve to be accepted, got %+v (%v)", opts, err)
+	}
+}
+
+func TestRunForwarded_BacksUpStatePerCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, ".config", "thegrid")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "settings:\n  autoBackupState: true\nlayouts:\n  - id: full\n    grid: {columns: [1fr], rows: [1fr]}\n    areas: [[main]]\n"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer gridState.SetBackupPolicy(false, 0)
+
+	// The first run creates the state file, each later one backs it up
+	for _, mode := range []string{"tabs", "vertical", "horizontal"} {
+		if resp := runForwarded([]string{"state", "set-stack-mode", "1", mode}); resp.Error != "" {
+			t.Fatalf("set-stack-mode %s: %s", mode, resp.Error)
+		}
+	}
+
+	names, err := gridState.ListBackups(gridState.GetStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 {
+		t.Errorf("expected a backup from each of the 2 later runs, got %v", names)
+	}
+}
@@ -0,0 +1,87 @@
+package cell
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/focus"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// PullWindow is the inverse of SendWindow: it moves the focused window of the
+// adjacent cell in the given direction into the focused cell and focuses it.
+// Returns the pulled window and the cell it came from.
+func PullWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+) (uint32, string, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return 0, "", fmt.Errorf("no layout applied")
+	}
+
+	currentCell := spaceState.FocusedCell
+	if currentCell == "" {
+		return 0, "", fmt.Errorf("no focused cell")
+	}
+
+	// Calculate layout bounds
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return 0, "", fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	// Find source cell, jumping over skipNavigation cells
+	navBounds := layout.NavigableCells(layoutDef, calculated.CellBounds, currentCell)
+	candidates := layout.GetAdjacentCells(currentCell, navBounds)[direction]
+	if len(candidates) == 0 {
+		return 0, "", fmt.Errorf("no cell in direction %s", direction.String())
+	}
+	sourceCell := pickClosestCell(currentCell, candidates, navBounds)
+
+	// Take the source cell's focused (top) window
+	source := spaceState.Cells[sourceCell]
+	if source == nil || len(source.Windows) == 0 {
+		return 0, "", fmt.Errorf("cell %s is empty", sourceCell)
+	}
+	idx := source.LastFocusedIdx
+	if idx < 0 || idx >= len(source.Windows) {
+		idx = 0
+	}
+	windowID := source.Windows[idx]
+
+	// Move window in state and focus it in the current cell
+	mutableSpace := rs.GetSpace(snap.SpaceID)
+	mutableSpace.RemoveWindow(windowID)
+	mutableSpace.AssignWindow(windowID, currentCell)
+	mutableSpace.SetFocus(currentCell, len(mutableSpace.Cells[currentCell].Windows)-1)
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		return 0, "", fmt.Errorf("failed to save state: %w", err)
+	}
+
+	// Reapply layout, keeping the new assignment rather than sorting
+	// windows back into the cells their frames are still over
+	opts := layout.DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	opts.Strategy = types.AssignPreserve
+	if err := layout.ReapplyLayout(ctx, c, snap, cfg, rs, opts); err != nil {
+		return 0, "", err
+	}
+
+	if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+		logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus pulled window")
+	}
+	return windowID, sourceCell, nil
+}
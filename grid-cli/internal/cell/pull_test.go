@@ -0,0 +1,56 @@
+package cell
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestPullWindow_FromRightIntoFocusedLeft(t *testing.T) {
+	c, srv, snap, cfg, rs := sendFixture(t)
+	space := rs.GetSpace("1")
+	space.RemoveWindow(101)
+	space.AssignWindow(101, "right")
+	space.SetFocus("left", 0)
+
+	windowID, source, err := PullWindow(context.Background(), c, snap, cfg, rs, types.DirRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if windowID != 101 || source != "right" {
+		t.Errorf("expected window 101 pulled from right, got %d from %q", windowID, source)
+	}
+	if frame, ok := lastFrame(srv, 101); !ok || frame.X != 0 {
+		t.Errorf("expected window 101 placed in the left column, got %+v", frame)
+	}
+
+	space = rs.GetSpaceReadOnly("1")
+	if space.GetWindowCell(101) != "left" {
+		t.Errorf("window 101 should be pulled into left, got %q", space.GetWindowCell(101))
+	}
+	if len(space.Cells["right"].Windows) != 0 {
+		t.Errorf("right should be empty, got %v", space.Cells["right"].Windows)
+	}
+	if space.FocusedCell != "left" || space.GetFocusedWindow() != 101 {
+		t.Errorf("focus should move to the pulled window, got %q/%d", space.FocusedCell, space.GetFocusedWindow())
+	}
+}
+
+func TestPullWindow_EmptyCell(t *testing.T) {
+	c, _, snap, cfg, rs := sendFixture(t)
+
+	_, _, err := PullWindow(context.Background(), c, snap, cfg, rs, types.DirRight)
+	if err == nil || !strings.Contains(err.Error(), "cell right is empty") {
+		t.Fatalf("expected empty cell error, got %v", err)
+	}
+}
+
+func TestPullWindow_NoCellInDirection(t *testing.T) {
+	c, _, snap, cfg, rs := sendFixture(t)
+
+	if _, _, err := PullWindow(context.Background(), c, snap, cfg, rs, types.DirLeft); err == nil {
+		t.Error("expected error with no cell to the left")
+	}
+}
@@ -12,7 +12,8 @@ import (
 	"github.com/yourusername/grid-cli/internal/types"
 )
 
-// SendWindow moves the focused window to an adjacent cell.
+// SendWindow moves a window to an adjacent cell.
+// A windowID of 0 sends the focused window; focus follows it only then.
 func SendWindow(
 	ctx context.Context,
 	c *client.Client,
@@ -20,56 +21,65 @@ func SendWindow(
 	cfg *config.Config,
 	rs *state.RuntimeState,
 	direction types.Direction,
+	windowID uint32,
 ) error {
 	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
 	if spaceState == nil || spaceState.CurrentLayoutID == "" {
 		return fmt.Errorf("no layout applied")
 	}
 
-	// Get focused window
-	windowID := spaceState.GetFocusedWindow()
+	// Default to the focused window
+	focused := spaceState.GetFocusedWindow()
 	if windowID == 0 {
-		return fmt.Errorf("no focused window")
+		windowID = focused
+		if windowID == 0 {
+			return fmt.Errorf("no focused window")
+		}
 	}
 
-	currentCell := spaceState.FocusedCell
+	currentCell := spaceState.GetWindowCell(windowID)
 	if currentCell == "" {
-		return fmt.Errorf("no focused cell")
+		return fmt.Errorf("window %d is not tracked on space %s", windowID, snap.SpaceID)
 	}
 
 	// Calculate layout bounds
-	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
 	if err != nil {
 		return fmt.Errorf("layout not found: %w", err)
 	}
-	calculated := layout.CalculateLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding))
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
 
-	// Find target cell
-	adjacentMap := layout.GetAdjacentCells(currentCell, calculated.CellBounds)
+	// Find target cell, jumping over skipNavigation cells
+	navBounds := layout.NavigableCells(layoutDef, calculated.CellBounds, currentCell)
+	adjacentMap := layout.GetAdjacentCells(currentCell, navBounds)
 	candidates := adjacentMap[direction]
 	if len(candidates) == 0 {
 		return fmt.Errorf("no cell in direction %s", direction.String())
 	}
 
 	// Pick closest candidate
-	targetCell := pickClosestCell(currentCell, candidates, calculated.CellBounds)
+	targetCell := pickClosestCell(currentCell, candidates, navBounds)
 
 	// Move window in state
 	mutableSpace := rs.GetSpace(snap.SpaceID)
 	mutableSpace.RemoveWindow(windowID)
 	mutableSpace.AssignWindow(windowID, targetCell)
 
-	// Update focus to follow window
-	targetCellState := mutableSpace.Cells[targetCell]
-	mutableSpace.SetFocus(targetCell, len(targetCellState.Windows)-1)
+	// Update focus to follow the focused window
+	if windowID == focused {
+		targetCellState := mutableSpace.Cells[targetCell]
+		mutableSpace.SetFocus(targetCell, len(targetCellState.Windows)-1)
+	}
 	rs.MarkUpdated()
 	if err := rs.Save(); err != nil {
 		return fmt.Errorf("failed to save state: %w", err)
 	}
 
-	// Reapply layout
+	// Reapply layout, keeping the new assignment rather than sorting
+	// windows back into the cells their frames are still over
 	opts := layout.DefaultApplyOptions()
 	opts.Gap = float64(cfg.Settings.CellPadding)
+	opts.Strategy = types.AssignPreserve
 	return layout.ReapplyLayout(ctx, c, snap, cfg, rs, opts)
 }
 
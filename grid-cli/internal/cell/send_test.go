@@ -0,0 +1,121 @@
+package cell
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func sendFixture(t *testing.T) (*client.Client, *testutil.Server, *server.Snapshot, *config.Config, *state.RuntimeState) {
+	t.Setenv("HOME", t.TempDir())
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+		Windows: []server.WindowInfo{
+			{ID: 100, Frame: types.Rect{X: 0, Y: 0, Width: 960, Height: 540}},
+			{ID: 101, Frame: types.Rect{X: 0, Y: 540, Width: 960, Height: 540}},
+		},
+		WindowIDs: map[uint32]bool{100: true, 101: true},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "cols",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"left", "right"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("cols", 0)
+	space.AssignWindow(100, "left")
+	space.AssignWindow(101, "left")
+	space.SetFocus("left", 0)
+
+	srv := &testutil.Server{}
+	c := client.NewClient(srv.Start(t), 0)
+	t.Cleanup(func() { c.Close() })
+	return c, srv, snap, cfg, rs
+}
+
+// lastFrame returns the last frame the server was sent for a window
+func lastFrame(srv *testutil.Server, windowID uint32) (types.Rect, bool) {
+	updates := srv.WindowUpdates()
+	for i := len(updates) - 1; i >= 0; i-- {
+		u := updates[i]
+		if u["windowId"] != float64(windowID) {
+			continue
+		}
+		if _, ok := u["width"]; !ok {
+			continue
+		}
+		return types.Rect{
+			X:      u["x"].(float64),
+			Y:      u["y"].(float64),
+			Width:  u["width"].(float64),
+			Height: u["height"].(float64),
+		}, true
+	}
+	return types.Rect{}, false
+}
+
+func TestSendWindow_DefaultsToFocused(t *testing.T) {
+	c, srv, snap, cfg, rs := sendFixture(t)
+
+	if err := SendWindow(context.Background(), c, snap, cfg, rs, types.DirRight, 0); err != nil {
+		t.Fatal(err)
+	}
+	if frame, ok := lastFrame(srv, 100); !ok || frame.X < 960 {
+		t.Errorf("expected window 100 placed in the right column, got %+v", frame)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if space.GetWindowCell(100) != "right" {
+		t.Errorf("focused window 100 should be in right, got %q", space.GetWindowCell(100))
+	}
+	if space.FocusedCell != "right" {
+		t.Errorf("focus should follow the sent window, got %q", space.FocusedCell)
+	}
+}
+
+func TestSendWindow_ByWindowID(t *testing.T) {
+	c, srv, snap, cfg, rs := sendFixture(t)
+
+	if err := SendWindow(context.Background(), c, snap, cfg, rs, types.DirRight, 101); err != nil {
+		t.Fatal(err)
+	}
+	if frame, ok := lastFrame(srv, 101); !ok || frame.X < 960 {
+		t.Errorf("expected window 101 placed in the right column, got %+v", frame)
+	}
+	if frame, ok := lastFrame(srv, 100); !ok || frame.X != 0 || frame.Height != 1080 {
+		t.Errorf("expected window 100 to fill the left column, got %+v", frame)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if space.GetWindowCell(101) != "right" {
+		t.Errorf("window 101 should be in right, got %q", space.GetWindowCell(101))
+	}
+	if space.GetWindowCell(100) != "left" {
+		t.Errorf("focused window 100 should stay in left, got %q", space.GetWindowCell(100))
+	}
+	if space.FocusedCell != "left" || space.GetFocusedWindow() != 100 {
+		t.Errorf("focus should stay on window 100 in left, got %q/%d", space.FocusedCell, space.GetFocusedWindow())
+	}
+}
+
+func TestSendWindow_UntrackedWindow(t *testing.T) {
+	c, _, snap, cfg, rs := sendFixture(t)
+
+	err := SendWindow(context.Background(), c, snap, cfg, rs, types.DirRight, 999)
+	if err == nil || !strings.Contains(err.Error(), "not tracked on space 1") {
+		t.Fatalf("expected untracked window error, got %v", err)
+	}
+}
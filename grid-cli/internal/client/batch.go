@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// BatchUpdateMethod updates several windows in one request
+const BatchUpdateMethod = "window.batchUpdate"
+
+// errCodeMethodNotFound is the error code the server answers an unknown
+// method with
+const errCodeMethodNotFound = -32601
+
+// WindowUpdate is one window's changes in a BatchUpdateWindows call, with
+// the same keys UpdateWindow takes
+type WindowUpdate struct {
+	WindowID int
+	Updates  map[string]interface{}
+}
+
+// BatchUpdateWindows applies updates in a single window.batchUpdate request.
+// Servers without window.batchUpdate get one updateWindow request per
+// window instead; the client remembers this for later calls.
+//
+// The returned slice holds one error per update (nil when it was applied),
+// so a bad window doesn't keep the rest from moving. The error is set only
+// when the request as a whole failed, e.g. the server is unreachable.
+func (c *Client) BatchUpdateWindows(ctx context.Context, updates []WindowUpdate) ([]error, error) {
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	if !c.noBatch {
+		errs, err := c.batchUpdate(ctx, updates)
+		if !errors.Is(err, errBatchUnsupported) {
+			return errs, err
+		}
+		c.noBatch = true
+	}
+
+	errs := make([]error, len(updates))
+	for i, u := range updates {
+		_, errs[i] = c.UpdateWindow(ctx, u.WindowID, u.Updates)
+	}
+	return errs, nil
+}
+
+// errBatchUnsupported is returned by batchUpdate when the server doesn't
+// know window.batchUpdate
+var errBatchUnsupported = errors.New("window.batchUpdate not supported")
+
+// batchUpdate sends the window.batchUpdate request. The server answers with
+// a "results" list of {windowId, error} entries; windows without an entry,
+// or with an empty error, were updated.
+func (c *Client) batchUpdate(ctx context.Context, updates []WindowUpdate) ([]error, error) {
+	list := make([]interface{}, 0, len(updates))
+	for _, u := range updates {
+		params := map[string]interface{}{"windowId": u.WindowID}
+		for k, v := range u.Updates {
+			params[k] = v
+		}
+		list = append(list, params)
+	}
+
+	resp, err := c.request(ctx, BatchUpdateMethod, map[string]interface{}{"updates": list})
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		if resp.Error.Code == errCodeMethodNotFound {
+			return nil, errBatchUnsupported
+		}
+		return nil, fmt.Errorf("server error: %s", resp.GetError())
+	}
+
+	failed := make(map[int]string)
+	results, _ := resp.Result["results"].([]interface{})
+	for _, r := range results {
+		entry, _ := r.(map[string]interface{})
+		id, _ := entry["windowId"].(float64)
+		if msg, _ := entry["error"].(string); msg != "" {
+			failed[int(id)] = msg
+		}
+	}
+
+	errs := make([]error, len(updates))
+	for i, u := range updates {
+		if msg, ok := failed[u.WindowID]; ok {
+			errs[i] = fmt.Errorf("server error: %s", msg)
+		}
+	}
+	return errs, nil
+}
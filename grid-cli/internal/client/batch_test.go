@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/testutil"
+)
+
+// batchServer answers window.batchUpdate (unless noBatch is set, when it
+// reports the method as not found), failing the windows in failing, and
+// every other request with an empty result. It records the requests sent.
+type batchServer struct {
+	noBatch bool
+	failing map[int]string
+
+	testutil.Server
+}
+
+func startBatchServer(t *testing.T, bs *batchServer) *Client {
+	t.Helper()
+	bs.Handle = func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+		if req.Method != BatchUpdateMethod {
+			return nil, nil
+		}
+		if bs.noBatch {
+			return nil, &models.ErrorInfo{
+				Code:    errCodeMethodNotFound,
+				Message: "Method not found: " + BatchUpdateMethod,
+			}
+		}
+		return map[string]interface{}{"results": bs.results(req.Params)}, nil
+	}
+	c := NewClient(bs.Start(t), time.Second)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func (bs *batchServer) results(params map[string]interface{}) []interface{} {
+	updates, _ := params["updates"].([]interface{})
+	results := make([]interface{}, 0, len(updates))
+	for _, u := range updates {
+		id := int(u.(map[string]interface{})["windowId"].(float64))
+		entry := map[string]interface{}{"windowId": id}
+		if msg, ok := bs.failing[id]; ok {
+			entry["error"] = msg
+		}
+		results = append(results, entry)
+	}
+	return results
+}
+
+func testUpdates(ids ...int) []WindowUpdate {
+	updates := make([]WindowUpdate, 0, len(ids))
+	for _, id := range ids {
+		updates = append(updates, WindowUpdate{WindowID: id, Updates: map[string]interface{}{"x": 0, "y": 0}})
+	}
+	return updates
+}
+
+func TestBatchUpdateWindows_OneRequest(t *testing.T) {
+	bs := &batchServer{}
+	c := startBatchServer(t, bs)
+
+	errs, err := c.BatchUpdateWindows(context.Background(), testUpdates(1, 2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range errs {
+		if e != nil {
+			t.Errorf("update %d failed: %v", i, e)
+		}
+	}
+	if n := bs.Count(BatchUpdateMethod); n != 1 {
+		t.Errorf("expected 1 batch request, got %d", n)
+	}
+	if n := bs.Count("updateWindow"); n != 0 {
+		t.Errorf("expected no single updates, got %d", n)
+	}
+}
+
+func TestBatchUpdateWindows_PartialFailure(t *testing.T) {
+	bs := &batchServer{failing: map[int]string{2: "window not found"}}
+	c := startBatchServer(t, bs)
+
+	errs, err := c.BatchUpdateWindows(context.Background(), testUpdates(1, 2, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(errs))
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("windows 1 and 3 should succeed, got %v", errs)
+	}
+	if errs[1] == nil {
+		t.Error("expected window 2 to fail")
+	}
+}
+
+func TestBatchUpdateWindows_FallsBackWithoutBatch(t *testing.T) {
+	bs := &batchServer{noBatch: true}
+	c := startBatchServer(t, bs)
+
+	for i := 0; i < 2; i++ {
+		errs, err := c.BatchUpdateWindows(context.Background(), testUpdates(1, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range errs {
+			if e != nil {
+				t.Errorf("fallback update failed: %v", e)
+			}
+		}
+	}
+
+	if n := bs.Count(BatchUpdateMethod); n != 1 {
+		t.Errorf("expected the batch method to be tried once, got %d", n)
+	}
+	if n := bs.Count("updateWindow"); n != 4 {
+		t.Errorf("expected 4 single updates, got %d", n)
+	}
+}
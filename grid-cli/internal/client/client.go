@@ -16,7 +16,11 @@ const (
 
 // Client is the main GridServer client
 type Client struct {
-	conn *Connection
+	conn    *Connection
+	retries int  // Attempts for Ping, GetServerInfo, Dump and CallMethod
+	noBatch bool // Server lacks window.batchUpdate (see BatchUpdateWindows)
+
+	capabilities map[string]interface{} // getServerInfo capabilities, once fetched (see HasCapability)
 }
 
 // NewClient creates a new GridServer client
@@ -29,7 +33,8 @@ func NewClient(socketPath string, timeout time.Duration) *Client {
 	}
 
 	return &Client{
-		conn: NewConnection(socketPath, timeout),
+		conn:    NewConnection(socketPath, timeout),
+		retries: defaultRetries,
 	}
 }
 
@@ -55,9 +60,11 @@ func (c *Client) request(ctx context.Context, method string, params map[string]i
 	return c.conn.SendRequest(ctx, req)
 }
 
-// Ping sends a ping request to test connectivity
+// Ping sends a ping request to test connectivity.
+// Ping, GetServerInfo, Dump and CallMethod retry transport failures (see
+// requestWithRetry); server errors are returned as is.
 func (c *Client) Ping(ctx context.Context) (map[string]interface{}, error) {
-	resp, err := c.request(ctx, "ping", nil)
+	resp, err := c.requestWithRetry(ctx, "ping", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +78,7 @@ func (c *Client) Ping(ctx context.Context) (map[string]interface{}, error) {
 
 // GetServerInfo retrieves server information
 func (c *Client) GetServerInfo(ctx context.Context) (map[string]interface{}, error) {
-	resp, err := c.request(ctx, "getServerInfo", nil)
+	resp, err := c.requestWithRetry(ctx, "getServerInfo", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -83,9 +90,28 @@ func (c *Client) GetServerInfo(ctx context.Context) (map[string]interface{}, err
 	return resp.Result, nil
 }
 
+// HasCapability reports whether the server lists a capability as enabled in
+// getServerInfo. The capabilities are fetched once per client and shared by
+// every check (animation, mouse warp, fullscreen); a server that can't be
+// asked has none, and isn't asked again.
+func (c *Client) HasCapability(ctx context.Context, name string) bool {
+	if c.capabilities == nil {
+		var caps map[string]interface{}
+		if info, err := c.GetServerInfo(ctx); err == nil {
+			caps, _ = info["capabilities"].(map[string]interface{})
+		}
+		if caps == nil {
+			caps = map[string]interface{}{}
+		}
+		c.capabilities = caps
+	}
+	enabled, _ := c.capabilities[name].(bool)
+	return enabled
+}
+
 // Dump retrieves the complete window manager state
 func (c *Client) Dump(ctx context.Context) (map[string]interface{}, error) {
-	resp, err := c.request(ctx, "dump", map[string]interface{}{})
+	resp, err := c.requestWithRetry(ctx, "dump", map[string]interface{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +148,7 @@ func (c *Client) UpdateWindow(ctx context.Context, windowID int, updates map[str
 
 // CallMethod sends a generic RPC request with the given method and parameters
 func (c *Client) CallMethod(ctx context.Context, method string, params map[string]interface{}) (map[string]interface{}, error) {
-	resp, err := c.request(ctx, method, params)
+	resp, err := c.requestWithRetry(ctx, method, params)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/testutil"
+)
+
+// startFlakyServer starts a fake server that drops its first drop
+// connections without answering, then answers every request with result
+// (or errMsg as a server error). Returns the socket path.
+func startFlakyServer(t *testing.T, drop int, result map[string]interface{}, errMsg string) (string, *testutil.Server) {
+	t.Helper()
+	s := &testutil.Server{
+		Drop: drop,
+		Handle: func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+			if errMsg != "" {
+				return nil, &models.ErrorInfo{Message: errMsg}
+			}
+			return result, nil
+		},
+	}
+	return s.Start(t), s
+}
+
+func TestDump_RetriesDroppedConnection(t *testing.T) {
+	socket, fs := startFlakyServer(t, 1, map[string]interface{}{"ok": true}, "")
+	c := NewClient(socket, time.Second)
+	defer c.Close()
+
+	result, err := c.Dump(context.Background())
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if result["ok"] != true {
+		t.Errorf("unexpected result %v", result)
+	}
+	if conns := fs.Conns(); conns != 2 {
+		t.Errorf("expected a reconnect after the dropped connection, got %d connections", conns)
+	}
+}
+
+func TestCallMethod_ServerErrorNotRetried(t *testing.T) {
+	socket, fs := startFlakyServer(t, 0, nil, "unknown method")
+	c := NewClient(socket, time.Second)
+	defer c.Close()
+
+	_, err := c.CallMethod(context.Background(), "nope", nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown method") {
+		t.Fatalf("expected the server error, got %v", err)
+	}
+	if requests := len(fs.Requests()); requests != 1 {
+		t.Errorf("server error was retried: %d requests", requests)
+	}
+}
+
+func TestPing_GivesUpAfterRetries(t *testing.T) {
+	socket, fs := startFlakyServer(t, 10, nil, "")
+	c := NewClient(socket, time.Second)
+	c.SetRetries(2)
+	defer c.Close()
+
+	if _, err := c.Ping(context.Background()); err == nil {
+		t.Fatal("expected an error once retries run out")
+	}
+	if conns := fs.Conns(); conns != 2 {
+		t.Errorf("expected 2 attempts, got %d", conns)
+	}
+}
+
+func TestHasCapability_AsksOnce(t *testing.T) {
+	socket, fs := startFlakyServer(t, 0, map[string]interface{}{
+		"capabilities": map[string]interface{}{"windowAnimation": true},
+	}, "")
+	c := NewClient(socket, time.Second)
+	defer c.Close()
+
+	if !c.HasCapability(context.Background(), "windowAnimation") {
+		t.Error("expected windowAnimation to be reported")
+	}
+	if c.HasCapability(context.Background(), "mouseWarp") {
+		t.Error("expected mouseWarp to be missing")
+	}
+	if n := fs.Count("getServerInfo"); n != 1 {
+		t.Errorf("expected 1 getServerInfo request, got %d", n)
+	}
+}
+
+func TestHasCapability_ServerErrorAskedOnce(t *testing.T) {
+	socket, fs := startFlakyServer(t, 0, nil, "unknown method")
+	c := NewClient(socket, time.Second)
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		if c.HasCapability(context.Background(), "windowAnimation") {
+			t.Fatal("expected no capabilities from a server that can't be asked")
+		}
+	}
+	if n := fs.Count("getServerInfo"); n != 1 {
+		t.Errorf("expected 1 getServerInfo request, got %d", n)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	missing := NewClient(filepath.Join(t.TempDir(), "missing.sock"), time.Second)
+	missing.SetRetries(1)
+	_, err := missing.Ping(context.Background())
+	if err == nil || !isRetryable(err) {
+		t.Errorf("expected a missing socket to be retryable, got %v", err)
+	}
+
+	if isRetryable(context.DeadlineExceeded) {
+		t.Error("timeouts should not be retried")
+	}
+}
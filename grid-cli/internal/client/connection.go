@@ -46,6 +46,15 @@ func (c *Connection) Close() error {
 	return nil
 }
 
+// reset drops the connection so the next request reconnects
+func (c *Connection) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
 // SendRequest sends a request and waits for the response
 func (c *Connection) SendRequest(ctx context.Context, req *models.MessageEnvelope) (*models.Response, error) {
 	// Apply timeout if not already set
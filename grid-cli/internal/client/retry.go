@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/models"
+)
+
+const (
+	// DefaultRetries is how many attempts a retrying request makes
+	DefaultRetries = 3
+
+	// retryBackoff is the delay before the first retry; it doubles after
+	// each failed attempt
+	retryBackoff = 100 * time.Millisecond
+)
+
+// defaultRetries is the attempt count new clients start with
+var defaultRetries = DefaultRetries
+
+// SetDefaultRetries sets how many attempts clients created afterwards make
+// for retrying requests. n < 1 means a single attempt.
+func SetDefaultRetries(n int) {
+	if n < 1 {
+		n = 1
+	}
+	defaultRetries = n
+}
+
+// SetRetries sets how many attempts this client makes for retrying
+// requests. n < 1 means a single attempt.
+func (c *Client) SetRetries(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.retries = n
+}
+
+// isRetryable reports whether err is a transport failure that a fresh
+// connection may fix, such as the server restarting. Server-side method
+// errors never reach here: they come back as error responses.
+func isRetryable(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ENOENT) || // Socket not recreated yet
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// requestWithRetry is request, retried with exponential backoff on
+// retryable transport errors. The connection is re-established before each
+// retry. Retries stop once the next delay would run past the request
+// timeout (or ctx's deadline, if sooner).
+func (c *Client) requestWithRetry(ctx context.Context, method string, params map[string]interface{}) (*models.Response, error) {
+	deadline := time.Now().Add(c.conn.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	delay := retryBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.request(ctx, method, params)
+		if err == nil || !isRetryable(err) || attempt >= c.retries {
+			return resp, err
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+
+		logging.Debug().Err(err).Str("method", method).Int("attempt", attempt).Dur("backoff", delay).Msg("retrying request")
+		c.conn.reset()
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -22,23 +23,107 @@ const (
 // Supports both .yaml and .json extensions
 func LoadConfig(path string) (*Config, error) {
 	if path == "" {
-		home, err := os.UserHomeDir()
+		found, err := FindConfigFile()
 		if err != nil {
-			return nil, fmt.Errorf("cannot determine home directory: %w", err)
+			return nil, err
 		}
-		// Try YAML first, then JSON
-		yamlPath := filepath.Join(home, DefaultConfigDir, "config.yaml")
-		jsonPath := filepath.Join(home, DefaultConfigDir, "config.json")
+		path = found
+	}
+
+	cfg, err := ParseConfigFile(ExpandPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// FindConfigFile returns the default config file that exists, trying
+// ~/.config/thegrid/config.yaml first, then config.json.
+func FindConfigFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	// Try YAML first, then JSON
+	yamlPath := filepath.Join(home, DefaultConfigDir, "config.yaml")
+	jsonPath := filepath.Join(home, DefaultConfigDir, "config.json")
+
+	if _, err := os.Stat(yamlPath); err == nil {
+		return yamlPath, nil
+	}
+	if _, err := os.Stat(jsonPath); err == nil {
+		return jsonPath, nil
+	}
+	return "", fmt.Errorf("no config file found at %s or %s", yamlPath, jsonPath)
+}
+
+// ParseConfigFile reads and parses a config file without validating it.
+// The format is chosen by file extension (.yaml, .yml, or .json).
+// Files listed under includes are parsed and merged in; see mergeIncludes.
+func ParseConfigFile(path string) (*Config, error) {
+	return parseConfigFile(path, make(map[string]bool))
+}
+
+// parseConfigFile parses a config file and its includes. visiting holds the
+// files on the current include chain, to reject circular includes.
+func parseConfigFile(path string, visiting map[string]bool) (*Config, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	if visiting[abs] {
+		return nil, fmt.Errorf("circular include: %s", path)
+	}
+	visiting[abs] = true
+	defer delete(visiting, abs)
 
-		if _, err := os.Stat(yamlPath); err == nil {
-			path = yamlPath
-		} else if _, err := os.Stat(jsonPath); err == nil {
-			path = jsonPath
-		} else {
-			return nil, fmt.Errorf("no config file found at %s or %s", yamlPath, jsonPath)
+	cfg, err := parseConfigData(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Includes) == 0 {
+		return cfg, nil
+	}
+
+	// Include paths are relative to the including file
+	included := make([]*Config, 0, len(cfg.Includes))
+	for i, inc := range cfg.Includes {
+		inc = ExpandPath(inc)
+		cfg.Includes[i] = inc
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(filepath.Dir(abs), incPath)
+		}
+		incCfg, err := parseConfigFile(incPath, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc, err)
+		}
+		included = append(included, incCfg)
+	}
+
+	mergeIncludes(cfg, included)
+	return cfg, nil
+}
+
+// ExpandPath expands a leading ~ to the home directory and $VAR or ${VAR}
+// references to environment variables. Only config fields that hold paths
+// are expanded; names and other strings are used as written.
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
 		}
 	}
+	return os.ExpandEnv(path)
+}
 
+// parseConfigData reads and decodes a single config file.
+func parseConfigData(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -60,11 +145,82 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("unsupported config format: %s", ext)
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid config: %w", err)
+	return &cfg, nil
+}
+
+// mergeIncludes merges layouts, spaces and app rules from included configs
+// into cfg. On an ID conflict the including file wins, then later includes
+// win over earlier ones. Merged entries keep the position of their first
+// appearance (including file first, then includes in order), so layout
+// cycling order is stable. App rules conflict per app across files only:
+// the winning file keeps all of its rules for that app. Settings are only
+// read from the including file.
+func mergeIncludes(cfg *Config, included []*Config) {
+	// Sources from lowest to highest precedence
+	sources := append(append([]*Config{}, included...), cfg)
+
+	// Layouts, keyed by ID
+	layouts := make(map[string]LayoutConfig)
+	for _, src := range sources {
+		for _, l := range src.Layouts {
+			layouts[l.ID] = l
+		}
+	}
+	var layoutOrder []string
+	seen := make(map[string]bool)
+	for _, src := range append([]*Config{cfg}, included...) {
+		for _, l := range src.Layouts {
+			if !seen[l.ID] {
+				seen[l.ID] = true
+				layoutOrder = append(layoutOrder, l.ID)
+			}
+		}
+	}
+	cfg.Layouts = make([]LayoutConfig, 0, len(layoutOrder))
+	for _, id := range layoutOrder {
+		cfg.Layouts = append(cfg.Layouts, layouts[id])
 	}
 
-	return &cfg, nil
+	// App rules, by app: every rule for an app comes from the highest
+	// precedence file that has one, so a file's own same-app rules (e.g.
+	// per-title rules) all survive, in that file's order
+	owner := make(map[string]*Config)
+	for _, src := range sources {
+		for _, r := range src.AppRules {
+			owner[r.App] = src
+		}
+	}
+	var rules []AppRule
+	for _, src := range append([]*Config{cfg}, included...) {
+		for _, r := range src.AppRules {
+			if owner[r.App] == src {
+				rules = append(rules, r)
+			}
+		}
+	}
+	cfg.AppRules = rules
+
+	// Spaces, keyed by space ID
+	spaces := make(map[string]SpaceConfig)
+	for _, src := range sources {
+		for id, sc := range src.Spaces {
+			spaces[id] = sc
+		}
+	}
+	if len(spaces) > 0 {
+		cfg.Spaces = spaces
+	}
+
+	// Displays, keyed by UUID or index
+	displays := make(map[string]DisplayConfig)
+	for _, src := range sources {
+		for key, dc := range src.Displays {
+			displays[key] = dc
+		}
+	}
+	if len(displays) > 0 {
+		cfg.Displays = displays
+	}
 }
 
 // LoadConfigFromBytes loads configuration from raw bytes
@@ -117,6 +273,17 @@ func (c *Config) GetLayoutIDs() []string {
 	return ids
 }
 
+// LayoutDisabled reports whether a layout is marked disabled: it stays
+// valid and can be shown or applied by ID, but layout cycling skips it
+func (c *Config) LayoutDisabled(id string) bool {
+	for _, lc := range c.Layouts {
+		if lc.ID == id {
+			return lc.Disabled
+		}
+	}
+	return false
+}
+
 // GetSpaceConfig returns configuration for a specific space
 func (c *Config) GetSpaceConfig(spaceID string) *SpaceConfig {
 	if sc, ok := c.Spaces[spaceID]; ok {
@@ -125,6 +292,66 @@ func (c *Config) GetSpaceConfig(spaceID string) *SpaceConfig {
 	return nil
 }
 
+// GetDisplayConfig returns configuration for a display, matched by UUID
+// first and then by index. A nil display has none.
+func (c *Config) GetDisplayConfig(display *DisplayRef) *DisplayConfig {
+	if display == nil {
+		return nil
+	}
+	if dc, ok := c.Displays[display.UUID]; ok && display.UUID != "" {
+		return &dc
+	}
+	if dc, ok := c.Displays[strconv.Itoa(display.Index)]; ok {
+		return &dc
+	}
+	return nil
+}
+
+// ResolveLayoutID picks the layout to apply to a space: the explicit layout
+// if given, then the space's defaultLayout, then the defaultLayout of the
+// display showing it (nil if unknown), then settings.defaultLayout.
+func (c *Config) ResolveLayoutID(spaceID string, display *DisplayRef, explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+	if sc := c.GetSpaceConfig(spaceID); sc != nil && sc.DefaultLayout != "" {
+		return sc.DefaultLayout, nil
+	}
+	if dc := c.GetDisplayConfig(display); dc != nil && dc.DefaultLayout != "" {
+		return dc.DefaultLayout, nil
+	}
+	if c.Settings.DefaultLayout != "" {
+		return c.Settings.DefaultLayout, nil
+	}
+	return "", fmt.Errorf("no layout given and no default layout configured for space %s", spaceID)
+}
+
+// Wraps reports whether focus in direction wraps at the display edge by
+// default. Diagonal directions wrap only if both axes do.
+func (w FocusWrap) Wraps(direction types.Direction) bool {
+	horizontal := w.Horizontal == nil || *w.Horizontal
+	vertical := w.Vertical == nil || *w.Vertical
+
+	switch direction {
+	case types.DirLeft, types.DirRight:
+		return horizontal
+	case types.DirUp, types.DirDown:
+		return vertical
+	default:
+		return horizontal && vertical
+	}
+}
+
+// FocusFollowsMoves reports whether a window move focuses the moved window:
+// override (from window move --then-focus/--no-focus) if set, else
+// settings.focusFollowsMove, which defaults to true.
+func (s Settings) FocusFollowsMoves(override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return s.FocusFollowsMove == nil || *s.FocusFollowsMove
+}
+
 // GetAppRule finds the first matching app rule
 func (c *Config) GetAppRule(appName, bundleID string) *AppRule {
 	for _, rule := range c.AppRules {
@@ -172,6 +399,14 @@ func (lc *LayoutConfig) ToLayout() (*types.Layout, error) {
 		}
 	}
 
+	columnGap, rowGap := lc.Grid.ColumnGap, lc.Grid.RowGap
+	if columnGap == nil {
+		columnGap = lc.Grid.Gap
+	}
+	if rowGap == nil {
+		rowGap = lc.Grid.Gap
+	}
+
 	return &types.Layout{
 		ID:          lc.ID,
 		Name:        lc.Name,
@@ -180,6 +415,8 @@ func (lc *LayoutConfig) ToLayout() (*types.Layout, error) {
 		Rows:        rows,
 		Cells:       cells,
 		CellModes:   lc.CellModes,
+		ColumnGap:   columnGap,
+		RowGap:      rowGap,
 	}, nil
 }
 
@@ -195,6 +432,13 @@ func (cc *CellConfig) ToCell() (types.Cell, error) {
 		return types.Cell{}, fmt.Errorf("invalid row span: %w", err)
 	}
 
+	var aspectRatio float64
+	if cc.AspectRatio != "" {
+		if aspectRatio, err = ParseAspectRatio(cc.AspectRatio); err != nil {
+			return types.Cell{}, err
+		}
+	}
+
 	return types.Cell{
 		ID:          cc.ID,
 		ColumnStart: colStart,
@@ -202,5 +446,10 @@ func (cc *CellConfig) ToCell() (types.Cell, error) {
 		RowStart:    rowStart,
 		RowEnd:      rowEnd,
 		StackMode:   cc.StackMode,
+		Launch:      cc.Launch,
+		Weight:      cc.Weight,
+		AspectRatio: aspectRatio,
+
+		SkipNavigation: cc.SkipNavigation,
 	}, nil
 }
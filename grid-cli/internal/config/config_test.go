@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/yourusername/grid-cli/internal/types"
@@ -57,6 +62,41 @@ func TestParseTrackSize(t *testing.T) {
 	}
 }
 
+func TestParseAspectRatio(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		hasError bool
+	}{
+		{"16:9", 16.0 / 9.0, false},
+		{"4:3", 4.0 / 3.0, false},
+		{" 21:9 ", 21.0 / 9.0, false},
+		{"1.5:1", 1.5, false},
+		{"16x9", 0, true},
+		{"0:9", 0, true},
+		{"16:0", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAspectRatio(tt.input)
+			if tt.hasError {
+				if err == nil {
+					t.Errorf("ParseAspectRatio(%q) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAspectRatio(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseAspectRatio(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestFormatTrackSize(t *testing.T) {
 	tests := []struct {
 		input    types.TrackSize
@@ -200,6 +240,44 @@ layouts:
 	}
 }
 
+func TestLoadConfigFromBytes_DisabledLayout(t *testing.T) {
+	valid := `
+layouts:
+  - id: spare
+    disabled: true
+    grid:
+      columns: ["1fr"]
+      rows: ["1fr"]
+    areas:
+      - [main]
+`
+	cfg, err := LoadConfigFromBytes([]byte(valid), "yaml")
+	if err != nil {
+		t.Fatalf("LoadConfigFromBytes() error: %v", err)
+	}
+	if !cfg.LayoutDisabled("spare") {
+		t.Error("expected layout spare to be disabled")
+	}
+	if cfg.LayoutDisabled("missing") {
+		t.Error("unknown layout should not be reported disabled")
+	}
+
+	// Disabled layouts are still validated
+	invalid := `
+layouts:
+  - id: spare
+    disabled: true
+    grid:
+      columns: ["1fr"]
+      rows: ["1fr"]
+    areas:
+      - [main, extra]
+`
+	if _, err := LoadConfigFromBytes([]byte(invalid), "yaml"); err == nil {
+		t.Error("expected validation error for disabled layout with bad areas")
+	}
+}
+
 func TestLoadConfigFromBytes_JSON(t *testing.T) {
 	jsonConfig := `{
   "settings": {
@@ -276,6 +354,113 @@ func TestLayoutConfigToLayout(t *testing.T) {
 	}
 }
 
+func TestLayoutConfigToLayout_CellLaunchAndWeight(t *testing.T) {
+	lc := LayoutConfig{
+		ID:   "test",
+		Grid: GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+		Cells: []CellConfig{
+			{ID: "a", Column: "1/2", Row: "1/2", Launch: "com.apple.Terminal"},
+			{ID: "b", Column: "2/3", Row: "1/2", Weight: 2},
+		},
+	}
+
+	layout, err := lc.ToLayout()
+	if err != nil {
+		t.Fatalf("ToLayout() error: %v", err)
+	}
+
+	if layout.Cells[0].Launch != "com.apple.Terminal" {
+		t.Errorf("Cells[0].Launch = %q, want com.apple.Terminal", layout.Cells[0].Launch)
+	}
+	if layout.Cells[1].Weight != 2 {
+		t.Errorf("Cells[1].Weight = %d, want 2", layout.Cells[1].Weight)
+	}
+}
+
+func TestValidation_NegativeCellWeight(t *testing.T) {
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "w", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Cells: []CellConfig{{ID: "a", Column: "1/2", Row: "1/2", Weight: -1}}},
+		},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "weight") {
+		t.Errorf("expected weight error, got %v", err)
+	}
+}
+
+func TestValidation_InvalidAspectRatio(t *testing.T) {
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "r", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Cells: []CellConfig{{ID: "a", Column: "1/2", Row: "1/2", AspectRatio: "wide"}}},
+		},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "aspect ratio") {
+		t.Errorf("expected aspectRatio error, got %v", err)
+	}
+}
+
+func TestValidation_CrossDisplayMapping(t *testing.T) {
+	cfg := Config{Settings: Settings{CrossDisplayMapping: CrossDisplayNearestEdge}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	cfg.Settings.CrossDisplayMapping = "closest"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "cross-display mapping") {
+		t.Errorf("expected cross-display mapping error, got %v", err)
+	}
+}
+
+func TestLayoutConfigToLayout_Gaps(t *testing.T) {
+	yamlConfig := `
+layouts:
+  - id: gapped
+    grid:
+      columns: ["1fr", "1fr"]
+      rows: ["1fr", "1fr"]
+      gap: 8
+      rowGap: 20
+    areas:
+      - [a, b]
+      - [c, d]
+`
+	cfg, err := LoadConfigFromBytes([]byte(yamlConfig), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout, err := cfg.GetLayout("gapped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.ColumnGap == nil || *layout.ColumnGap != 8 {
+		t.Errorf("ColumnGap = %v, want 8 from the gap shorthand", layout.ColumnGap)
+	}
+	if layout.RowGap == nil || *layout.RowGap != 20 {
+		t.Errorf("RowGap = %v, want 20", layout.RowGap)
+	}
+
+	// No gaps configured: the layout leaves them to settings.cellPadding
+	plain := LayoutConfig{ID: "plain", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"a"}}}
+	layout, err = plain.ToLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.ColumnGap != nil || layout.RowGap != nil {
+		t.Errorf("expected unset gaps, got %v/%v", layout.ColumnGap, layout.RowGap)
+	}
+}
+
+func TestValidation_NegativeGap(t *testing.T) {
+	gap := -4.0
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "g", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}, RowGap: &gap}, Cells: []CellConfig{{ID: "a", Column: "1/2", Row: "1/2"}}},
+		},
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "rowGap") {
+		t.Errorf("expected rowGap error, got %v", err)
+	}
+}
+
 func TestValidation_DuplicateLayoutID(t *testing.T) {
 	cfg := Config{
 		Layouts: []LayoutConfig{
@@ -289,6 +474,27 @@ func TestValidation_DuplicateLayoutID(t *testing.T) {
 	}
 }
 
+func TestValidation_UnknownDisplayLayout(t *testing.T) {
+	layouts := []LayoutConfig{
+		{ID: "one", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Cells: []CellConfig{{ID: "a", Column: "1/2", Row: "1/2"}}},
+	}
+
+	cfg := Config{Layouts: layouts, Displays: map[string]DisplayConfig{"0": {Layouts: []string{"one", "missing"}}}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "display 0 references unknown layout: missing") {
+		t.Errorf("expected unknown layout error, got %v", err)
+	}
+
+	cfg = Config{Layouts: layouts, Displays: map[string]DisplayConfig{"SIDE-UUID": {DefaultLayout: "missing"}}}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "display SIDE-UUID has unknown default layout: missing") {
+		t.Errorf("expected unknown default layout error, got %v", err)
+	}
+
+	cfg = Config{Layouts: layouts, Displays: map[string]DisplayConfig{"0": {Layouts: []string{"one"}, DefaultLayout: "one"}}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid display config, got %v", err)
+	}
+}
+
 func TestValidation_MissingCellsAndAreas(t *testing.T) {
 	cfg := Config{
 		Layouts: []LayoutConfig{
@@ -374,6 +580,34 @@ func TestValidation_AreasDimensionMismatch(t *testing.T) {
 	}
 }
 
+func TestValidation_ResizeSnapPoints(t *testing.T) {
+	for _, points := range [][]float64{{0}, {1}, {0.5, 1.5}} {
+		cfg := Config{Settings: Settings{ResizeSnapPoints: points}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for snap points %v", points)
+		}
+	}
+
+	cfg := Config{Settings: Settings{ResizeSnapPoints: []float64{0.25, 0.75}}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidation_SizeIncrement(t *testing.T) {
+	for _, inc := range [][]float64{{8}, {8, 16, 1}, {-8, 16}} {
+		cfg := Config{AppRules: []AppRule{{App: "Terminal", SizeIncrement: inc}}}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for sizeIncrement %v", inc)
+		}
+	}
+
+	cfg := Config{AppRules: []AppRule{{App: "Terminal", SizeIncrement: []float64{8, 16}}}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestGetLayout(t *testing.T) {
 	cfg := Config{
 		Layouts: []LayoutConfig{
@@ -396,6 +630,63 @@ func TestGetLayout(t *testing.T) {
 	}
 }
 
+func TestResolveLayoutID(t *testing.T) {
+	cfg := Config{
+		Spaces: map[string]SpaceConfig{
+			"1": {DefaultLayout: "ide"},
+			"2": {Layouts: []string{"ide"}},
+		},
+		Displays: map[string]DisplayConfig{
+			"SIDE-UUID": {DefaultLayout: "rows"},
+			"2":         {DefaultLayout: "thirds"},
+		},
+		Settings: Settings{DefaultLayout: "full"},
+	}
+	main := &DisplayRef{UUID: "MAIN-UUID", Index: 0}
+	side := &DisplayRef{UUID: "SIDE-UUID", Index: 1}
+	third := &DisplayRef{UUID: "OTHER-UUID", Index: 2}
+
+	tests := []struct {
+		name     string
+		spaceID  string
+		display  *DisplayRef
+		explicit string
+		want     string
+	}{
+		{"explicit wins", "1", side, "columns", "columns"},
+		{"space default", "1", side, "", "ide"},
+		{"display default by UUID", "2", side, "", "rows"},
+		{"display default by index", "2", third, "", "thirds"},
+		{"global default for unconfigured display", "2", main, "", "full"},
+		{"global default for space without one", "2", nil, "", "full"},
+		{"global default for unconfigured space", "9", nil, "", "full"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.ResolveLayoutID(tt.spaceID, tt.display, tt.explicit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveLayoutID(%q, %q) = %q, want %q", tt.spaceID, tt.explicit, got, tt.want)
+			}
+		})
+	}
+
+	cfg.Settings.DefaultLayout = ""
+	if _, err := cfg.ResolveLayoutID("2", main, ""); err == nil {
+		t.Error("expected error with no layout and no defaults")
+	}
+}
+
+func TestValidation_UnknownGlobalDefaultLayout(t *testing.T) {
+	cfg := Config{Settings: Settings{DefaultLayout: "missing"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown settings.defaultLayout")
+	}
+}
+
 func TestGetLayoutIDs(t *testing.T) {
 	cfg := Config{
 		Layouts: []LayoutConfig{
@@ -458,3 +749,322 @@ func TestIsRectangular(t *testing.T) {
 		})
 	}
 }
+
+func TestCheck_AggregatesIssues(t *testing.T) {
+	grid := GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}}
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "good", Grid: grid, Cells: []CellConfig{{ID: "left", Column: "1/2", Row: "1/2"}, {ID: "right", Column: "2/3", Row: "1/2"}}},
+			{ID: "overlap", Grid: grid, Cells: []CellConfig{{ID: "a", Column: "1/3", Row: "1/2"}, {ID: "b", Column: "2/3", Row: "1/2"}}},
+			{ID: "broken", Grid: grid},
+		},
+		Spaces: map[string]SpaceConfig{
+			"1": {Layouts: []string{"good", "missing"}},
+		},
+		AppRules: []AppRule{
+			{App: "Safari", PreferredCell: "nowhere"},
+			{App: "Finder", Float: true, PreferredCell: "left"},
+		},
+		Settings: Settings{CellPadding: -1},
+	}
+
+	report := Report{Issues: cfg.Check()}
+
+	// Validate's first error: the broken layout
+	if report.Errors() != 1 {
+		t.Errorf("expected 1 error, got %d: %+v", report.Errors(), report.Issues)
+	}
+	if err := cfg.Validate(); err == nil || report.Issues[0].Message != err.Error() {
+		t.Errorf("expected the error to be Validate's, got %+v", report.Issues[0])
+	}
+	// overlapping cells, unknown preferredCell, float + preferredCell
+	if report.Warnings() != 3 {
+		t.Errorf("expected 3 warnings, got %d: %+v", report.Warnings(), report.Issues)
+	}
+}
+
+func TestCheck_CleanConfig(t *testing.T) {
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "one", Grid: GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Cells: []CellConfig{{ID: "main", Column: "1/2", Row: "1/2"}}},
+		},
+		Spaces: map[string]SpaceConfig{
+			"1": {Layouts: []string{"one"}, DefaultLayout: "one"},
+		},
+	}
+
+	if issues := cfg.Check(); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCheckFit(t *testing.T) {
+	gap := 20.0
+	cfg := Config{
+		Layouts: []LayoutConfig{
+			{ID: "flex", Grid: GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"a", "b"}}},
+			// 800 + 20 gap + 300 = 1120 wide; 700 + 10 gap + 300 = 1010 tall
+			{ID: "sidebar", Grid: GridConfig{Columns: []string{"800px", "minmax(300px, 1fr)"}, Rows: []string{"700px", "300px"}, ColumnGap: &gap},
+				Areas: [][]string{{"a", "b"}, {"a", "c"}}},
+		},
+		Settings: Settings{CellPadding: 10},
+	}
+
+	issues := cfg.CheckFit([]FitDisplay{
+		{Name: "laptop", Width: 1100, Height: 1000},
+		{Name: "monitor", Width: 2560, Height: 1440},
+	})
+
+	want := []Issue{
+		{Severity: SeverityWarning, Scope: "layout sidebar", Message: "columns need at least 1120px but display laptop is 1100px wide"},
+		{Severity: SeverityWarning, Scope: "layout sidebar", Message: "rows need at least 1010px but display laptop is 1000px tall"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("issues = %+v, want %+v", issues, want)
+	}
+}
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// includeLayout renders a one-cell layout list entry for include tests
+func includeLayout(id, name string) string {
+	return fmt.Sprintf(`
+  - id: %s
+    name: %s
+    grid:
+      columns: ["1fr"]
+      rows: ["1fr"]
+    areas:
+      - ["main"]
+`, id, name)
+}
+
+func TestLoadConfig_Includes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), `
+includes:
+  - layouts/extra.yaml
+  - rules.yaml
+settings:
+  cellPadding: 8
+layouts:
+`+includeLayout("full", "Main Full"))
+	writeConfigFile(t, filepath.Join(dir, "layouts", "extra.yaml"), `
+settings:
+  cellPadding: 99
+layouts:
+`+includeLayout("full", "Included Full")+includeLayout("focus", "Focus"))
+	writeConfigFile(t, filepath.Join(dir, "rules.yaml"), `
+appRules:
+  - app: Finder
+    float: true
+`)
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	ids := cfg.GetLayoutIDs()
+	if len(ids) != 2 || ids[0] != "full" || ids[1] != "focus" {
+		t.Fatalf("layout IDs = %v, want [full focus]", ids)
+	}
+	if cfg.Layouts[0].Name != "Main Full" {
+		t.Errorf("including file should win on ID conflict, got %q", cfg.Layouts[0].Name)
+	}
+	if len(cfg.AppRules) != 1 || cfg.AppRules[0].App != "Finder" {
+		t.Errorf("expected app rule from rules.yaml, got %+v", cfg.AppRules)
+	}
+	if cfg.Settings.CellPadding != 8 {
+		t.Errorf("settings should come from the including file, got cellPadding %d", cfg.Settings.CellPadding)
+	}
+}
+
+func TestLoadConfig_LaterIncludeWins(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), `
+includes: [a.yaml, b.yaml]
+`)
+	writeConfigFile(t, filepath.Join(dir, "a.yaml"), `
+appRules:
+  - app: Finder
+    preferredCell: left
+`)
+	writeConfigFile(t, filepath.Join(dir, "b.yaml"), `
+appRules:
+  - app: Finder
+    preferredCell: right
+`)
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("ParseConfigFile() error: %v", err)
+	}
+	if len(cfg.AppRules) != 1 || cfg.AppRules[0].PreferredCell != "right" {
+		t.Errorf("expected later include to win, got %+v", cfg.AppRules)
+	}
+}
+
+func TestLoadConfig_IncludesKeepSameAppRules(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), `
+includes: [rules.yaml]
+appRules:
+  - app: Safari
+    preferredCell: left
+    layouts: [half]
+  - app: Safari
+    preferredCell: main
+  - app: Finder
+    float: true
+`)
+	writeConfigFile(t, filepath.Join(dir, "rules.yaml"), `
+appRules:
+  - app: Safari
+    float: true
+  - app: Mail
+    preferredCell: right
+`)
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("ParseConfigFile() error: %v", err)
+	}
+
+	// The main file's two Safari rules both survive and override the include's
+	var got []string
+	for _, r := range cfg.AppRules {
+		got = append(got, r.App+":"+r.PreferredCell)
+	}
+	want := []string{"Safari:left", "Safari:main", "Finder:", "Mail:right"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("app rules = %v, want %v", got, want)
+	}
+}
+
+func TestLoadConfig_CircularInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), "includes: [a.yaml]\n")
+	writeConfigFile(t, filepath.Join(dir, "a.yaml"), "includes: [sub/b.yaml]\n")
+	writeConfigFile(t, filepath.Join(dir, "sub", "b.yaml"), "includes: [../config.yaml]\n")
+
+	_, err := ParseConfigFile(filepath.Join(dir, "config.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "circular include") {
+		t.Fatalf("expected circular include error, got %v", err)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GRID_TEST_DIR", "/opt/grid")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"~/bin/hook", home + "/bin/hook"},
+		{"~", home},
+		{"$HOME/layouts.yaml", home + "/layouts.yaml"},
+		{"${GRID_TEST_DIR}/rules.yaml", "/opt/grid/rules.yaml"},
+		{"rules.yaml", "rules.yaml"},
+		{"~other/rules.yaml", "~other/rules.yaml"},
+	}
+	for _, tt := range tests {
+		if got := ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_ExpandsIncludePaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GRID_TEST_LAYOUTS", "shared")
+	writeConfigFile(t, filepath.Join(home, "grid", "home.yaml"), "layouts:\n"+includeLayout("home", "Home"))
+	writeConfigFile(t, filepath.Join(home, "shared", "env.yaml"), "layouts:\n"+includeLayout("env", "Env"))
+
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "config.yaml"), `
+includes:
+  - ~/grid/home.yaml
+  - $HOME/${GRID_TEST_LAYOUTS}/env.yaml
+layouts:
+`+includeLayout("$HOME", "~/not-a-path"))
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConfig() error: %v", err)
+	}
+
+	ids := cfg.GetLayoutIDs()
+	if len(ids) != 3 || ids[1] != "home" || ids[2] != "env" {
+		t.Fatalf("layout IDs = %v, want [$HOME home env]", ids)
+	}
+	// Layout IDs and names are not paths and stay as written
+	if ids[0] != "$HOME" || cfg.Layouts[0].Name != "~/not-a-path" {
+		t.Errorf("non-path fields were expanded: id %q, name %q", ids[0], cfg.Layouts[0].Name)
+	}
+}
+
+func TestFocusWrap_Wraps(t *testing.T) {
+	on, off := true, false
+
+	// Unset axes wrap, like the --wrap flag default
+	var unset FocusWrap
+	if !unset.Wraps(types.DirUp) || !unset.Wraps(types.DirLeft) {
+		t.Error("expected unset focus wrap to wrap on both axes")
+	}
+
+	w := FocusWrap{Horizontal: &on, Vertical: &off}
+	if !w.Wraps(types.DirLeft) || !w.Wraps(types.DirRight) {
+		t.Error("expected horizontal focus to wrap")
+	}
+	if w.Wraps(types.DirUp) || w.Wraps(types.DirDown) {
+		t.Error("expected vertical focus not to wrap")
+	}
+	if w.Wraps(types.DirUpLeft) {
+		t.Error("expected diagonal focus to wrap only when both axes do")
+	}
+}
+
+func TestSettings_FocusFollowsMoves(t *testing.T) {
+	on, off := true, false
+
+	if !(Settings{}).FocusFollowsMoves(nil) {
+		t.Error("expected moves to focus the window by default")
+	}
+	s := Settings{FocusFollowsMove: &off}
+	if s.FocusFollowsMoves(nil) {
+		t.Error("expected focusFollowsMove: false to keep focus")
+	}
+	if !s.FocusFollowsMoves(&on) {
+		t.Error("expected --then-focus to override the setting")
+	}
+	if (Settings{}).FocusFollowsMoves(&off) {
+		t.Error("expected --no-focus to override the default")
+	}
+}
+
+func TestLoadConfigFromBytes_FocusWrap(t *testing.T) {
+	cfg, err := LoadConfigFromBytes([]byte(`
+settings:
+  focus:
+    wrap:
+      horizontal: true
+      vertical: false
+`), "yaml")
+	if err != nil {
+		t.Fatalf("LoadConfigFromBytes() error: %v", err)
+	}
+	if cfg.Settings.Focus.Wrap.Wraps(types.DirDown) || !cfg.Settings.Focus.Wrap.Wraps(types.DirRight) {
+		t.Errorf("unexpected focus wrap: %+v", cfg.Settings.Focus.Wrap)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// maxGenerateTracks bounds the columns and rows Generate accepts
+const maxGenerateTracks = 12
+
+// GenerateOptions describes the config Generate builds
+type GenerateOptions struct {
+	Columns int      // Equal-width columns in the generated grid layout
+	Rows    int      // Equal-height rows in the generated grid layout
+	Spaces  []string // Space IDs to configure with the generated layouts
+}
+
+// Generate builds a starter config: a grid layout of equal fr tracks with
+// one cell per slot (cell1, cell2, … in reading order), a single-cell "full"
+// layout to cycle to, and a spaces entry for each requested space. The grid
+// layout is also settings.defaultLayout. The result is validated.
+func Generate(opts GenerateOptions) (*Config, error) {
+	if opts.Columns < 1 || opts.Columns > maxGenerateTracks || opts.Rows < 1 || opts.Rows > maxGenerateTracks {
+		return nil, fmt.Errorf("columns and rows must be between 1 and %d, got %dx%d", maxGenerateTracks, opts.Columns, opts.Rows)
+	}
+
+	full := LayoutConfig{
+		ID:    "full",
+		Name:  "Full",
+		Grid:  GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}},
+		Areas: [][]string{{"main"}},
+	}
+	layouts := []LayoutConfig{full}
+	defaultLayout := full.ID
+
+	if opts.Columns > 1 || opts.Rows > 1 {
+		grid := LayoutConfig{
+			ID:   fmt.Sprintf("grid-%dx%d", opts.Columns, opts.Rows),
+			Name: fmt.Sprintf("%dx%d Grid", opts.Columns, opts.Rows),
+			Grid: GridConfig{Columns: equalTracks(opts.Columns), Rows: equalTracks(opts.Rows)},
+		}
+		for row := 0; row < opts.Rows; row++ {
+			names := make([]string, opts.Columns)
+			for col := range names {
+				names[col] = fmt.Sprintf("cell%d", row*opts.Columns+col+1)
+			}
+			grid.Areas = append(grid.Areas, names)
+		}
+		layouts = []LayoutConfig{grid, full}
+		defaultLayout = grid.ID
+	}
+
+	layoutIDs := make([]string, len(layouts))
+	for i, l := range layouts {
+		layoutIDs[i] = l.ID
+	}
+
+	cfg := &Config{
+		Settings: Settings{
+			DefaultStackMode:  types.StackVertical,
+			CellPadding:       8,
+			AnimationDuration: 0.2,
+			DefaultLayout:     defaultLayout,
+		},
+		Layouts: layouts,
+	}
+	for _, spaceID := range opts.Spaces {
+		spaceID = strings.TrimSpace(spaceID)
+		if spaceID == "" {
+			continue
+		}
+		if cfg.Spaces == nil {
+			cfg.Spaces = make(map[string]SpaceConfig)
+		}
+		cfg.Spaces[spaceID] = SpaceConfig{Layouts: layoutIDs, DefaultLayout: defaultLayout}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("generated config is invalid: %w", err)
+	}
+	return cfg, nil
+}
+
+// equalTracks returns n "1fr" track sizes
+func equalTracks(n int) []string {
+	tracks := make([]string, n)
+	for i := range tracks {
+		tracks[i] = "1fr"
+	}
+	return tracks
+}
+
+// MarshalConfigYAML renders a config as YAML, with layouts written in flow
+// style like MarshalLayoutYAML and each space's layout list on one line
+func MarshalConfigYAML(cfg *Config) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if layouts := mappingValue(&node, "layouts"); layouts != nil {
+		for _, layout := range layouts.Content {
+			flowLayout(layout)
+		}
+	}
+	if spaces := mappingValue(&node, "spaces"); spaces != nil {
+		for i := 1; i < len(spaces.Content); i += 2 {
+			if ids := mappingValue(spaces.Content[i], "layouts"); ids != nil {
+				ids.Style = yaml.FlowStyle
+			}
+		}
+	}
+	return encodeYAML(&node)
+}
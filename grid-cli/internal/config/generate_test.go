@@ -0,0 +1,75 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_FromDimensions(t *testing.T) {
+	cfg, err := Generate(GenerateOptions{Columns: 3, Rows: 2, Spaces: []string{"1", " 2 "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid, err := cfg.GetLayout("grid-3x2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(grid.Columns) != 3 || len(grid.Rows) != 2 || len(grid.Cells) != 6 {
+		t.Errorf("expected a 3x2 grid with 6 cells, got %d columns, %d rows, %d cells", len(grid.Columns), len(grid.Rows), len(grid.Cells))
+	}
+	if cfg.Settings.DefaultLayout != "grid-3x2" {
+		t.Errorf("defaultLayout = %q, want grid-3x2", cfg.Settings.DefaultLayout)
+	}
+
+	want := SpaceConfig{Layouts: []string{"grid-3x2", "full"}, DefaultLayout: "grid-3x2"}
+	for _, id := range []string{"1", "2"} {
+		if got := cfg.Spaces[id]; !reflect.DeepEqual(got, want) {
+			t.Errorf("space %s = %+v, want %+v", id, got, want)
+		}
+	}
+}
+
+func TestGenerate_RoundTripsThroughYAML(t *testing.T) {
+	cfg, err := Generate(GenerateOptions{Columns: 2, Rows: 1, Spaces: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalConfigYAML(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "columns: [1fr, 1fr]") {
+		t.Errorf("expected flow-style tracks, got:\n%s", data)
+	}
+
+	loaded, err := LoadConfigFromBytes(data, "yaml")
+	if err != nil {
+		t.Fatalf("generated YAML doesn't load: %v\n%s", err, data)
+	}
+	if _, err := loaded.GetLayout("grid-2x1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerate_SingleCell(t *testing.T) {
+	cfg, err := Generate(GenerateOptions{Columns: 1, Rows: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Layouts) != 1 || cfg.Layouts[0].ID != "full" || cfg.Settings.DefaultLayout != "full" {
+		t.Errorf("expected only the full layout, got %+v", cfg.Layouts)
+	}
+	if cfg.Spaces != nil {
+		t.Errorf("expected no spaces, got %+v", cfg.Spaces)
+	}
+}
+
+func TestGenerate_InvalidDimensions(t *testing.T) {
+	for _, opts := range []GenerateOptions{{Columns: 0, Rows: 1}, {Columns: 2, Rows: 13}} {
+		if _, err := Generate(opts); err == nil {
+			t.Errorf("expected an error for %dx%d", opts.Columns, opts.Rows)
+		}
+	}
+}
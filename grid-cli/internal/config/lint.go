@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// Severity indicates how serious a config issue is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // Config will be rejected
+	SeverityWarning Severity = "warning" // Config loads but likely isn't what was meant
+)
+
+// Issue is a single problem found while checking a config
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Scope    string   `json:"scope"` // What the issue is about, e.g. "layout two-column"
+	Message  string   `json:"message"`
+}
+
+// Report aggregates every issue found in a config in a single pass
+type Report struct {
+	Path   string  `json:"path"`
+	Issues []Issue `json:"issues"`
+}
+
+// Errors returns the number of error-severity issues
+func (r *Report) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of warning-severity issues
+func (r *Report) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *Report) count(severity Severity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Check validates the config and adds lint warnings on top: things
+// Validate accepts but that likely aren't what was meant. Validate stops at
+// the first error, so there is at most one error issue.
+func (c *Config) Check() []Issue {
+	var issues []Issue
+	if err := c.Validate(); err != nil {
+		issues = append(issues, Issue{Severity: SeverityError, Scope: "config", Message: err.Error()})
+	}
+	return append(issues, c.lint()...)
+}
+
+// lint collects every warning-level issue. Anything that fails validation
+// is left to Validate.
+func (c *Config) lint() []Issue {
+	var issues []Issue
+	warn := func(scope, format string, args ...interface{}) {
+		issues = append(issues, Issue{Severity: SeverityWarning, Scope: scope, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Layouts
+	layoutIDs := make(map[string]bool)
+	cellIDs := make(map[string]bool) // Cells across all layouts
+	for _, layout := range c.Layouts {
+		layoutIDs[layout.ID] = true
+		l, err := layout.ToLayout()
+		if err != nil {
+			continue
+		}
+		scope := fmt.Sprintf("layout %s", layout.ID)
+		for _, cell := range l.Cells {
+			cellIDs[cell.ID] = true
+		}
+		for _, pair := range overlappingCells(l.Cells) {
+			warn(scope, "cells %s and %s overlap", pair[0], pair[1])
+		}
+		for cellID := range layout.CellModes {
+			if !layoutHasCell(l.Cells, cellID) {
+				warn(scope, "cellModes references unknown cell: %s", cellID)
+			}
+		}
+	}
+
+	// Spaces
+	for _, spaceID := range sortedSpaceIDs(c.Spaces) {
+		spaceConfig := c.Spaces[spaceID]
+		scope := fmt.Sprintf("space %s", spaceID)
+		if spaceConfig.DefaultLayout != "" && len(spaceConfig.Layouts) > 0 && !containsString(spaceConfig.Layouts, spaceConfig.DefaultLayout) {
+			warn(scope, "default layout %s is not in its layout cycle", spaceConfig.DefaultLayout)
+		}
+		if spaceConfig.AutoApply && spaceConfig.DefaultLayout == "" {
+			warn(scope, "autoApply is set but no defaultLayout is configured")
+		}
+	}
+
+	// Displays
+	for _, key := range sortedDisplayKeys(c.Displays) {
+		displayConfig := c.Displays[key]
+		if displayConfig.DefaultLayout != "" && len(displayConfig.Layouts) > 0 && !containsString(displayConfig.Layouts, displayConfig.DefaultLayout) {
+			warn(fmt.Sprintf("display %s", key), "default layout %s is not in its layout cycle", displayConfig.DefaultLayout)
+		}
+	}
+
+	// App rules
+	seenApps := make(map[string]bool)
+	for _, rule := range c.AppRules {
+		if rule.App == "" {
+			continue
+		}
+		scope := fmt.Sprintf("appRule %s", rule.App)
+		if seenApps[rule.App] {
+			warn(scope, "duplicate rule; only the first match applies")
+		}
+		seenApps[rule.App] = true
+		if rule.Float && rule.PreferredCell != "" {
+			warn(scope, "preferredCell is ignored for floating apps")
+		}
+		if rule.PreferredCell != "" && !cellIDs[rule.PreferredCell] {
+			warn(scope, "preferredCell %s does not exist in any layout", rule.PreferredCell)
+		}
+		for _, layoutID := range rule.Layouts {
+			if !layoutIDs[layoutID] {
+				warn(scope, "references unknown layout: %s", layoutID)
+			}
+		}
+	}
+
+	return issues
+}
+
+// FitDisplay is a connected display's usable size, for CheckFit
+type FitDisplay struct {
+	Name   string
+	Width  float64
+	Height float64
+}
+
+// CheckFit warns about layouts whose fixed tracks (px sizes and minmax
+// minimums) plus gaps need more room than a display has, so their cells
+// would be squeezed or pushed off screen there. Layouts that don't parse
+// are left to Check.
+func (c *Config) CheckFit(displays []FitDisplay) []Issue {
+	var issues []Issue
+	for _, layoutConfig := range c.Layouts {
+		layout, err := layoutConfig.ToLayout()
+		if err != nil {
+			continue
+		}
+		columnGap, rowGap := float64(c.Settings.CellPadding), float64(c.Settings.CellPadding)
+		if layout.ColumnGap != nil {
+			columnGap = *layout.ColumnGap
+		}
+		if layout.RowGap != nil {
+			rowGap = *layout.RowGap
+		}
+		width := fixedTrackSize(layout.Columns, columnGap)
+		height := fixedTrackSize(layout.Rows, rowGap)
+
+		scope := fmt.Sprintf("layout %s", layout.ID)
+		for _, display := range displays {
+			if width > display.Width {
+				issues = append(issues, Issue{Severity: SeverityWarning, Scope: scope,
+					Message: fmt.Sprintf("columns need at least %.0fpx but display %s is %.0fpx wide", width, display.Name, display.Width)})
+			}
+			if height > display.Height {
+				issues = append(issues, Issue{Severity: SeverityWarning, Scope: scope,
+					Message: fmt.Sprintf("rows need at least %.0fpx but display %s is %.0fpx tall", height, display.Name, display.Height)})
+			}
+		}
+	}
+	return issues
+}
+
+// fixedTrackSize is the space tracks take up before any fr share: px
+// tracks, minmax minimums and the gaps between tracks.
+func fixedTrackSize(tracks []types.TrackSize, gap float64) float64 {
+	total := 0.0
+	for _, track := range tracks {
+		switch track.Type {
+		case types.TrackPx:
+			total += track.Value
+		case types.TrackMinMax:
+			total += track.Min
+		}
+	}
+	if len(tracks) > 1 {
+		total += gap * float64(len(tracks)-1)
+	}
+	return total
+}
+
+// overlappingCells returns pairs of cell IDs whose grid spans intersect.
+func overlappingCells(cells []types.Cell) [][2]string {
+	var pairs [][2]string
+	for i := 0; i < len(cells); i++ {
+		for j := i + 1; j < len(cells); j++ {
+			a, b := cells[i], cells[j]
+			if a.ColumnStart < b.ColumnEnd && b.ColumnStart < a.ColumnEnd &&
+				a.RowStart < b.RowEnd && b.RowStart < a.RowEnd {
+				pairs = append(pairs, [2]string{a.ID, b.ID})
+			}
+		}
+	}
+	return pairs
+}
+
+func layoutHasCell(cells []types.Cell, cellID string) bool {
+	for _, cell := range cells {
+		if cell.ID == cellID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedSpaceIDs(spaces map[string]SpaceConfig) []string {
+	ids := make([]string, 0, len(spaces))
+	for id := range spaces {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedDisplayKeys(displays map[string]DisplayConfig) []string {
+	keys := make([]string, 0, len(displays))
+	for key := range displays {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
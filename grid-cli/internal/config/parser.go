@@ -14,6 +14,9 @@ var (
 	frPattern     = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*fr$`)
 	pxPattern     = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*px$`)
 	minmaxPattern = regexp.MustCompile(`^minmax\s*\(\s*(\d+(?:\.\d+)?)\s*px\s*,\s*(\d+(?:\.\d+)?)\s*fr\s*\)$`)
+
+	// Aspect ratio pattern, "width:height"
+	aspectRatioPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*:\s*(\d+(?:\.\d+)?)$`)
 )
 
 // ParseTrackSize parses a track size string into a TrackSize struct
@@ -52,6 +55,21 @@ func ParseTrackSize(s string) (types.TrackSize, error) {
 	return types.TrackSize{}, fmt.Errorf("invalid track size format: %s", s)
 }
 
+// ParseAspectRatio parses a "width:height" aspect ratio such as "16:9" or
+// "2.39:1" into width divided by height. Both sides must be positive.
+func ParseAspectRatio(s string) (float64, error) {
+	matches := aspectRatioPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid aspect ratio %q (use width:height, e.g. 16:9)", s)
+	}
+	width, _ := strconv.ParseFloat(matches[1], 64)
+	height, _ := strconv.ParseFloat(matches[2], 64)
+	if width <= 0 || height <= 0 {
+		return 0, fmt.Errorf("invalid aspect ratio %q: both sides must be positive", s)
+	}
+	return width / height, nil
+}
+
 // AreasToCell converts an areas grid to cell definitions
 // Areas format:
 //
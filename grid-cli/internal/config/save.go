@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalLayoutYAML renders a layout as a one-item YAML list, ready to paste
+// under a config's layouts: key.
+func MarshalLayoutYAML(lc LayoutConfig) ([]byte, error) {
+	node, err := layoutNode(lc)
+	if err != nil {
+		return nil, err
+	}
+	return encodeYAML(&yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{node}})
+}
+
+// SaveLayout adds a layout to a YAML config file, keeping the rest of the
+// file (including comments) as it is. An existing layout with the same ID
+// is an error unless replace is set, in which case it's replaced in place.
+func SaveLayout(path string, lc LayoutConfig, replace bool) error {
+	if err := validateLayout(&lc); err != nil {
+		return fmt.Errorf("layout %s: %w", lc.ID, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("saving layouts needs a YAML config file, not %s", filepath.Base(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc.Kind == 0 {
+		// Empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file is not a YAML mapping")
+	}
+
+	layouts := mappingValue(root, "layouts")
+	switch {
+	case layouts == nil:
+		layouts = &yaml.Node{Kind: yaml.SequenceNode}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "layouts"}, layouts)
+	case layouts.Kind == yaml.ScalarNode && layouts.Tag == "!!null":
+		// layouts: with no value
+		*layouts = yaml.Node{Kind: yaml.SequenceNode}
+	case layouts.Kind != yaml.SequenceNode:
+		return fmt.Errorf("layouts in %s is not a list", filepath.Base(path))
+	}
+
+	node, err := layoutNode(lc)
+	if err != nil {
+		return err
+	}
+
+	existing := -1
+	for i, item := range layouts.Content {
+		if id := mappingValue(item, "id"); id != nil && id.Value == lc.ID {
+			existing = i
+			break
+		}
+	}
+	switch {
+	case existing >= 0 && !replace:
+		return fmt.Errorf("layout %s already exists (use --force to overwrite)", lc.ID)
+	case existing >= 0:
+		layouts.Content[existing] = node
+	default:
+		layouts.Content = append(layouts.Content, node)
+	}
+
+	out, err := encodeYAML(&doc)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// FileHasLayout reports whether the config file at path itself defines the
+// layout, leaving its includes out. SaveLayout only writes that file.
+func FileHasLayout(path, layoutID string) (bool, error) {
+	cfg, err := parseConfigData(path)
+	if err != nil {
+		return false, err
+	}
+	for _, lc := range cfg.Layouts {
+		if lc.ID == layoutID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// layoutNode encodes a layout with grid tracks and area rows in flow style,
+// the way layouts are written by hand.
+func layoutNode(lc LayoutConfig) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(lc); err != nil {
+		return nil, fmt.Errorf("failed to encode layout: %w", err)
+	}
+	flowLayout(&node)
+	return &node, nil
+}
+
+// flowLayout switches an encoded layout's grid tracks and area rows to flow
+// style
+func flowLayout(node *yaml.Node) {
+	if grid := mappingValue(node, "grid"); grid != nil {
+		for _, key := range []string{"columns", "rows"} {
+			if tracks := mappingValue(grid, key); tracks != nil {
+				tracks.Style = yaml.FlowStyle
+			}
+		}
+	}
+	if areas := mappingValue(node, "areas"); areas != nil {
+		for _, row := range areas.Content {
+			row.Style = yaml.FlowStyle
+		}
+	}
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func encodeYAML(node *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func savedLayout() LayoutConfig {
+	return LayoutConfig{
+		ID:    "saved",
+		Name:  "Saved",
+		Grid:  GridConfig{Columns: []string{"2fr", "1fr"}, Rows: []string{"1fr"}},
+		Areas: [][]string{{"cell1", "cell2"}},
+	}
+}
+
+func TestSaveLayout_AppendsAndKeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, `# my grid config
+settings:
+  cellPadding: 8 # pixels
+layouts:
+`+includeLayout("full", "Full"))
+
+	if err := SaveLayout(path, savedLayout(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, want := range []string{"# my grid config", "# pixels", "columns: [2fr, 1fr]", "- [cell1, cell2]"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("saved config missing %q:\n%s", want, text)
+		}
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("saved config doesn't load: %v", err)
+	}
+	if ids := cfg.GetLayoutIDs(); len(ids) != 2 || ids[1] != "saved" {
+		t.Errorf("layout IDs = %v, want [full saved]", ids)
+	}
+}
+
+func TestSaveLayout_ExistingID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "layouts:\n"+includeLayout("saved", "Old"))
+
+	if err := SaveLayout(path, savedLayout(), false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected an error pointing at --force, got %v", err)
+	}
+
+	if err := SaveLayout(path, savedLayout(), true); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Layouts) != 1 || cfg.Layouts[0].Name != "Saved" {
+		t.Errorf("expected the layout replaced in place, got %+v", cfg.Layouts)
+	}
+}
+
+func TestFileHasLayout_LeavesOutIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeConfigFile(t, filepath.Join(dir, "extra.yaml"), "layouts:\n"+includeLayout("saved", "Included"))
+	writeConfigFile(t, path, "includes: [extra.yaml]\nlayouts:\n"+includeLayout("full", "Full"))
+
+	if has, err := FileHasLayout(path, "full"); err != nil || !has {
+		t.Errorf("FileHasLayout(full) = %v, %v; want true", has, err)
+	}
+	// Only in the include: saving it writes an override to the main file
+	if has, err := FileHasLayout(path, "saved"); err != nil || has {
+		t.Errorf("FileHasLayout(saved) = %v, %v; want false", has, err)
+	}
+	if err := SaveLayout(path, savedLayout(), false); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc, err := cfg.GetLayout("saved"); err != nil || lc.Name != "Saved" {
+		t.Errorf("expected the saved layout to override the included one, got %+v (%v)", lc, err)
+	}
+}
+
+func TestSaveLayout_NoLayoutsKeyOrJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeConfigFile(t, path, "settings:\n  cellPadding: 4\n")
+	if err := SaveLayout(path, savedLayout(), false); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ParseConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Layouts) != 1 || cfg.Settings.CellPadding != 4 {
+		t.Errorf("unexpected config after save: %+v", cfg)
+	}
+
+	jsonPath := filepath.Join(dir, "config.json")
+	writeConfigFile(t, jsonPath, "{}")
+	if err := SaveLayout(jsonPath, savedLayout(), false); err == nil {
+		t.Error("expected JSON config files to be rejected")
+	}
+}
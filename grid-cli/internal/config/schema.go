@@ -0,0 +1,173 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// SchemaURI is the JSON Schema dialect Schema produces
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// spanPattern matches the "start/end" cell lines parseSpan accepts
+const spanPattern = `^\s*\d+\s*/\s*\d+\s*$`
+
+// stackModes are the values isValidStackMode accepts, besides empty
+var stackModes = []types.StackMode{types.StackVertical, types.StackHorizontal, types.StackTabs, types.StackGrid}
+
+// schemaRequired lists the fields Validate rejects a config without, by type
+var schemaRequired = map[string][]string{
+	"LayoutConfig": {"id", "grid"},
+	"GridConfig":   {"columns", "rows"},
+	"CellConfig":   {"id", "column", "row"},
+	"AppRule":      {"app"},
+}
+
+// schemaConstraints holds what the Go types can't express, keyed by
+// "<type>.<yaml field>". Entries are merged over the generated property
+// schema; they mirror the checks in validate.go.
+var schemaConstraints = map[string]map[string]interface{}{
+	"GridConfig.columns":   {"items": trackSizeSchema(), "minItems": 1},
+	"GridConfig.rows":      {"items": trackSizeSchema(), "minItems": 1},
+	"GridConfig.gap":       {"minimum": 0},
+	"GridConfig.columnGap": {"minimum": 0},
+	"GridConfig.rowGap":    {"minimum": 0},
+	"CellConfig.column": {
+		"pattern":     spanPattern,
+		"description": `Grid lines the cell spans, "start/end" (1-based), e.g. "1/3"`,
+	},
+	"CellConfig.row": {
+		"pattern":     spanPattern,
+		"description": `Grid lines the cell spans, "start/end" (1-based), e.g. "1/2"`,
+	},
+	"CellConfig.weight": {"minimum": 0},
+	"CellConfig.aspectRatio": {
+		"pattern":     `^\s*\d+(\.\d+)?\s*:\s*\d+(\.\d+)?\s*$`,
+		"description": `Width:height the cell's windows keep, e.g. "16:9"`,
+	},
+	"LayoutConfig.areas": {
+		"description": `Rows of cell IDs; "." leaves a slot empty`,
+	},
+	"Settings.animationDuration": {"minimum": 0},
+	"Settings.cellPadding":       {"minimum": 0, "description": "Gap between cells in pixels"},
+	"Settings.stateBackups":      {"minimum": 0},
+	"Settings.minWindowWidth":    {"minimum": 0},
+	"Settings.minWindowHeight":   {"minimum": 0},
+	"Settings.resizeSnapPoints": {
+		"items": map[string]interface{}{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1},
+	},
+	"HookSettings.timeout": {"minimum": 0},
+	"AppRule.app":          {"minLength": 1, "description": "App name or bundle ID"},
+	"AppRule.sizeIncrement": {
+		"items":       map[string]interface{}{"type": "number", "minimum": 0},
+		"minItems":    2,
+		"maxItems":    2,
+		"description": "[width, height] resize step in pixels",
+	},
+}
+
+// trackSizeSchema describes a track size string, using the same patterns
+// ParseTrackSize matches
+func trackSizeSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "string",
+		"anyOf": []interface{}{
+			map[string]interface{}{"const": "auto"},
+			map[string]interface{}{"pattern": frPattern.String()},
+			map[string]interface{}{"pattern": pxPattern.String()},
+			map[string]interface{}{"pattern": minmaxPattern.String()},
+		},
+		"description": `Track size: "1fr", "300px", "auto" or "minmax(200px, 1fr)"`,
+	}
+}
+
+// Schema returns a JSON Schema for the config file. Properties are generated
+// from the config structs' yaml tags, so new fields show up without changes
+// here; schemaRequired and schemaConstraints add the rules Validate enforces.
+func Schema() map[string]interface{} {
+	defs := make(map[string]interface{})
+	root := schemaFor(reflect.TypeOf(Config{}), defs)
+
+	return map[string]interface{}{
+		"$schema": SchemaURI,
+		"title":   "grid config",
+		"$ref":    root["$ref"],
+		"$defs":   defs,
+	}
+}
+
+// schemaFor returns the schema for t, adding named structs to defs and
+// referring to them by $ref
+func schemaFor(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	if t == reflect.TypeOf(types.StackMode("")) {
+		values := make([]interface{}, 0, len(stackModes)+1)
+		for _, mode := range stackModes {
+			values = append(values, string(mode))
+		}
+		values = append(values, "")
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	if t == reflect.TypeOf(CrossDisplayMapping("")) {
+		values := make([]interface{}, 0, len(crossDisplayMappings))
+		for _, mapping := range crossDisplayMappings {
+			values = append(values, string(mapping))
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem(), defs)
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil // Reserve the name so recursive types terminate
+			defs[name] = structSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's yaml fields as an object schema
+func structSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		prop := schemaFor(field.Type, defs)
+		for key, value := range schemaConstraints[t.Name()+"."+name] {
+			prop[key] = value
+		}
+		properties[name] = prop
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required := schemaRequired[t.Name()]; len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
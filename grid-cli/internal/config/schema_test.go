@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func schemaDef(t *testing.T, schema map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+	def, ok := schema["$defs"].(map[string]interface{})[name].(map[string]interface{})
+	if !ok {
+		t.Fatalf("schema has no $defs entry for %s", name)
+	}
+	return def
+}
+
+func TestSchema_CoversConfigTypes(t *testing.T) {
+	schema := Schema()
+	if schema["$ref"] != "#/$defs/Config" {
+		t.Errorf("root $ref = %v, want #/$defs/Config", schema["$ref"])
+	}
+	for _, name := range []string{"Config", "Settings", "LayoutConfig", "GridConfig", "CellConfig", "AppRule", "SpaceConfig", "DisplayConfig", "FocusWrap"} {
+		schemaDef(t, schema, name)
+	}
+
+	// Every yaml field of the struct shows up as a property
+	props := schemaDef(t, schema, "Settings")["properties"].(map[string]interface{})
+	st := reflect.TypeOf(Settings{})
+	if len(props) != st.NumField() {
+		t.Errorf("Settings has %d fields, schema has %d properties", st.NumField(), len(props))
+	}
+	if _, ok := props["tileStickyWindows"]; !ok {
+		t.Error("expected tileStickyWindows in Settings properties")
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema doesn't marshal: %v", err)
+	}
+}
+
+func TestSchema_TrackSizesMatchParser(t *testing.T) {
+	grid := schemaDef(t, Schema(), "GridConfig")
+	columns := grid["properties"].(map[string]interface{})["columns"].(map[string]interface{})
+	alternatives := columns["items"].(map[string]interface{})["anyOf"].([]interface{})
+
+	matches := func(s string) bool {
+		for _, alt := range alternatives {
+			alt := alt.(map[string]interface{})
+			if alt["const"] == s {
+				return true
+			}
+			if p, ok := alt["pattern"].(string); ok && regexp.MustCompile(p).MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, s := range []string{"1fr", "2.5fr", "300px", "auto", "minmax(200px, 1fr)", "1px", "3em", "fr", "minmax(1fr, 200px)"} {
+		_, err := ParseTrackSize(s)
+		if got, want := matches(s), err == nil; got != want {
+			t.Errorf("%q: schema match = %v, parser accepts = %v", s, got, want)
+		}
+	}
+}
+
+func TestSchema_RequiredAndConstraints(t *testing.T) {
+	schema := Schema()
+	cell := schemaDef(t, schema, "CellConfig")
+	if !reflect.DeepEqual(cell["required"], []string{"id", "column", "row"}) {
+		t.Errorf("CellConfig required = %v", cell["required"])
+	}
+
+	column := cell["properties"].(map[string]interface{})["column"].(map[string]interface{})
+	span := regexp.MustCompile(column["pattern"].(string))
+	for s, want := range map[string]bool{"1/3": true, " 2 / 4 ": true, "1": false, "a/b": false} {
+		if span.MatchString(s) != want {
+			t.Errorf("span %q: match = %v, want %v", s, !want, want)
+		}
+	}
+
+	padding := schemaDef(t, schema, "Settings")["properties"].(map[string]interface{})["cellPadding"].(map[string]interface{})
+	if padding["type"] != "integer" || padding["minimum"] != 0 {
+		t.Errorf("cellPadding = %v, want a non-negative integer", padding)
+	}
+}
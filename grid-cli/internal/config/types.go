@@ -4,18 +4,79 @@ import "github.com/yourusername/grid-cli/internal/types"
 
 // Config is the root configuration structure
 type Config struct {
-	Settings Settings               `yaml:"settings" json:"settings"`
-	Layouts  []LayoutConfig         `yaml:"layouts" json:"layouts"`
-	Spaces   map[string]SpaceConfig `yaml:"spaces" json:"spaces"`
-	AppRules []AppRule              `yaml:"appRules" json:"appRules"`
+	Includes []string                 `yaml:"includes,omitempty" json:"includes,omitempty"` // Extra config files merged in at load time
+	Settings Settings                 `yaml:"settings" json:"settings"`
+	Layouts  []LayoutConfig           `yaml:"layouts" json:"layouts"`
+	Spaces   map[string]SpaceConfig   `yaml:"spaces" json:"spaces"`
+	Displays map[string]DisplayConfig `yaml:"displays,omitempty" json:"displays,omitempty"` // Per-display defaults, keyed by display UUID or index
+	AppRules []AppRule                `yaml:"appRules" json:"appRules"`
 }
 
 // Settings contains global application settings
 type Settings struct {
-	DefaultStackMode  types.StackMode `yaml:"defaultStackMode" json:"defaultStackMode"`
-	AnimationDuration float64         `yaml:"animationDuration" json:"animationDuration"`
-	CellPadding       int             `yaml:"cellPadding" json:"cellPadding"`
-	FocusFollowsMouse bool            `yaml:"focusFollowsMouse" json:"focusFollowsMouse"`
+	DefaultStackMode       types.StackMode `yaml:"defaultStackMode" json:"defaultStackMode"`
+	AnimationDuration      float64         `yaml:"animationDuration" json:"animationDuration"`
+	CellPadding            int             `yaml:"cellPadding" json:"cellPadding"`
+	FocusFollowsMouse      bool            `yaml:"focusFollowsMouse" json:"focusFollowsMouse"`
+	MouseFollowsFocus      bool            `yaml:"mouseFollowsFocus,omitempty" json:"mouseFollowsFocus,omitempty"`           // Warp the cursor to windows focused by focus commands (needs server mouse.warp)
+	AutoBackupState        bool            `yaml:"autoBackupState" json:"autoBackupState"`                                   // Back up state before mutating commands
+	StateBackups           int             `yaml:"stateBackups,omitempty" json:"stateBackups,omitempty"`                     // Backups to keep (default 5)
+	OrientationAwareStacks bool            `yaml:"orientationAwareStacks,omitempty" json:"orientationAwareStacks,omitempty"` // Swap vertical/horizontal default stacking on portrait displays
+	ResizeCyclesTabs       bool            `yaml:"resizeCyclesTabs,omitempty" json:"resizeCyclesTabs,omitempty"`             // Resize grow/shrink switches tabs in tabbed cells
+	ResizeSnapPoints       []float64       `yaml:"resizeSnapPoints,omitempty" json:"resizeSnapPoints,omitempty"`             // Ratios resize --snap snaps to (default 1/3, 1/2, 2/3)
+	DefaultLayout          string          `yaml:"defaultLayout,omitempty" json:"defaultLayout,omitempty"`                   // Layout for spaces without their own defaultLayout
+	PlaceNewAtFocus        bool            `yaml:"placeNewAtFocus,omitempty" json:"placeNewAtFocus,omitempty"`               // Layout apply preserves cells and puts new windows in the focused cell
+	Focus                  FocusSettings   `yaml:"focus,omitempty" json:"focus,omitempty"`                                   // Focus navigation defaults
+	TileStickyWindows      bool            `yaml:"tileStickyWindows,omitempty" json:"tileStickyWindows,omitempty"`           // Un-stick sticky windows and tile them instead of skipping them
+	MinWindowWidth         int             `yaml:"minWindowWidth,omitempty" json:"minWindowWidth,omitempty"`                 // Narrowest a stacked window may get before move --auto-expand switches layout (0 = no limit)
+	MinWindowHeight        int             `yaml:"minWindowHeight,omitempty" json:"minWindowHeight,omitempty"`               // Shortest a stacked window may get before move --auto-expand switches layout (0 = no limit)
+	Hooks                  HookSettings    `yaml:"hooks,omitempty" json:"hooks,omitempty"`                                   // Shell commands run around layout applies
+	FocusFollowsMove       *bool           `yaml:"focusFollowsMove,omitempty" json:"focusFollowsMove,omitempty"`             // Moving a window focuses it (default true)
+	MainCell               string          `yaml:"mainCell,omitempty" json:"mainCell,omitempty"`                             // Cell window promote targets, where the layout has it (default "main", else the largest cell)
+
+	// CrossDisplayMapping picks the cell focus and window moves land in on
+	// the next display (default proportional)
+	CrossDisplayMapping CrossDisplayMapping `yaml:"crossDisplayMapping,omitempty" json:"crossDisplayMapping,omitempty"`
+}
+
+// CrossDisplayMapping is how a cell on one display maps to a cell on the
+// display next to it
+type CrossDisplayMapping string
+
+const (
+	// CrossDisplayProportional lands in the cell at the same relative
+	// position on the target display
+	CrossDisplayProportional CrossDisplayMapping = "proportional"
+	// CrossDisplayNearestEdge lands in the cell along the entered edge that
+	// is physically closest to the source cell
+	CrossDisplayNearestEdge CrossDisplayMapping = "nearest-edge"
+	// CrossDisplayPreserveRow lands in the cell along the entered edge with
+	// the same row index (column index when moving up or down)
+	CrossDisplayPreserveRow CrossDisplayMapping = "preserve-row"
+)
+
+// crossDisplayMappings are the values validateSettings accepts, besides empty
+var crossDisplayMappings = []CrossDisplayMapping{CrossDisplayProportional, CrossDisplayNearestEdge, CrossDisplayPreserveRow}
+
+// HookSettings are shell commands run before and after a layout is applied.
+// Hooks get the space and layout in GRID_* environment variables and as JSON
+// on stdin; a failing hook only produces a warning.
+type HookSettings struct {
+	PreApply  string  `yaml:"preApply,omitempty" json:"preApply,omitempty"`   // Run before any window is moved
+	PostApply string  `yaml:"postApply,omitempty" json:"postApply,omitempty"` // Run after a successful apply
+	Timeout   float64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`     // Seconds before a hook is killed (default 5)
+}
+
+// FocusSettings configures directional focus navigation
+type FocusSettings struct {
+	Wrap FocusWrap `yaml:"wrap,omitempty" json:"wrap,omitempty"` // Per-axis default for focus --wrap
+}
+
+// FocusWrap sets whether focus wraps at the display edge, per axis.
+// Unset axes wrap, matching the --wrap flag's default.
+type FocusWrap struct {
+	Horizontal *bool `yaml:"horizontal,omitempty" json:"horizontal,omitempty"`
+	Vertical   *bool `yaml:"vertical,omitempty" json:"vertical,omitempty"`
 }
 
 // LayoutConfig is the configuration representation of a layout
@@ -28,12 +89,19 @@ type LayoutConfig struct {
 	Areas       [][]string             `yaml:"areas,omitempty" json:"areas,omitempty"`   // ASCII grid syntax
 	Cells       []CellConfig           `yaml:"cells,omitempty" json:"cells,omitempty"`   // Explicit cell definitions
 	CellModes   map[string]types.StackMode `yaml:"cellModes,omitempty" json:"cellModes,omitempty"`
+	Disabled    bool                       `yaml:"disabled,omitempty" json:"disabled,omitempty"` // Kept out of the layout cycle
 }
 
 // GridConfig defines the grid structure
 type GridConfig struct {
 	Columns []string `yaml:"columns" json:"columns"` // Track size strings
 	Rows    []string `yaml:"rows" json:"rows"`       // Track size strings
+
+	// Gaps between cells in pixels. Gap sets both axes; ColumnGap and RowGap
+	// override it per axis. Unset gaps use settings.cellPadding.
+	Gap       *float64 `yaml:"gap,omitempty" json:"gap,omitempty"`
+	ColumnGap *float64 `yaml:"columnGap,omitempty" json:"columnGap,omitempty"`
+	RowGap    *float64 `yaml:"rowGap,omitempty" json:"rowGap,omitempty"`
 }
 
 // CellConfig is the configuration representation of a cell
@@ -42,6 +110,16 @@ type CellConfig struct {
 	Column    string          `yaml:"column" json:"column"`                       // "start/end" format, e.g., "1/3"
 	Row       string          `yaml:"row" json:"row"`                             // "start/end" format, e.g., "1/2"
 	StackMode types.StackMode `yaml:"stackMode,omitempty" json:"stackMode,omitempty"`
+	Launch    string          `yaml:"launch,omitempty" json:"launch,omitempty"` // App to launch into the cell when empty (layout apply --launch-empty)
+	Weight    int             `yaml:"weight,omitempty" json:"weight,omitempty"` // Share of auto-flow windows relative to other cells (default 1)
+
+	// AspectRatio keeps the cell's windows at "width:height" (e.g. "16:9"),
+	// centered in the cell's grid region with margins on the other axis
+	AspectRatio string `yaml:"aspectRatio,omitempty" json:"aspectRatio,omitempty"`
+
+	// SkipNavigation keeps directional focus and moves from landing in the
+	// cell; they jump over it. `focus cell` still reaches it.
+	SkipNavigation bool `yaml:"skipNavigation,omitempty" json:"skipNavigation,omitempty"`
 }
 
 // SpaceConfig defines per-Space settings
@@ -52,6 +130,21 @@ type SpaceConfig struct {
 	AutoApply     bool     `yaml:"autoApply" json:"autoApply"`         // Auto-apply on space switch
 }
 
+// DisplayConfig defines defaults for the spaces on a display, used where a
+// space has no spaces entry of its own. Space IDs change as spaces are
+// added and removed, displays don't.
+type DisplayConfig struct {
+	Layouts       []string `yaml:"layouts,omitempty" json:"layouts,omitempty"`             // Layout IDs the display's spaces cycle through
+	DefaultLayout string   `yaml:"defaultLayout,omitempty" json:"defaultLayout,omitempty"` // Layout applied when none is given
+}
+
+// DisplayRef identifies a display for looking up its DisplayConfig: by UUID,
+// or by its index in the server's display list
+type DisplayRef struct {
+	UUID  string
+	Index int
+}
+
 // AppRule defines application-specific window behavior
 type AppRule struct {
 	App                string          `yaml:"app" json:"app"`                                             // App name or bundle ID
@@ -59,4 +152,5 @@ type AppRule struct {
 	Layouts            []string        `yaml:"layouts,omitempty" json:"layouts,omitempty"`                 // Only applies to these layouts
 	Float              bool            `yaml:"float,omitempty" json:"float,omitempty"`                     // Never tile this app
 	PreferredStackMode types.StackMode `yaml:"preferredStackMode,omitempty" json:"preferredStackMode,omitempty"`
+	SizeIncrement      []float64       `yaml:"sizeIncrement,omitempty" json:"sizeIncrement,omitempty"`     // [width, height] resize step in pixels, e.g. a terminal's character cell
 }
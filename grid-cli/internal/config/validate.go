@@ -37,17 +37,35 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate display configs reference existing layouts
+	for key, displayConfig := range c.Displays {
+		for _, layoutID := range displayConfig.Layouts {
+			if !layoutIDs[layoutID] {
+				return fmt.Errorf("display %s references unknown layout: %s", key, layoutID)
+			}
+		}
+		if displayConfig.DefaultLayout != "" && !layoutIDs[displayConfig.DefaultLayout] {
+			return fmt.Errorf("display %s has unknown default layout: %s", key, displayConfig.DefaultLayout)
+		}
+	}
+
 	// Validate app rules
 	for i, rule := range c.AppRules {
 		if rule.App == "" {
 			return fmt.Errorf("appRule %d: missing app identifier", i)
 		}
+		if err := validateSizeIncrement(rule.SizeIncrement); err != nil {
+			return fmt.Errorf("appRule %s: %w", rule.App, err)
+		}
 	}
 
 	// Validate settings
 	if err := validateSettings(&c.Settings); err != nil {
 		return fmt.Errorf("settings: %w", err)
 	}
+	if c.Settings.DefaultLayout != "" && !layoutIDs[c.Settings.DefaultLayout] {
+		return fmt.Errorf("settings: unknown default layout: %s", c.Settings.DefaultLayout)
+	}
 
 	return nil
 }
@@ -73,6 +91,17 @@ func validateLayout(layout *LayoutConfig) error {
 		}
 	}
 
+	// Gaps must not be negative
+	if g := layout.Grid.Gap; g != nil && *g < 0 {
+		return fmt.Errorf("gap cannot be negative")
+	}
+	if g := layout.Grid.ColumnGap; g != nil && *g < 0 {
+		return fmt.Errorf("columnGap cannot be negative")
+	}
+	if g := layout.Grid.RowGap; g != nil && *g < 0 {
+		return fmt.Errorf("rowGap cannot be negative")
+	}
+
 	// Must have either cells or areas (not both, not neither)
 	hasCells := len(layout.Cells) > 0
 	hasAreas := len(layout.Areas) > 0
@@ -127,6 +156,16 @@ func validateCellConfig(cell *CellConfig, numCols, numRows int) error {
 		return fmt.Errorf("row span %d/%d out of bounds (grid has %d rows)", rowStart, rowEnd, numRows)
 	}
 
+	if cell.Weight < 0 {
+		return fmt.Errorf("weight cannot be negative")
+	}
+
+	if cell.AspectRatio != "" {
+		if _, err := ParseAspectRatio(cell.AspectRatio); err != nil {
+			return err
+		}
+	}
+
 	// Validate stack mode if specified
 	if cell.StackMode != "" {
 		if !isValidStackMode(cell.StackMode) {
@@ -205,12 +244,56 @@ func validateSettings(s *Settings) error {
 	if s.CellPadding < 0 {
 		return fmt.Errorf("cell padding cannot be negative")
 	}
+	if s.StateBackups < 0 {
+		return fmt.Errorf("state backups cannot be negative")
+	}
+	if s.MinWindowWidth < 0 || s.MinWindowHeight < 0 {
+		return fmt.Errorf("minimum window size cannot be negative")
+	}
+	if s.Hooks.Timeout < 0 {
+		return fmt.Errorf("hook timeout cannot be negative")
+	}
+	for _, p := range s.ResizeSnapPoints {
+		if p <= 0 || p >= 1 {
+			return fmt.Errorf("resize snap point must be between 0 and 1: %v", p)
+		}
+	}
+	if !isValidCrossDisplayMapping(s.CrossDisplayMapping) {
+		return fmt.Errorf("invalid cross-display mapping: %s (use proportional, nearest-edge or preserve-row)", s.CrossDisplayMapping)
+	}
+	return nil
+}
+
+func isValidCrossDisplayMapping(mapping CrossDisplayMapping) bool {
+	if mapping == "" {
+		return true
+	}
+	for _, m := range crossDisplayMappings {
+		if m == mapping {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSizeIncrement checks an appRules[].sizeIncrement, if set, is a
+// positive [width, height] pair
+func validateSizeIncrement(inc []float64) error {
+	if len(inc) == 0 {
+		return nil
+	}
+	if len(inc) != 2 {
+		return fmt.Errorf("sizeIncrement must be [width, height], got %d values", len(inc))
+	}
+	if inc[0] < 0 || inc[1] < 0 {
+		return fmt.Errorf("sizeIncrement cannot be negative: %v", inc)
+	}
 	return nil
 }
 
 func isValidStackMode(mode types.StackMode) bool {
 	switch mode {
-	case types.StackVertical, types.StackHorizontal, types.StackTabs, "":
+	case types.StackVertical, types.StackHorizontal, types.StackTabs, types.StackGrid, "":
 		return true
 	default:
 		return false
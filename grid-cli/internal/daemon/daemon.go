@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultControlSocket is where `grid watch` listens for commands
+	DefaultControlSocket = "/tmp/grid-control.sock"
+
+	// dialTimeout bounds the check for a running daemon, so a stale socket
+	// doesn't slow down the standalone fallback
+	dialTimeout = 100 * time.Millisecond
+)
+
+// Request is a grid command forwarded to the daemon
+type Request struct {
+	Args []string `json:"args"`
+}
+
+// Response is the outcome of a forwarded command
+type Response struct {
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+	Error  string `json:"error,omitempty"` // Set when the command failed
+}
+
+// Handler runs a forwarded command
+type Handler func(args []string) Response
+
+// ControlSocketPath returns the control socket path: $GRID_CONTROL_SOCKET,
+// or DefaultControlSocket.
+func ControlSocketPath() string {
+	if path := os.Getenv("GRID_CONTROL_SOCKET"); path != "" {
+		return path
+	}
+	return DefaultControlSocket
+}
+
+// Available reports whether a daemon is accepting connections on path
+func Available(path string) bool {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// Call sends a command to the daemon on path and waits for its result
+func Call(ctx context.Context, path string, args []string) (*Response, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon %s: %w", path, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	data, err := json.Marshal(Request{Args: args})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send to daemon: %w", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daemon response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("invalid daemon response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Route runs a command through the daemon on path if one is running,
+// copying its output to stdout and stderr. Returns false if no daemon
+// answered, so the caller runs the command itself.
+func Route(ctx context.Context, path string, args []string, stdout, stderr io.Writer) (bool, error) {
+	if !Available(path) {
+		return false, nil
+	}
+
+	resp, err := Call(ctx, path, args)
+	if err != nil {
+		// The daemon went away between the check and the call
+		return false, nil
+	}
+
+	io.WriteString(stdout, resp.Stdout)
+	io.WriteString(stderr, resp.Stderr)
+	if resp.Error != "" {
+		return true, errors.New(resp.Error)
+	}
+	return true, nil
+}
+
+// Serve answers forwarded commands on ln until ctx is done. Commands run one
+// at a time in arrival order, so repeated keybindings apply in sequence.
+func Serve(ctx context.Context, ln net.Listener, handle Handler) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var mu sync.Mutex
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to accept control connection: %w", err)
+		}
+
+		go func() {
+			defer conn.Close()
+
+			line, err := bufio.NewReader(conn).ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var req Request
+			if err := json.Unmarshal(line, &req); err != nil {
+				return
+			}
+
+			mu.Lock()
+			resp := handle(req.Args)
+			mu.Unlock()
+
+			data, err := json.Marshal(resp)
+			if err != nil {
+				return
+			}
+			conn.Write(append(data, '\n'))
+		}()
+	}
+}
+
+// Listen opens the control socket at path, replacing a stale socket left by
+// a daemon that didn't shut down cleanly. Fails if a daemon is already running.
+func Listen(path string) (net.Listener, error) {
+	if Available(path) {
+		return nil, fmt.Errorf("a grid daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("failed to restrict control socket: %w", err)
+	}
+	return ln, nil
+}
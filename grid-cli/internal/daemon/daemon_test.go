@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startDaemon serves handle on a control socket in a temp dir
+func startDaemon(t *testing.T, handle Handler) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "control.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Serve(ctx, ln, handle)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return path
+}
+
+func TestRoute_UsesDaemon(t *testing.T) {
+	var got []string
+	path := startDaemon(t, func(args []string) Response {
+		got = args
+		return Response{Stdout: "moved\n"}
+	})
+
+	var stdout, stderr bytes.Buffer
+	handled, err := Route(context.Background(), path, []string{"window", "move", "right"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !handled {
+		t.Fatal("expected the daemon to handle the command")
+	}
+	if strings.Join(got, " ") != "window move right" {
+		t.Errorf("daemon got args %v", got)
+	}
+	if stdout.String() != "moved\n" {
+		t.Errorf("stdout = %q, want the daemon's output", stdout.String())
+	}
+}
+
+func TestRoute_DaemonError(t *testing.T) {
+	path := startDaemon(t, func(args []string) Response {
+		return Response{Stderr: "Error: no layout applied\n", Error: "no layout applied"}
+	})
+
+	var stdout, stderr bytes.Buffer
+	handled, err := Route(context.Background(), path, []string{"focus", "left"}, &stdout, &stderr)
+	if !handled || err == nil || err.Error() != "no layout applied" {
+		t.Fatalf("expected handled command error, got handled=%v err=%v", handled, err)
+	}
+	if !strings.Contains(stderr.String(), "no layout applied") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRoute_FallsBackWithoutDaemon(t *testing.T) {
+	dir := t.TempDir()
+
+	// No socket at all
+	handled, err := Route(context.Background(), filepath.Join(dir, "missing.sock"), []string{"focus", "left"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if handled || err != nil {
+		t.Errorf("expected fallback without a socket, got handled=%v err=%v", handled, err)
+	}
+
+	// A stale socket file nobody listens on
+	stale := filepath.Join(dir, "stale.sock")
+	if err := os.WriteFile(stale, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	handled, err = Route(context.Background(), stale, []string{"focus", "left"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if handled || err != nil {
+		t.Errorf("expected fallback with a stale socket, got handled=%v err=%v", handled, err)
+	}
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.sock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// A second daemon on the same socket is refused
+	if _, err := Listen(path); err == nil {
+		t.Error("expected an error while a daemon is listening")
+	}
+}
+
+func TestControlSocketPath(t *testing.T) {
+	t.Setenv("GRID_CONTROL_SOCKET", "")
+	if got := ControlSocketPath(); got != DefaultControlSocket {
+		t.Errorf("default path = %s", got)
+	}
+	t.Setenv("GRID_CONTROL_SOCKET", "/tmp/other.sock")
+	if got := ControlSocketPath(); got != "/tmp/other.sock" {
+		t.Errorf("env path = %s", got)
+	}
+}
@@ -0,0 +1,153 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// redactedValue replaces sensitive strings in a redacted bundle
+const redactedValue = "[redacted]"
+
+// redactedKeys are dump fields that can identify the user's documents or apps:
+// window titles and application bundle/executable paths
+var redactedKeys = map[string]bool{
+	"title":         true,
+	"bundleURL":     true,
+	"executableURL": true,
+}
+
+// Options configures what goes into a bundle
+type Options struct {
+	Version    string // CLI version
+	SocketPath string // Server socket the CLI talks to
+	Redact     bool   // Strip window titles and app paths from the dump
+}
+
+// Bundle is everything needed to reproduce a layout bug. Sections that
+// couldn't be collected are listed in Errors instead of failing the bundle.
+type Bundle struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	Version     string                 `json:"version"`
+	SocketPath  string                 `json:"socketPath"`
+	Redacted    bool                   `json:"redacted"`
+	ServerInfo  map[string]interface{} `json:"serverInfo,omitempty"`
+	Dump        map[string]interface{} `json:"dump,omitempty"`
+	State       *state.RuntimeState    `json:"state,omitempty"`
+	Config      *config.Config         `json:"config,omitempty"`
+	Errors      map[string]string      `json:"errors,omitempty"` // section -> why it's missing
+}
+
+// Collect gathers server info, a dump, the runtime state and the loaded
+// config into a bundle, redacting the dump if requested.
+func Collect(ctx context.Context, c *client.Client, opts Options) *Bundle {
+	b := &Bundle{
+		GeneratedAt: time.Now(),
+		Version:     opts.Version,
+		SocketPath:  opts.SocketPath,
+		Errors:      make(map[string]string),
+	}
+
+	if info, err := c.GetServerInfo(ctx); err != nil {
+		b.Errors["serverInfo"] = err.Error()
+	} else {
+		b.ServerInfo = info
+	}
+
+	if dump, err := c.Dump(ctx); err != nil {
+		b.Errors["dump"] = err.Error()
+	} else {
+		b.Dump = dump
+	}
+
+	if rs, err := state.LoadState(); err != nil {
+		b.Errors["state"] = err.Error()
+	} else {
+		b.State = rs
+	}
+
+	if cfg, err := config.LoadConfig(""); err != nil {
+		b.Errors["config"] = err.Error()
+	} else {
+		b.Config = cfg
+	}
+
+	if opts.Redact {
+		b.Redact()
+	}
+	return b
+}
+
+// Redact strips window titles and application paths from the dump.
+func (b *Bundle) Redact() {
+	redact(b.Dump)
+	b.Redacted = true
+}
+
+// redact walks nested maps and slices, replacing sensitive string fields.
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if _, isString := child.(string); isString && redactedKeys[key] {
+				v[key] = redactedValue
+				continue
+			}
+			redact(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redact(child)
+		}
+	}
+}
+
+// WriteJSON writes the bundle as a single JSON document.
+func (b *Bundle) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// WriteZip writes the bundle as a zip archive with one JSON file per section.
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	sections := []struct {
+		name  string
+		value interface{}
+	}{
+		{"bundle.json", map[string]interface{}{
+			"generatedAt": b.GeneratedAt,
+			"version":     b.Version,
+			"socketPath":  b.SocketPath,
+			"redacted":    b.Redacted,
+			"errors":      b.Errors,
+		}},
+		{"server-info.json", b.ServerInfo},
+		{"dump.json", b.Dump},
+		{"state.json", b.State},
+		{"config.json", b.Config},
+	}
+
+	for _, section := range sections {
+		f, err := zw.Create(section.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", section.name, err)
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(section.value); err != nil {
+			return fmt.Errorf("failed to write %s: %w", section.name, err)
+		}
+	}
+
+	return zw.Close()
+}
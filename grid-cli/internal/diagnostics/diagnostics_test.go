@@ -0,0 +1,110 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+func sampleDump() map[string]interface{} {
+	return map[string]interface{}{
+		"windows": map[string]interface{}{
+			"10": map[string]interface{}{"id": 10.0, "title": "secret-plans.txt", "appName": "TextEdit"},
+		},
+		"applications": map[string]interface{}{
+			"42": map[string]interface{}{
+				"localizedName": "TextEdit",
+				"bundleURL":     "file:///Applications/TextEdit.app/",
+				"executableURL": "file:///Applications/TextEdit.app/Contents/MacOS/TextEdit",
+			},
+		},
+	}
+}
+
+func TestRedact(t *testing.T) {
+	b := &Bundle{Dump: sampleDump()}
+	b.Redact()
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	for _, leaked := range []string{"secret-plans.txt", "/Applications/TextEdit.app"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("redacted bundle still contains %q", leaked)
+		}
+	}
+	// Non-sensitive fields survive
+	if !strings.Contains(out, "TextEdit") || !b.Redacted {
+		t.Errorf("expected app names kept and bundle marked redacted: %s", out)
+	}
+}
+
+func TestWriteZip_Sections(t *testing.T) {
+	b := &Bundle{
+		Version:    "0.1.0",
+		SocketPath: "/tmp/grid-server.sock",
+		ServerInfo: map[string]interface{}{"version": "1.0"},
+		Dump:       sampleDump(),
+		State:      state.NewRuntimeState(),
+	}
+
+	var buf bytes.Buffer
+	if err := b.WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, f := range zr.File {
+		got[f.Name] = true
+	}
+	for _, name := range []string{"bundle.json", "server-info.json", "dump.json", "state.json", "config.json"} {
+		if !got[name] {
+			t.Errorf("zip missing section %s", name)
+		}
+	}
+}
+
+func TestCollect_RecordsMissingSections(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := client.NewClient(filepath.Join(t.TempDir(), "missing.sock"), 0)
+
+	b := Collect(context.Background(), c, Options{Version: "0.1.0", SocketPath: "missing.sock", Redact: true})
+
+	// No server and no config file: those sections are reported, state still loads
+	for _, section := range []string{"serverInfo", "dump", "config"} {
+		if _, ok := b.Errors[section]; !ok {
+			t.Errorf("expected error recorded for %s", section)
+		}
+	}
+	if b.State == nil {
+		t.Error("expected empty runtime state to be collected")
+	}
+
+	var buf bytes.Buffer
+	if err := b.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"generatedAt", "version", "socketPath", "redacted", "state", "errors"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("bundle JSON missing %s", key)
+		}
+	}
+}
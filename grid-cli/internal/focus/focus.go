@@ -4,16 +4,37 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/yourusername/grid-cli/internal/client"
 	"github.com/yourusername/grid-cli/internal/config"
 	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
 	"github.com/yourusername/grid-cli/internal/server"
 	"github.com/yourusername/grid-cli/internal/state"
 	"github.com/yourusername/grid-cli/internal/types"
 )
 
-// CycleFocus cycles to the next/prev window in the focused cell.
+// CycleOrder is the order CycleFocus visits a cell's windows in
+type CycleOrder string
+
+const (
+	CycleByPosition CycleOrder = "position" // Stack order
+	CycleByMRU      CycleOrder = "mru"      // Most recently focused first, like Alt-Tab
+)
+
+// ParseCycleOrder parses a --order value
+func ParseCycleOrder(s string) (CycleOrder, bool) {
+	switch CycleOrder(s) {
+	case CycleByPosition, CycleByMRU:
+		return CycleOrder(s), true
+	default:
+		return "", false
+	}
+}
+
+// CycleFocus cycles to the next/prev window in the focused cell, in stack
+// order or most-recently-used order (see state.CellState.MRUCycle).
 // Operates entirely on LOCAL state (which must be reconciled first).
 // Returns the window ID that was focused.
 func CycleFocus(
@@ -22,6 +43,7 @@ func CycleFocus(
 	rs *state.RuntimeState,
 	spaceID string,
 	forward bool,
+	order CycleOrder,
 ) (uint32, error) {
 	spaceState := rs.GetSpaceReadOnly(spaceID)
 	if spaceState == nil {
@@ -63,7 +85,16 @@ func CycleFocus(
 	}
 
 	// Cycle to next/prev window
-	if forward {
+	var mru []uint32
+	if order == CycleByMRU {
+		mru = cell.MRUCycle(forward)
+		for i, wid := range cell.Windows {
+			if wid == mru[0] {
+				idx = i
+				break
+			}
+		}
+	} else if forward {
 		idx = (idx + 1) % len(cell.Windows)
 	} else {
 		idx = (idx - 1 + len(cell.Windows)) % len(cell.Windows)
@@ -78,6 +109,10 @@ func CycleFocus(
 
 	// Update local state
 	mutableSpace := rs.GetSpace(spaceID)
+	mutableSpace.PushFocusHistory()
+	if mru != nil {
+		mutableSpace.GetCell(cellID).ZOrder = mru
+	}
 	mutableSpace.SetFocus(cellID, idx)
 	rs.MarkUpdated()
 	rs.Save()
@@ -134,11 +169,11 @@ func MoveFocus(
 	}
 
 	// Get current layout and calculate bounds
-	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
 	if err != nil {
 		return 0, fmt.Errorf("layout not found: %w", err)
 	}
-	calculated := layout.CalculateLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding))
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
 
 	// Find current cell
 	currentCell := spaceState.FocusedCell
@@ -149,8 +184,9 @@ func MoveFocus(
 		}
 	}
 
-	// Find adjacent cells on current display
-	adjacentMap := layout.GetAdjacentCells(currentCell, calculated.CellBounds)
+	// Find adjacent cells on current display, jumping over skipNavigation cells
+	navBounds := layout.NavigableCells(layoutDef, calculated.CellBounds, currentCell)
+	adjacentMap := layout.GetAdjacentCells(currentCell, navBounds)
 	candidates := adjacentMap[direction]
 
 	if len(candidates) == 0 {
@@ -158,6 +194,9 @@ func MoveFocus(
 		if opts.Extend {
 			windowID, err := moveFocusCrossDisplay(ctx, c, snap, cfg, rs, direction, currentCell, calculated.CellBounds, opts.WrapAround)
 			if err == nil {
+				if opts.MouseFollowsFocus {
+					WarpMouseToWindow(ctx, c, snap, windowID)
+				}
 				return windowID, nil
 			}
 			// If cross-display failed and wrap is not enabled, return the error
@@ -170,17 +209,21 @@ func MoveFocus(
 			return 0, fmt.Errorf("no cell in direction %s", direction.String())
 		}
 		// Wrap: find cell on opposite edge of current display
-		candidates = FindWrapTarget(direction, currentCell, calculated.CellBounds)
+		candidates = FindWrapTarget(direction, currentCell, navBounds)
 		if len(candidates) == 0 {
 			return 0, fmt.Errorf("no cell in direction %s (wrap)", direction.String())
 		}
 	}
 
 	// Pick closest candidate
-	targetCell := PickClosestCell(currentCell, candidates, calculated.CellBounds)
+	targetCell := PickClosestCell(currentCell, candidates, navBounds)
 
 	// Focus the target cell
-	return focusCellByID(ctx, c, rs, snap.SpaceID, targetCell)
+	windowID, err := focusCellByID(ctx, c, rs, snap.SpaceID, targetCell)
+	if err == nil && opts.MouseFollowsFocus {
+		WarpMouseToWindow(ctx, c, snap, windowID)
+	}
+	return windowID, err
 }
 
 // moveFocusCrossDisplay handles focus movement to an adjacent display.
@@ -238,17 +281,13 @@ func moveFocusCrossDisplay(
 		}
 	}
 
-	// Map visual position from current cell to target display
-	currentBounds := currentCellBounds[currentCell]
+	// Map the current cell to a cell on the target display
 	targetDisplayBounds := adjacentDisplay.VisibleFrame
 	if targetDisplayBounds == (types.Rect{}) {
 		targetDisplayBounds = adjacentDisplay.Frame
 	}
 
-	targetPoint := MatchVisualPosition(currentBounds, currentDisplayBounds, targetDisplayBounds)
-
-	// Find closest cell to target point
-	targetCell := FindClosestCellToPoint(targetPoint, targetCellBounds)
+	targetCell := CrossDisplayCell(cfg.Settings.CrossDisplayMapping, direction, currentCell, currentCellBounds, currentDisplayBounds, targetDisplayBounds, targetCellBounds)
 	if targetCell == "" {
 		return 0, fmt.Errorf("no cells on adjacent display")
 	}
@@ -369,12 +408,140 @@ func focusCellByID(ctx context.Context, c *client.Client, rs *state.RuntimeState
 	if err := FocusWindow(ctx, c, windowID); err != nil {
 		return 0, err
 	}
+	if windowID != mutableSpace.GetFocusedWindow() {
+		mutableSpace.PushFocusHistory()
+	}
 	mutableSpace.SetFocus(cellID, idx)
 	rs.MarkUpdated()
 	rs.Save()
 	return windowID, nil
 }
 
+// FocusBack re-focuses the previously focused window on a space, walking the
+// focus history one step back. Going back doesn't record the window being
+// left, so repeated calls keep walking back through the history.
+func FocusBack(ctx context.Context, c *client.Client, rs *state.RuntimeState, spaceID string) (uint32, error) {
+	spaceState := rs.GetSpaceReadOnly(spaceID)
+	if spaceState == nil {
+		return 0, fmt.Errorf("no layout applied to space %s", spaceID)
+	}
+
+	mutableSpace := rs.GetSpace(spaceID)
+	entry, ok := mutableSpace.PopFocusHistory()
+	if !ok {
+		return 0, fmt.Errorf("no previous window in focus history")
+	}
+
+	if err := FocusWindow(ctx, c, entry.WindowID); err != nil {
+		return 0, err
+	}
+	mutableSpace.SetFocus(entry.CellID, entry.WindowIndex)
+	rs.MarkUpdated()
+	rs.Save()
+	return entry.WindowID, nil
+}
+
+// FocusMaster focuses the space's master window. Without an explicit master,
+// the first window of the top-left cell is used.
+func FocusMaster(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+) (uint32, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return 0, fmt.Errorf("no layout applied to space %s", snap.SpaceID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return 0, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	windowID := spaceState.GetMaster(layout.SortCellsByPosition(calculated.CellBounds))
+	if windowID == 0 {
+		return 0, fmt.Errorf("no master window on space %s", snap.SpaceID)
+	}
+
+	if err := FocusWindow(ctx, c, windowID); err != nil {
+		return 0, err
+	}
+
+	rs.GetSpace(snap.SpaceID).SetFocusedWindow(windowID)
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return windowID, nil
+}
+
+// MostRecentUrgent returns the window that most recently requested
+// attention, or nil if none has. Without urgency times from the server, a
+// window on the current space wins, then the lowest window ID.
+func MostRecentUrgent(snap *server.Snapshot) *server.UrgentWindow {
+	var best *server.UrgentWindow
+	for i := range snap.Urgent {
+		w := &snap.Urgent[i]
+		if best == nil || urgentBefore(best, w, snap.SpaceID) {
+			best = w
+		}
+	}
+	return best
+}
+
+// urgentBefore reports whether a ranks behind b for focus urgent
+func urgentBefore(a, b *server.UrgentWindow, currentSpace string) bool {
+	if a.UrgentSince != b.UrgentSince {
+		return a.UrgentSince < b.UrgentSince
+	}
+	if aHere, bHere := a.SpaceID == currentSpace, b.SpaceID == currentSpace; aHere != bHere {
+		return bHere
+	}
+	return b.ID < a.ID
+}
+
+// FocusUrgent focuses the window that most recently requested attention,
+// switching to its space first if it isn't the current one. Returns nil
+// without error when no window is urgent.
+func FocusUrgent(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	rs *state.RuntimeState,
+) (*server.UrgentWindow, error) {
+	urgent := MostRecentUrgent(snap)
+	if urgent == nil {
+		return nil, nil
+	}
+
+	if urgent.SpaceID != "" && urgent.SpaceID != snap.SpaceID {
+		if _, err := c.CallMethod(ctx, "space.focus", map[string]interface{}{
+			"spaceId": urgent.SpaceID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to switch to space %s: %w", urgent.SpaceID, err)
+		}
+	}
+
+	if err := FocusWindow(ctx, c, urgent.ID); err != nil {
+		return nil, err
+	}
+
+	// Keep the space's focused cell in step when grid tracks the window
+	if space := rs.GetSpaceReadOnly(urgent.SpaceID); space != nil && space.GetWindowCell(urgent.ID) != "" {
+		rs.GetSpace(urgent.SpaceID).SetFocusedWindow(urgent.ID)
+		rs.MarkUpdated()
+		if err := rs.Save(); err != nil {
+			logging.Warn().Err(err).Msg("failed to save state")
+		}
+	}
+
+	return urgent, nil
+}
+
 // FindWrapTarget finds cells on the opposite edge for wrap-around navigation.
 func FindWrapTarget(direction types.Direction, currentCell string, cellBounds map[string]types.Rect) []string {
 	current, ok := cellBounds[currentCell]
@@ -509,6 +676,26 @@ func PickClosestCell(currentCell string, candidates []string, cellBounds map[str
 	return closest
 }
 
+// FindDiagonalStep resolves a diagonal direction as two orthogonal moves
+// (horizontal then vertical, or vertical then horizontal if that fails) for
+// layouts where no cell sits diagonally across. Returns "" if neither path
+// leads anywhere.
+func FindDiagonalStep(direction types.Direction, currentCell string, cellBounds map[string]types.Rect) string {
+	horizontal, vertical := direction.Components()
+
+	for _, steps := range [][2]types.Direction{{horizontal, vertical}, {vertical, horizontal}} {
+		first := PickClosestCell(currentCell, layout.GetAdjacentCells(currentCell, cellBounds)[steps[0]], cellBounds)
+		if first == "" {
+			continue
+		}
+		second := PickClosestCell(first, layout.GetAdjacentCells(first, cellBounds)[steps[1]], cellBounds)
+		if second != "" && second != currentCell {
+			return second
+		}
+	}
+	return ""
+}
+
 // overlapsVertically checks if two rects have vertical overlap.
 func overlapsVertically(a, b types.Rect) bool {
 	return a.Y < b.Y+b.Height && a.Y+a.Height > b.Y
@@ -521,8 +708,9 @@ func overlapsHorizontally(a, b types.Rect) bool {
 
 // MoveFocusOpts configures focus movement behavior
 type MoveFocusOpts struct {
-	WrapAround bool // Wrap within current monitor (existing behavior)
-	Extend     bool // Allow crossing to adjacent monitors
+	WrapAround        bool // Wrap within current monitor (existing behavior)
+	Extend            bool // Allow crossing to adjacent monitors
+	MouseFollowsFocus bool // Warp the cursor to the newly focused window
 }
 
 // FindAdjacentDisplay finds the display adjacent to the current one in the given direction.
@@ -619,6 +807,151 @@ func MatchVisualPosition(sourceCell types.Rect, sourceDisplay, targetDisplay typ
 	return types.Point{X: targetX, Y: targetY}
 }
 
+// edgeMatchTolerance is how far apart, in pixels, cell edges may be and still
+// count as the same grid line
+const edgeMatchTolerance = 1.0
+
+// CrossDisplayCell picks the cell on the target display that focus or a
+// window crossing displays in direction lands in, according to mapping.
+// sourceCells are the cells on the source display; targetCells those on the
+// target. Diagonal directions always map proportionally.
+func CrossDisplayCell(
+	mapping config.CrossDisplayMapping,
+	direction types.Direction,
+	sourceCell string,
+	sourceCells map[string]types.Rect,
+	sourceDisplay, targetDisplay types.Rect,
+	targetCells map[string]types.Rect,
+) string {
+	if !direction.IsDiagonal() {
+		switch mapping {
+		case config.CrossDisplayNearestEdge:
+			return nearestEdgeCell(direction, sourceCells[sourceCell], targetCells)
+		case config.CrossDisplayPreserveRow:
+			return preserveRowCell(direction, sourceCell, sourceCells, targetCells)
+		}
+	}
+
+	targetPoint := MatchVisualPosition(sourceCells[sourceCell], sourceDisplay, targetDisplay)
+	return FindClosestCellToPoint(targetPoint, targetCells)
+}
+
+// entryCells returns the target cells along the edge entered when moving in
+// direction: the leftmost column when moving right, the top row moving down
+func entryCells(direction types.Direction, cells map[string]types.Rect) map[string]types.Rect {
+	edge := func(r types.Rect) float64 {
+		switch direction {
+		case types.DirRight:
+			return -r.X
+		case types.DirLeft:
+			return r.X + r.Width
+		case types.DirDown:
+			return -r.Y
+		default:
+			return r.Y + r.Height
+		}
+	}
+
+	best := -math.MaxFloat64
+	for _, r := range cells {
+		best = math.Max(best, edge(r))
+	}
+	entry := make(map[string]types.Rect)
+	for id, r := range cells {
+		if best-edge(r) <= edgeMatchTolerance {
+			entry[id] = r
+		}
+	}
+	return entry
+}
+
+// perpendicularSpan returns a rect's extent across direction: its vertical
+// span for left/right moves, its horizontal span for up/down
+func perpendicularSpan(direction types.Direction, r types.Rect) (start, end float64) {
+	if direction == types.DirLeft || direction == types.DirRight {
+		return r.Y, r.Y + r.Height
+	}
+	return r.X, r.X + r.Width
+}
+
+// nearestEdgeCell picks the entry cell that physically lines up best with
+// the source cell's center, ignoring the displays' relative sizes
+func nearestEdgeCell(direction types.Direction, source types.Rect, targetCells map[string]types.Rect) string {
+	center := source.Center()
+	pos := center.Y
+	if direction == types.DirUp || direction == types.DirDown {
+		pos = center.X
+	}
+
+	bestCell := ""
+	bestGap, bestDist := math.MaxFloat64, math.MaxFloat64
+	for id, r := range entryCells(direction, targetCells) {
+		start, end := perpendicularSpan(direction, r)
+		// 0 when the cell spans the source center; the distance to the
+		// cell's middle breaks ties, e.g. a center on a cell boundary
+		gap := math.Max(0, math.Max(start-pos, pos-end))
+		dist := math.Abs((start+end)/2 - pos)
+		if gap < bestGap || (gap == bestGap && (dist < bestDist || (dist == bestDist && id < bestCell))) {
+			bestCell, bestGap, bestDist = id, gap, dist
+		}
+	}
+	return bestCell
+}
+
+// preserveRowCell picks the entry cell in the same row as the source cell,
+// counted from the top (the same column counted from the left when moving up
+// or down). Rows past the target's last land in its last.
+func preserveRowCell(direction types.Direction, sourceCell string, sourceCells, targetCells map[string]types.Rect) string {
+	source, ok := sourceCells[sourceCell]
+	if !ok {
+		return ""
+	}
+	sourceStart, _ := perpendicularSpan(direction, source)
+	row := 0
+	for _, start := range gridLines(direction, sourceCells) {
+		if start < sourceStart-edgeMatchTolerance {
+			row++
+		}
+	}
+
+	entry := entryCells(direction, targetCells)
+	lines := gridLines(direction, entry)
+	if len(lines) == 0 {
+		return ""
+	}
+	if row >= len(lines) {
+		row = len(lines) - 1
+	}
+
+	bestCell := ""
+	for id, r := range entry {
+		start, _ := perpendicularSpan(direction, r)
+		if math.Abs(start-lines[row]) <= edgeMatchTolerance && (bestCell == "" || id < bestCell) {
+			bestCell = id
+		}
+	}
+	return bestCell
+}
+
+// gridLines returns the distinct starts of the cells' perpendicular spans,
+// sorted: the top edges of their rows for left/right moves
+func gridLines(direction types.Direction, cells map[string]types.Rect) []float64 {
+	var lines []float64
+	for _, r := range cells {
+		start, _ := perpendicularSpan(direction, r)
+		lines = append(lines, start)
+	}
+	sort.Float64s(lines)
+
+	distinct := lines[:0]
+	for _, line := range lines {
+		if len(distinct) == 0 || line-distinct[len(distinct)-1] > edgeMatchTolerance {
+			distinct = append(distinct, line)
+		}
+	}
+	return distinct
+}
+
 // FindClosestCellToPoint finds the cell whose center is closest to the given point.
 // Returns empty string if cellBounds is empty.
 func FindClosestCellToPoint(point types.Point, cellBounds map[string]types.Rect) string {
@@ -662,7 +995,7 @@ func GetDisplayCells(displayInfo server.DisplayInfo, cfg *config.Config, rs *sta
 		return nil, currentSpaceID, fmt.Errorf("space %s has no active layout", spaceIDStr)
 	}
 
-	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
 	if err != nil {
 		return nil, currentSpaceID, fmt.Errorf("layout %s not found: %w", spaceState.CurrentLayoutID, err)
 	}
@@ -678,10 +1011,11 @@ func GetDisplayCells(displayInfo server.DisplayInfo, cfg *config.Config, rs *sta
 	}
 
 	// Calculate layout bounds
-	calculated := layout.CalculateLayout(layoutDef, displayBounds, float64(cfg.Settings.CellPadding))
+	calculated := layout.CalculateSpaceLayout(layoutDef, displayBounds, float64(cfg.Settings.CellPadding), spaceState)
 	if calculated == nil {
 		return nil, currentSpaceID, fmt.Errorf("failed to calculate layout for space %s", spaceIDStr)
 	}
 
-	return calculated.CellBounds, currentSpaceID, nil
+	// Arriving from another display never lands in a skipNavigation cell
+	return layout.NavigableCells(layoutDef, calculated.CellBounds, ""), currentSpaceID, nil
 }
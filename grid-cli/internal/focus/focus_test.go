@@ -0,0 +1,344 @@
+package focus
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestFindDiagonalStep(t *testing.T) {
+	// Full-width top cell over two bottom cells: nothing sits diagonally
+	// across from "br", so up-left goes left to "bl" and then up to "top".
+	cellBounds := map[string]types.Rect{
+		"top": {X: 0, Y: 0, Width: 210, Height: 100},
+		"bl":  {X: 0, Y: 110, Width: 100, Height: 100},
+		"br":  {X: 110, Y: 110, Width: 100, Height: 100},
+	}
+
+	if got := FindDiagonalStep(types.DirUpLeft, "br", cellBounds); got != "top" {
+		t.Errorf("up-left from br = %q, want top", got)
+	}
+	if got := FindDiagonalStep(types.DirDownLeft, "br", cellBounds); got != "" {
+		t.Errorf("down-left from br = %q, want none", got)
+	}
+}
+
+func TestFindDiagonalStep_VerticalFirst(t *testing.T) {
+	// Full-height left cell beside two right cells: from "br", going left
+	// first reaches "main" which has nothing above, so up then left is used.
+	cellBounds := map[string]types.Rect{
+		"main": {X: 0, Y: 0, Width: 100, Height: 210},
+		"tr":   {X: 110, Y: 0, Width: 100, Height: 100},
+		"br":   {X: 110, Y: 110, Width: 100, Height: 100},
+	}
+
+	if got := FindDiagonalStep(types.DirUpLeft, "br", cellBounds); got != "main" {
+		t.Errorf("up-left from br = %q, want main", got)
+	}
+}
+
+// lastFocused returns the window of the last window.focus request
+func lastFocused(s *testutil.Server) uint32 {
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == "window.focus" {
+			id, _ := requests[i].Params["windowId"].(float64)
+			return uint32(id)
+		}
+	}
+	return 0
+}
+
+func TestMoveFocus_PerAxisWrap(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	noWrap := false
+	wrap := config.FocusWrap{Vertical: &noWrap}
+
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "quad",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr", "1fr"}},
+				Areas: [][]string{{"tl", "tr"}, {"bl", "br"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("quad", 0)
+	space.AssignWindow(101, "tl")
+	space.AssignWindow(102, "tr")
+	space.AssignWindow(103, "bl")
+	space.SetFocus("tl", 0)
+
+	c, s := startInfoServer(t, nil)
+	ctx := context.Background()
+
+	// Up from the top edge with vertical wrap off goes nowhere
+	opts := MoveFocusOpts{WrapAround: wrap.Wraps(types.DirUp)}
+	_, err := MoveFocus(ctx, c, snap, cfg, rs, types.DirUp, opts)
+	if err == nil || !strings.Contains(err.Error(), "no cell in direction up") {
+		t.Errorf("expected no-op at top edge, got %v", err)
+	}
+	if space.FocusedCell != "tl" {
+		t.Errorf("focus should stay on tl, got %q", space.FocusedCell)
+	}
+	if s.Request("window.focus") != nil {
+		t.Error("expected no window to be focused")
+	}
+
+	// Left from the left edge still wraps to the right edge
+	opts = MoveFocusOpts{WrapAround: wrap.Wraps(types.DirLeft)}
+	if _, err := MoveFocus(ctx, c, snap, cfg, rs, types.DirLeft, opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastFocused(s); got != 102 {
+		t.Errorf("expected horizontal wrap to focus window 102 in tr, got %d", got)
+	}
+}
+
+func TestMostRecentUrgent(t *testing.T) {
+	urgent := func(id uint32, space string, since float64) server.UrgentWindow {
+		return server.UrgentWindow{
+			WindowInfo: server.WindowInfo{ID: id, IsUrgent: true, UrgentSince: since},
+			SpaceID:    space,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		urgent []server.UrgentWindow
+		want   uint32
+	}{
+		{"latest wins across spaces", []server.UrgentWindow{urgent(10, "1", 100), urgent(20, "4", 200)}, 20},
+		{"current space breaks ties", []server.UrgentWindow{urgent(10, "4", 0), urgent(20, "1", 0)}, 20},
+		{"lowest ID breaks remaining ties", []server.UrgentWindow{urgent(30, "4", 0), urgent(20, "4", 0)}, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MostRecentUrgent(&server.Snapshot{SpaceID: "1", Urgent: tt.urgent})
+			if got == nil || got.ID != tt.want {
+				t.Errorf("MostRecentUrgent = %+v, want window %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFocusUrgent_NoneUrgent(t *testing.T) {
+	snap := &server.Snapshot{SpaceID: "1"}
+
+	// No server calls are made when nothing is urgent
+	got, err := FocusUrgent(context.Background(), nil, snap, state.NewRuntimeState())
+	if err != nil || got != nil {
+		t.Errorf("expected no urgent window and no error, got %+v, %v", got, err)
+	}
+}
+
+func TestFocusUrgent_SwitchesSpace(t *testing.T) {
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Urgent: []server.UrgentWindow{{
+			WindowInfo: server.WindowInfo{ID: 20, IsUrgent: true},
+			SpaceID:    "4",
+		}},
+	}
+	c, s := startInfoServer(t, nil)
+
+	got, err := FocusUrgent(context.Background(), c, snap, state.NewRuntimeState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.ID != 20 {
+		t.Errorf("expected urgent window 20, got %+v", got)
+	}
+
+	// The space switch comes before the focus
+	methods := s.Methods()
+	if len(methods) < 2 || methods[0] != "space.focus" || methods[1] != "window.focus" {
+		t.Fatalf("expected space.focus then window.focus, got %v", methods)
+	}
+	if space := s.Request("space.focus").Params["spaceId"]; space != "4" {
+		t.Errorf("switched to space %v, want 4", space)
+	}
+	if id := lastFocused(s); id != 20 {
+		t.Errorf("focused window %d, want 20", id)
+	}
+}
+
+func TestMoveFocus_SkipsNavigationCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:   "three",
+				Grid: config.GridConfig{Columns: []string{"1fr", "1fr", "1fr"}, Rows: []string{"1fr"}},
+				Cells: []config.CellConfig{
+					{ID: "left", Column: "1/2", Row: "1/2"},
+					{ID: "video", Column: "2/3", Row: "1/2", SkipNavigation: true},
+					{ID: "right", Column: "3/4", Row: "1/2"},
+				},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("three", 0)
+	space.AssignWindow(101, "left")
+	space.AssignWindow(102, "video")
+	space.AssignWindow(103, "right")
+	space.SetFocus("left", 0)
+
+	c, s := startInfoServer(t, nil)
+	ctx := context.Background()
+
+	if _, err := MoveFocus(ctx, c, snap, cfg, rs, types.DirRight, MoveFocusOpts{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastFocused(s); got != 103 {
+		t.Errorf("expected focus to jump over video to window 103, got %d", got)
+	}
+
+	// The skipped cell is still reachable directly
+	if _, err := FocusCell(ctx, c, rs, "1", "video"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastFocused(s); got != 102 {
+		t.Errorf("expected focus cell to focus window 102, got %d", got)
+	}
+}
+
+func TestFocusBack_TargetsPreviousWindow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("cols", 0)
+	space.AssignWindow(101, "left")
+	space.AssignWindow(102, "right")
+	space.SetFocus("left", 0)
+	space.PushFocusHistory()
+	space.SetFocus("right", 0)
+
+	c, s := startInfoServer(t, nil)
+
+	if _, err := FocusBack(context.Background(), c, rs, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastFocused(s); got != 101 {
+		t.Errorf("expected focus back to focus window 101, got %d", got)
+	}
+
+	_, err := FocusBack(context.Background(), c, rs, "1")
+	if err == nil || !strings.Contains(err.Error(), "no previous window") {
+		t.Errorf("expected empty history, got %v", err)
+	}
+}
+
+func TestCycleFocus_MRUOrder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c, s := startInfoServer(t, nil)
+
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 0)
+	for _, id := range []uint32{101, 102, 103} {
+		space.AssignWindow(id, "main")
+	}
+	// Focused 103, then 101, then 102 (now focused)
+	space.SetFocus("main", 2)
+	space.SetFocus("main", 0)
+	space.SetFocus("main", 1)
+
+	var visited []uint32
+	for i := 0; i < 3; i++ {
+		windowID, err := CycleFocus(context.Background(), c, rs, "1", true, CycleByMRU)
+		if err != nil {
+			t.Fatal(err)
+		}
+		visited = append(visited, windowID)
+	}
+
+	// Positional order from 102 would be 103, 101, 102
+	if !reflect.DeepEqual(visited, []uint32{101, 103, 102}) {
+		t.Errorf("visited %v, want last-focused order [101 103 102]", visited)
+	}
+	if s.Request("window.focus") == nil {
+		t.Error("expected windows to be focused via the server")
+	}
+}
+
+func TestCrossDisplayCell_Mappings(t *testing.T) {
+	// A landscape display with three uneven rows on the right, next to a
+	// tall portrait display split into two columns of four rows
+	sourceDisplay := types.Rect{X: 0, Y: 0, Width: 2000, Height: 1000}
+	sourceCells := map[string]types.Rect{
+		"side": {X: 0, Y: 0, Width: 1000, Height: 1000},
+		"a":    {X: 1000, Y: 0, Width: 1000, Height: 400},
+		"b":    {X: 1000, Y: 400, Width: 1000, Height: 300},
+		"c":    {X: 1000, Y: 700, Width: 1000, Height: 300},
+	}
+	targetDisplay := types.Rect{X: 2000, Y: 0, Width: 1000, Height: 4000}
+	targetCells := make(map[string]types.Rect)
+	for row := 0; row < 4; row++ {
+		y := float64(row) * 1000
+		targetCells["L"+string(rune('1'+row))] = types.Rect{X: 2000, Y: y, Width: 500, Height: 1000}
+		targetCells["R"+string(rune('1'+row))] = types.Rect{X: 2500, Y: y, Width: 500, Height: 1000}
+	}
+
+	tests := []struct {
+		mapping config.CrossDisplayMapping
+		want    string
+	}{
+		// Same relative position: 75% across, 55% down
+		{"", "R3"},
+		{config.CrossDisplayProportional, "R3"},
+		// Entered left column, at the source cell's height on screen
+		{config.CrossDisplayNearestEdge, "L1"},
+		// Entered left column, second row like the source cell
+		{config.CrossDisplayPreserveRow, "L2"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mapping), func(t *testing.T) {
+			got := CrossDisplayCell(tt.mapping, types.DirRight, "b", sourceCells, sourceDisplay, targetDisplay, targetCells)
+			if got != tt.want {
+				t.Errorf("CrossDisplayCell(%q) = %q, want %q", tt.mapping, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrossDisplayCell_PreserveRowMovingDown(t *testing.T) {
+	// Moving down keeps the column: the third column of the source lands in
+	// the last (second) column of the target's top row
+	sourceDisplay := types.Rect{X: 0, Y: 0, Width: 1500, Height: 500}
+	sourceCells := map[string]types.Rect{
+		"one":   {X: 0, Y: 0, Width: 500, Height: 500},
+		"two":   {X: 500, Y: 0, Width: 500, Height: 500},
+		"three": {X: 1000, Y: 0, Width: 500, Height: 500},
+	}
+	targetDisplay := types.Rect{X: 0, Y: 500, Width: 1000, Height: 1000}
+	targetCells := map[string]types.Rect{
+		"topLeft":  {X: 0, Y: 500, Width: 500, Height: 500},
+		"topRight": {X: 500, Y: 500, Width: 500, Height: 500},
+		"bottom":   {X: 0, Y: 1000, Width: 1000, Height: 500},
+	}
+
+	got := CrossDisplayCell(config.CrossDisplayPreserveRow, types.DirDown, "three", sourceCells, sourceDisplay, targetDisplay, targetCells)
+	if got != "topRight" {
+		t.Errorf("CrossDisplayCell() = %q, want topRight", got)
+	}
+}
@@ -0,0 +1,44 @@
+package focus
+
+import (
+	"context"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+// MouseWarpCapability is the getServerInfo capability a server reports when
+// it handles mouse.warp
+const MouseWarpCapability = "mouseWarp"
+
+// WarpMouseToWindow moves the cursor to the center of a window's frame, for
+// settings.mouseFollowsFocus. It's best effort: a server without the
+// mouseWarp capability, or a window outside the snapshot (e.g. on another
+// display's space), leaves the cursor where it is.
+func WarpMouseToWindow(ctx context.Context, c *client.Client, snap *server.Snapshot, windowID uint32) {
+	var window *server.WindowInfo
+	for i := range snap.Windows {
+		if snap.Windows[i].ID == windowID {
+			window = &snap.Windows[i]
+			break
+		}
+	}
+	if window == nil {
+		logging.Debug().Uint32("windowId", windowID).Msg("mouse follows focus: window frame unknown, not warping")
+		return
+	}
+
+	if !c.HasCapability(ctx, MouseWarpCapability) {
+		logging.Debug().Msg("mouse follows focus: server lacks mouse.warp, not warping")
+		return
+	}
+
+	center := window.Frame.Center()
+	if _, err := c.CallMethod(ctx, "mouse.warp", map[string]interface{}{
+		"x": center.X,
+		"y": center.Y,
+	}); err != nil {
+		logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to warp mouse to focused window")
+	}
+}
@@ -0,0 +1,70 @@
+package focus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// startInfoServer starts a fake server that answers getServerInfo with the
+// given capabilities and every other request with an empty result
+func startInfoServer(t *testing.T, capabilities map[string]interface{}) (*client.Client, *testutil.Server) {
+	t.Helper()
+	s := &testutil.Server{
+		Handle: func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+			if req.Method == "getServerInfo" {
+				return map[string]interface{}{"capabilities": capabilities}, nil
+			}
+			return nil, nil
+		},
+	}
+	c := client.NewClient(s.Start(t), 0)
+	t.Cleanup(func() { c.Close() })
+	return c, s
+}
+
+func mouseSnapshot() *server.Snapshot {
+	return &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 7, Frame: types.Rect{X: 100, Y: 50, Width: 800, Height: 600}},
+		},
+	}
+}
+
+func TestWarpMouseToWindow_WarpsToCenter(t *testing.T) {
+	c, s := startInfoServer(t, map[string]interface{}{MouseWarpCapability: true})
+
+	WarpMouseToWindow(context.Background(), c, mouseSnapshot(), 7)
+
+	warp := s.Request("mouse.warp")
+	if warp == nil {
+		t.Fatal("expected mouse.warp to be called")
+	}
+	if warp.Params["x"] != float64(500) || warp.Params["y"] != float64(350) {
+		t.Errorf("warped to (%v, %v), want (500, 350)", warp.Params["x"], warp.Params["y"])
+	}
+}
+
+func TestWarpMouseToWindow_NoCapability(t *testing.T) {
+	c, s := startInfoServer(t, map[string]interface{}{"windows": true})
+
+	WarpMouseToWindow(context.Background(), c, mouseSnapshot(), 7)
+
+	if s.Request("getServerInfo") == nil {
+		t.Error("expected capabilities to be checked")
+	}
+	if s.Request("mouse.warp") != nil {
+		t.Error("mouse.warp should not be called without the capability")
+	}
+}
+
+func TestWarpMouseToWindow_UnknownWindow(t *testing.T) {
+	// No frame to warp to: returns before talking to the server
+	WarpMouseToWindow(context.Background(), nil, mouseSnapshot(), 99)
+}
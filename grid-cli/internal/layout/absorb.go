@@ -0,0 +1,178 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// SpanCellBounds returns cellBounds with each spanning cell grown to cover
+// the empty cells it absorbed (state.SpaceState.Spans). Absorbed cells that
+// hold windows in assignments are left out, so a window placed into one
+// takes the cell back.
+func SpanCellBounds(cellBounds map[string]types.Rect, spans map[string][]string, assignments map[string][]uint32) map[string]types.Rect {
+	if len(spans) == 0 {
+		return cellBounds
+	}
+
+	result := make(map[string]types.Rect, len(cellBounds))
+	for id, bounds := range cellBounds {
+		result[id] = bounds
+	}
+	for cellID, absorbed := range spans {
+		bounds, ok := result[cellID]
+		if !ok {
+			continue
+		}
+		for _, id := range absorbed {
+			if other, ok := cellBounds[id]; ok && len(assignments[id]) == 0 {
+				bounds = unionRect(bounds, other)
+			}
+		}
+		result[cellID] = bounds
+	}
+	return result
+}
+
+// CalculateSpaceLayout is CalculateLayout for a space as ApplyLayout places
+// it: cells that absorbed empty neighbours (resize absorb) cover them.
+// spaceState may be nil.
+func CalculateSpaceLayout(layoutDef *types.Layout, screenRect types.Rect, gap float64, spaceState *state.SpaceState) *types.CalculatedLayout {
+	calculated := CalculateLayout(layoutDef, screenRect, gap)
+	if calculated == nil || spaceState == nil || len(spaceState.Spans) == 0 {
+		return calculated
+	}
+	calculated.CellBounds = SpanCellBounds(calculated.CellBounds, spaceState.Spans, spaceAssignments(spaceState))
+	return calculated
+}
+
+// spaceAssignments returns the windows of each of the space's cells
+func spaceAssignments(spaceState *state.SpaceState) map[string][]uint32 {
+	assignments := make(map[string][]uint32, len(spaceState.Cells))
+	for id, cell := range spaceState.Cells {
+		assignments[id] = cell.Windows
+	}
+	return assignments
+}
+
+// unionRect returns the smallest rect covering a and b
+func unionRect(a, b types.Rect) types.Rect {
+	x := math.Min(a.X, b.X)
+	y := math.Min(a.Y, b.Y)
+	return types.Rect{
+		X:      x,
+		Y:      y,
+		Width:  math.Max(a.X+a.Width, b.X+b.Width) - x,
+		Height: math.Max(a.Y+a.Height, b.Y+b.Height) - y,
+	}
+}
+
+// AbsorbCell makes cellID span into the empty cell next to it in direction,
+// recording the span in spaceState. The neighbour must line up with the
+// cell's current (possibly already spanned) edge so the two still form a
+// rectangle. Returns the absorbed cell's ID.
+func AbsorbCell(spaceState *state.SpaceState, cellBounds map[string]types.Rect, cellID string, direction types.Direction) (string, error) {
+	assignments := spaceAssignments(spaceState)
+	spanned := SpanCellBounds(cellBounds, spaceState.Spans, assignments)
+	current, ok := spanned[cellID]
+	if !ok {
+		return "", fmt.Errorf("cell %s not found in layout", cellID)
+	}
+
+	// Cells already absorbed by some cell are taken
+	candidates := make(map[string]types.Rect, len(spanned))
+	for id, bounds := range spanned {
+		if id == cellID || spaceState.SpanningCell(id) == "" {
+			candidates[id] = bounds
+		}
+	}
+
+	target := ""
+	for _, id := range GetAdjacentCells(cellID, candidates)[direction] {
+		if !linesUp(current, candidates[id], direction) {
+			continue
+		}
+		if target == "" || edgeDistance(current, candidates[id], direction) < edgeDistance(current, candidates[target], direction) {
+			target = id
+		}
+	}
+	if target == "" {
+		return "", fmt.Errorf("no cell %s of %s lines up with it", direction, cellID)
+	}
+	if len(assignments[target]) > 0 {
+		return "", fmt.Errorf("cell %s is not empty", target)
+	}
+
+	spaceState.AddSpan(cellID, target)
+	return target, nil
+}
+
+// linesUp reports whether b shares a's full edge facing direction
+func linesUp(a, b types.Rect, direction types.Direction) bool {
+	const tolerance = 1.0
+	if direction == types.DirLeft || direction == types.DirRight {
+		return math.Abs(a.Y-b.Y) <= tolerance && math.Abs(a.Height-b.Height) <= tolerance
+	}
+	return math.Abs(a.X-b.X) <= tolerance && math.Abs(a.Width-b.Width) <= tolerance
+}
+
+// edgeDistance is the gap between a's edge facing direction and b
+func edgeDistance(a, b types.Rect, direction types.Direction) float64 {
+	switch direction {
+	case types.DirLeft:
+		return a.X - (b.X + b.Width)
+	case types.DirRight:
+		return b.X - (a.X + a.Width)
+	case types.DirUp:
+		return a.Y - (b.Y + b.Height)
+	default:
+		return b.Y - (a.Y + a.Height)
+	}
+}
+
+// AbsorbAdjacentCell grows the focused cell over the empty cell next to it
+// in direction, see AbsorbCell, and reapplies the layout. Returns the
+// absorbed cell's ID.
+func AbsorbAdjacentCell(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+) (string, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return "", fmt.Errorf("no layout applied")
+	}
+	cellID := spaceState.FocusedCell
+	if cellID == "" {
+		return "", fmt.Errorf("no focused cell")
+	}
+
+	layoutDef, err := SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return "", fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := CalculateLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding))
+
+	absorbed, err := AbsorbCell(rs.GetSpace(snap.SpaceID), calculated.CellBounds, cellID, direction)
+	if err != nil {
+		return "", err
+	}
+	rs.MarkUpdated()
+
+	// The reapply saves the span along with the new placements
+	opts := DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	if err := ReapplyLayout(ctx, c, snap, cfg, rs, opts); err != nil {
+		return absorbed, err
+	}
+	return absorbed, nil
+}
@@ -0,0 +1,125 @@
+package layout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// threeColumnBounds lays out cells a, b and c side by side, 600px each
+func threeColumnBounds() map[string]types.Rect {
+	return map[string]types.Rect{
+		"a": {X: 0, Width: 600, Height: 1000},
+		"b": {X: 600, Width: 600, Height: 1000},
+		"c": {X: 1200, Width: 600, Height: 1000},
+	}
+}
+
+func TestAbsorbCell_EmptyRightDoublesWidth(t *testing.T) {
+	ss := state.NewRuntimeState().GetSpace("1")
+	ss.AssignWindow(20, "a")
+
+	absorbed, err := AbsorbCell(ss, threeColumnBounds(), "a", types.DirRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if absorbed != "b" {
+		t.Errorf("absorbed %q, want b", absorbed)
+	}
+
+	bounds := SpanCellBounds(threeColumnBounds(), ss.Spans, map[string][]uint32{"a": {20}})
+	placements := CalculateAllWindowPlacements(
+		&types.CalculatedLayout{CellBounds: bounds},
+		map[string][]uint32{"a": {20}},
+		nil, nil, types.StackVertical, 0,
+	)
+	if len(placements) != 1 || placements[0].Bounds.Width != 1200 {
+		t.Errorf("expected window 20 to double to 1200px, got %+v", placements)
+	}
+
+	// The next absorb continues from the spanned edge
+	if absorbed, err := AbsorbCell(ss, threeColumnBounds(), "a", types.DirRight); err != nil || absorbed != "c" {
+		t.Errorf("second absorb = %q, %v, want c", absorbed, err)
+	}
+}
+
+func TestAbsorbCell_NonEmptyCellNotAbsorbed(t *testing.T) {
+	ss := state.NewRuntimeState().GetSpace("1")
+	ss.AssignWindow(20, "a")
+	ss.AssignWindow(21, "b")
+
+	if _, err := AbsorbCell(ss, threeColumnBounds(), "a", types.DirRight); err == nil {
+		t.Error("expected an occupied cell to be refused")
+	}
+	if _, err := AbsorbCell(ss, threeColumnBounds(), "a", types.DirLeft); err == nil {
+		t.Error("expected no cell to the left of a")
+	}
+	if ss.Spans != nil {
+		t.Errorf("expected no span recorded, got %v", ss.Spans)
+	}
+}
+
+func TestSpanCellBounds_WindowTakesCellBack(t *testing.T) {
+	spans := map[string][]string{"a": {"b"}}
+	bounds := SpanCellBounds(threeColumnBounds(), spans, map[string][]uint32{"a": {20}, "b": {21}})
+	if bounds["a"].Width != 600 || bounds["b"].Width != 600 {
+		t.Errorf("expected an occupied cell to keep its bounds, got %+v", bounds)
+	}
+}
+
+func TestCalculateSpaceLayout_UsesCellSpans(t *testing.T) {
+	layoutDef, err := fullLayoutConfig().GetLayout("half")
+	if err != nil {
+		t.Fatal(err)
+	}
+	display := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	ss := state.NewRuntimeState().GetSpace("1")
+	ss.AssignWindow(20, "a")
+
+	if got := CalculateSpaceLayout(layoutDef, display, 0, ss).CellBounds["a"]; got.Width != 960 {
+		t.Errorf("expected cell a unspanned at 960px, got %+v", got)
+	}
+	ss.AddSpan("a", "b")
+	if got := CalculateSpaceLayout(layoutDef, display, 0, ss).CellBounds["a"]; got != display {
+		t.Errorf("expected cell a spanning the display, got %+v", got)
+	}
+	if got := CalculateSpaceLayout(layoutDef, display, 0, nil).CellBounds["a"]; got.Width != 960 {
+		t.Errorf("expected no spans without space state, got %+v", got)
+	}
+}
+
+func TestApplyLayout_UsesCellSpans(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Window 20 already covers both halves, where spanning cell a puts it
+	display := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: display,
+		Windows:       []server.WindowInfo{{ID: 20, AppName: "Safari", Frame: display}},
+		WindowIDs:     map[uint32]bool{20: true},
+	}
+	rs := state.NewRuntimeState()
+	ss := rs.GetSpace("1")
+	ss.CurrentLayoutID = "half"
+	ss.AssignWindow(20, "a")
+	ss.AddSpan("a", "b")
+
+	opts := DefaultApplyOptions()
+	opts.Gap = 0
+	opts.Padding = 0
+	opts.Placements = map[uint32]string{20: "a"}
+	result, err := ApplyLayoutWithResult(context.Background(), nil, snap, fullLayoutConfig(), rs, "half", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 1 || result.Applied != 0 {
+		t.Errorf("expected window 20 already at the spanned frame, got %+v", result)
+	}
+	if spans := rs.GetSpaceReadOnly("1").Spans; len(spans["a"]) != 1 {
+		t.Errorf("expected the span to survive a reapply, got %v", spans)
+	}
+}
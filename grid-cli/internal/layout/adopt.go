@@ -0,0 +1,55 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// AdoptWindowPositions rebuilds a space's state from where its windows are
+// now, for read-only mode where nothing is remembered between runs. The
+// space's default layout (see config.ResolveLayoutID) becomes current and
+// each window is assigned to the cell it overlaps most; no window is moved.
+// A space that already has a layout in state is left alone, as is one with no
+// default layout configured. Returns the layout adopted ("" if none).
+func AdoptWindowPositions(snap *server.Snapshot, cfg *config.Config, rs *state.RuntimeState) (string, error) {
+	if space := rs.GetSpaceReadOnly(snap.SpaceID); space != nil && space.CurrentLayoutID != "" {
+		return "", nil
+	}
+
+	layoutID, err := cfg.ResolveLayoutID(snap.SpaceID, SpaceDisplay(snap, snap.SpaceID), "")
+	if err != nil {
+		logging.Debug().Str("space", snap.SpaceID).Msg("adopt: no default layout")
+		return "", nil
+	}
+	layoutDef, err := cfg.GetLayout(layoutID)
+	if err != nil {
+		return "", fmt.Errorf("layout not found: %w", err)
+	}
+
+	calculated := CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), rs.GetSpaceReadOnly(snap.SpaceID))
+	assignment := AssignWindowsWithOverrides(
+		convertWindows(snap.Windows),
+		layoutDef,
+		calculated.CellBounds,
+		cfg.AppRules,
+		nil,
+		types.AssignPosition,
+		"",
+		WindowOverrides{},
+	)
+
+	rs.GetSpace(snap.SpaceID).SetCurrentLayout(layoutID, findLayoutIndex(cfg, layoutID))
+	rs.SetWindowAssignments(snap.SpaceID, assignment.Assignments)
+
+	logging.Debug().
+		Str("space", snap.SpaceID).
+		Str("layout", layoutID).
+		Int("windows", len(snap.Windows)).
+		Msg("adopt: assigned windows by position")
+	return layoutID, nil
+}
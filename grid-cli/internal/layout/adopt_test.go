@@ -0,0 +1,59 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+func TestAdoptWindowPositions(t *testing.T) {
+	cfg := fullLayoutConfig()
+	cfg.Settings.DefaultLayout = "half"
+	rs := state.NewRuntimeState()
+
+	layoutID, err := AdoptWindowPositions(stickySnapshot(), cfg, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layoutID != "half" {
+		t.Fatalf("adopted %q, want half", layoutID)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if space.CurrentLayoutID != "half" {
+		t.Errorf("current layout = %q, want half", space.CurrentLayoutID)
+	}
+	// Window 21 starts at x=900 but mostly overlaps the right cell
+	if got := space.Cells["a"].Windows; !reflect.DeepEqual(got, []uint32{20}) {
+		t.Errorf("cell a = %v, want [20]", got)
+	}
+	if got := space.Cells["b"].Windows; !reflect.DeepEqual(got, []uint32{21}) {
+		t.Errorf("cell b = %v, want [21]", got)
+	}
+}
+
+func TestAdoptWindowPositions_KeepsExistingLayout(t *testing.T) {
+	cfg := fullLayoutConfig()
+	cfg.Settings.DefaultLayout = "half"
+	rs := state.NewRuntimeState()
+	rs.GetSpace("1").SetCurrentLayout("full", 1)
+	rs.GetSpace("1").AssignWindow(20, "main")
+
+	if layoutID, err := AdoptWindowPositions(stickySnapshot(), cfg, rs); err != nil || layoutID != "" {
+		t.Fatalf("expected nothing adopted, got %q, %v", layoutID, err)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(20); got != "main" {
+		t.Errorf("window 20 moved to %q", got)
+	}
+}
+
+func TestAdoptWindowPositions_NoDefaultLayout(t *testing.T) {
+	rs := state.NewRuntimeState()
+	if layoutID, err := AdoptWindowPositions(stickySnapshot(), fullLayoutConfig(), rs); err != nil || layoutID != "" {
+		t.Fatalf("expected nothing adopted, got %q, %v", layoutID, err)
+	}
+	if space := rs.GetSpaceReadOnly("1"); space != nil && space.CurrentLayoutID != "" {
+		t.Errorf("expected no layout, got %q", space.CurrentLayoutID)
+	}
+}
@@ -3,6 +3,8 @@ package layout
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/yourusername/grid-cli/internal/client"
 	"github.com/yourusername/grid-cli/internal/config"
@@ -14,11 +16,50 @@ import (
 
 // ApplyLayoutOptions configures layout application
 type ApplyLayoutOptions struct {
-	Strategy types.AssignmentStrategy // Window assignment strategy
-	Gap      float64                  // Gap between cells in pixels
-	Padding  float64                  // Padding between windows in same cell
+	Strategy   types.AssignmentStrategy // Window assignment strategy
+	Gap        float64                  // Gap between cells in pixels
+	Padding    float64                  // Padding between windows in same cell
+	Placements map[uint32]string        // Explicit window -> cell overrides for this apply
+
+	// OrientationAware flips the default stack mode on portrait displays,
+	// as if settings.orientationAwareStacks were enabled
+	OrientationAware bool
+
+	// PlaceNewAtFocus sends windows without a previous cell to the focused
+	// cell under the preserve strategy, as if settings.placeNewAtFocus were enabled
+	PlaceNewAtFocus bool
+
+	// LaunchEmpty launches each empty cell's configured app (cells[].launch)
+	// and assigns its new window to the cell
+	LaunchEmpty bool
+	Launch      LaunchOptions
+
+	// FocusWindow is the window focus follows into its new cell after the
+	// apply (0 = the space's focused window before the apply)
+	FocusWindow uint32
+
+	// AnimationDuration animates window moves over this many seconds on
+	// servers that can (0 = settings.animationDuration)
+	AnimationDuration float64
 }
 
+// ApplyResult reports what an apply actually changed
+type ApplyResult struct {
+	Applied int      // Windows repositioned via the server
+	Skipped int      // Windows already at their target frame
+	Sticky  []uint32 // Sticky windows left untiled
+
+	Launched []LaunchedWindow // Windows launched into empty cells
+}
+
+// AnimationCapability is the getServerInfo capability a server reports when
+// window updates take a duration to animate over
+const AnimationCapability = "windowAnimation"
+
+// placementTolerance is how far (in pixels) a window's frame may differ from
+// its target and still be considered in place
+const placementTolerance = 1.0
+
 // DefaultApplyOptions returns sensible default options
 func DefaultApplyOptions() ApplyLayoutOptions {
 	return ApplyLayoutOptions{
@@ -42,63 +83,119 @@ func ApplyLayout(
 	layoutID string,
 	opts ApplyLayoutOptions,
 ) error {
+	_, err := ApplyLayoutWithResult(ctx, c, snap, cfg, rs, layoutID, opts)
+	return err
+}
+
+// ApplyLayoutWithResult applies a layout like ApplyLayout and reports how
+// many windows were moved and how many were already in place.
+func ApplyLayoutWithResult(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	layoutID string,
+	opts ApplyLayoutOptions,
+) (*ApplyResult, error) {
 	// 1. Get layout from config
 	layout, err := cfg.GetLayout(layoutID)
 	if err != nil {
-		return fmt.Errorf("layout not found: %w", err)
+		return nil, fmt.Errorf("layout not found: %w", err)
 	}
+	layout = WithTrackOverrides(layout, rs.GetSpaceReadOnly(snap.SpaceID))
 
 	logging.Info().Str("layout", layoutID).Str("space", snap.SpaceID).Msg("applying layout")
 
+	// 1b. Run the preApply hook before anything moves
+	runApplyHook(ctx, cfg.Settings.Hooks, HookContext{Event: HookPreApply, SpaceID: snap.SpaceID, LayoutID: layoutID})
+
 	// 2. Calculate grid layout using snapshot's display bounds
 	calculatedLayout := CalculateLayout(layout, snap.DisplayBounds, opts.Gap)
 
-	// 3. Convert snapshot windows to layout windows
-	windows := convertWindows(snap.Windows)
+	// 3. Convert snapshot windows to layout windows, leaving out sticky
+	// windows unless settings.tileStickyWindows un-sticks them
+	tileWindows, sticky := PrepareStickyWindows(ctx, NewStickyChecker(c), snap.Windows, cfg.Settings.TileStickyWindows)
+	if len(sticky) > 0 {
+		logging.Info().Int("count", len(sticky)).Msg("skipping sticky windows")
+	}
+	windows := convertWindows(tileWindows)
 
-	// 4. Get previous assignments from local state
+	// 4. Get previous assignments and focus from local state
 	spaceState := rs.GetSpace(snap.SpaceID)
+	focusedWindow := opts.FocusWindow
+	if focusedWindow == 0 {
+		focusedWindow = spaceState.GetFocusedWindow()
+	}
 	previousAssignments := make(map[string][]uint32)
 	for cellID, cellState := range spaceState.Cells {
 		previousAssignments[cellID] = cellState.Windows
 	}
 
 	// 5. Assign windows to cells
-	assignment := AssignWindows(
+	if err := validatePlacements(opts.Placements, layout, snap); err != nil {
+		return nil, err
+	}
+	bsp := opts.Strategy == types.AssignBSP
+	focusedCell := ""
+	if opts.PlaceNewAtFocus || cfg.Settings.PlaceNewAtFocus || bsp {
+		focusedCell = spaceState.FocusedCell
+	}
+	assignment := AssignWindowsWithPlacements(
 		windows,
 		layout,
 		calculatedLayout.CellBounds,
 		cfg.AppRules,
 		previousAssignments,
 		opts.Strategy,
+		focusedCell,
+		opts.Placements,
+		WindowOverrides{
+			Pins:     spaceState.PinnedWindows,
+			Floated:  spaceState.FloatedWindows,
+			Reinsert: spaceState.ReinsertWindows,
+		},
 	)
+	if len(assignment.Unplaced) > 0 {
+		wid := assignment.Unplaced[0]
+		return nil, fmt.Errorf("placement %d=%s: window is minimized, hidden or an overlay", wid, opts.Placements[wid])
+	}
 
-	// 6. Get cell modes and ratios from config/state
+	// 5b. Launch apps into empty cells that name one. Cells whose app fails
+	// to launch stay empty; only a cancelled apply stops here.
+	placedWindows := snap.Windows
+	var launched []LaunchedWindow
+	if opts.LaunchEmpty {
+		known := make(map[uint32]bool, len(snap.Windows))
+		for _, w := range snap.Windows {
+			known[w.ID] = true
+		}
+		launched, err = LaunchEmptyCells(ctx, layout, assignment.Assignments, known, opts.Launch.withDefaults(c))
+		if err != nil {
+			return nil, err
+		}
+		if len(launched) > 0 {
+			placedWindows = append([]server.WindowInfo(nil), snap.Windows...)
+			for _, l := range launched {
+				placedWindows = append(placedWindows, l.Window)
+			}
+		}
+	}
+
+	// 5c. Cells grown over empty neighbours (resize absorb) cover them
+	calculatedLayout.CellBounds = SpanCellBounds(calculatedLayout.CellBounds, spaceState.Spans, assignment.Assignments)
+
+	// 6. Get cell modes and ratios from config/state. With bsp, every cell
+	// but tabbed ones halves its largest window per new window.
+	defaultMode := DefaultStackMode(cfg, snap.DisplayBounds, opts.OrientationAware)
 	cellModes := make(map[string]types.StackMode)
 	cellRatios := make(map[string][]float64)
 
 	for cellID := range assignment.Assignments {
-		// Check individual cell's StackMode first
-		for _, cell := range layout.Cells {
-			if cell.ID == cellID && cell.StackMode != "" {
-				cellModes[cellID] = cell.StackMode
-				break
-			}
-		}
-		// CellModes map can override individual cell settings
-		if layout.CellModes != nil {
-			if mode, ok := layout.CellModes[cellID]; ok {
-				cellModes[cellID] = mode
-			}
-		}
-		// State override
-		if cellState, ok := spaceState.Cells[cellID]; ok {
-			if cellState.StackMode != "" {
-				cellModes[cellID] = cellState.StackMode
-			}
-			if len(cellState.SplitRatios) > 0 {
-				cellRatios[cellID] = cellState.SplitRatios
-			}
+		cellModes[cellID] = EffectiveCellMode(cfg, layout, spaceState, cellID, snap.DisplayBounds, opts.OrientationAware, bsp)
+		// Only ratios set by resizing survive; the rest re-equalize
+		if cellState, ok := spaceState.Cells[cellID]; ok && cellState.RatiosUserSet && len(cellState.SplitRatios) > 0 {
+			cellRatios[cellID] = cellState.SplitRatios
 		}
 	}
 
@@ -108,59 +205,177 @@ func ApplyLayout(
 		assignment.Assignments,
 		cellModes,
 		cellRatios,
-		cfg.Settings.DefaultStackMode,
+		defaultMode,
 		opts.Padding,
 	)
 
-	// 8. Apply placements via server
-	if err := ApplyPlacements(ctx, c, placements); err != nil {
-		return fmt.Errorf("failed to apply placements: %w", err)
+	// Snap windows with a resize increment (appRules[].sizeIncrement),
+	// letting their neighbors take up the difference
+	placements = SnapPlacements(cfg, calculatedLayout, placements, placedWindows, opts.Padding)
+
+	// 8. Apply placements via server, skipping windows already in place
+	pending, skipped := FilterUnchangedPlacements(placements, placedWindows, placementTolerance)
+	if skipped > 0 {
+		logging.Info().Int("skipped", skipped).Int("pending", len(pending)).Msg("windows already in place")
+	}
+	animation := opts.AnimationDuration
+	if animation == 0 {
+		animation = cfg.Settings.AnimationDuration
+	}
+	if len(pending) > 0 {
+		if err := ApplyPlacementsAnimated(ctx, c, pending, animation); err != nil {
+			return nil, fmt.Errorf("failed to apply placements: %w", err)
+		}
 	}
 
-	// 9. Update local state
+	// 9. Update local state, keeping user-set ratios when reapplying
+	var userRatios map[string][]float64
+	var previousCells map[string]*state.CellState
+	if spaceState.CurrentLayoutID == layoutID {
+		userRatios = spaceState.UserRatios()
+		previousCells = spaceState.Cells // SetCurrentLayout clears them
+	}
 	spaceState.SetCurrentLayout(layoutID, findLayoutIndex(cfg, layoutID))
-	rs.SetWindowAssignments(snap.SpaceID, assignment.Assignments)
+	rs.SetWindowAssignmentsFrom(snap.SpaceID, assignment.Assignments, previousCells)
+	rs.GetSpace(snap.SpaceID).RestoreUserRatios(userRatios)
+	rs.GetSpace(snap.SpaceID).BSP = bsp
+	rs.GetSpace(snap.SpaceID).ReinsertWindows = nil // Reinserted windows are tracked in their cells now
+	rs.GetSpace(snap.SpaceID).PruneSpans()
+	if focusedWindow != 0 {
+		// Focus follows the window into whichever cell it landed in
+		rs.GetSpace(snap.SpaceID).SetFocusedWindow(focusedWindow)
+	}
 	rs.MarkUpdated()
 
+	// 9b. Bring each tabbed cell's frontmost window in front of its siblings,
+	// which all share the cell's frame. Cells none of whose windows moved
+	// keep their stacking, so their front window is already frontmost.
+	fronts := TabFronts(rs.GetSpace(snap.SpaceID), cellModes, defaultMode)
+	if fronts = movedCellFronts(rs.GetSpace(snap.SpaceID), fronts, pending); len(fronts) > 0 {
+		RaiseWindows(ctx, c, fronts, snap.FocusedWindowID)
+	}
+
 	// 10. Save state
 	if err := rs.Save(); err != nil {
-		return fmt.Errorf("failed to save state: %w", err)
+		return nil, fmt.Errorf("failed to save state: %w", err)
 	}
 
-	return nil
+	// 11. Run the postApply hook
+	runApplyHook(ctx, cfg.Settings.Hooks, HookContext{
+		Event:    HookPostApply,
+		SpaceID:  snap.SpaceID,
+		LayoutID: layoutID,
+		Applied:  len(pending),
+		Skipped:  skipped,
+	})
+
+	return &ApplyResult{Applied: len(pending), Skipped: skipped, Sticky: sticky, Launched: launched}, nil
 }
 
 // ApplyPlacements sends window placements to the server.
 // Continues on individual errors to apply as many windows as possible.
 func ApplyPlacements(ctx context.Context, c *client.Client, placements []types.WindowPlacement) error {
-	successCount := 0
-	errorCount := 0
+	return ApplyPlacementsAnimated(ctx, c, placements, 0)
+}
+
+// ApplyPlacementsAnimated sends window placements like ApplyPlacements,
+// asking the server to animate each move over duration seconds. Servers
+// without AnimationCapability move the windows instantly.
+func ApplyPlacementsAnimated(ctx context.Context, c *client.Client, placements []types.WindowPlacement, duration float64) error {
+	if len(placements) == 0 {
+		return nil
+	}
+	if duration > 0 && !c.HasCapability(ctx, AnimationCapability) {
+		logging.Debug().Float64("duration", duration).Msg("server can't animate window moves, moving instantly")
+		duration = 0
+	}
 
+	updates := make([]client.WindowUpdate, 0, len(placements))
 	for _, p := range placements {
-		updates := map[string]interface{}{
+		frame := map[string]interface{}{
 			"x":      p.Bounds.X,
 			"y":      p.Bounds.Y,
 			"width":  p.Bounds.Width,
 			"height": p.Bounds.Height,
 		}
+		if duration > 0 {
+			frame["duration"] = duration
+		}
+		updates = append(updates, client.WindowUpdate{WindowID: int(p.WindowID), Updates: frame})
+	}
+
+	errs, err := c.BatchUpdateWindows(ctx, updates)
+	if err != nil {
+		return fmt.Errorf("failed to update all %d windows: %w", len(placements), err)
+	}
 
-		_, err := c.UpdateWindow(ctx, int(p.WindowID), updates)
+	errorCount := 0
+	for i, err := range errs {
 		if err != nil {
-			fmt.Printf("Warning: failed to update window %d: %v\n", p.WindowID, err)
+			fmt.Printf("Warning: failed to update window %d: %v\n", placements[i].WindowID, err)
 			errorCount++
-		} else {
-			successCount++
 		}
 	}
 
 	// Only fail if NO windows could be updated
-	if successCount == 0 && errorCount > 0 {
+	if errorCount == len(placements) {
 		return fmt.Errorf("failed to update all %d windows", errorCount)
 	}
 
 	return nil
 }
 
+// FilterUnchangedPlacements drops placements whose window already sits at
+// its target frame (each edge within tolerance pixels).
+// Returns the placements that still need to be applied and the skip count.
+func FilterUnchangedPlacements(placements []types.WindowPlacement, windows []server.WindowInfo, tolerance float64) ([]types.WindowPlacement, int) {
+	frames := make(map[uint32]types.Rect, len(windows))
+	for _, w := range windows {
+		frames[w.ID] = w.Frame
+	}
+
+	pending := make([]types.WindowPlacement, 0, len(placements))
+	skipped := 0
+	for _, p := range placements {
+		if frame, ok := frames[p.WindowID]; ok && framesMatch(frame, p.Bounds, tolerance) {
+			skipped++
+			continue
+		}
+		pending = append(pending, p)
+	}
+
+	return pending, skipped
+}
+
+// framesMatch reports whether two frames are equal within tolerance.
+func framesMatch(a, b types.Rect, tolerance float64) bool {
+	return math.Abs(a.X-b.X) <= tolerance &&
+		math.Abs(a.Y-b.Y) <= tolerance &&
+		math.Abs(a.Width-b.Width) <= tolerance &&
+		math.Abs(a.Height-b.Height) <= tolerance
+}
+
+// validatePlacements checks that explicit placements target cells in the
+// layout and tileable windows on the current space.
+func validatePlacements(placements map[uint32]string, layout *types.Layout, snap *server.Snapshot) error {
+	for windowID, cellID := range placements {
+		found := false
+		for _, cell := range layout.Cells {
+			if cell.ID == cellID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("placement %d=%s: cell not found in layout %s", windowID, cellID, layout.ID)
+		}
+		if !snap.WindowIDs[windowID] {
+			return fmt.Errorf("placement %d=%s: window is not a tileable window on space %s", windowID, cellID, snap.SpaceID)
+		}
+	}
+	return nil
+}
+
 // convertWindows converts server.WindowInfo slice to layout.Window slice.
 func convertWindows(windows []server.WindowInfo) []Window {
 	result := make([]Window, 0, len(windows))
@@ -199,17 +414,17 @@ func CycleLayout(
 	opts ApplyLayoutOptions,
 ) (string, error) {
 	// Get available layouts for this space
-	availableLayouts := cfg.GetLayoutIDs()
-	if spaceConfig := cfg.GetSpaceConfig(snap.SpaceID); spaceConfig != nil && len(spaceConfig.Layouts) > 0 {
-		availableLayouts = spaceConfig.Layouts
-	}
+	availableLayouts := LayoutCycle(cfg, snap.SpaceID, SpaceDisplay(snap, snap.SpaceID))
 
 	if len(availableLayouts) == 0 {
 		return "", fmt.Errorf("no layouts available")
 	}
 
-	// Cycle in state
+	// Cycle in state, remembering the focused window before cycling clears it
 	spaceState := rs.GetSpace(snap.SpaceID)
+	if opts.FocusWindow == 0 {
+		opts.FocusWindow = spaceState.GetFocusedWindow()
+	}
 	newLayoutID := spaceState.CycleLayout(availableLayouts)
 
 	// Apply the new layout
@@ -220,7 +435,173 @@ func CycleLayout(
 	return newLayoutID, nil
 }
 
-// ReapplyLayout reapplies the current layout.
+// SpaceApplyResult is the outcome of applying a space's default layout in
+// ApplyDefaultLayouts
+type SpaceApplyResult struct {
+	SpaceID  string       `json:"spaceId"`
+	Display  string       `json:"display,omitempty"` // UUID of the display showing the space
+	LayoutID string       `json:"layoutId,omitempty"`
+	Error    string       `json:"error,omitempty"` // Why the apply failed ("" on success)
+	Result   *ApplyResult `json:"-"`
+}
+
+// ApplyDefaultLayouts applies each snapshot's space its default layout (see
+// config.ResolveLayoutID) with the options optsFor returns for it. A failed
+// space doesn't stop the others; every space gets a result, in order.
+func ApplyDefaultLayouts(
+	ctx context.Context,
+	c *client.Client,
+	snaps []*server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	optsFor func(snap *server.Snapshot) ApplyLayoutOptions,
+) []SpaceApplyResult {
+	results := make([]SpaceApplyResult, 0, len(snaps))
+	for _, snap := range snaps {
+		display := SpaceDisplay(snap, snap.SpaceID)
+		res := SpaceApplyResult{SpaceID: snap.SpaceID}
+		if display != nil {
+			res.Display = display.UUID
+		}
+
+		layoutID, err := cfg.ResolveLayoutID(snap.SpaceID, display, "")
+		if err == nil {
+			res.LayoutID = layoutID
+			res.Result, err = ApplyLayoutWithResult(ctx, c, snap, cfg, rs, layoutID, optsFor(snap))
+		}
+		if err != nil {
+			logging.Warn().Err(err).Str("space", snap.SpaceID).Msg("failed to apply default layout")
+			res.Error = err.Error()
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// LayoutCycle returns the layouts a space cycles through: its configured
+// layouts, else those of the display showing it (nil if unknown), else every
+// layout. Disabled layouts are left out.
+func LayoutCycle(cfg *config.Config, spaceID string, display *config.DisplayRef) []string {
+	ids := cfg.GetLayoutIDs()
+	if spaceConfig := cfg.GetSpaceConfig(spaceID); spaceConfig != nil && len(spaceConfig.Layouts) > 0 {
+		ids = spaceConfig.Layouts
+	} else if displayConfig := cfg.GetDisplayConfig(display); displayConfig != nil && len(displayConfig.Layouts) > 0 {
+		ids = displayConfig.Layouts
+	}
+
+	cycle := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !cfg.LayoutDisabled(id) {
+			cycle = append(cycle, id)
+		}
+	}
+	return cycle
+}
+
+// SpaceCycle is a space's layout cycle joined with its runtime position
+type SpaceCycle struct {
+	SpaceID       string   `json:"spaceId"`
+	Layouts       []string `json:"layouts"`
+	CurrentIndex  int      `json:"currentIndex"`  // Position of CurrentLayout in Layouts (-1 = not in the cycle)
+	CurrentLayout string   `json:"currentLayout"` // "" when no layout is applied
+	NextLayout    string   `json:"nextLayout"`    // Layout `layout cycle` switches to
+}
+
+// SpaceCycles returns the layout cycle of every space in the state or the
+// config, and of the spaces the snapshot's displays show (snap may be nil),
+// ordered by space ID.
+func SpaceCycles(cfg *config.Config, rs *state.RuntimeState, snap *server.Snapshot) []SpaceCycle {
+	spaceIDs := make(map[string]bool)
+	for id := range rs.Spaces {
+		spaceIDs[id] = true
+	}
+	for id := range cfg.Spaces {
+		spaceIDs[id] = true
+	}
+	if snap != nil {
+		for _, d := range snap.AllDisplays {
+			spaceIDs[fmt.Sprintf("%v", d.CurrentSpaceID)] = true
+		}
+	}
+
+	cycles := make([]SpaceCycle, 0, len(spaceIDs))
+	for id := range spaceIDs {
+		var display *config.DisplayRef
+		if snap != nil {
+			display = SpaceDisplay(snap, id)
+		}
+		cycle := SpaceCycle{
+			SpaceID:      id,
+			Layouts:      LayoutCycle(cfg, id, display),
+			CurrentIndex: -1,
+		}
+
+		layoutIndex := 0
+		if space := rs.GetSpaceReadOnly(id); space != nil {
+			cycle.CurrentLayout = space.CurrentLayoutID
+			layoutIndex = space.LayoutIndex
+		}
+		for i, layoutID := range cycle.Layouts {
+			if layoutID == cycle.CurrentLayout && cycle.CurrentLayout != "" {
+				cycle.CurrentIndex = i
+				break
+			}
+		}
+		// Same step as state.SpaceState.CycleLayout
+		if n := len(cycle.Layouts); n > 0 {
+			cycle.NextLayout = cycle.Layouts[(layoutIndex+1)%n]
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		a, b := cycles[i].SpaceID, cycles[j].SpaceID
+		if len(a) != len(b) {
+			return len(a) < len(b) // numeric IDs: "2" before "10"
+		}
+		return a < b
+	})
+	return cycles
+}
+
+// SpaceDisplay identifies the display showing a space in the snapshot, for
+// per-display config. Returns nil when no display shows it.
+func SpaceDisplay(snap *server.Snapshot, spaceID string) *config.DisplayRef {
+	for i, d := range snap.AllDisplays {
+		if fmt.Sprintf("%v", d.CurrentSpaceID) == spaceID {
+			return &config.DisplayRef{UUID: d.UUID, Index: i}
+		}
+	}
+	return nil
+}
+
+// NextLargerLayout returns the first layout after currentID in the space's
+// cycle that has more cells than it, or "" if there is none.
+func NextLargerLayout(cfg *config.Config, spaceID string, display *config.DisplayRef, currentID string) string {
+	current, err := cfg.GetLayout(currentID)
+	if err != nil {
+		return ""
+	}
+
+	cycle := LayoutCycle(cfg, spaceID, display)
+	start := 0
+	for i, id := range cycle {
+		if id == currentID {
+			start = i + 1
+			break
+		}
+	}
+	for i := 0; i < len(cycle); i++ {
+		id := cycle[(start+i)%len(cycle)]
+		if candidate, err := cfg.GetLayout(id); err == nil && len(candidate.Cells) > len(current.Cells) {
+			return id
+		}
+	}
+	return ""
+}
+
+// ReapplyLayout reapplies the current layout, with the bsp strategy if the
+// space was last applied with it.
 func ReapplyLayout(
 	ctx context.Context,
 	c *client.Client,
@@ -234,5 +615,26 @@ func ReapplyLayout(
 		return fmt.Errorf("no layout currently applied")
 	}
 
+	// A space tiled with the bsp strategy keeps it until a layout is
+	// applied without it
+	if spaceState.BSP {
+		opts.Strategy = types.AssignBSP
+	}
 	return ApplyLayout(ctx, c, snap, cfg, rs, spaceState.CurrentLayoutID, opts)
 }
+
+// SetSpaceLayout records a layout as current for a space without moving any
+// windows. The layout is arranged on the next apply or reapply.
+func SetSpaceLayout(cfg *config.Config, rs *state.RuntimeState, spaceID, layoutID string) error {
+	if _, err := cfg.GetLayout(layoutID); err != nil {
+		return fmt.Errorf("layout not found: %w", err)
+	}
+
+	rs.GetSpace(spaceID).SetCurrentLayout(layoutID, findLayoutIndex(cfg, layoutID))
+	rs.MarkUpdated()
+
+	if err := rs.Save(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,598 @@
+package layout
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestFilterUnchangedPlacements_AllInPlace(t *testing.T) {
+	windows := []server.WindowInfo{
+		{ID: 1, Frame: types.Rect{X: 0, Y: 0, Width: 500, Height: 1000}},
+		{ID: 2, Frame: types.Rect{X: 500.4, Y: 0, Width: 499.6, Height: 1000}},
+	}
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 500, Height: 1000}},
+		{WindowID: 2, Bounds: types.Rect{X: 500, Y: 0, Width: 500, Height: 1000}},
+	}
+
+	pending, skipped := FilterUnchangedPlacements(placements, windows, placementTolerance)
+
+	if len(pending) != 0 {
+		t.Errorf("expected no placements to apply, got %v", pending)
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped, got %d", skipped)
+	}
+}
+
+func TestFilterUnchangedPlacements_Mixed(t *testing.T) {
+	windows := []server.WindowInfo{
+		{ID: 1, Frame: types.Rect{X: 0, Y: 0, Width: 500, Height: 1000}},
+		{ID: 2, Frame: types.Rect{X: 100, Y: 100, Width: 300, Height: 300}},
+	}
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 500, Height: 1000}},
+		{WindowID: 2, Bounds: types.Rect{X: 500, Y: 0, Width: 500, Height: 1000}},
+		{WindowID: 3, Bounds: types.Rect{X: 0, Y: 0, Width: 100, Height: 100}}, // Unknown frame
+	}
+
+	pending, skipped := FilterUnchangedPlacements(placements, windows, placementTolerance)
+
+	if skipped != 1 {
+		t.Errorf("expected 1 skipped, got %d", skipped)
+	}
+	if len(pending) != 2 || pending[0].WindowID != 2 || pending[1].WindowID != 3 {
+		t.Errorf("expected windows [2 3] to apply, got %v", pending)
+	}
+}
+
+func fullLayoutConfig() *config.Config {
+	return &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "half", Grid: config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"a", "b"}}},
+			{ID: "full", Grid: config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"main"}}},
+		},
+	}
+}
+
+func TestApplyLayout_UsesSnapshotDisplayBounds(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Snapshot for a secondary display's space: the window already fills that
+	// display, so applying "full" there needs no server calls.
+	side := types.Rect{X: 1920, Y: 25, Width: 1080, Height: 1895}
+	snap := &server.Snapshot{
+		SpaceID:       "4",
+		DisplayBounds: side,
+		Windows:       []server.WindowInfo{{ID: 20, AppName: "Safari", Frame: side}},
+		WindowIDs:     map[uint32]bool{20: true},
+	}
+	rs := state.NewRuntimeState()
+
+	opts := DefaultApplyOptions()
+	opts.Gap = 0
+	opts.Padding = 0
+	result, err := ApplyLayoutWithResult(context.Background(), nil, snap, fullLayoutConfig(), rs, "full", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 1 || result.Applied != 0 {
+		t.Errorf("expected window already in place on display bounds, got %+v", result)
+	}
+	if got := rs.GetCurrentLayoutForSpace("4"); got != "full" {
+		t.Errorf("layout for space 4 = %q, want full", got)
+	}
+}
+
+func TestSetSpaceLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := fullLayoutConfig()
+	rs := state.NewRuntimeState()
+	rs.GetSpace("4").AssignWindow(20, "a")
+
+	if err := SetSpaceLayout(cfg, rs, "4", "full"); err != nil {
+		t.Fatal(err)
+	}
+	space := rs.GetSpaceReadOnly("4")
+	if space.CurrentLayoutID != "full" || space.LayoutIndex != 1 {
+		t.Errorf("expected full at index 1, got %s at %d", space.CurrentLayoutID, space.LayoutIndex)
+	}
+	if len(space.Cells) != 0 {
+		t.Errorf("expected cell state cleared until next apply, got %v", space.Cells)
+	}
+
+	if err := SetSpaceLayout(cfg, rs, "4", "missing"); err == nil {
+		t.Error("expected error for unknown layout")
+	}
+}
+
+func TestNextLargerLayout(t *testing.T) {
+	grid := config.GridConfig{Columns: []string{"1fr", "1fr", "1fr"}, Rows: []string{"1fr"}}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "full", Grid: grid, Areas: [][]string{{"a", "a", "a"}}},
+			{ID: "half", Grid: grid, Areas: [][]string{{"a", "a", "b"}}},
+			{ID: "thirds", Grid: grid, Areas: [][]string{{"a", "b", "c"}}},
+			{ID: "split", Grid: grid, Areas: [][]string{{"a", "b", "b"}}},
+		},
+		Spaces: map[string]config.SpaceConfig{
+			"2": {Layouts: []string{"thirds", "full", "half"}},
+		},
+	}
+
+	tests := []struct {
+		space, current, want string
+	}{
+		{"1", "full", "half"},
+		{"1", "half", "thirds"},
+		{"1", "split", "thirds"}, // Wraps around the cycle
+		{"1", "thirds", ""},      // Nothing larger
+		{"2", "full", "half"},
+		{"2", "half", "thirds"},
+		{"1", "missing", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NextLargerLayout(cfg, tt.space, nil, tt.current); got != tt.want {
+			t.Errorf("NextLargerLayout(space %s, %s) = %q, want %q", tt.space, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutCycle_DisplayFallback(t *testing.T) {
+	grid := config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "a", Grid: grid, Areas: [][]string{{"main"}}},
+			{ID: "b", Grid: grid, Areas: [][]string{{"main"}}},
+			{ID: "c", Grid: grid, Areas: [][]string{{"main"}}},
+		},
+		Spaces: map[string]config.SpaceConfig{
+			"2": {Layouts: []string{"a"}},
+		},
+		Displays: map[string]config.DisplayConfig{
+			"SIDE-UUID": {Layouts: []string{"b", "c"}},
+		},
+	}
+	snap := &server.Snapshot{
+		SpaceID: "5",
+		AllDisplays: []server.DisplayInfo{
+			{UUID: "MAIN-UUID", CurrentSpaceID: float64(2)},
+			{UUID: "SIDE-UUID", CurrentSpaceID: float64(5)},
+		},
+	}
+
+	// Space 5 has no entry, so the side display's cycle applies
+	display := SpaceDisplay(snap, "5")
+	if display == nil || display.UUID != "SIDE-UUID" || display.Index != 1 {
+		t.Fatalf("SpaceDisplay = %+v, want SIDE-UUID at index 1", display)
+	}
+	if got := LayoutCycle(cfg, "5", display); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("cycle = %v, want display cycle [b c]", got)
+	}
+
+	// A space's own layouts win over its display's
+	if got := LayoutCycle(cfg, "2", SpaceDisplay(snap, "2")); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("space 2 cycle = %v, want [a]", got)
+	}
+
+	// Spaces no display shows fall back to every layout
+	if SpaceDisplay(snap, "9") != nil {
+		t.Error("expected no display for a hidden space")
+	}
+	if got := LayoutCycle(cfg, "9", nil); len(got) != 3 {
+		t.Errorf("cycle = %v, want all layouts", got)
+	}
+}
+
+func TestSpaceCycles(t *testing.T) {
+	grid := config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "a", Grid: grid, Areas: [][]string{{"main"}}},
+			{ID: "b", Grid: grid, Areas: [][]string{{"main"}}},
+			{ID: "c", Grid: grid, Areas: [][]string{{"main"}}},
+		},
+		Spaces: map[string]config.SpaceConfig{
+			"10": {Layouts: []string{"c", "a"}},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("2")
+	space.CycleLayout([]string{"a", "b", "c"}) // now on b, index 1
+	snap := &server.Snapshot{AllDisplays: []server.DisplayInfo{{CurrentSpaceID: float64(3)}}}
+
+	cycles := SpaceCycles(cfg, rs, snap)
+	var ids []string
+	for _, c := range cycles {
+		ids = append(ids, c.SpaceID)
+	}
+	if !reflect.DeepEqual(ids, []string{"2", "3", "10"}) {
+		t.Fatalf("spaces = %v, want [2 3 10]", ids)
+	}
+
+	want := SpaceCycle{SpaceID: "2", Layouts: []string{"a", "b", "c"}, CurrentIndex: 1, CurrentLayout: "b", NextLayout: "c"}
+	if !reflect.DeepEqual(cycles[0], want) {
+		t.Errorf("space 2 = %+v, want %+v", cycles[0], want)
+	}
+	// A space without state starts at index 0, so cycling goes to the second layout
+	if got := cycles[2]; got.CurrentIndex != -1 || got.CurrentLayout != "" || got.NextLayout != "a" {
+		t.Errorf("space 10 = %+v, want no current layout and next a", got)
+	}
+}
+
+func TestLayoutCycle_SkipsDisabled(t *testing.T) {
+	grid := config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "a", Grid: grid, Areas: [][]string{{"main"}}},
+			{ID: "b", Grid: grid, Areas: [][]string{{"main"}}, Disabled: true},
+			{ID: "c", Grid: grid, Areas: [][]string{{"main"}}},
+		},
+		Spaces: map[string]config.SpaceConfig{
+			"2": {Layouts: []string{"c", "b"}},
+		},
+	}
+
+	if got := LayoutCycle(cfg, "1", nil); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("cycle = %v, want [a c]", got)
+	}
+	if got := LayoutCycle(cfg, "2", nil); len(got) != 1 || got[0] != "c" {
+		t.Errorf("space 2 cycle = %v, want [c]", got)
+	}
+
+	// Cycling from a goes straight to c and back
+	space := state.NewSpaceState("1")
+	space.SetCurrentLayout("a", 0)
+	if next := space.CycleLayout(LayoutCycle(cfg, "1", nil)); next != "c" {
+		t.Errorf("cycled to %s, want c", next)
+	}
+	if next := space.CycleLayout(LayoutCycle(cfg, "1", nil)); next != "a" {
+		t.Errorf("cycled to %s, want a", next)
+	}
+
+	// Still available by ID
+	if _, err := cfg.GetLayout("b"); err != nil {
+		t.Errorf("disabled layout should still load: %v", err)
+	}
+}
+
+func TestReapplyLayout_KeepsUserSetRatios(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	display := types.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	for _, w := range []uint32{1, 2} {
+		space.AssignWindow(w, "a")
+	}
+	for _, w := range []uint32{3, 4} {
+		space.AssignWindow(w, "b")
+	}
+	// Cell a was resized by the user; cell b drifted without a resize
+	space.Cells["a"].SplitRatios = []float64{0.7, 0.3}
+	space.Cells["a"].RatiosUserSet = true
+	space.Cells["b"].SplitRatios = []float64{0.6, 0.4}
+
+	// Windows already sit at the expected frames, so no server calls are needed
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: display,
+		Windows: []server.WindowInfo{
+			{ID: 1, Frame: types.Rect{X: 0, Y: 0, Width: 500, Height: 700}},
+			{ID: 2, Frame: types.Rect{X: 0, Y: 700, Width: 500, Height: 300}},
+			{ID: 3, Frame: types.Rect{X: 500, Y: 0, Width: 500, Height: 500}},
+			{ID: 4, Frame: types.Rect{X: 500, Y: 500, Width: 500, Height: 500}},
+		},
+		WindowIDs: map[uint32]bool{1: true, 2: true, 3: true, 4: true},
+	}
+
+	opts := DefaultApplyOptions()
+	opts.Gap = 0
+	opts.Padding = 0
+	result, err := ApplyLayoutWithResult(context.Background(), nil, snap, fullLayoutConfig(), rs, "half", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != 0 {
+		t.Errorf("expected every window already in place, got %+v", result)
+	}
+
+	got := rs.GetSpaceReadOnly("1")
+	if a := got.Cells["a"]; !a.RatiosUserSet || a.SplitRatios[0] != 0.7 || a.SplitRatios[1] != 0.3 {
+		t.Errorf("user-resized cell a = %+v, want ratios [0.7 0.3] kept", a)
+	}
+	if b := got.Cells["b"]; b.RatiosUserSet || b.SplitRatios[0] != 0.5 || b.SplitRatios[1] != 0.5 {
+		t.Errorf("untouched cell b = %+v, want re-equalized ratios", b)
+	}
+}
+
+func TestReapplyLayout_KeepsPrependedRatios(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(1, "a")
+	space.AssignWindow(2, "a")
+	space.Cells["a"].SplitRatios = []float64{0.8, 0.2}
+	space.PrependWindowToCellKeepRatios(3, "a")
+	want := append([]float64(nil), space.Cells["a"].SplitRatios...)
+
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1000, Height: 1000},
+		Windows: []server.WindowInfo{
+			{ID: 1, Frame: types.Rect{Width: 100, Height: 100}},
+			{ID: 2, Frame: types.Rect{Width: 100, Height: 100}},
+			{ID: 3, Frame: types.Rect{Width: 100, Height: 100}},
+		},
+		WindowIDs: map[uint32]bool{1: true, 2: true, 3: true},
+	}
+
+	if _, err := ApplyLayoutWithResult(context.Background(), c, snap, fullLayoutConfig(), rs, "half", DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rs.GetSpaceReadOnly("1").Cells["a"]
+	if !got.RatiosUserSet || !reflect.DeepEqual(got.SplitRatios, want) {
+		t.Errorf("cell a = %+v, want the prepended ratios %v kept", got, want)
+	}
+}
+
+func TestCycleLayout_FocusFollowsWindow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	display := types.Rect{X: 0, Y: 0, Width: 1000, Height: 1000}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("4")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(21, "b")
+	space.SetFocus("b", 0)
+
+	// The window already fills the display, as "full" will place it
+	snap := &server.Snapshot{
+		SpaceID:       "4",
+		DisplayBounds: display,
+		Windows:       []server.WindowInfo{{ID: 21, AppName: "Safari", Frame: display}},
+		WindowIDs:     map[uint32]bool{21: true},
+	}
+
+	opts := DefaultApplyOptions()
+	opts.Gap = 0
+	opts.Padding = 0
+	opts.Strategy = types.AssignPreserve
+	newLayout, err := CycleLayout(context.Background(), nil, snap, fullLayoutConfig(), rs, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newLayout != "full" {
+		t.Fatalf("cycled to %s, want full", newLayout)
+	}
+
+	got := rs.GetSpaceReadOnly("4")
+	if got.FocusedCell != "main" || got.GetFocusedWindow() != 21 {
+		t.Errorf("focus = cell %q window %d, want window 21 in main", got.FocusedCell, got.GetFocusedWindow())
+	}
+}
+
+func TestReapplyLayout_HonorsPinnedWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+	rs := state.NewRuntimeState()
+
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, "half", DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	space := rs.GetSpace("1")
+	if space.GetWindowCell(20) != "a" {
+		t.Fatalf("expected window 20 to start in cell a, got %q", space.GetWindowCell(20))
+	}
+
+	space.PinWindow(20, "b")
+	if err := ReapplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	space = rs.GetSpace("1")
+	if space.GetWindowCell(20) != "b" {
+		t.Errorf("expected pinned window 20 in cell b, got %q", space.GetWindowCell(20))
+	}
+	if space.PinnedWindows[20] != "b" {
+		t.Errorf("expected the pin to survive reapply, got %v", space.PinnedWindows)
+	}
+}
+
+func TestReapplyLayout_FloatAndUnfloat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+	rs := state.NewRuntimeState()
+
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, "half", DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	rs.GetSpace("1").FloatWindow(20)
+	if err := ReapplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	space := rs.GetSpace("1")
+	if cell := space.GetWindowCell(20); cell != "" {
+		t.Fatalf("floated window 20 was tiled into cell %q", cell)
+	}
+	if !space.IsFloated(20) {
+		t.Fatal("expected the float to survive reapply")
+	}
+
+	// Unfloated over cell b, the window goes there even though a is empty
+	space.UnfloatWindow(20)
+	snap := stickySnapshot()
+	snap.Windows[0].Frame = types.Rect{X: 1100, Y: 100, Width: 700, Height: 600}
+	if err := ReapplyLayout(context.Background(), c, snap, fullLayoutConfig(), rs, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	space = rs.GetSpace("1")
+	if cell := space.GetWindowCell(20); cell != "b" {
+		t.Errorf("expected unfloated window 20 in cell b, got %q", cell)
+	}
+	if space.ReinsertWindows != nil {
+		t.Errorf("expected the reinsert to be consumed, got %v", space.ReinsertWindows)
+	}
+}
+
+func TestApplyPlacements_SendsOneBatch(t *testing.T) {
+	ss := &stickyServer{}
+	c := startStickyServer(t, ss)
+
+	placements := []types.WindowPlacement{
+		{WindowID: 20, Bounds: types.Rect{Width: 960, Height: 1055}},
+		{WindowID: 21, Bounds: types.Rect{X: 960, Width: 960, Height: 1055}},
+	}
+	if err := ApplyPlacements(context.Background(), c, placements); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := ss.Count(client.BatchUpdateMethod); n != 1 {
+		t.Errorf("expected 1 batch request, got %d", n)
+	}
+	if n := ss.Count("updateWindow"); n != 0 {
+		t.Errorf("expected no single updates, got %d", n)
+	}
+}
+
+func TestApplyLayout_PassesAnimationDuration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ss := &stickyServer{capabilities: map[string]interface{}{AnimationCapability: true}}
+	c := startStickyServer(t, ss)
+	cfg := fullLayoutConfig()
+	cfg.Settings.AnimationDuration = 0.2
+
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), cfg, state.NewRuntimeState(), "half", DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := ss.WindowUpdates()
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 window updates, got %v", updates)
+	}
+	for _, u := range updates {
+		if u["duration"] != 0.2 {
+			t.Errorf("expected duration 0.2 on window %v, got %v", u["windowId"], u["duration"])
+		}
+	}
+}
+
+func TestApplyPlacementsAnimated_InstantWithoutCapability(t *testing.T) {
+	ss := &stickyServer{}
+	c := startStickyServer(t, ss)
+
+	placements := []types.WindowPlacement{{WindowID: 20, Bounds: types.Rect{Width: 960, Height: 1055}}}
+	if err := ApplyPlacementsAnimated(context.Background(), c, placements, 0.2); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := ss.WindowUpdates()
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 window update, got %v", updates)
+	}
+	if _, ok := updates[0]["duration"]; ok {
+		t.Errorf("expected no duration for a server that can't animate, got %v", updates[0])
+	}
+
+	// Later moves with the same client reuse the capabilities
+	if err := ApplyPlacementsAnimated(context.Background(), c, placements, 0.2); err != nil {
+		t.Fatal(err)
+	}
+	if n := ss.Count("getServerInfo"); n != 1 {
+		t.Errorf("expected 1 getServerInfo request, got %d", n)
+	}
+}
+
+func BenchmarkApplyPlacements_Batch(b *testing.B) {
+	ss := &stickyServer{}
+	c := startStickyServer(b, ss)
+
+	placements := make([]types.WindowPlacement, 50)
+	for i := range placements {
+		placements[i] = types.WindowPlacement{
+			WindowID: uint32(100 + i),
+			Bounds:   types.Rect{X: float64(i * 10), Width: 400, Height: 300},
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ApplyPlacements(context.Background(), c, placements); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	// One request per apply, however many windows it places
+	if n := ss.Count(client.BatchUpdateMethod); n != b.N {
+		b.Errorf("expected %d batch requests, got %d", b.N, n)
+	}
+	if n := ss.Count("updateWindow"); n != 0 {
+		b.Errorf("expected no single updates, got %d", n)
+	}
+}
+
+func TestApplyDefaultLayouts_ReportsEachSpace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+	cfg := fullLayoutConfig()
+	cfg.Spaces = map[string]config.SpaceConfig{"1": {DefaultLayout: "half"}}
+	rs := state.NewRuntimeState()
+
+	// Space 4 has no default layout and fails; space 1 is still applied
+	other := &server.Snapshot{SpaceID: "4", DisplayBounds: types.Rect{Width: 1080, Height: 1920}, WindowIDs: map[uint32]bool{}}
+	results := ApplyDefaultLayouts(context.Background(), c, []*server.Snapshot{other, stickySnapshot()}, cfg, rs, func(*server.Snapshot) ApplyLayoutOptions {
+		return DefaultApplyOptions()
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("expected a result per space, got %+v", results)
+	}
+	if results[0].SpaceID != "4" || results[0].Error == "" || results[0].Result != nil {
+		t.Errorf("expected space 4 to fail without a default layout, got %+v", results[0])
+	}
+	if results[1].SpaceID != "1" || results[1].LayoutID != "half" || results[1].Error != "" || results[1].Result == nil {
+		t.Errorf("expected space 1 to get half, got %+v", results[1])
+	}
+	if got := rs.GetCurrentLayoutForSpace("1"); got != "half" {
+		t.Errorf("space 1 layout = %q, want half", got)
+	}
+}
+
+func TestApplyLayout_RejectsPlacementOfMinimizedWindow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	display := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: display,
+		Windows:       []server.WindowInfo{{ID: 20, IsMinimized: true}, {ID: 21}},
+		WindowIDs:     map[uint32]bool{20: true, 21: true},
+	}
+	rs := state.NewRuntimeState()
+
+	opts := DefaultApplyOptions()
+	opts.Placements = map[uint32]string{20: "a"}
+	_, err := ApplyLayoutWithResult(context.Background(), nil, snap, fullLayoutConfig(), rs, "half", opts)
+	if err == nil || !strings.Contains(err.Error(), "placement 20=a") {
+		t.Fatalf("expected the minimized window's placement to be rejected, got %v", err)
+	}
+	if rs.GetSpace("1").CurrentLayoutID != "" {
+		t.Error("expected nothing applied")
+	}
+}
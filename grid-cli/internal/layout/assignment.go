@@ -133,6 +133,7 @@ type AssignmentResult struct {
 	Assignments map[string][]uint32 // cellID -> window IDs
 	Floating    []uint32            // Windows that should float (not tiled)
 	Excluded    []uint32            // Windows excluded from layout (minimized, hidden, etc.)
+	Unplaced    []uint32            // Explicitly placed windows that were excluded instead
 }
 
 // AssignWindows distributes windows to cells based on the given strategy.
@@ -144,6 +145,7 @@ type AssignmentResult struct {
 //   - appRules: Application-specific rules
 //   - previousAssignments: Previous window-to-cell mappings (for preserve strategy)
 //   - strategy: How to assign windows
+//   - focusedCell: Cell new windows go to under the preserve strategy ("" for least populated)
 //
 // Returns: AssignmentResult with cell assignments and floating windows
 func AssignWindows(
@@ -153,49 +155,51 @@ func AssignWindows(
 	appRules []config.AppRule,
 	previousAssignments map[string][]uint32,
 	strategy types.AssignmentStrategy,
+	focusedCell string,
 ) *AssignmentResult {
-	result := &AssignmentResult{
-		Assignments: make(map[string][]uint32),
-		Floating:    make([]uint32, 0),
-		Excluded:    make([]uint32, 0),
-	}
-
-	// Initialize empty assignments for all cells
-	for _, cell := range layout.Cells {
-		result.Assignments[cell.ID] = make([]uint32, 0)
-	}
+	return AssignWindowsWithOverrides(windows, layout, cellBounds, appRules, previousAssignments, strategy, focusedCell, WindowOverrides{})
+}
 
-	// Filter windows and identify floating/excluded
-	var tileable []Window
-	for _, w := range windows {
-		// Check if window should be excluded first (minimized, hidden, overlay)
-		if shouldExclude(w) {
-			result.Excluded = append(result.Excluded, w.ID)
-			continue
-		}
+// WindowOverrides are per-window choices the user made on a space that take
+// precedence over the assignment strategy
+type WindowOverrides struct {
+	Pins     map[uint32]string // windowID -> cellID (window pin)
+	Floated  map[uint32]bool   // Windows kept out of cells (window float)
+	Reinsert map[uint32]bool   // Windows placed by overlap with the cells (window unfloat)
+}
 
-		// Check if window should float
-		if shouldFloat(w, appRules) {
-			result.Floating = append(result.Floating, w.ID)
-			continue
-		}
+// AssignWindowsWithOverrides is AssignWindows with the space's window
+// overrides. Floated windows are reported as floating and left out of the
+// cells. Pinned windows go to their cell before the strategy runs and
+// regardless of app rules; pins to cells the layout doesn't have are
+// ignored. Reinserted windows go to the cell they overlap most, whatever
+// the strategy, unless an app rule floats them.
+func AssignWindowsWithOverrides(
+	windows []Window,
+	layout *types.Layout,
+	cellBounds map[string]types.Rect,
+	appRules []config.AppRule,
+	previousAssignments map[string][]uint32,
+	strategy types.AssignmentStrategy,
+	focusedCell string,
+	overrides WindowOverrides,
+) *AssignmentResult {
+	return AssignWindowsWithPlacements(windows, layout, cellBounds, appRules, previousAssignments, strategy, focusedCell, nil, overrides)
+}
 
-		tileable = append(tileable, w)
+// assignPin puts a window in the cell it's pinned to. Returns false when the
+// window isn't pinned or the layout has no such cell.
+func assignPin(windowID uint32, pins map[uint32]string, result *AssignmentResult) bool {
+	cellID, ok := pins[windowID]
+	if !ok {
+		return false
 	}
-
-	// Apply assignment strategy
-	switch strategy {
-	case types.AssignPinned:
-		assignPinned(tileable, layout, appRules, result)
-	case types.AssignPreserve:
-		assignPreserve(tileable, layout, previousAssignments, result)
-	case types.AssignAutoFlow:
-		assignAutoFlow(tileable, layout, cellBounds, result)
-	default:
-		assignByPosition(tileable, cellBounds, result)
+	if _, exists := result.Assignments[cellID]; !exists {
+		logging.Debug().Uint32("wid", windowID).Str("cell", cellID).Msg("pin to missing cell ignored")
+		return false
 	}
-
-	return result
+	result.Assignments[cellID] = append(result.Assignments[cellID], windowID)
+	return true
 }
 
 // shouldFloat checks if a window should be floating.
@@ -224,7 +228,8 @@ func matchesAppRule(w Window, rule config.AppRule) bool {
 	return rule.App == w.AppName || rule.App == w.BundleID
 }
 
-// assignAutoFlow distributes windows evenly across cells using round-robin.
+// assignAutoFlow distributes windows across cells by weighted round-robin:
+// each cell receives windows in proportion to its weight (evenly by default).
 func assignAutoFlow(windows []Window, layout *types.Layout, cellBounds map[string]types.Rect, result *AssignmentResult) {
 	if len(windows) == 0 || len(layout.Cells) == 0 {
 		return
@@ -240,10 +245,32 @@ func assignAutoFlow(windows []Window, layout *types.Layout, cellBounds map[strin
 		}
 	}
 
-	// Round-robin assignment
-	for i, w := range windows {
-		cellID := sortedCells[i%len(sortedCells)]
-		result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
+	weights := make(map[string]int, len(layout.Cells))
+	for _, cell := range layout.Cells {
+		weights[cell.ID] = cell.Weight
+	}
+
+	// Smooth weighted round-robin: every turn each cell gains its weight and
+	// the cell furthest ahead takes the window, so heavier cells fill faster
+	// without taking all their windows in one run
+	total := 0
+	for _, cellID := range sortedCells {
+		if weights[cellID] < 1 {
+			weights[cellID] = 1
+		}
+		total += weights[cellID]
+	}
+	current := make(map[string]int, len(sortedCells))
+	for _, w := range windows {
+		best := ""
+		for _, cellID := range sortedCells {
+			current[cellID] += weights[cellID]
+			if best == "" || current[cellID] > current[best] {
+				best = cellID
+			}
+		}
+		current[best] -= total
+		result.Assignments[best] = append(result.Assignments[best], w.ID)
 	}
 }
 
@@ -270,36 +297,147 @@ func assignPinned(windows []Window, layout *types.Layout, rules []config.AppRule
 	}
 
 	// Second pass: distribute unpinned windows
-	if len(unpinned) > 0 {
-		// Find cells with no windows yet
-		emptyCells := make([]string, 0)
-		for cellID, windows := range result.Assignments {
-			if len(windows) == 0 {
-				emptyCells = append(emptyCells, cellID)
+	fillEmptyCellsFirst(unpinned, result)
+}
+
+// fillEmptyCellsFirst assigns windows to cells with no windows yet,
+// then round-robins the remainder to the least populated cells.
+func fillEmptyCellsFirst(windows []Window, result *AssignmentResult) {
+	if len(windows) == 0 {
+		return
+	}
+
+	// Find cells with no windows yet
+	emptyCells := make([]string, 0)
+	for cellID, assigned := range result.Assignments {
+		if len(assigned) == 0 {
+			emptyCells = append(emptyCells, cellID)
+		}
+	}
+
+	// Sort empty cells for consistent ordering
+	sort.Strings(emptyCells)
+
+	// Assign windows to empty cells first, then round-robin
+	for i, w := range windows {
+		var cellID string
+		if i < len(emptyCells) {
+			cellID = emptyCells[i]
+		} else {
+			// Round-robin to cells with fewest windows
+			cellID = FindLeastPopulatedCell(result.Assignments)
+		}
+		result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
+	}
+}
+
+// AssignWindowsWithPlacements is AssignWindowsWithOverrides with explicit
+// window->cell placements for a single apply. Explicitly placed windows
+// bypass floats, pins, app rules and the strategy; the remaining windows are
+// assigned by the strategy as usual. Placed windows that can't be tiled
+// (minimized, hidden, overlays) are excluded and reported in Unplaced.
+func AssignWindowsWithPlacements(
+	windows []Window,
+	layout *types.Layout,
+	cellBounds map[string]types.Rect,
+	appRules []config.AppRule,
+	previousAssignments map[string][]uint32,
+	strategy types.AssignmentStrategy,
+	focusedCell string,
+	placements map[uint32]string,
+	overrides WindowOverrides,
+) *AssignmentResult {
+	result := &AssignmentResult{
+		Assignments: make(map[string][]uint32),
+		Floating:    make([]uint32, 0),
+		Excluded:    make([]uint32, 0),
+	}
+
+	// Initialize empty assignments for all cells
+	for _, cell := range layout.Cells {
+		result.Assignments[cell.ID] = make([]uint32, 0)
+	}
+
+	// Filter windows and identify floating/excluded
+	var tileable, reinsert []Window
+	for _, w := range windows {
+		// Check if window should be excluded first (minimized, hidden, overlay)
+		if shouldExclude(w) {
+			result.Excluded = append(result.Excluded, w.ID)
+			if cellID, ok := placements[w.ID]; ok {
+				logging.Warn().Uint32("wid", w.ID).Str("cell", cellID).Msg("placed window is not tileable")
+				result.Unplaced = append(result.Unplaced, w.ID)
 			}
+			continue
 		}
 
-		// Sort empty cells for consistent ordering
-		sort.Strings(emptyCells)
-
-		// Assign unpinned windows to empty cells first, then round-robin
-		for i, w := range unpinned {
-			var cellID string
-			if i < len(emptyCells) {
-				cellID = emptyCells[i]
-			} else {
-				// Round-robin to cells with fewest windows
-				cellID = findLeastPopulatedCell(result.Assignments)
+		if cellID, ok := placements[w.ID]; ok {
+			if _, exists := result.Assignments[cellID]; exists {
+				logging.Debug().Uint32("wid", w.ID).Str("cell", cellID).Msg("explicit placement")
+				result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
+				continue
 			}
-			result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
 		}
+
+		if overrides.Floated[w.ID] {
+			result.Floating = append(result.Floating, w.ID)
+			continue
+		}
+
+		if assignPin(w.ID, overrides.Pins, result) {
+			continue
+		}
+
+		// Check if window should float
+		if shouldFloat(w, appRules) {
+			result.Floating = append(result.Floating, w.ID)
+			continue
+		}
+
+		if overrides.Reinsert[w.ID] && len(cellBounds) > 0 {
+			reinsert = append(reinsert, w)
+			continue
+		}
+
+		tileable = append(tileable, w)
+	}
+
+	// Unfloated windows return to the cell they sit over
+	assignByPosition(reinsert, cellBounds, result)
+
+	// Apply assignment strategy
+	switch strategy {
+	case types.AssignPinned:
+		assignPinned(tileable, layout, appRules, result)
+	case types.AssignPreserve, types.AssignBSP:
+		// BSP differs from preserve only in how cells tile their windows
+		assignPreserve(tileable, layout, cellBounds, previousAssignments, focusedCell, result)
+	case types.AssignAutoFlow:
+		assignAutoFlow(tileable, layout, cellBounds, result)
+	default:
+		assignByPosition(tileable, cellBounds, result)
 	}
+
+	return result
 }
 
 // assignPreserve tries to maintain previous window-to-cell mappings.
-func assignPreserve(windows []Window, layout *types.Layout, previous map[string][]uint32, result *AssignmentResult) {
+// Windows without a surviving mapping go to focusedCell if it exists in the
+// layout, otherwise to the least populated cell. With no previous mappings
+// at all (fresh state), the mappings are seeded from current window positions.
+func assignPreserve(windows []Window, layout *types.Layout, cellBounds map[string]types.Rect, previous map[string][]uint32, focusedCell string, result *AssignmentResult) {
 	var unassigned []Window
 
+	if !hasAssignments(previous) && len(cellBounds) > 0 {
+		logging.Debug().Msg("no previous assignments, seeding preserve from positions")
+		seeded := &AssignmentResult{Assignments: make(map[string][]uint32)}
+		for cellID := range result.Assignments {
+			seeded.Assignments[cellID] = make([]uint32, 0)
+		}
+		assignByPosition(windows, cellBounds, seeded)
+		previous = seeded.Assignments
+	}
+
 	// Build a lookup of previous cell assignments
 	prevCellMap := make(map[uint32]string)
 	for cellID, windowIDs := range previous {
@@ -320,13 +458,27 @@ func assignPreserve(windows []Window, layout *types.Layout, previous map[string]
 		unassigned = append(unassigned, w)
 	}
 
-	// Second pass: auto-flow unassigned windows
-	if len(unassigned) > 0 {
-		for _, w := range unassigned {
-			cellID := findLeastPopulatedCell(result.Assignments)
-			result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
+	// Second pass: place unassigned windows at focus or auto-flow them
+	if _, ok := result.Assignments[focusedCell]; !ok {
+		focusedCell = ""
+	}
+	for _, w := range unassigned {
+		cellID := focusedCell
+		if cellID == "" {
+			cellID = FindLeastPopulatedCell(result.Assignments)
+		}
+		result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
+	}
+}
+
+// hasAssignments reports whether any cell has a window assigned.
+func hasAssignments(assignments map[string][]uint32) bool {
+	for _, windowIDs := range assignments {
+		if len(windowIDs) > 0 {
+			return true
 		}
 	}
+	return false
 }
 
 // assignByPosition assigns windows to cells based on maximum overlap with current position.
@@ -366,16 +518,16 @@ func assignByPosition(windows []Window, cellBounds map[string]types.Rect, result
 			logging.Debug().Str("cell", bestCell).Float64("overlap", bestOverlap).Msg("assigned")
 			result.Assignments[bestCell] = append(result.Assignments[bestCell], w.ID)
 		} else {
-			cellID := findLeastPopulatedCell(result.Assignments)
+			cellID := FindLeastPopulatedCell(result.Assignments)
 			logging.Debug().Str("cell", cellID).Msg("no overlap, fallback")
 			result.Assignments[cellID] = append(result.Assignments[cellID], w.ID)
 		}
 	}
 }
 
-// findLeastPopulatedCell returns the cell ID with fewest windows.
+// FindLeastPopulatedCell returns the cell ID with fewest windows.
 // Uses alphabetical ordering as tiebreaker for deterministic behavior.
-func findLeastPopulatedCell(assignments map[string][]uint32) string {
+func FindLeastPopulatedCell(assignments map[string][]uint32) string {
 	var minCellID string
 	minCount := -1
 
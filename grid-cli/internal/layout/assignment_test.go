@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/yourusername/grid-cli/internal/config"
@@ -21,7 +22,7 @@ func TestAssignAutoFlow(t *testing.T) {
 		"right": {X: 500, Y: 0, Width: 500, Height: 1000},
 	}
 
-	result := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "")
 
 	// Expect 2 windows per cell (round-robin)
 	if len(result.Assignments["left"]) != 2 {
@@ -46,7 +47,7 @@ func TestAssignAutoFlow_UnevenDistribution(t *testing.T) {
 		"b": {X: 500, Y: 0, Width: 500, Height: 1000},
 	}
 
-	result := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "")
 
 	// With 3 windows and 2 cells, one gets 2 and one gets 1
 	total := len(result.Assignments["a"]) + len(result.Assignments["b"])
@@ -55,6 +56,35 @@ func TestAssignAutoFlow_UnevenDistribution(t *testing.T) {
 	}
 }
 
+func TestAssignAutoFlow_Weighted(t *testing.T) {
+	var windows []Window
+	for id := uint32(1); id <= 8; id++ {
+		windows = append(windows, Window{ID: id})
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "main", Weight: 3}, {ID: "side", Weight: 1},
+		},
+	}
+	cellBounds := map[string]types.Rect{
+		"main": {X: 0, Y: 0, Width: 1500, Height: 1000},
+		"side": {X: 1500, Y: 0, Width: 500, Height: 1000},
+	}
+
+	result := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "")
+
+	if got := len(result.Assignments["main"]); got != 6 {
+		t.Errorf("expected 6 windows in main, got %d", got)
+	}
+	if got := len(result.Assignments["side"]); got != 2 {
+		t.Errorf("expected 2 windows in side, got %d", got)
+	}
+	// The side cell's windows are spread out, not all taken at the end
+	if side := result.Assignments["side"]; len(side) == 2 && side[0] > 4 {
+		t.Errorf("expected side to get a window in the first half, got %v", side)
+	}
+}
+
 func TestAssignAutoFlow_Empty(t *testing.T) {
 	layout := &types.Layout{
 		Cells: []types.Cell{
@@ -62,7 +92,7 @@ func TestAssignAutoFlow_Empty(t *testing.T) {
 		},
 	}
 
-	result := AssignWindows(nil, layout, nil, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(nil, layout, nil, nil, nil, types.AssignAutoFlow, "")
 
 	if len(result.Assignments["main"]) != 0 {
 		t.Error("expected no assignments for empty windows")
@@ -84,7 +114,7 @@ func TestAssignPinned(t *testing.T) {
 		{App: "Terminal", PreferredCell: "side"},
 	}
 
-	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignPinned)
+	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignPinned, "")
 
 	// Terminal should be in side
 	found := false
@@ -118,7 +148,7 @@ func TestAssignPinned_NonexistentCell(t *testing.T) {
 		{App: "Terminal", PreferredCell: "nonexistent"},
 	}
 
-	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignPinned)
+	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignPinned, "")
 
 	// Should be assigned to main since preferred cell doesn't exist
 	if len(result.Assignments["main"]) != 1 {
@@ -126,6 +156,200 @@ func TestAssignPinned_NonexistentCell(t *testing.T) {
 	}
 }
 
+func TestAssignWindowsWithPlacements(t *testing.T) {
+	windows := []Window{
+		{ID: 1}, {ID: 2}, {ID: 3},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "a"}, {ID: "b"}, {ID: "c"},
+		},
+	}
+	placements := map[uint32]string{3: "a"}
+
+	result := AssignWindowsWithPlacements(windows, layout, nil, nil, nil, types.AssignPinned, "", placements, WindowOverrides{})
+
+	if len(result.Assignments["a"]) != 1 || result.Assignments["a"][0] != 3 {
+		t.Errorf("expected window 3 alone in cell a, got %v", result.Assignments["a"])
+	}
+	// Unmapped windows fill the remaining empty cells
+	if len(result.Assignments["b"]) != 1 || result.Assignments["b"][0] != 1 {
+		t.Errorf("expected window 1 in cell b, got %v", result.Assignments["b"])
+	}
+	if len(result.Assignments["c"]) != 1 || result.Assignments["c"][0] != 2 {
+		t.Errorf("expected window 2 in cell c, got %v", result.Assignments["c"])
+	}
+}
+
+func TestAssignWindowsWithPlacements_OverridesFloatRule(t *testing.T) {
+	windows := []Window{
+		{ID: 1, AppName: "Finder"},
+		{ID: 2, AppName: "Safari"},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "main"}, {ID: "side"},
+		},
+	}
+	rules := []config.AppRule{{App: "Finder", Float: true}}
+	placements := map[uint32]string{1: "side"}
+
+	result := AssignWindowsWithPlacements(windows, layout, nil, rules, nil, types.AssignAutoFlow, "", placements, WindowOverrides{})
+
+	if len(result.Assignments["side"]) != 1 || result.Assignments["side"][0] != 1 {
+		t.Errorf("expected explicitly placed window 1 in side, got %v", result.Assignments["side"])
+	}
+	if len(result.Floating) != 0 {
+		t.Errorf("expected no floating windows, got %v", result.Floating)
+	}
+}
+
+func TestAssignWindowsWithPlacements_FollowsStrategy(t *testing.T) {
+	windows := []Window{{ID: 1}, {ID: 2}, {ID: 3}}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+	previous := map[string][]uint32{"a": {1, 2}, "b": {3}}
+
+	// Window 2 keeps its preserved cell even though b is emptied by the placement
+	result := AssignWindowsWithPlacements(windows, layout, nil, nil, previous, types.AssignPreserve, "",
+		map[uint32]string{3: "a"}, WindowOverrides{})
+
+	if !reflect.DeepEqual(result.Assignments["a"], []uint32{3, 1, 2}) {
+		t.Errorf("cell a = %v, want placed 3 then preserved 1, 2", result.Assignments["a"])
+	}
+	if len(result.Assignments["b"]) != 0 {
+		t.Errorf("cell b = %v, want empty", result.Assignments["b"])
+	}
+}
+
+func TestAssignWindowsWithPlacements_ReportsExcluded(t *testing.T) {
+	windows := []Window{{ID: 1, IsMinimized: true}, {ID: 2}}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+
+	result := AssignWindowsWithPlacements(windows, layout, nil, nil, nil, types.AssignAutoFlow, "",
+		map[uint32]string{1: "a"}, WindowOverrides{})
+
+	if !reflect.DeepEqual(result.Unplaced, []uint32{1}) {
+		t.Errorf("unplaced = %v, want [1]", result.Unplaced)
+	}
+	if !reflect.DeepEqual(result.Excluded, []uint32{1}) {
+		t.Errorf("excluded = %v, want [1]", result.Excluded)
+	}
+}
+
+func TestAssignWindowsWithPlacements_NoPlacements(t *testing.T) {
+	windows := []Window{{ID: 1}, {ID: 2}}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "left"}, {ID: "right"}},
+	}
+	cellBounds := map[string]types.Rect{
+		"left":  {X: 0, Y: 0, Width: 500, Height: 1000},
+		"right": {X: 500, Y: 0, Width: 500, Height: 1000},
+	}
+
+	got := AssignWindowsWithPlacements(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "", nil, WindowOverrides{})
+	want := AssignWindows(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "")
+
+	for cellID, ids := range want.Assignments {
+		if len(got.Assignments[cellID]) != len(ids) {
+			t.Errorf("cell %s: got %v, want %v", cellID, got.Assignments[cellID], ids)
+		}
+	}
+}
+
+func TestAssignWindowsWithPins(t *testing.T) {
+	windows := []Window{
+		{ID: 1}, {ID: 2}, {ID: 3, AppName: "Finder"}, {ID: 4},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+	rules := []config.AppRule{{App: "Finder", Float: true}}
+	previous := map[string][]uint32{"a": {1, 2, 4}}
+	// Pins beat preserved cells and float rules; a pin to a missing cell is ignored
+	pins := map[uint32]string{1: "b", 3: "b", 4: "gone"}
+
+	result := AssignWindowsWithOverrides(windows, layout, nil, rules, previous, types.AssignPreserve, "", WindowOverrides{Pins: pins})
+
+	if !reflect.DeepEqual(result.Assignments["b"], []uint32{1, 3}) {
+		t.Errorf("cell b = %v, want pinned [1 3]", result.Assignments["b"])
+	}
+	if !reflect.DeepEqual(result.Assignments["a"], []uint32{2, 4}) {
+		t.Errorf("cell a = %v, want preserved [2 4]", result.Assignments["a"])
+	}
+	if len(result.Floating) != 0 {
+		t.Errorf("expected pinned window not to float, got %v", result.Floating)
+	}
+}
+
+func TestAssignWindowsWithOverrides_Floated(t *testing.T) {
+	windows := []Window{{ID: 1}, {ID: 2}, {ID: 3}}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+	previous := map[string][]uint32{"a": {1, 2}, "b": {3}}
+	// Floating beats the window's pin and its preserved cell
+	overrides := WindowOverrides{
+		Pins:    map[uint32]string{2: "b"},
+		Floated: map[uint32]bool{1: true, 2: true},
+	}
+
+	result := AssignWindowsWithOverrides(windows, layout, nil, nil, previous, types.AssignPreserve, "", overrides)
+
+	if !reflect.DeepEqual(result.Floating, []uint32{1, 2}) {
+		t.Errorf("Floating = %v, want [1 2]", result.Floating)
+	}
+	for cellID, ids := range result.Assignments {
+		for _, id := range ids {
+			if id == 1 || id == 2 {
+				t.Errorf("floated window %d assigned to cell %s", id, cellID)
+			}
+		}
+	}
+}
+
+func TestAssignWindowsWithOverrides_ReinsertByPosition(t *testing.T) {
+	// Window 3 sits over cell b; autoflow alone would put it in a
+	windows := []Window{
+		{ID: 3, Frame: types.Rect{X: 1100, Y: 100, Width: 600, Height: 500}},
+		{ID: 1, Frame: types.Rect{X: 0, Y: 0, Width: 500, Height: 500}},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+	cellBounds := map[string]types.Rect{
+		"a": {X: 0, Y: 0, Width: 960, Height: 1080},
+		"b": {X: 960, Y: 0, Width: 960, Height: 1080},
+	}
+
+	result := AssignWindowsWithOverrides(windows, layout, cellBounds, nil, nil, types.AssignAutoFlow, "",
+		WindowOverrides{Reinsert: map[uint32]bool{3: true}})
+
+	if !reflect.DeepEqual(result.Assignments["b"], []uint32{3}) {
+		t.Errorf("cell b = %v, want reinserted [3]", result.Assignments["b"])
+	}
+	if !reflect.DeepEqual(result.Assignments["a"], []uint32{1}) {
+		t.Errorf("cell a = %v, want [1]", result.Assignments["a"])
+	}
+}
+
+func TestAssignWindowsWithPlacements_BeatsPins(t *testing.T) {
+	windows := []Window{{ID: 1}, {ID: 2}}
+	layout := &types.Layout{
+		Cells: []types.Cell{{ID: "a"}, {ID: "b"}},
+	}
+
+	result := AssignWindowsWithPlacements(windows, layout, nil, nil, nil, types.AssignAutoFlow, "",
+		map[uint32]string{1: "a"}, WindowOverrides{Pins: map[uint32]string{1: "b", 2: "a"}})
+
+	if !reflect.DeepEqual(result.Assignments["a"], []uint32{1, 2}) {
+		t.Errorf("cell a = %v, want placed 1 then pinned 2", result.Assignments["a"])
+	}
+}
+
 func TestAssignPreserve(t *testing.T) {
 	windows := []Window{
 		{ID: 1}, {ID: 2}, {ID: 3},
@@ -140,7 +364,7 @@ func TestAssignPreserve(t *testing.T) {
 		"b": {2},
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve)
+	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve, "")
 
 	// Windows should maintain previous cells
 	if len(result.Assignments["a"]) != 2 {
@@ -189,7 +413,7 @@ func TestAssignPreserve_NewWindows(t *testing.T) {
 		"b": {2, 3},
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve)
+	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve, "")
 
 	// Total should be 4
 	total := len(result.Assignments["a"]) + len(result.Assignments["b"])
@@ -203,6 +427,81 @@ func TestAssignPreserve_NewWindows(t *testing.T) {
 	}
 }
 
+func TestAssignPreserve_NewWindowAtFocus(t *testing.T) {
+	windows := []Window{
+		{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, // Window 4 is new
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "a"}, {ID: "b"},
+		},
+	}
+	previous := map[string][]uint32{
+		"a": {1},
+		"b": {2, 3},
+	}
+
+	// Cell a is least populated, but b has focus
+	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve, "b")
+
+	b := result.Assignments["b"]
+	if len(b) != 3 || b[2] != 4 {
+		t.Errorf("expected new window 4 in focused cell b, got %v", b)
+	}
+	if len(result.Assignments["a"]) != 1 {
+		t.Errorf("expected cell a unchanged, got %v", result.Assignments["a"])
+	}
+}
+
+func TestAssignPreserve_FocusedCellNotInLayout(t *testing.T) {
+	windows := []Window{
+		{ID: 1}, {ID: 2}, {ID: 3},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "a"}, {ID: "b"},
+		},
+	}
+	previous := map[string][]uint32{
+		"b": {1, 2},
+	}
+
+	// A focused cell from the previous layout falls back to least populated
+	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve, "gone")
+
+	if a := result.Assignments["a"]; len(a) != 1 || a[0] != 3 {
+		t.Errorf("expected window 3 in least populated cell a, got %v", a)
+	}
+}
+
+func TestAssignPreserve_SeedsFromPositions(t *testing.T) {
+	windows := []Window{
+		{ID: 1, Frame: types.Rect{X: 1000, Y: 0, Width: 900, Height: 1000}},
+		{ID: 2, Frame: types.Rect{X: 50, Y: 20, Width: 800, Height: 900}},
+		{ID: 3, Frame: types.Rect{X: 1100, Y: 100, Width: 700, Height: 800}},
+	}
+	layout := &types.Layout{
+		Cells: []types.Cell{
+			{ID: "a"}, {ID: "b"},
+		},
+	}
+	cellBounds := map[string]types.Rect{
+		"a": {X: 0, Y: 0, Width: 960, Height: 1080},
+		"b": {X: 960, Y: 0, Width: 960, Height: 1080},
+	}
+	// Fresh state: cells exist but hold no windows
+	previous := map[string][]uint32{"a": {}, "b": {}}
+
+	result := AssignWindows(windows, layout, cellBounds, nil, previous, types.AssignPreserve, "")
+
+	if a := result.Assignments["a"]; len(a) != 1 || a[0] != 2 {
+		t.Errorf("expected window 2 to stay in cell a, got %v", a)
+	}
+	if b := result.Assignments["b"]; len(b) != 2 || b[0] != 1 || b[1] != 3 {
+		t.Errorf("expected windows [1 3] to stay in cell b, got %v", b)
+	}
+}
+
 func TestAssignPreserve_CellRemoved(t *testing.T) {
 	windows := []Window{
 		{ID: 1}, {ID: 2},
@@ -217,7 +516,7 @@ func TestAssignPreserve_CellRemoved(t *testing.T) {
 		"b": {2}, // This cell no longer exists
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve)
+	result := AssignWindows(windows, layout, nil, nil, previous, types.AssignPreserve, "")
 
 	// Window 2 should be reassigned to remaining cell
 	if len(result.Assignments["a"]) != 2 {
@@ -239,7 +538,7 @@ func TestFloatingWindows(t *testing.T) {
 		{App: "Finder", Float: true},
 	}
 
-	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, nil, appRules, nil, types.AssignAutoFlow, "")
 
 	// Finder should be floating
 	if len(result.Floating) != 1 || result.Floating[0] != 1 {
@@ -263,7 +562,7 @@ func TestExcludedWindows_Minimized(t *testing.T) {
 		},
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow, "")
 
 	// Minimized window should be excluded
 	if len(result.Excluded) != 1 || result.Excluded[0] != 1 {
@@ -287,7 +586,7 @@ func TestExcludedWindows_Hidden(t *testing.T) {
 		},
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow, "")
 
 	if len(result.Excluded) != 1 || result.Excluded[0] != 1 {
 		t.Error("hidden window should be excluded")
@@ -305,7 +604,7 @@ func TestExcludedWindows_HighLevel(t *testing.T) {
 		},
 	}
 
-	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(windows, layout, nil, nil, nil, types.AssignAutoFlow, "")
 
 	if len(result.Excluded) != 1 || result.Excluded[0] != 2 {
 		t.Error("high-level window should be excluded")
@@ -366,7 +665,7 @@ func TestFindLeastPopulatedCell(t *testing.T) {
 		"c": {5, 6},
 	}
 
-	result := findLeastPopulatedCell(assignments)
+	result := FindLeastPopulatedCell(assignments)
 	if result != "b" {
 		t.Errorf("expected 'b' (least populated), got %q", result)
 	}
@@ -380,7 +679,7 @@ func TestFindLeastPopulatedCell_Tie(t *testing.T) {
 	}
 
 	// With alphabetical tiebreaker, "a" should win
-	result := findLeastPopulatedCell(assignments)
+	result := FindLeastPopulatedCell(assignments)
 	if result != "a" {
 		t.Errorf("expected 'a' (alphabetically first in tie), got %q", result)
 	}
@@ -416,7 +715,7 @@ func TestAssignmentResult_InitializedMaps(t *testing.T) {
 		},
 	}
 
-	result := AssignWindows(nil, layout, nil, nil, nil, types.AssignAutoFlow)
+	result := AssignWindows(nil, layout, nil, nil, nil, types.AssignAutoFlow, "")
 
 	// All cells should have initialized (empty) slices
 	for _, cellID := range []string{"a", "b", "c"} {
@@ -0,0 +1,48 @@
+package layout
+
+import (
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// stackBSP tiles a cell's windows as a binary space partition. It's only set
+// by ApplyLayout for the bsp assignment strategy, never read from config.
+const stackBSP types.StackMode = "bsp"
+
+// calculateBSP splits a cell between n windows the way a binary space
+// partition grows: each window after the first halves the largest window
+// so far (the earliest one on ties) across its longer side, keeping the
+// first half for that window and taking the second. Window i gets bounds[i].
+func calculateBSP(cellBounds types.Rect, n int, padding float64) []types.Rect {
+	if n <= 0 {
+		return nil
+	}
+
+	bounds := make([]types.Rect, 1, n)
+	bounds[0] = cellBounds
+	for len(bounds) < n {
+		largest := 0
+		for i, r := range bounds {
+			if r.Width*r.Height > bounds[largest].Width*bounds[largest].Height {
+				largest = i
+			}
+		}
+
+		first, second := halveRect(bounds[largest], padding)
+		bounds[largest] = first
+		bounds = append(bounds, second)
+	}
+	return bounds
+}
+
+// halveRect splits r across its longer side, with padding between the halves:
+// left and right for wide (or square) rects, top and bottom for tall ones.
+func halveRect(r types.Rect, padding float64) (types.Rect, types.Rect) {
+	if r.Width >= r.Height {
+		w := (r.Width - padding) / 2
+		return types.Rect{X: r.X, Y: r.Y, Width: w, Height: r.Height},
+			types.Rect{X: r.X + w + padding, Y: r.Y, Width: w, Height: r.Height}
+	}
+	h := (r.Height - padding) / 2
+	return types.Rect{X: r.X, Y: r.Y, Width: r.Width, Height: h},
+		types.Rect{X: r.X, Y: r.Y + h + padding, Width: r.Width, Height: h}
+}
@@ -0,0 +1,109 @@
+package layout
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestCalculateBSP(t *testing.T) {
+	cell := types.Rect{X: 0, Y: 0, Width: 1000, Height: 600}
+	left := types.Rect{X: 0, Y: 0, Width: 500, Height: 600}
+	right := types.Rect{X: 500, Y: 0, Width: 500, Height: 600}
+
+	tests := []struct {
+		n    int
+		want []types.Rect
+	}{
+		{1, []types.Rect{cell}},
+		// Wide cell splits left/right
+		{2, []types.Rect{left, right}},
+		// Both halves are the same size: the first, tall, splits top/bottom
+		{3, []types.Rect{
+			{X: 0, Y: 0, Width: 500, Height: 300},
+			right,
+			{X: 0, Y: 300, Width: 500, Height: 300},
+		}},
+		// The right half is now the largest window
+		{4, []types.Rect{
+			{X: 0, Y: 0, Width: 500, Height: 300},
+			{X: 500, Y: 0, Width: 500, Height: 300},
+			{X: 0, Y: 300, Width: 500, Height: 300},
+			{X: 500, Y: 300, Width: 500, Height: 300},
+		}},
+	}
+
+	for _, tt := range tests {
+		if got := calculateBSP(cell, tt.n, 0); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("calculateBSP(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateBSP_Padding(t *testing.T) {
+	got := calculateBSP(types.Rect{X: 10, Y: 20, Width: 1008, Height: 600}, 3, 8)
+	want := []types.Rect{
+		{X: 10, Y: 20, Width: 500, Height: 296},
+		{X: 518, Y: 20, Width: 500, Height: 600},
+		{X: 10, Y: 324, Width: 500, Height: 296},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("calculateBSP = %v, want %v", got, want)
+	}
+}
+
+func TestCalculateAllWindowPlacements_BSP(t *testing.T) {
+	calculated := &types.CalculatedLayout{
+		CellBounds: map[string]types.Rect{"main": {Width: 1000, Height: 600}},
+	}
+	placements := CalculateAllWindowPlacements(
+		calculated,
+		map[string][]uint32{"main": {1, 2}},
+		map[string]types.StackMode{"main": stackBSP},
+		nil,
+		types.StackVertical,
+		0,
+	)
+
+	want := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{Width: 500, Height: 600}},
+		{WindowID: 2, Bounds: types.Rect{X: 500, Width: 500, Height: 600}},
+	}
+	if !reflect.DeepEqual(placements, want) {
+		t.Errorf("placements = %v, want %v", placements, want)
+	}
+}
+
+func TestApplyLayout_BSPPersistsForReapply(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+	rs := state.NewRuntimeState()
+
+	opts := DefaultApplyOptions()
+	opts.Strategy = types.AssignBSP
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, "full", opts); err != nil {
+		t.Fatal(err)
+	}
+	if !rs.GetSpace("1").BSP {
+		t.Fatal("expected the space to be marked BSP")
+	}
+
+	// Reapplying with the default strategy stays BSP
+	if err := ReapplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if !rs.GetSpace("1").BSP {
+		t.Error("expected reapply to keep BSP")
+	}
+
+	// Applying a layout without it ends BSP
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, "full", DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if rs.GetSpace("1").BSP {
+		t.Error("expected apply without bsp to clear it")
+	}
+}
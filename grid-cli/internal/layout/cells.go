@@ -14,14 +14,14 @@ import (
 //   - rowPositions: Starting Y position for each row (len = rows + 1)
 //   - colSizes: Width of each column
 //   - rowSizes: Height of each row
-//   - gap: Gap between cells
+//   - columnGap, rowGap: Gaps between columns and between rows
 //
 // Returns: Rect with cell's position and size
 func CalculateCellBounds(
 	cell types.Cell,
 	colPositions, rowPositions []float64,
 	colSizes, rowSizes []float64,
-	gap float64,
+	columnGap, rowGap float64,
 ) types.Rect {
 	// Convert 1-indexed to 0-indexed
 	colStart := cell.ColumnStart - 1
@@ -43,7 +43,7 @@ func CalculateCellBounds(
 	for i := colStart; i < colEnd; i++ {
 		width += colSizes[i]
 		if i < colEnd-1 {
-			width += gap // Add gap between spanned columns
+			width += columnGap // Add gap between spanned columns
 		}
 	}
 
@@ -53,7 +53,7 @@ func CalculateCellBounds(
 	for i := rowStart; i < rowEnd; i++ {
 		height += rowSizes[i]
 		if i < rowEnd-1 {
-			height += gap // Add gap between spanned rows
+			height += rowGap // Add gap between spanned rows
 		}
 	}
 
@@ -76,6 +76,29 @@ func GetCellAtPoint(cellBounds map[string]types.Rect, point types.Point) string
 	return ""
 }
 
+// NavigableCells returns the cell bounds that directional navigation may
+// land in: every cell except those marked skipNavigation. The current cell
+// is always kept so navigation can start from it.
+func NavigableCells(layoutDef *types.Layout, cellBounds map[string]types.Rect, currentCell string) map[string]types.Rect {
+	skip := make(map[string]bool)
+	for _, cell := range layoutDef.Cells {
+		if cell.SkipNavigation && cell.ID != currentCell {
+			skip[cell.ID] = true
+		}
+	}
+	if len(skip) == 0 {
+		return cellBounds
+	}
+
+	result := make(map[string]types.Rect, len(cellBounds))
+	for id, bounds := range cellBounds {
+		if !skip[id] {
+			result[id] = bounds
+		}
+	}
+	return result
+}
+
 // GetAdjacentCells returns cells adjacent to the given cell in each direction.
 // Adjacency is determined by visual overlap in the perpendicular axis.
 func GetAdjacentCells(
@@ -87,6 +110,11 @@ func GetAdjacentCells(
 		types.DirRight: {},
 		types.DirUp:    {},
 		types.DirDown:  {},
+
+		types.DirUpLeft:    {},
+		types.DirUpRight:   {},
+		types.DirDownLeft:  {},
+		types.DirDownRight: {},
 	}
 
 	current, ok := cellBounds[cellID]
@@ -120,6 +148,21 @@ func GetAdjacentCells(
 		if dy > 0 && overlapsHorizontally(current, bounds) {
 			result[types.DirDown] = append(result[types.DirDown], id)
 		}
+
+		// Diagonal neighbours overlap on neither axis
+		if overlapsVertically(current, bounds) || overlapsHorizontally(current, bounds) {
+			continue
+		}
+		switch {
+		case dx < 0 && dy < 0:
+			result[types.DirUpLeft] = append(result[types.DirUpLeft], id)
+		case dx > 0 && dy < 0:
+			result[types.DirUpRight] = append(result[types.DirUpRight], id)
+		case dx < 0 && dy > 0:
+			result[types.DirDownLeft] = append(result[types.DirDownLeft], id)
+		case dx > 0 && dy > 0:
+			result[types.DirDownRight] = append(result[types.DirDownRight], id)
+		}
 	}
 
 	return result
@@ -156,3 +199,28 @@ func SortCellsByPosition(cellBounds map[string]types.Rect) []string {
 
 	return ids
 }
+
+// DefaultMainCell is the cell ID MainCell prefers when settings.mainCell
+// isn't set or isn't in the layout
+const DefaultMainCell = "main"
+
+// MainCell returns the layout's main cell, the one window promote moves
+// windows into: the configured cell if the layout has it, else a cell named
+// "main", else the largest cell (the top-left one on ties).
+func MainCell(configured string, cellBounds map[string]types.Rect) string {
+	for _, id := range []string{configured, DefaultMainCell} {
+		if _, ok := cellBounds[id]; ok && id != "" {
+			return id
+		}
+	}
+
+	mainCell := ""
+	largest := -1.0
+	for _, id := range SortCellsByPosition(cellBounds) {
+		bounds := cellBounds[id]
+		if area := bounds.Width * bounds.Height; area > largest {
+			mainCell, largest = id, area
+		}
+	}
+	return mainCell
+}
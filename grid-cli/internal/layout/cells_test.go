@@ -13,7 +13,7 @@ func TestCalculateCellBounds_SingleCell(t *testing.T) {
 	colSizes := []float64{500, 500}
 	rowSizes := []float64{500, 500}
 
-	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0)
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0, 0)
 
 	if bounds.X != 0 {
 		t.Errorf("X = %v, want 0", bounds.X)
@@ -38,7 +38,7 @@ func TestCalculateCellBounds_SpanningColumns(t *testing.T) {
 	rowSizes := []float64{500}
 	gap := float64(10)
 
-	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap)
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap, gap)
 
 	// Width = col[0] + gap + col[1] = 100 + 10 + 100 = 210
 	if bounds.X != 0 {
@@ -58,7 +58,7 @@ func TestCalculateCellBounds_SpanningRows(t *testing.T) {
 	rowSizes := []float64{200, 200}
 	gap := float64(10)
 
-	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap)
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap, gap)
 
 	// Height = row[0] + gap + row[1] = 200 + 10 + 200 = 410
 	if bounds.Y != 0 {
@@ -78,7 +78,7 @@ func TestCalculateCellBounds_SecondCell(t *testing.T) {
 	rowSizes := []float64{100, 100}
 	gap := float64(10)
 
-	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap)
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, gap, gap)
 
 	if bounds.X != 110 {
 		t.Errorf("X = %v, want 110", bounds.X)
@@ -102,19 +102,86 @@ func TestCalculateCellBounds_InvalidBounds(t *testing.T) {
 
 	// Cell out of bounds (column)
 	cell := types.Cell{ID: "bad", ColumnStart: 1, ColumnEnd: 3, RowStart: 1, RowEnd: 2}
-	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0)
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0, 0)
 	if bounds.Width != 0 && bounds.Height != 0 {
 		t.Error("expected zero rect for out-of-bounds cell")
 	}
 
 	// Invalid span (start >= end)
 	cell = types.Cell{ID: "bad", ColumnStart: 2, ColumnEnd: 1, RowStart: 1, RowEnd: 2}
-	bounds = CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0)
+	bounds = CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 0, 0)
 	if bounds.Width != 0 && bounds.Height != 0 {
 		t.Error("expected zero rect for invalid span")
 	}
 }
 
+// autoColumnsLayout builds a one-row layout over ["200px", "auto", "1fr"] columns
+func autoColumnsLayout(cells ...types.Cell) *types.Layout {
+	return &types.Layout{
+		ID: "auto",
+		Columns: []types.TrackSize{
+			{Type: types.TrackPx, Value: 200},
+			{Type: types.TrackAuto},
+			{Type: types.TrackFr, Value: 1},
+		},
+		Rows:  []types.TrackSize{{Type: types.TrackFr, Value: 1}},
+		Cells: cells,
+	}
+}
+
+func TestCalculateLayout_AutoColumnOccupied(t *testing.T) {
+	layout := autoColumnsLayout(
+		types.Cell{ID: "fixed", ColumnStart: 1, ColumnEnd: 2, RowStart: 1, RowEnd: 2},
+		types.Cell{ID: "auto", ColumnStart: 2, ColumnEnd: 3, RowStart: 1, RowEnd: 2},
+		types.Cell{ID: "flex", ColumnStart: 3, ColumnEnd: 4, RowStart: 1, RowEnd: 2},
+	)
+
+	calc := CalculateLayout(layout, types.Rect{Width: 1000, Height: 600}, 0)
+
+	// 800px left after the px column, shared by the auto column and 1fr
+	want := map[string]types.Rect{
+		"fixed": {X: 0, Y: 0, Width: 200, Height: 600},
+		"auto":  {X: 200, Y: 0, Width: 400, Height: 600},
+		"flex":  {X: 600, Y: 0, Width: 400, Height: 600},
+	}
+	for cellID, rect := range want {
+		if got := calc.CellBounds[cellID]; got != rect {
+			t.Errorf("%s bounds = %+v, want %+v", cellID, got, rect)
+		}
+	}
+}
+
+func TestCalculateLayout_AutoColumnEmptyCollapses(t *testing.T) {
+	layout := autoColumnsLayout(
+		types.Cell{ID: "fixed", ColumnStart: 1, ColumnEnd: 2, RowStart: 1, RowEnd: 2},
+		types.Cell{ID: "flex", ColumnStart: 3, ColumnEnd: 4, RowStart: 1, RowEnd: 2},
+	)
+
+	calc := CalculateLayout(layout, types.Rect{Width: 1000, Height: 600}, 10)
+
+	if calc.ColumnSizes[1] != 0 {
+		t.Errorf("empty auto column = %v, want 0", calc.ColumnSizes[1])
+	}
+	// Both gaps are still laid out: 1000 - 200 - 2*10 = 780 for the fr column
+	if got := calc.CellBounds["flex"]; got.X != 220 || got.Width != 780 {
+		t.Errorf("flex bounds = %+v, want x 220 width 780", got)
+	}
+}
+
+func TestCalculateLayout_AutoColumnSpanned(t *testing.T) {
+	// A cell spanning the auto column counts as occupying it
+	layout := autoColumnsLayout(
+		types.Cell{ID: "wide", ColumnStart: 1, ColumnEnd: 3, RowStart: 1, RowEnd: 2},
+		types.Cell{ID: "flex", ColumnStart: 3, ColumnEnd: 4, RowStart: 1, RowEnd: 2},
+	)
+
+	calc := CalculateLayout(layout, types.Rect{Width: 1000, Height: 600}, 0)
+
+	if got := calc.CellBounds["wide"].Width; got != 600 {
+		t.Errorf("wide width = %v, want 600", got)
+	}
+}
+
 func TestGetCellAtPoint(t *testing.T) {
 	cellBounds := map[string]types.Rect{
 		"left":  {X: 0, Y: 0, Width: 500, Height: 1000},
@@ -168,6 +235,31 @@ func TestGetAdjacentCells(t *testing.T) {
 	}
 }
 
+func TestGetAdjacentCells_Diagonal(t *testing.T) {
+	// Simple 2x2 grid
+	cellBounds := map[string]types.Rect{
+		"tl": {X: 0, Y: 0, Width: 100, Height: 100},
+		"tr": {X: 110, Y: 0, Width: 100, Height: 100},
+		"bl": {X: 0, Y: 110, Width: 100, Height: 100},
+		"br": {X: 110, Y: 110, Width: 100, Height: 100},
+	}
+
+	adj := GetAdjacentCells("br", cellBounds)
+
+	if len(adj[types.DirUpLeft]) != 1 || adj[types.DirUpLeft][0] != "tl" {
+		t.Errorf("DirUpLeft = %v, want [tl]", adj[types.DirUpLeft])
+	}
+	for _, dir := range []types.Direction{types.DirUpRight, types.DirDownLeft, types.DirDownRight} {
+		if len(adj[dir]) != 0 {
+			t.Errorf("%v = %v, want []", dir, adj[dir])
+		}
+	}
+	// Orthogonal neighbours are not diagonal candidates
+	if len(adj[types.DirLeft]) != 1 || adj[types.DirLeft][0] != "bl" {
+		t.Errorf("DirLeft = %v, want [bl]", adj[types.DirLeft])
+	}
+}
+
 func TestGetAdjacentCells_UnknownCell(t *testing.T) {
 	cellBounds := map[string]types.Rect{
 		"main": {X: 0, Y: 0, Width: 100, Height: 100},
@@ -259,3 +351,63 @@ func TestOverlapsHorizontally(t *testing.T) {
 		})
 	}
 }
+
+func TestNavigableCells(t *testing.T) {
+	layoutDef := &types.Layout{Cells: []types.Cell{
+		{ID: "left"},
+		{ID: "video", SkipNavigation: true},
+		{ID: "right"},
+	}}
+	cellBounds := map[string]types.Rect{
+		"left":  {X: 0, Y: 0, Width: 100, Height: 100},
+		"video": {X: 100, Y: 0, Width: 100, Height: 100},
+		"right": {X: 200, Y: 0, Width: 100, Height: 100},
+	}
+
+	nav := NavigableCells(layoutDef, cellBounds, "left")
+	if _, ok := nav["video"]; ok || len(nav) != 2 {
+		t.Errorf("expected video to be skipped, got %v", nav)
+	}
+	if got := GetAdjacentCells("left", nav)[types.DirRight]; len(got) != 1 || got[0] != "right" {
+		t.Errorf("right of left = %v, want [right]", got)
+	}
+
+	// Navigation can still start from a skipped cell
+	if _, ok := NavigableCells(layoutDef, cellBounds, "video")["video"]; !ok {
+		t.Error("current cell should be kept even if it skips navigation")
+	}
+}
+
+func TestCalculateCellBounds_PerAxisGaps(t *testing.T) {
+	// A cell spanning two columns and two rows picks up one gap of each kind
+	cell := types.Cell{ID: "all", ColumnStart: 1, ColumnEnd: 3, RowStart: 1, RowEnd: 3}
+	colSizes := []float64{100, 100}
+	rowSizes := []float64{50, 50}
+	colPositions := []float64{0, 110, 220}
+	rowPositions := []float64{0, 80, 160}
+
+	bounds := CalculateCellBounds(cell, colPositions, rowPositions, colSizes, rowSizes, 10, 30)
+	if bounds.Width != 210 || bounds.Height != 130 {
+		t.Errorf("bounds = %+v, want 210x130", bounds)
+	}
+}
+
+func TestMainCell(t *testing.T) {
+	cells := map[string]types.Rect{
+		"side":  {X: 0, Y: 0, Width: 400, Height: 1000},
+		"wide":  {X: 400, Y: 0, Width: 1200, Height: 1000},
+		"main":  {X: 1600, Y: 0, Width: 400, Height: 500},
+		"other": {X: 1600, Y: 500, Width: 400, Height: 500},
+	}
+
+	if got := MainCell("side", cells); got != "side" {
+		t.Errorf("configured cell: got %q, want side", got)
+	}
+	if got := MainCell("missing", cells); got != "main" {
+		t.Errorf("cell named main: got %q, want main", got)
+	}
+	delete(cells, "main")
+	if got := MainCell("", cells); got != "wide" {
+		t.Errorf("largest cell: got %q, want wide", got)
+	}
+}
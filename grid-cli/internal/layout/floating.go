@@ -0,0 +1,294 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// FloatingWindows returns the windows that layout application leaves
+// floating: visible windows classified as floating or matched by a float rule.
+func FloatingWindows(windows []Window, appRules []config.AppRule) []Window {
+	var floating []Window
+	for _, w := range windows {
+		if shouldExclude(w) {
+			continue
+		}
+		if shouldFloat(w, appRules) {
+			floating = append(floating, w)
+		}
+	}
+	return floating
+}
+
+// Tileable reports whether layout application tiles a window: it's visible,
+// not an overlay, and neither classified as floating nor matched by a float
+// rule. Sticky windows are checked separately (see StickyChecker).
+func Tileable(w server.WindowInfo, appRules []config.AppRule) bool {
+	lw := convertWindows([]server.WindowInfo{w})[0]
+	return !shouldExclude(lw) && !shouldFloat(lw, appRules)
+}
+
+// CenterPlacements centers windows on a display, keeping their sizes.
+// Each successive window is offset by cascade pixels right and down so
+// the windows don't fully overlap. Positions are clamped to the display.
+func CenterPlacements(windows []Window, display types.Rect, cascade float64) []types.WindowPlacement {
+	placements := make([]types.WindowPlacement, 0, len(windows))
+
+	for i, w := range windows {
+		width := math.Min(w.Frame.Width, display.Width)
+		height := math.Min(w.Frame.Height, display.Height)
+
+		offset := float64(i) * cascade
+		x := display.X + (display.Width-width)/2 + offset
+		y := display.Y + (display.Height-height)/2 + offset
+
+		// Keep the window fully on the display
+		x = math.Min(x, display.X+display.Width-width)
+		y = math.Min(y, display.Y+display.Height-height)
+
+		placements = append(placements, types.WindowPlacement{
+			WindowID: w.ID,
+			Bounds:   types.Rect{X: x, Y: y, Width: width, Height: height},
+		})
+	}
+
+	return placements
+}
+
+// CascadeSize is the fraction of the display's width and height each
+// cascaded window is sized to
+const CascadeSize = 0.6
+
+// CascadePlacements stacks windows from the display's top-left corner, each
+// offset by offset pixels right and down from the previous one and sized to
+// CascadeSize of the display. Positions are clamped to the display.
+func CascadePlacements(windowIDs []uint32, display types.Rect, offset float64) []types.WindowPlacement {
+	placements := make([]types.WindowPlacement, 0, len(windowIDs))
+
+	width := display.Width * CascadeSize
+	height := display.Height * CascadeSize
+	for i, wid := range windowIDs {
+		step := float64(i) * offset
+		x := math.Min(display.X+step, display.X+display.Width-width)
+		y := math.Min(display.Y+step, display.Y+display.Height-height)
+
+		placements = append(placements, types.WindowPlacement{
+			WindowID: wid,
+			Bounds:   types.Rect{X: x, Y: y, Width: width, Height: height},
+		})
+	}
+
+	return placements
+}
+
+// CascadeWindows arranges the given windows in a cascade, outside tiling
+// state. The cascade goes on the display at displayIndex when it is >= 0,
+// otherwise on the display containing the first window.
+func CascadeWindows(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	windowIDs []uint32,
+	displayIndex int,
+	offset float64,
+) error {
+	if len(windowIDs) == 0 {
+		return fmt.Errorf("no windows to cascade")
+	}
+	if displayIndex >= len(snap.AllDisplays) {
+		return fmt.Errorf("display index %d out of range (have %d displays)", displayIndex, len(snap.AllDisplays))
+	}
+
+	windows := make(map[uint32]Window)
+	for _, w := range convertWindows(snap.Windows) {
+		windows[w.ID] = w
+	}
+	for _, wid := range windowIDs {
+		if _, ok := windows[wid]; !ok {
+			return fmt.Errorf("window %d not found on current space", wid)
+		}
+	}
+
+	display := displayForWindow(windows[windowIDs[0]], snap)
+	if displayIndex >= 0 {
+		display = displayBounds(snap.AllDisplays[displayIndex])
+	}
+
+	placements := CascadePlacements(windowIDs, display, offset)
+	logging.Info().Int("windows", len(placements)).Float64("offset", offset).Msg("cascading windows")
+
+	if err := ApplyPlacements(ctx, c, placements); err != nil {
+		return fmt.Errorf("failed to apply placements: %w", err)
+	}
+	return nil
+}
+
+// CenterFloating centers every floating window on the current space.
+// Windows are centered on the display containing them, or on the display
+// at displayIndex when it is >= 0. Returns the number of windows moved.
+func CenterFloating(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	displayIndex int,
+	cascade float64,
+) (int, error) {
+	floating := FloatingWindows(convertWindows(snap.Windows), cfg.AppRules)
+	if len(floating) == 0 {
+		return 0, nil
+	}
+
+	if displayIndex >= len(snap.AllDisplays) {
+		return 0, fmt.Errorf("display index %d out of range (have %d displays)", displayIndex, len(snap.AllDisplays))
+	}
+
+	// Group windows by target display, preserving window order
+	var order []types.Rect
+	groups := make(map[types.Rect][]Window)
+	for _, w := range floating {
+		var display types.Rect
+		if displayIndex >= 0 {
+			display = displayBounds(snap.AllDisplays[displayIndex])
+		} else {
+			display = displayForWindow(w, snap)
+		}
+		if _, ok := groups[display]; !ok {
+			order = append(order, display)
+		}
+		groups[display] = append(groups[display], w)
+	}
+
+	var placements []types.WindowPlacement
+	for _, display := range order {
+		placements = append(placements, CenterPlacements(groups[display], display, cascade)...)
+	}
+
+	logging.Info().Int("windows", len(placements)).Float64("cascade", cascade).Msg("centering floating windows")
+
+	if err := ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
+		return 0, fmt.Errorf("failed to apply placements: %w", err)
+	}
+
+	return len(placements), nil
+}
+
+// CenterWindow moves a window (0 = the focused one) to the middle of its
+// display's visible frame, inset by padding on every side. The window keeps
+// its size unless that doesn't fit. Returns the frame it was given.
+func CenterWindow(ctx context.Context, c *client.Client, snap *server.Snapshot, windowID uint32, padding float64) (uint32, types.Rect, error) {
+	return resizeOnDisplay(ctx, c, snap, windowID, padding, func(frame, area types.Rect) types.Rect {
+		width := math.Min(frame.Width, area.Width)
+		height := math.Min(frame.Height, area.Height)
+		return types.Rect{
+			X:      area.X + (area.Width-width)/2,
+			Y:      area.Y + (area.Height-height)/2,
+			Width:  width,
+			Height: height,
+		}
+	})
+}
+
+// MaximizeWindow sizes a window (0 = the focused one) to its display's
+// visible frame, inset by padding on every side. Tiling state isn't changed.
+// Returns the frame it was given.
+func MaximizeWindow(ctx context.Context, c *client.Client, snap *server.Snapshot, windowID uint32, padding float64) (uint32, types.Rect, error) {
+	return resizeOnDisplay(ctx, c, snap, windowID, padding, func(_, area types.Rect) types.Rect {
+		return area
+	})
+}
+
+// resizeOnDisplay gives a window the frame place picks from its current
+// frame and the padded visible frame of the display containing its center
+func resizeOnDisplay(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	windowID uint32,
+	padding float64,
+	place func(frame, area types.Rect) types.Rect,
+) (uint32, types.Rect, error) {
+	if padding < 0 {
+		return 0, types.Rect{}, fmt.Errorf("padding cannot be negative")
+	}
+	if windowID == 0 {
+		windowID = snap.FocusedWindowID
+		if windowID == 0 {
+			return 0, types.Rect{}, fmt.Errorf("no focused window")
+		}
+	}
+
+	var frame types.Rect
+	found := false
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			frame, found = w.Frame, true
+			break
+		}
+	}
+	if !found {
+		return 0, types.Rect{}, fmt.Errorf("window %d not found on space %s", windowID, snap.SpaceID)
+	}
+
+	display := displayAt(frame.Center(), snap)
+	area := types.Rect{
+		X:      display.X + padding,
+		Y:      display.Y + padding,
+		Width:  display.Width - 2*padding,
+		Height: display.Height - 2*padding,
+	}
+	if area.Width <= 0 || area.Height <= 0 {
+		return 0, types.Rect{}, fmt.Errorf("padding %.0f leaves no room on a %.0fx%.0f display", padding, display.Width, display.Height)
+	}
+
+	bounds := place(frame, area)
+	logging.Info().
+		Uint32("windowId", windowID).
+		Float64("x", bounds.X).
+		Float64("y", bounds.Y).
+		Float64("w", bounds.Width).
+		Float64("h", bounds.Height).
+		Msg("positioning window on its display")
+
+	if _, err := c.UpdateWindow(ctx, int(windowID), map[string]interface{}{
+		"x":      bounds.X,
+		"y":      bounds.Y,
+		"width":  bounds.Width,
+		"height": bounds.Height,
+	}); err != nil {
+		return 0, types.Rect{}, fmt.Errorf("failed to update window: %w", err)
+	}
+	return windowID, bounds, nil
+}
+
+// displayForWindow returns the bounds of the display containing the
+// window's center, falling back to the snapshot's active display.
+func displayForWindow(w Window, snap *server.Snapshot) types.Rect {
+	return displayAt(w.Frame.Center(), snap)
+}
+
+// displayAt returns the bounds of the display containing p, falling back to
+// the snapshot's active display.
+func displayAt(p types.Point, snap *server.Snapshot) types.Rect {
+	for _, d := range snap.AllDisplays {
+		if d.Frame.Contains(p) {
+			return displayBounds(d)
+		}
+	}
+	return snap.DisplayBounds
+}
+
+// displayBounds returns a display's visible frame, or its full frame if unknown.
+func displayBounds(d server.DisplayInfo) types.Rect {
+	if d.VisibleFrame != (types.Rect{}) {
+		return d.VisibleFrame
+	}
+	return d.Frame
+}
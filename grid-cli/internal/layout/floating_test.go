@@ -0,0 +1,189 @@
+package layout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestFloatingWindows_Classification(t *testing.T) {
+	windows := []Window{
+		{ID: 1, AppName: "Finder", HasCloseButton: true, HasFullscreenButton: true},
+		{ID: 2, AppName: "Calculator", HasCloseButton: true, HasFullscreenButton: true},
+		{ID: 3, AppName: "Preview", Role: "AXWindow", Subrole: "AXDialog", HasCloseButton: true},
+		{ID: 4, AppName: "Calculator", IsMinimized: true},
+	}
+	rules := []config.AppRule{{App: "Calculator", Float: true}}
+
+	floating := FloatingWindows(windows, rules)
+
+	if len(floating) != 2 || floating[0].ID != 2 || floating[1].ID != 3 {
+		t.Errorf("expected floating windows [2 3], got %v", floating)
+	}
+}
+
+func TestCenterPlacements_Cascade(t *testing.T) {
+	display := types.Rect{X: 0, Y: 25, Width: 1000, Height: 800}
+	windows := []Window{
+		{ID: 1, Frame: types.Rect{X: -500, Y: -500, Width: 400, Height: 300}},
+		{ID: 2, Frame: types.Rect{X: 2000, Y: 0, Width: 400, Height: 300}},
+		{ID: 3, Frame: types.Rect{X: 100, Y: 100, Width: 200, Height: 100}},
+	}
+
+	placements := CenterPlacements(windows, display, 30)
+
+	want := []types.Rect{
+		{X: 300, Y: 275, Width: 400, Height: 300},
+		{X: 330, Y: 305, Width: 400, Height: 300},
+		{X: 460, Y: 435, Width: 200, Height: 100},
+	}
+	if len(placements) != len(want) {
+		t.Fatalf("expected %d placements, got %d", len(want), len(placements))
+	}
+	for i, p := range placements {
+		if p.WindowID != windows[i].ID {
+			t.Errorf("placement %d window = %d, want %d", i, p.WindowID, windows[i].ID)
+		}
+		if p.Bounds != want[i] {
+			t.Errorf("placement %d bounds = %+v, want %+v", i, p.Bounds, want[i])
+		}
+	}
+}
+
+func TestCenterPlacements_ClampsToDisplay(t *testing.T) {
+	display := types.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+	windows := []Window{
+		{ID: 1, Frame: types.Rect{Width: 1200, Height: 600}},
+		{ID: 2, Frame: types.Rect{Width: 900, Height: 700}},
+	}
+
+	placements := CenterPlacements(windows, display, 200)
+
+	// Oversized window shrinks to the display width
+	if placements[0].Bounds.Width != 1000 || placements[0].Bounds.X != 0 {
+		t.Errorf("placement 0 = %+v, want width 1000 at x 0", placements[0].Bounds)
+	}
+	// Cascade offset can't push a window off the display
+	b := placements[1].Bounds
+	if b.X+b.Width > 1000 || b.Y+b.Height > 800 {
+		t.Errorf("placement 1 = %+v extends past display", b)
+	}
+}
+
+func TestCascadePlacements(t *testing.T) {
+	display := types.Rect{X: 0, Y: 25, Width: 1000, Height: 800}
+
+	placements := CascadePlacements([]uint32{7, 8, 9}, display, 40)
+
+	want := []types.Rect{
+		{X: 0, Y: 25, Width: 600, Height: 480},
+		{X: 40, Y: 65, Width: 600, Height: 480},
+		{X: 80, Y: 105, Width: 600, Height: 480},
+	}
+	if len(placements) != len(want) {
+		t.Fatalf("expected %d placements, got %d", len(want), len(placements))
+	}
+	for i, p := range placements {
+		if p.WindowID != uint32(7+i) {
+			t.Errorf("placement %d window = %d, want %d", i, p.WindowID, 7+i)
+		}
+		if p.Bounds != want[i] {
+			t.Errorf("placement %d bounds = %+v, want %+v", i, p.Bounds, want[i])
+		}
+	}
+}
+
+func TestCascadePlacements_ClampsToDisplay(t *testing.T) {
+	display := types.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+
+	placements := CascadePlacements([]uint32{1, 2, 3}, display, 300)
+
+	// The third window would start at 600,600; it stops at the display edge
+	b := placements[2].Bounds
+	if b.X != 400 || b.Y != 320 {
+		t.Errorf("placement 2 = %+v, want clamped to 400,320", b)
+	}
+}
+
+// twoDisplaySnapshot has window 30 on the side display (to the right of the
+// main one) and window 31, focused, on the main display
+func twoDisplaySnapshot() *server.Snapshot {
+	main := types.Rect{Width: 1920, Height: 1080}
+	side := types.Rect{X: 1920, Width: 1280, Height: 1024}
+	return &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{Y: 25, Width: 1920, Height: 1055},
+		AllDisplays: []server.DisplayInfo{
+			{UUID: "main", Frame: main, VisibleFrame: types.Rect{Y: 25, Width: 1920, Height: 1055}},
+			{UUID: "side", Frame: side, VisibleFrame: types.Rect{X: 1920, Y: 25, Width: 1280, Height: 999}},
+		},
+		Windows: []server.WindowInfo{
+			{ID: 30, Frame: types.Rect{X: 2000, Y: 100, Width: 600, Height: 400}},
+			{ID: 31, Frame: types.Rect{X: 10, Y: 40, Width: 2400, Height: 500}},
+		},
+		FocusedWindowID: 31,
+	}
+}
+
+func TestMaximizeWindow_UsesWindowDisplayWithPadding(t *testing.T) {
+	ss := &stickyServer{}
+	c := startStickyServer(t, ss)
+
+	windowID, bounds, err := MaximizeWindow(context.Background(), c, twoDisplaySnapshot(), 30, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := types.Rect{X: 1930, Y: 35, Width: 1260, Height: 979}
+	if windowID != 30 || bounds != want {
+		t.Errorf("got window %d at %+v, want 30 at %+v", windowID, bounds, want)
+	}
+
+	updates := ss.WindowUpdates()
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 window update, got %v", updates)
+	}
+	u := updates[0]
+	if u["windowId"] != 30.0 || u["x"] != 1930.0 || u["y"] != 35.0 || u["width"] != 1260.0 || u["height"] != 979.0 {
+		t.Errorf("unexpected update %v", u)
+	}
+}
+
+func TestCenterWindow_KeepsSizeAndDefaultsToFocused(t *testing.T) {
+	c := startStickyServer(t, &stickyServer{})
+	snap := twoDisplaySnapshot()
+
+	windowID, bounds, err := CenterWindow(context.Background(), c, snap, 30, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (types.Rect{X: 2260, Y: 324.5, Width: 600, Height: 400}); windowID != 30 || bounds != want {
+		t.Errorf("got window %d at %+v, want 30 at %+v", windowID, bounds, want)
+	}
+
+	// The focused window is wider than its display and is shrunk to fit
+	windowID, bounds, err = CenterWindow(context.Background(), c, snap, 0, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (types.Rect{X: 20, Y: 302.5, Width: 1880, Height: 500}); windowID != 31 || bounds != want {
+		t.Errorf("got window %d at %+v, want 31 at %+v", windowID, bounds, want)
+	}
+}
+
+func TestCenterWindow_Errors(t *testing.T) {
+	c := startStickyServer(t, &stickyServer{})
+	snap := twoDisplaySnapshot()
+
+	if _, _, err := CenterWindow(context.Background(), c, snap, 99, 0); err == nil {
+		t.Error("expected an error for a window not on the space")
+	}
+	if _, _, err := MaximizeWindow(context.Background(), c, snap, 30, -1); err == nil {
+		t.Error("expected an error for negative padding")
+	}
+	if _, _, err := MaximizeWindow(context.Background(), c, snap, 30, 700); err == nil {
+		t.Error("expected an error for padding wider than the display")
+	}
+}
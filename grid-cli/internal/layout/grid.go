@@ -5,6 +5,8 @@ import (
 )
 
 // CalculateTracks converts track definitions to pixel sizes.
+// Without cell information every auto track is treated as empty and
+// collapses to zero; CalculateLayout sizes auto tracks from the cells.
 //
 // Parameters:
 //   - tracks: Track size definitions from layout
@@ -13,65 +15,98 @@ import (
 //
 // Returns: Array of pixel sizes for each track
 func CalculateTracks(tracks []types.TrackSize, available float64, gap float64) []float64 {
+	return calculateTracks(tracks, available, gap, nil)
+}
+
+// calculateTracks sizes tracks like CalculateTracks. An auto track that a
+// cell occupies (occupied[i]) takes an equal share of the space left after
+// px tracks, the same share a 1fr track gets; an unoccupied one collapses to zero.
+func calculateTracks(tracks []types.TrackSize, available float64, gap float64, occupied []bool) []float64 {
 	if len(tracks) == 0 {
 		return nil
 	}
 
 	// Subtract gaps from available space
-	totalGaps := gap * float64(len(tracks)-1)
-	available -= totalGaps
+	available -= gap * float64(len(tracks)-1)
+
+	// Window content size is unknown, so an occupied auto track shares the
+	// remaining space like 1fr; an empty one collapses to 0
+	resolved := make([]types.TrackSize, len(tracks))
+	for i, track := range tracks {
+		resolved[i] = track
+		if track.Type == types.TrackAuto {
+			if i < len(occupied) && occupied[i] {
+				resolved[i] = types.TrackSize{Type: types.TrackFr, Value: 1}
+			} else {
+				resolved[i] = types.TrackSize{Type: types.TrackPx}
+			}
+		}
+	}
+
+	return applyMinMaxConstraints(resolved, resolveMinMax(resolved, available))
+}
 
+// resolveMinMax sizes px, fr and minmax tracks within available pixels.
+// A minmax(Npx, Xfr) track is an Xfr track floored at N pixels: when its
+// share would drop below N it is pinned to N and the other fr tracks share
+// what is left. Tracks of any other type get no space.
+func resolveMinMax(tracks []types.TrackSize, available float64) []float64 {
 	sizes := make([]float64, len(tracks))
 	remaining := available
 
-	// First pass: allocate fixed pixel tracks and collect fr tracks
-	var totalFr float64
-	var frIndices []int
-
+	var flexible []int
 	for i, track := range tracks {
 		switch track.Type {
 		case types.TrackPx:
 			sizes[i] = track.Value
 			remaining -= track.Value
-		case types.TrackFr:
-			totalFr += track.Value
-			frIndices = append(frIndices, i)
-		case types.TrackMinMax:
-			// Start with minimum, will adjust later
-			sizes[i] = track.Min
-			remaining -= track.Min
-			totalFr += track.Max // Max is in fr units
-			frIndices = append(frIndices, i)
-		case types.TrackAuto:
-			// Auto tracks get minimum size initially
-			// Content-based sizing not supported, treat as 0
-			sizes[i] = 0
+		case types.TrackFr, types.TrackMinMax:
+			flexible = append(flexible, i)
 		}
 	}
 
-	// Second pass: distribute remaining space to fr tracks
-	if totalFr > 0 && remaining > 0 {
-		frUnit := remaining / totalFr
-
-		for _, i := range frIndices {
-			track := tracks[i]
-			switch track.Type {
-			case types.TrackFr:
-				sizes[i] = frUnit * track.Value
-			case types.TrackMinMax:
-				// Add fr portion to minimum
-				frPortion := frUnit * track.Max
-				sizes[i] = track.Min + frPortion
+	// Pin minmax tracks that fall below their minimum, then share again,
+	// until every remaining track's share fits
+	for len(flexible) > 0 {
+		var totalFr float64
+		for _, i := range flexible {
+			totalFr += frWeight(tracks[i])
+		}
+		frUnit := 0.0
+		if totalFr > 0 && remaining > 0 {
+			frUnit = remaining / totalFr
+		}
+
+		var unpinned []int
+		for _, i := range flexible {
+			if track := tracks[i]; track.Type == types.TrackMinMax && frUnit*track.Max < track.Min {
+				sizes[i] = track.Min
+				remaining -= track.Min
+				continue
 			}
+			unpinned = append(unpinned, i)
 		}
-	}
 
-	// Third pass: apply minmax constraints and ensure non-negative
-	sizes = applyMinMaxConstraints(tracks, sizes)
+		if len(unpinned) == len(flexible) {
+			for _, i := range flexible {
+				sizes[i] = frUnit * frWeight(tracks[i])
+			}
+			break
+		}
+		flexible = unpinned
+	}
 
 	return sizes
 }
 
+// frWeight returns a flexible track's fr value (the max of a minmax track)
+func frWeight(track types.TrackSize) float64 {
+	if track.Type == types.TrackMinMax {
+		return track.Max
+	}
+	return track.Value
+}
+
 // applyMinMaxConstraints ensures minmax tracks stay within bounds
 // and all sizes are non-negative.
 func applyMinMaxConstraints(tracks []types.TrackSize, sizes []float64) []float64 {
@@ -92,6 +127,21 @@ func applyMinMaxConstraints(tracks []types.TrackSize, sizes []float64) []float64
 	return sizes
 }
 
+// occupiedTracks reports which of n tracks are covered by at least one cell.
+// span returns a cell's 1-indexed start and exclusive end line on the axis.
+func occupiedTracks(n int, cells []types.Cell, span func(types.Cell) (int, int)) []bool {
+	occupied := make([]bool, n)
+	for _, cell := range cells {
+		start, end := span(cell)
+		for i := start - 1; i < end-1; i++ {
+			if i >= 0 && i < n {
+				occupied[i] = true
+			}
+		}
+	}
+	return occupied
+}
+
 // CalculateTrackPositions returns the starting position of each track.
 // The returned slice has length len(sizes)+1, where positions[i] is the
 // start of track i, and positions[len(sizes)] is the end of the last track.
@@ -115,7 +165,8 @@ func CalculateTrackPositions(sizes []float64, gap float64) []float64 {
 // Parameters:
 //   - layout: Layout definition with columns, rows, and cells
 //   - screenRect: Screen bounds to fit the layout into
-//   - gap: Gap between cells in pixels
+//   - gap: Gap between cells in pixels, unless the layout sets its own
+//     column or row gap
 //
 // Returns: CalculatedLayout with all cell bounds computed
 func CalculateLayout(layout *types.Layout, screenRect types.Rect, gap float64) *types.CalculatedLayout {
@@ -123,30 +174,50 @@ func CalculateLayout(layout *types.Layout, screenRect types.Rect, gap float64) *
 		return nil
 	}
 
+	columnGap, rowGap := gap, gap
+	if layout.ColumnGap != nil {
+		columnGap = *layout.ColumnGap
+	}
+	if layout.RowGap != nil {
+		rowGap = *layout.RowGap
+	}
+
 	// Calculate column and row sizes
-	columnSizes := CalculateTracks(layout.Columns, screenRect.Width, gap)
-	rowSizes := CalculateTracks(layout.Rows, screenRect.Height, gap)
+	columnSizes := calculateTracks(layout.Columns, screenRect.Width, columnGap,
+		occupiedTracks(len(layout.Columns), layout.Cells, func(c types.Cell) (int, int) { return c.ColumnStart, c.ColumnEnd }))
+	rowSizes := calculateTracks(layout.Rows, screenRect.Height, rowGap,
+		occupiedTracks(len(layout.Rows), layout.Cells, func(c types.Cell) (int, int) { return c.RowStart, c.RowEnd }))
 
 	// Calculate column and row positions
-	colPositions := CalculateTrackPositions(columnSizes, gap)
-	rowPositions := CalculateTrackPositions(rowSizes, gap)
+	colPositions := CalculateTrackPositions(columnSizes, columnGap)
+	rowPositions := CalculateTrackPositions(rowSizes, rowGap)
 
 	// Calculate bounds for each cell
 	cellBounds := make(map[string]types.Rect)
+	var aspectRatios map[string]float64
 	for _, cell := range layout.Cells {
-		bounds := CalculateCellBounds(cell, colPositions, rowPositions, columnSizes, rowSizes, gap)
+		bounds := CalculateCellBounds(cell, colPositions, rowPositions, columnSizes, rowSizes, columnGap, rowGap)
 		// Offset by screen position
 		bounds.X += screenRect.X
 		bounds.Y += screenRect.Y
 		cellBounds[cell.ID] = bounds
+
+		if cell.AspectRatio > 0 {
+			if aspectRatios == nil {
+				aspectRatios = make(map[string]float64)
+			}
+			aspectRatios[cell.ID] = cell.AspectRatio
+		}
 	}
 
 	return &types.CalculatedLayout{
-		LayoutID:    layout.ID,
-		ScreenRect:  screenRect,
-		Gap:         gap,
-		ColumnSizes: columnSizes,
-		RowSizes:    rowSizes,
-		CellBounds:  cellBounds,
+		LayoutID:     layout.ID,
+		ScreenRect:   screenRect,
+		ColumnGap:    columnGap,
+		RowGap:       rowGap,
+		ColumnSizes:  columnSizes,
+		RowSizes:     rowSizes,
+		CellBounds:   cellBounds,
+		AspectRatios: aspectRatios,
 	}
 }
@@ -94,20 +94,67 @@ func TestCalculateTracks_MinMax(t *testing.T) {
 	}
 	sizes := CalculateTracks(tracks, 1000, 0)
 
-	// Available = 1000
-	// Min 200px allocated first, remaining = 800
-	// Total fr = 2 (1 from minmax max, 1 from second track)
-	// fr unit = 800/2 = 400
-	// First track: 200 + 400 = 600
-	// Second track: 400
+	// Available = 1000, total fr = 2 (1 from minmax max, 1 from second track)
+	// fr unit = 500, above the 200px minimum, so both tracks get 500
 	if len(sizes) != 2 {
 		t.Fatalf("expected 2 sizes, got %d", len(sizes))
 	}
-	if sizes[0] != 600 {
-		t.Errorf("sizes[0] = %v, want 600", sizes[0])
+	if sizes[0] != 500 {
+		t.Errorf("sizes[0] = %v, want 500", sizes[0])
 	}
-	if sizes[1] != 400 {
-		t.Errorf("sizes[1] = %v, want 400", sizes[1])
+	if sizes[1] != 500 {
+		t.Errorf("sizes[1] = %v, want 500", sizes[1])
+	}
+}
+
+func TestResolveMinMax_PinsToMinimum(t *testing.T) {
+	// 800px split three ways would give the minmax column 266px, under its minimum
+	tracks := []types.TrackSize{
+		{Type: types.TrackFr, Value: 1},
+		{Type: types.TrackMinMax, Min: 300, Max: 1}, // minmax(300px, 1fr)
+		{Type: types.TrackFr, Value: 1},
+	}
+	sizes := resolveMinMax(tracks, 800)
+
+	// Pinned at 300px; the two fr columns share the other 500px
+	want := []float64{250, 300, 250}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Errorf("sizes = %v, want %v", sizes, want)
+			break
+		}
+	}
+
+	// On a wider display the fr share is above the minimum and wins
+	sizes = resolveMinMax(tracks, 1200)
+	if sizes[0] != 400 || sizes[1] != 400 || sizes[2] != 400 {
+		t.Errorf("sizes = %v, want [400 400 400]", sizes)
+	}
+}
+
+func TestCalculateLayout_MinMaxOnNarrowDisplay(t *testing.T) {
+	layout := &types.Layout{
+		ID: "three",
+		Columns: []types.TrackSize{
+			{Type: types.TrackFr, Value: 1},
+			{Type: types.TrackMinMax, Min: 300, Max: 1},
+			{Type: types.TrackFr, Value: 1},
+		},
+		Rows: []types.TrackSize{{Type: types.TrackFr, Value: 1}},
+		Cells: []types.Cell{
+			{ID: "left", ColumnStart: 1, ColumnEnd: 2, RowStart: 1, RowEnd: 2},
+			{ID: "center", ColumnStart: 2, ColumnEnd: 3, RowStart: 1, RowEnd: 2},
+			{ID: "right", ColumnStart: 3, ColumnEnd: 4, RowStart: 1, RowEnd: 2},
+		},
+	}
+
+	calc := CalculateLayout(layout, types.Rect{Width: 800, Height: 600}, 0)
+
+	if got := calc.CellBounds["center"]; got.X != 250 || got.Width != 300 {
+		t.Errorf("center bounds = %+v, want x 250 width 300", got)
+	}
+	if got := calc.CellBounds["right"]; got.X != 550 || got.Width != 250 {
+		t.Errorf("right bounds = %+v, want x 550 width 250", got)
 	}
 }
 
@@ -251,3 +298,44 @@ func TestCalculateLayout_Nil(t *testing.T) {
 func floatEquals(a, b, epsilon float64) bool {
 	return math.Abs(a-b) < epsilon
 }
+
+func TestCalculateLayout_PerAxisGaps(t *testing.T) {
+	columnGap, rowGap := 10.0, 40.0
+	layout := &types.Layout{
+		ID: "quad",
+		Columns: []types.TrackSize{
+			{Type: types.TrackFr, Value: 1},
+			{Type: types.TrackFr, Value: 1},
+		},
+		Rows: []types.TrackSize{
+			{Type: types.TrackFr, Value: 1},
+			{Type: types.TrackFr, Value: 1},
+		},
+		Cells: []types.Cell{
+			{ID: "tl", ColumnStart: 1, ColumnEnd: 2, RowStart: 1, RowEnd: 2},
+			{ID: "br", ColumnStart: 2, ColumnEnd: 3, RowStart: 2, RowEnd: 3},
+		},
+		ColumnGap: &columnGap,
+		RowGap:    &rowGap,
+	}
+
+	// The layout's gaps win over the gap passed in
+	result := CalculateLayout(layout, types.Rect{Width: 1000, Height: 500}, 4)
+
+	// Columns: (1000 - 10) / 2 = 495; rows: (500 - 40) / 2 = 230
+	want := types.Rect{X: 505, Y: 270, Width: 495, Height: 230}
+	if got := result.CellBounds["br"]; got != want {
+		t.Errorf("br = %+v, want %+v", got, want)
+	}
+	if result.ColumnGap != 10 || result.RowGap != 40 {
+		t.Errorf("gaps = %v/%v, want 10/40", result.ColumnGap, result.RowGap)
+	}
+
+	// Without layout gaps, the passed gap applies to both axes
+	layout.ColumnGap, layout.RowGap = nil, nil
+	result = CalculateLayout(layout, types.Rect{Width: 1000, Height: 500}, 4)
+	want = types.Rect{X: 502, Y: 252, Width: 498, Height: 248}
+	if got := result.CellBounds["br"]; got != want {
+		t.Errorf("br with shared gap = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/logging"
+)
+
+// DefaultHookTimeout is how long a hook may run when settings.hooks.timeout
+// isn't set
+const DefaultHookTimeout = 5 * time.Second
+
+// Hook events
+const (
+	HookPreApply  = "preApply"
+	HookPostApply = "postApply"
+)
+
+// HookContext is what a hook is told about the apply, as JSON on stdin and
+// as GRID_* environment variables
+type HookContext struct {
+	Event    string `json:"event"`
+	SpaceID  string `json:"spaceId"`
+	LayoutID string `json:"layoutId"`
+	Applied  int    `json:"applied,omitempty"` // postApply: windows moved
+	Skipped  int    `json:"skipped,omitempty"` // postApply: windows already in place
+}
+
+// env returns the context as environment variables
+func (hc HookContext) env() []string {
+	return []string{
+		"GRID_HOOK=" + hc.Event,
+		"GRID_SPACE_ID=" + hc.SpaceID,
+		"GRID_LAYOUT_ID=" + hc.LayoutID,
+		"GRID_APPLIED=" + strconv.Itoa(hc.Applied),
+		"GRID_SKIPPED=" + strconv.Itoa(hc.Skipped),
+	}
+}
+
+// RunHook runs command with sh -c, passing hc on stdin and in the
+// environment, and kills it after timeout (DefaultHookTimeout if zero).
+// An empty command does nothing.
+func RunHook(ctx context.Context, command string, hc HookContext, timeout time.Duration) error {
+	if command == "" {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	input, err := json.Marshal(hc)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), hc.env()...)
+	cmd.Stdin = bytes.NewReader(input)
+	// Don't wait on children still holding the output pipe after a kill
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// runApplyHook runs the configured hook for an apply event. Failures are
+// reported as warnings and never fail the apply.
+func runApplyHook(ctx context.Context, hooks config.HookSettings, hc HookContext) {
+	command := hooks.PreApply
+	if hc.Event == HookPostApply {
+		command = hooks.PostApply
+	}
+
+	timeout := time.Duration(hooks.Timeout * float64(time.Second))
+	if err := RunHook(ctx, command, hc, timeout); err != nil {
+		logging.Warn().Err(err).Str("hook", hc.Event).Msg("hook failed")
+		fmt.Fprintf(os.Stderr, "Warning: %s hook failed: %v\n", hc.Event, err)
+	}
+}
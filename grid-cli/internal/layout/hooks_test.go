@@ -0,0 +1,94 @@
+package layout
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+func TestRunHook_PassesContext(t *testing.T) {
+	dir := t.TempDir()
+	command := `cat > "$OUT/stdin.json"; echo "$GRID_HOOK $GRID_SPACE_ID $GRID_LAYOUT_ID $GRID_APPLIED" > "$OUT/env"`
+	t.Setenv("OUT", dir)
+
+	hc := HookContext{Event: HookPostApply, SpaceID: "3", LayoutID: "half", Applied: 2}
+	if err := RunHook(context.Background(), command, hc, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := os.ReadFile(filepath.Join(dir, "env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(env)); got != "postApply 3 half 2" {
+		t.Errorf("hook environment = %q, want %q", got, "postApply 3 half 2")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "stdin.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got HookContext
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook stdin isn't JSON: %v", err)
+	}
+	if got != hc {
+		t.Errorf("hook stdin = %+v, want %+v", got, hc)
+	}
+}
+
+func TestRunHook_FailureAndTimeout(t *testing.T) {
+	hc := HookContext{Event: HookPreApply, SpaceID: "1", LayoutID: "full"}
+
+	err := RunHook(context.Background(), "echo broken >&2; exit 3", hc, 0)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected failing hook's output in the error, got %v", err)
+	}
+
+	start := time.Now()
+	err = RunHook(context.Background(), "sleep 5", hc, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hook ran for %v despite the timeout", elapsed)
+	}
+
+	if err := RunHook(context.Background(), "", hc, 0); err != nil {
+		t.Errorf("expected an empty hook to do nothing, got %v", err)
+	}
+}
+
+func TestApplyLayout_HooksDontFailApply(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Setenv("OUT", dir)
+	c := startStickyServer(t, &stickyServer{})
+	rs := state.NewRuntimeState()
+
+	cfg := fullLayoutConfig()
+	cfg.Settings.Hooks.PreApply = "exit 1"
+	cfg.Settings.Hooks.PostApply = `cat > "$OUT/post.json"`
+
+	if err := ApplyLayout(context.Background(), c, stickySnapshot(), cfg, rs, "half", DefaultApplyOptions()); err != nil {
+		t.Fatalf("failing preApply hook failed the apply: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "post.json"))
+	if err != nil {
+		t.Fatalf("postApply hook didn't run: %v", err)
+	}
+	var hc HookContext
+	if err := json.Unmarshal(data, &hc); err != nil {
+		t.Fatal(err)
+	}
+	if hc.Event != HookPostApply || hc.SpaceID != "1" || hc.LayoutID != "half" {
+		t.Errorf("unexpected postApply context: %+v", hc)
+	}
+}
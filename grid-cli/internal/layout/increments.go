@@ -0,0 +1,164 @@
+package layout
+
+import (
+	"math"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// SizeIncrement is the step a window resizes in, e.g. a terminal's
+// character cell. A zero dimension is unconstrained.
+type SizeIncrement struct {
+	Width  float64
+	Height float64
+}
+
+// edgeTolerance is the slack (in pixels) allowed when deciding two windows
+// sit side by side, on top of the spacing between them
+const edgeTolerance = 1.0
+
+// SizeIncrements collects the appRules[].sizeIncrement for each window
+func SizeIncrements(cfg *config.Config, windows []server.WindowInfo) map[uint32]SizeIncrement {
+	increments := make(map[uint32]SizeIncrement)
+	for _, w := range windows {
+		rule := cfg.GetAppRule(w.AppName, w.BundleID)
+		if rule == nil || len(rule.SizeIncrement) != 2 {
+			continue
+		}
+		increments[w.ID] = SizeIncrement{Width: rule.SizeIncrement[0], Height: rule.SizeIncrement[1]}
+	}
+	return increments
+}
+
+// SnapPlacements snaps placements for calculated's cells to the windows'
+// size increments (see SnapToSizeIncrements). Windows sharing a cell are
+// padding apart, windows in neighboring cells the layout's column or row gap.
+func SnapPlacements(cfg *config.Config, calculated *types.CalculatedLayout, placements []types.WindowPlacement, windows []server.WindowInfo, padding float64) []types.WindowPlacement {
+	increments := SizeIncrements(cfg, windows)
+	if len(increments) == 0 || calculated == nil {
+		return placements
+	}
+	return SnapToSizeIncrements(placements, increments, math.Max(calculated.ColumnGap, padding), math.Max(calculated.RowGap, padding))
+}
+
+// SnapToSizeIncrements shrinks each increment-constrained placement to a
+// whole number of increments and hands the freed space to the unconstrained
+// windows beside it (right or below first, otherwise left or above), so the
+// layout keeps no gaps. columnSpacing and rowSpacing are the largest gaps
+// expected between neighboring windows side by side and stacked. With no
+// neighbor to absorb it, the space stays empty.
+func SnapToSizeIncrements(placements []types.WindowPlacement, increments map[uint32]SizeIncrement, columnSpacing, rowSpacing float64) []types.WindowPlacement {
+	if len(increments) == 0 {
+		return placements
+	}
+
+	result := make([]types.WindowPlacement, len(placements))
+	copy(result, placements)
+
+	for i := range result {
+		inc, ok := increments[result[i].WindowID]
+		if !ok {
+			continue
+		}
+		snapWidth(result, i, inc.Width, increments, columnSpacing)
+		snapHeight(result, i, inc.Height, increments, rowSpacing)
+	}
+
+	return result
+}
+
+// snappedSize rounds size down to a whole number of increments, keeping at
+// least one increment. Returns the size unchanged for a zero increment.
+func snappedSize(size, increment float64) float64 {
+	if increment <= 0 || size <= increment {
+		return size
+	}
+	return math.Floor(size/increment) * increment
+}
+
+func snapWidth(placements []types.WindowPlacement, i int, increment float64, increments map[uint32]SizeIncrement, spacing float64) {
+	p := &placements[i].Bounds
+	delta := p.Width - snappedSize(p.Width, increment)
+	if delta <= 0 {
+		return
+	}
+
+	right := neighbors(placements, i, increments, func(b types.Rect) bool {
+		gap := b.X - (p.X + p.Width)
+		return gap >= -edgeTolerance && gap <= spacing+edgeTolerance && overlaps(b.Y, b.Height, p.Y, p.Height)
+	})
+	left := neighbors(placements, i, increments, func(b types.Rect) bool {
+		gap := p.X - (b.X + b.Width)
+		return gap >= -edgeTolerance && gap <= spacing+edgeTolerance && overlaps(b.Y, b.Height, p.Y, p.Height)
+	})
+
+	switch {
+	case len(right) > 0:
+		for _, j := range right {
+			placements[j].Bounds.X -= delta
+			placements[j].Bounds.Width += delta
+		}
+	case len(left) > 0:
+		for _, j := range left {
+			placements[j].Bounds.Width += delta
+		}
+		p.X += delta
+	}
+	p.Width -= delta
+}
+
+func snapHeight(placements []types.WindowPlacement, i int, increment float64, increments map[uint32]SizeIncrement, spacing float64) {
+	p := &placements[i].Bounds
+	delta := p.Height - snappedSize(p.Height, increment)
+	if delta <= 0 {
+		return
+	}
+
+	below := neighbors(placements, i, increments, func(b types.Rect) bool {
+		gap := b.Y - (p.Y + p.Height)
+		return gap >= -edgeTolerance && gap <= spacing+edgeTolerance && overlaps(b.X, b.Width, p.X, p.Width)
+	})
+	above := neighbors(placements, i, increments, func(b types.Rect) bool {
+		gap := p.Y - (b.Y + b.Height)
+		return gap >= -edgeTolerance && gap <= spacing+edgeTolerance && overlaps(b.X, b.Width, p.X, p.Width)
+	})
+
+	switch {
+	case len(below) > 0:
+		for _, j := range below {
+			placements[j].Bounds.Y -= delta
+			placements[j].Bounds.Height += delta
+		}
+	case len(above) > 0:
+		for _, j := range above {
+			placements[j].Bounds.Height += delta
+		}
+		p.Y += delta
+	}
+	p.Height -= delta
+}
+
+// neighbors returns the indices of unconstrained placements other than i
+// whose bounds satisfy beside
+func neighbors(placements []types.WindowPlacement, i int, increments map[uint32]SizeIncrement, beside func(types.Rect) bool) []int {
+	var result []int
+	for j := range placements {
+		if j == i {
+			continue
+		}
+		if _, constrained := increments[placements[j].WindowID]; constrained {
+			continue
+		}
+		if beside(placements[j].Bounds) {
+			result = append(result, j)
+		}
+	}
+	return result
+}
+
+// overlaps reports whether two spans share any length
+func overlaps(aStart, aLen, bStart, bLen float64) bool {
+	return aStart < bStart+bLen && bStart < aStart+aLen
+}
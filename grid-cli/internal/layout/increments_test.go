@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func placementBounds(placements []types.WindowPlacement) map[uint32]types.Rect {
+	bounds := make(map[uint32]types.Rect, len(placements))
+	for _, p := range placements {
+		bounds[p.WindowID] = p.Bounds
+	}
+	return bounds
+}
+
+func TestSnapToSizeIncrements_NeighborsAbsorbRemainder(t *testing.T) {
+	// Terminal (1) top-left, window 2 below it, window 3 to the right; 10px gaps
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 955, Height: 535}},
+		{WindowID: 2, Bounds: types.Rect{X: 0, Y: 545, Width: 955, Height: 535}},
+		{WindowID: 3, Bounds: types.Rect{X: 965, Y: 0, Width: 955, Height: 1080}},
+	}
+	increments := map[uint32]SizeIncrement{1: {Width: 8, Height: 16}}
+
+	got := placementBounds(SnapToSizeIncrements(placements, increments, 10, 10))
+
+	// 955 -> 952 (119 columns), 535 -> 528 (33 rows)
+	if want := (types.Rect{X: 0, Y: 0, Width: 952, Height: 528}); got[1] != want {
+		t.Errorf("terminal = %+v, want %+v", got[1], want)
+	}
+	if want := (types.Rect{X: 962, Y: 0, Width: 958, Height: 1080}); got[3] != want {
+		t.Errorf("right neighbor = %+v, want %+v", got[3], want)
+	}
+	if want := (types.Rect{X: 0, Y: 538, Width: 955, Height: 542}); got[2] != want {
+		t.Errorf("lower neighbor = %+v, want %+v", got[2], want)
+	}
+	// The input is left untouched
+	if placements[0].Bounds.Width != 955 {
+		t.Errorf("input placements were modified")
+	}
+}
+
+func TestSnapToSizeIncrements_LeftNeighbor(t *testing.T) {
+	// Terminal on the right keeps its right edge; the left window grows
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 955, Height: 1072}},
+		{WindowID: 2, Bounds: types.Rect{X: 965, Y: 0, Width: 955, Height: 1072}},
+	}
+	increments := map[uint32]SizeIncrement{2: {Width: 8, Height: 16}}
+
+	got := placementBounds(SnapToSizeIncrements(placements, increments, 10, 10))
+
+	if want := (types.Rect{X: 968, Y: 0, Width: 952, Height: 1072}); got[2] != want {
+		t.Errorf("terminal = %+v, want %+v", got[2], want)
+	}
+	if want := (types.Rect{X: 0, Y: 0, Width: 958, Height: 1072}); got[1] != want {
+		t.Errorf("left neighbor = %+v, want %+v", got[1], want)
+	}
+}
+
+func TestSnapToSizeIncrements_NoNeighbor(t *testing.T) {
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 1915, Height: 1080}},
+	}
+	increments := map[uint32]SizeIncrement{1: {Width: 8}}
+
+	got := placementBounds(SnapToSizeIncrements(placements, increments, 10, 10))
+
+	// Width snaps, height is unconstrained
+	if want := (types.Rect{X: 0, Y: 0, Width: 1912, Height: 1080}); got[1] != want {
+		t.Errorf("terminal = %+v, want %+v", got[1], want)
+	}
+}
+
+func TestSnapToSizeIncrements_PerAxisSpacing(t *testing.T) {
+	// 30px between columns, 4px between rows
+	placements := []types.WindowPlacement{
+		{WindowID: 1, Bounds: types.Rect{X: 0, Y: 0, Width: 945, Height: 538}},
+		{WindowID: 2, Bounds: types.Rect{X: 0, Y: 542, Width: 945, Height: 538}},
+		{WindowID: 3, Bounds: types.Rect{X: 975, Y: 0, Width: 945, Height: 1080}},
+	}
+	increments := map[uint32]SizeIncrement{1: {Width: 8, Height: 16}}
+
+	got := placementBounds(SnapToSizeIncrements(placements, increments, 30, 4))
+
+	// 945 -> 944, 538 -> 528; both neighbors are within their axis' spacing
+	if want := (types.Rect{X: 974, Y: 0, Width: 946, Height: 1080}); got[3] != want {
+		t.Errorf("right neighbor = %+v, want %+v", got[3], want)
+	}
+	if want := (types.Rect{X: 0, Y: 532, Width: 945, Height: 548}); got[2] != want {
+		t.Errorf("lower neighbor = %+v, want %+v", got[2], want)
+	}
+
+	// With the row spacing too small, the window below isn't a neighbor
+	got = placementBounds(SnapToSizeIncrements(placements, increments, 30, 0))
+	if got[2] != placements[1].Bounds {
+		t.Errorf("lower window = %+v, want it left alone", got[2])
+	}
+}
+
+func TestSizeIncrements(t *testing.T) {
+	cfg := &config.Config{
+		AppRules: []config.AppRule{
+			{App: "com.apple.Terminal", SizeIncrement: []float64{8, 16}},
+			{App: "Safari", Float: true},
+		},
+	}
+	windows := []server.WindowInfo{
+		{ID: 1, AppName: "Terminal", BundleID: "com.apple.Terminal"},
+		{ID: 2, AppName: "Safari"},
+		{ID: 3, AppName: "Notes"},
+	}
+
+	increments := SizeIncrements(cfg, windows)
+	if len(increments) != 1 || increments[1] != (SizeIncrement{Width: 8, Height: 16}) {
+		t.Errorf("unexpected increments: %+v", increments)
+	}
+}
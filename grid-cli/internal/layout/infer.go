@@ -0,0 +1,219 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// DefaultInferTolerance is how close (in pixels) window edges must be to
+// share a grid line, on top of twice the cell padding
+const DefaultInferTolerance = 24.0
+
+// InferLayout builds a layout from window frames on a display, for
+// `layout save`. Window edges within tolerance pixels of each other are
+// merged into one grid line, so gaps between tiled windows don't become
+// tracks of their own. Windows that land on the same grid rectangle share a
+// cell. Cells are named cell1, cell2, ... in reading order and written as
+// areas when they don't overlap, or as explicit cells when they do.
+func InferLayout(id string, frames []types.Rect, display types.Rect, tolerance float64) (*config.LayoutConfig, error) {
+	var clipped []types.Rect
+	for _, f := range frames {
+		if r, ok := clipRect(f, display); ok {
+			clipped = append(clipped, r)
+		}
+	}
+	if len(clipped) == 0 {
+		return nil, fmt.Errorf("no windows on the display to save")
+	}
+
+	xs := []float64{display.X, display.X + display.Width}
+	ys := []float64{display.Y, display.Y + display.Height}
+	for _, r := range clipped {
+		xs = append(xs, r.X, r.X+r.Width)
+		ys = append(ys, r.Y, r.Y+r.Height)
+	}
+	xLines := gridLines(xs, display.X, display.X+display.Width, tolerance)
+	yLines := gridLines(ys, display.Y, display.Y+display.Height, tolerance)
+
+	// Snap each window to grid lines; identical rectangles are one cell
+	type span struct{ c0, c1, r0, r1 int }
+	var spans []span
+	seen := make(map[span]bool)
+	for _, r := range clipped {
+		s := span{
+			c0: nearestLine(xLines, r.X), c1: nearestLine(xLines, r.X+r.Width),
+			r0: nearestLine(yLines, r.Y), r1: nearestLine(yLines, r.Y+r.Height),
+		}
+		if s.c0 == s.c1 || s.r0 == s.r1 || seen[s] {
+			continue
+		}
+		seen[s] = true
+		spans = append(spans, s)
+	}
+	if len(spans) == 0 {
+		return nil, fmt.Errorf("windows are too small to form a grid")
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].r0 != spans[j].r0 {
+			return spans[i].r0 < spans[j].r0
+		}
+		return spans[i].c0 < spans[j].c0
+	})
+
+	lc := &config.LayoutConfig{
+		ID:   id,
+		Name: id,
+		Grid: config.GridConfig{
+			Columns: frTracks(xLines),
+			Rows:    frTracks(yLines),
+		},
+	}
+
+	// Fill areas; a slot claimed twice means the cells overlap
+	areas := make([][]string, len(yLines)-1)
+	for row := range areas {
+		areas[row] = make([]string, len(xLines)-1)
+		for col := range areas[row] {
+			areas[row][col] = "."
+		}
+	}
+	overlap := false
+	for i, s := range spans {
+		for row := s.r0; row < s.r1; row++ {
+			for col := s.c0; col < s.c1; col++ {
+				if areas[row][col] != "." {
+					overlap = true
+				}
+				areas[row][col] = fmt.Sprintf("cell%d", i+1)
+			}
+		}
+	}
+
+	if !overlap {
+		lc.Areas = areas
+		return lc, nil
+	}
+	for i, s := range spans {
+		lc.Cells = append(lc.Cells, config.CellConfig{
+			ID:     fmt.Sprintf("cell%d", i+1),
+			Column: fmt.Sprintf("%d/%d", s.c0+1, s.c1+1),
+			Row:    fmt.Sprintf("%d/%d", s.r0+1, s.r1+1),
+		})
+	}
+	return lc, nil
+}
+
+// clipRect returns r clipped to bounds, and false when nothing is left
+func clipRect(r, bounds types.Rect) (types.Rect, bool) {
+	x0 := math.Max(r.X, bounds.X)
+	y0 := math.Max(r.Y, bounds.Y)
+	x1 := math.Min(r.X+r.Width, bounds.X+bounds.Width)
+	y1 := math.Min(r.Y+r.Height, bounds.Y+bounds.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return types.Rect{}, false
+	}
+	return types.Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}, true
+}
+
+// gridLines merges edge positions within tolerance into lines at their
+// average, pinning the outermost lines to start and end.
+func gridLines(edges []float64, start, end, tolerance float64) []float64 {
+	sort.Float64s(edges)
+
+	var lines []float64
+	groupStart, sum, count := edges[0], 0.0, 0
+	flush := func() {
+		lines = append(lines, sum/float64(count))
+	}
+	for _, e := range edges {
+		if count > 0 && e-groupStart > tolerance {
+			flush()
+			groupStart, sum, count = e, 0, 0
+		}
+		sum += e
+		count++
+	}
+	flush()
+
+	lines[0] = start
+	if len(lines) == 1 {
+		return []float64{start, end}
+	}
+	lines[len(lines)-1] = end
+	return lines
+}
+
+// nearestLine returns the index of the line closest to v
+func nearestLine(lines []float64, v float64) int {
+	best := 0
+	for i, line := range lines {
+		if math.Abs(line-v) < math.Abs(lines[best]-v) {
+			best = i
+		}
+	}
+	return best
+}
+
+// frTracks turns the spaces between lines into fr tracks, using the
+// smallest whole fr values within 1% of the real proportions (so thirds
+// become 2fr 1fr), or whole percentages when nothing simpler fits.
+func frTracks(lines []float64) []string {
+	total := lines[len(lines)-1] - lines[0]
+	fractions := make([]float64, len(lines)-1)
+	for i := range fractions {
+		fractions[i] = (lines[i+1] - lines[i]) / total
+	}
+
+	values := wholeFr(fractions)
+	if values == nil {
+		values = make([]int, len(fractions))
+		for i, f := range fractions {
+			values[i] = int(math.Max(1, math.Round(f*100)))
+		}
+	}
+
+	// Reduce by the common divisor (e.g. 50/50 becomes 1fr 1fr)
+	divisor := 0
+	for _, v := range values {
+		divisor = gcd(divisor, v)
+	}
+
+	tracks := make([]string, len(values))
+	for i, v := range values {
+		tracks[i] = fmt.Sprintf("%dfr", v/divisor)
+	}
+	return tracks
+}
+
+// wholeFr returns the smallest whole numbers (up to 24 in total) whose
+// proportions are within 1% of fractions, or nil if there are none
+func wholeFr(fractions []float64) []int {
+	for units := 1; units <= 24; units++ {
+		values := make([]int, len(fractions))
+		fits := true
+		for i, f := range fractions {
+			v := math.Round(f * float64(units))
+			if v < 1 || math.Abs(v/float64(units)-f) > 0.01 {
+				fits = false
+				break
+			}
+			values[i] = int(v)
+		}
+		if fits {
+			return values
+		}
+	}
+	return nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
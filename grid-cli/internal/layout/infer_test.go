@@ -0,0 +1,74 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestInferLayout_TiledWindowsWithGaps(t *testing.T) {
+	display := types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055}
+	// A 2/3 main column and a split right column, 8px apart
+	frames := []types.Rect{
+		{X: 0, Y: 25, Width: 1276, Height: 1055},
+		{X: 1284, Y: 25, Width: 636, Height: 523},
+		{X: 1284, Y: 556, Width: 636, Height: 524},
+		{X: 1284, Y: 556, Width: 636, Height: 524}, // Stacked in the same cell
+	}
+
+	lc, err := InferLayout("saved", frames, display, DefaultInferTolerance+16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"2fr", "1fr"}; !reflect.DeepEqual(lc.Grid.Columns, want) {
+		t.Errorf("columns = %v, want %v", lc.Grid.Columns, want)
+	}
+	if want := []string{"1fr", "1fr"}; !reflect.DeepEqual(lc.Grid.Rows, want) {
+		t.Errorf("rows = %v, want %v", lc.Grid.Rows, want)
+	}
+	want := [][]string{{"cell1", "cell2"}, {"cell1", "cell3"}}
+	if !reflect.DeepEqual(lc.Areas, want) {
+		t.Errorf("areas = %v, want %v", lc.Areas, want)
+	}
+	if _, err := lc.ToLayout(); err != nil {
+		t.Errorf("inferred layout doesn't convert: %v", err)
+	}
+}
+
+func TestInferLayout_UncoveredSlotsAndOverlap(t *testing.T) {
+	display := types.Rect{Width: 1000, Height: 1000}
+
+	// One window in the left half leaves the right half empty
+	lc, err := InferLayout("half", []types.Rect{{Width: 500, Height: 1000}}, display, DefaultInferTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"cell1", "."}}; !reflect.DeepEqual(lc.Areas, want) {
+		t.Errorf("areas = %v, want %v", lc.Areas, want)
+	}
+
+	// A window floating over a full-screen one can't be written as areas
+	lc, err = InferLayout("overlap", []types.Rect{
+		{Width: 1000, Height: 1000},
+		{X: 250, Y: 250, Width: 500, Height: 500},
+	}, display, DefaultInferTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.Areas != nil || len(lc.Cells) != 2 {
+		t.Fatalf("expected explicit cells, got areas %v cells %+v", lc.Areas, lc.Cells)
+	}
+	if c := lc.Cells[1]; c.Column != "2/3" || c.Row != "2/3" {
+		t.Errorf("floating cell = %+v, want column 2/3 row 2/3", c)
+	}
+}
+
+func TestInferLayout_NoWindows(t *testing.T) {
+	display := types.Rect{Width: 1000, Height: 1000}
+	offscreen := []types.Rect{{X: 2000, Y: 0, Width: 500, Height: 500}}
+	if _, err := InferLayout("empty", offscreen, display, DefaultInferTolerance); err == nil {
+		t.Error("expected error with no windows on the display")
+	}
+}
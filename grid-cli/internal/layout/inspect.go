@@ -0,0 +1,98 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// WindowGridInfo describes how the grid sees a window: where it is tracked
+// and where the current layout would place it compared to where it is.
+type WindowGridInfo struct {
+	SpaceID     string          `json:"spaceId"`
+	LayoutID    string          `json:"layoutId"`
+	CellID      string          `json:"cellId"`
+	Index       int             `json:"index"` // Position within the cell
+	SplitRatio  float64         `json:"splitRatio"`
+	StackMode   types.StackMode `json:"stackMode"`
+	TargetFrame types.Rect      `json:"targetFrame"`
+	ActualFrame types.Rect      `json:"actualFrame"`
+	Drift       types.Rect      `json:"drift"`   // Actual minus target, per component
+	InPlace     bool            `json:"inPlace"` // Drift within placement tolerance
+}
+
+// InspectWindow cross-references a window's tracked cell in state with the
+// calculated layout for the snapshot's space.
+func InspectWindow(snap *server.Snapshot, cfg *config.Config, rs *state.RuntimeState, windowID uint32) (*WindowGridInfo, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, fmt.Errorf("no layout applied to space %s", snap.SpaceID)
+	}
+
+	cellID := spaceState.GetWindowCell(windowID)
+	if cellID == "" {
+		return nil, fmt.Errorf("window %d is not tracked on space %s", windowID, snap.SpaceID)
+	}
+	cell := spaceState.Cells[cellID]
+
+	info := &WindowGridInfo{
+		SpaceID:  snap.SpaceID,
+		LayoutID: spaceState.CurrentLayoutID,
+		CellID:   cellID,
+	}
+	for i, wid := range cell.Windows {
+		if wid == windowID {
+			info.Index = i
+			break
+		}
+	}
+	ratios := cell.SplitRatios
+	if len(ratios) != len(cell.Windows) {
+		ratios = equalRatios(len(cell.Windows))
+	}
+	info.SplitRatio = ratios[info.Index]
+
+	layoutDef, err := SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return nil, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	// Same mode hierarchy as ApplyLayout
+	info.StackMode = EffectiveCellMode(cfg, layoutDef, spaceState, cellID, snap.DisplayBounds, false, spaceState.BSP)
+
+	placements := CalculateAllWindowPlacements(
+		calculated,
+		map[string][]uint32{cellID: cell.Windows},
+		map[string]types.StackMode{cellID: info.StackMode},
+		map[string][]float64{cellID: ratios},
+		DefaultStackMode(cfg, snap.DisplayBounds, false),
+		DefaultApplyOptions().Padding,
+	)
+	placements = SnapPlacements(cfg, calculated, placements, snap.Windows, DefaultApplyOptions().Padding)
+	for _, p := range placements {
+		if p.WindowID == windowID {
+			info.TargetFrame = p.Bounds
+			break
+		}
+	}
+
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			info.ActualFrame = w.Frame
+			break
+		}
+	}
+	info.Drift = types.Rect{
+		X:      info.ActualFrame.X - info.TargetFrame.X,
+		Y:      info.ActualFrame.Y - info.TargetFrame.Y,
+		Width:  info.ActualFrame.Width - info.TargetFrame.Width,
+		Height: info.ActualFrame.Height - info.TargetFrame.Height,
+	}
+	info.InPlace = framesMatch(info.ActualFrame, info.TargetFrame, placementTolerance)
+
+	return info, nil
+}
@@ -0,0 +1,74 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestInspectWindow(t *testing.T) {
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "full", Grid: config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"main"}}},
+		},
+		Settings: config.Settings{DefaultStackMode: types.StackVertical},
+	}
+
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 0)
+	space.AssignWindow(10, "main")
+	space.AssignWindow(20, "main")
+
+	// Two windows stacked vertically on a 1000x1000 display: window 20 should
+	// be the bottom half (4px padding), but sits 30px lower and 30px shorter.
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1000, Height: 1000},
+		Windows: []server.WindowInfo{
+			{ID: 10, Frame: types.Rect{X: 0, Y: 0, Width: 1000, Height: 498}},
+			{ID: 20, Frame: types.Rect{X: 0, Y: 532, Width: 1000, Height: 468}},
+		},
+	}
+
+	info, err := InspectWindow(snap, cfg, rs, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.CellID != "main" || info.Index != 1 || info.SplitRatio != 0.5 {
+		t.Errorf("unexpected cell membership: %+v", info)
+	}
+	if info.StackMode != types.StackVertical {
+		t.Errorf("StackMode = %s, want vertical", info.StackMode)
+	}
+	if info.InPlace {
+		t.Error("expected window to be reported out of place")
+	}
+	wantTarget := types.Rect{X: 0, Y: 502, Width: 1000, Height: 498}
+	if info.TargetFrame != wantTarget {
+		t.Errorf("TargetFrame = %+v, want %+v", info.TargetFrame, wantTarget)
+	}
+	wantDrift := types.Rect{X: 0, Y: 30, Width: 0, Height: -30}
+	if info.Drift != wantDrift {
+		t.Errorf("Drift = %+v, want %+v", info.Drift, wantDrift)
+	}
+}
+
+func TestInspectWindow_Untracked(t *testing.T) {
+	cfg := &config.Config{}
+	rs := state.NewRuntimeState()
+	snap := &server.Snapshot{SpaceID: "1"}
+
+	if _, err := InspectWindow(snap, cfg, rs, 10); err == nil {
+		t.Error("expected error with no layout applied")
+	}
+
+	rs.GetSpace("1").SetCurrentLayout("full", 0)
+	if _, err := InspectWindow(snap, cfg, rs, 10); err == nil {
+		t.Error("expected error for untracked window")
+	}
+}
@@ -0,0 +1,150 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+const (
+	// DefaultLaunchTimeout is how long to wait for a launched app's window
+	DefaultLaunchTimeout = 10 * time.Second
+
+	// DefaultLaunchInterval is how often to re-read server state while waiting
+	DefaultLaunchInterval = 250 * time.Millisecond
+)
+
+// LaunchOptions configures launching apps into empty cells.
+// Zero fields fall back to the defaults.
+type LaunchOptions struct {
+	Launch   func(ctx context.Context, app string) error         // Starts an app (default: LaunchApp)
+	Fetch    func(ctx context.Context) (*server.Snapshot, error) // Re-reads server state while waiting
+	Timeout  time.Duration                                       // How long to wait for each window
+	Interval time.Duration                                       // Delay between fetches
+}
+
+// LaunchedWindow is a window launched into an empty cell
+type LaunchedWindow struct {
+	CellID string
+	App    string
+	Window server.WindowInfo
+}
+
+// withDefaults fills unset options, fetching from c
+func (o LaunchOptions) withDefaults(c *client.Client) LaunchOptions {
+	if o.Launch == nil {
+		o.Launch = LaunchApp
+	}
+	if o.Fetch == nil {
+		o.Fetch = func(ctx context.Context) (*server.Snapshot, error) {
+			return server.Fetch(ctx, c)
+		}
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultLaunchTimeout
+	}
+	if o.Interval <= 0 {
+		o.Interval = DefaultLaunchInterval
+	}
+	return o
+}
+
+// LaunchApp opens an app with open(1), by bundle ID if app looks like one
+// (e.g. com.apple.Terminal) and by name otherwise.
+func LaunchApp(ctx context.Context, app string) error {
+	flag := "-a"
+	if strings.Contains(app, ".") && !strings.ContainsAny(app, " /") {
+		flag = "-b"
+	}
+	if out, err := exec.CommandContext(ctx, "open", flag, app).CombinedOutput(); err != nil {
+		return fmt.Errorf("open %s %s: %w: %s", flag, app, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// LaunchEmptyCells launches the configured app for each layout cell with no
+// windows in assignments, waits for a new window of that app to appear, and
+// assigns it to the cell. known holds the window IDs present before
+// launching. A cell whose app fails to launch, or whose window doesn't
+// show up in time, is left empty with a warning; only cancelling ctx stops
+// the remaining launches.
+func LaunchEmptyCells(
+	ctx context.Context,
+	layout *types.Layout,
+	assignments map[string][]uint32,
+	known map[uint32]bool,
+	opts LaunchOptions,
+) ([]LaunchedWindow, error) {
+	seen := make(map[uint32]bool, len(known))
+	for id := range known {
+		seen[id] = true
+	}
+
+	var launched []LaunchedWindow
+	for _, cell := range layout.Cells {
+		if cell.Launch == "" || len(assignments[cell.ID]) > 0 {
+			continue
+		}
+
+		logging.Info().Str("cell", cell.ID).Str("app", cell.Launch).Msg("launching app into empty cell")
+		if err := opts.Launch(ctx, cell.Launch); err != nil {
+			if ctx.Err() != nil {
+				return launched, ctx.Err()
+			}
+			logging.Warn().Str("cell", cell.ID).Str("app", cell.Launch).Err(err).Msg("failed to launch app, leaving cell empty")
+			continue
+		}
+
+		win, err := waitForWindow(ctx, cell.Launch, seen, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return launched, ctx.Err()
+			}
+			logging.Warn().Str("cell", cell.ID).Str("app", cell.Launch).Err(err).Msg("failed waiting for launched window, leaving cell empty")
+			continue
+		}
+		if win == nil {
+			logging.Warn().Str("cell", cell.ID).Str("app", cell.Launch).Msg("no window appeared after launch")
+			continue
+		}
+
+		seen[win.ID] = true
+		assignments[cell.ID] = append(assignments[cell.ID], win.ID)
+		launched = append(launched, LaunchedWindow{CellID: cell.ID, App: cell.Launch, Window: *win})
+	}
+
+	return launched, nil
+}
+
+// waitForWindow polls server state until a tileable window of app that isn't
+// in seen appears. Returns nil if none appears before the timeout.
+func waitForWindow(ctx context.Context, app string, seen map[uint32]bool, opts LaunchOptions) (*server.WindowInfo, error) {
+	deadline := time.Now().Add(opts.Timeout)
+	for {
+		snap, err := opts.Fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch state while waiting for %s: %w", app, err)
+		}
+		for _, w := range snap.Windows {
+			if !seen[w.ID] && w.IsTileable() && (w.BundleID == app || w.AppName == app) {
+				return &w, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.Interval):
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package layout
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// launchMock launches apps by recording them; the launched app's window
+// shows up in fetched snapshots one fetch after its launch.
+type launchMock struct {
+	base     []server.WindowInfo
+	windows  map[string]server.WindowInfo // app -> window it opens
+	launched []string
+	pending  []server.WindowInfo
+	fetches  int
+}
+
+func (m *launchMock) options() LaunchOptions {
+	return LaunchOptions{
+		Launch: func(ctx context.Context, app string) error {
+			m.launched = append(m.launched, app)
+			if w, ok := m.windows[app]; ok {
+				m.pending = append(m.pending, w)
+			}
+			return nil
+		},
+		Fetch: func(ctx context.Context) (*server.Snapshot, error) {
+			m.fetches++
+			snap := &server.Snapshot{Windows: append([]server.WindowInfo(nil), m.base...)}
+			// The first fetch after a launch doesn't see the window yet
+			if m.fetches%2 == 0 {
+				m.base = append(m.base, m.pending...)
+				m.pending = nil
+			}
+			return snap, nil
+		},
+		Timeout:  time.Second,
+		Interval: time.Millisecond,
+	}
+}
+
+func launchLayout() *types.Layout {
+	return &types.Layout{
+		ID: "dash",
+		Cells: []types.Cell{
+			{ID: "a", Launch: "com.apple.Terminal"},
+			{ID: "b", Launch: "Notes"},
+			{ID: "c"},
+		},
+	}
+}
+
+func TestLaunchEmptyCells_AssignsNewWindow(t *testing.T) {
+	existing := server.WindowInfo{ID: 20, AppName: "Notes"}
+	m := &launchMock{
+		base: []server.WindowInfo{existing},
+		windows: map[string]server.WindowInfo{
+			"com.apple.Terminal": {ID: 21, AppName: "Terminal", BundleID: "com.apple.Terminal"},
+		},
+	}
+	assignments := map[string][]uint32{"b": {20}}
+
+	launched, err := LaunchEmptyCells(context.Background(), launchLayout(), assignments, map[uint32]bool{20: true}, m.options())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the empty cell with a launch app is filled
+	if !reflect.DeepEqual(m.launched, []string{"com.apple.Terminal"}) {
+		t.Errorf("launched apps = %v, want [com.apple.Terminal]", m.launched)
+	}
+	if len(launched) != 1 || launched[0].CellID != "a" || launched[0].Window.ID != 21 {
+		t.Fatalf("unexpected launched windows: %+v", launched)
+	}
+	if !reflect.DeepEqual(assignments["a"], []uint32{21}) {
+		t.Errorf("cell a = %v, want [21]", assignments["a"])
+	}
+	if !reflect.DeepEqual(assignments["b"], []uint32{20}) {
+		t.Errorf("cell b = %v, want [20]", assignments["b"])
+	}
+}
+
+func TestLaunchEmptyCells_IgnoresExistingWindows(t *testing.T) {
+	// An already-open Notes window isn't taken as the launched one
+	m := &launchMock{base: []server.WindowInfo{{ID: 20, AppName: "Notes"}}}
+	opts := m.options()
+	opts.Timeout = 10 * time.Millisecond
+	layout := &types.Layout{Cells: []types.Cell{{ID: "b", Launch: "Notes"}}}
+	assignments := map[string][]uint32{}
+
+	launched, err := LaunchEmptyCells(context.Background(), layout, assignments, map[uint32]bool{20: true}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(launched) != 0 || len(assignments["b"]) != 0 {
+		t.Errorf("expected cell b to stay empty, got launched=%+v assignments=%v", launched, assignments)
+	}
+}
+
+func TestLaunchEmptyCells_SkipsFailedCells(t *testing.T) {
+	m := &launchMock{
+		windows: map[string]server.WindowInfo{
+			"Notes": {ID: 22, AppName: "Notes"},
+		},
+	}
+	opts := m.options()
+	launch := opts.Launch
+	opts.Launch = func(ctx context.Context, app string) error {
+		if app == "com.apple.Terminal" {
+			return errors.New("unable to find application")
+		}
+		return launch(ctx, app)
+	}
+	assignments := map[string][]uint32{}
+
+	launched, err := LaunchEmptyCells(context.Background(), launchLayout(), assignments, nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(launched) != 1 || launched[0].CellID != "b" || len(assignments["a"]) != 0 {
+		t.Errorf("expected only cell b filled, got launched=%+v assignments=%v", launched, assignments)
+	}
+
+	// A failed wait leaves its cell empty too
+	fetch := opts.Fetch
+	fetches := 0
+	opts.Fetch = func(ctx context.Context) (*server.Snapshot, error) {
+		if fetches++; fetches == 1 {
+			return nil, errors.New("server went away")
+		}
+		return fetch(ctx)
+	}
+	opts.Launch = launch
+	m.windows["com.apple.Terminal"] = server.WindowInfo{ID: 23, AppName: "Terminal", BundleID: "com.apple.Terminal"}
+	m.windows["Notes"] = server.WindowInfo{ID: 24, AppName: "Notes"}
+	assignments = map[string][]uint32{}
+
+	_, err = LaunchEmptyCells(context.Background(), launchLayout(), assignments, map[uint32]bool{22: true}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assignments["a"]) != 0 || !reflect.DeepEqual(assignments["b"], []uint32{24}) {
+		t.Errorf("expected a empty and b filled after a failed wait, got %v", assignments)
+	}
+}
+
+func TestApplyLayout_LaunchEmpty(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	left := types.Rect{X: 0, Y: 0, Width: 960, Height: 1080}
+	right := types.Rect{X: 960, Y: 0, Width: 960, Height: 1080}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{{
+			ID:   "dash",
+			Grid: config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+			Cells: []config.CellConfig{
+				{ID: "term", Column: "1/2", Row: "1/2", Launch: "com.apple.Terminal"},
+				{ID: "web", Column: "2/3", Row: "1/2"},
+			},
+		}},
+	}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		Windows:       []server.WindowInfo{{ID: 20, AppName: "Safari", Frame: right}},
+		WindowIDs:     map[uint32]bool{20: true},
+	}
+	// The launched window opens where its cell is, so no server calls are needed
+	m := &launchMock{
+		base: snap.Windows,
+		windows: map[string]server.WindowInfo{
+			"com.apple.Terminal": {ID: 21, AppName: "Terminal", BundleID: "com.apple.Terminal", Frame: left},
+		},
+	}
+	rs := state.NewRuntimeState()
+
+	opts := DefaultApplyOptions()
+	opts.Gap = 0
+	opts.Padding = 0
+	opts.LaunchEmpty = true
+	opts.Launch = m.options()
+	result, err := ApplyLayoutWithResult(context.Background(), nil, snap, cfg, rs, "dash", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Launched) != 1 || result.Launched[0].Window.ID != 21 {
+		t.Errorf("unexpected launched windows: %+v", result.Launched)
+	}
+	if got := rs.GetCellWindows("1", "term"); !reflect.DeepEqual(got, []uint32{21}) {
+		t.Errorf("term cell = %v, want [21]", got)
+	}
+	if got := rs.GetCellWindows("1", "web"); !reflect.DeepEqual(got, []uint32{20}) {
+		t.Errorf("web cell = %v, want [20]", got)
+	}
+	if result.Applied != 0 || result.Skipped != 2 {
+		t.Errorf("expected both windows already in place, got %+v", result)
+	}
+}
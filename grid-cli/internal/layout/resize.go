@@ -6,11 +6,96 @@ import (
 
 	"github.com/yourusername/grid-cli/internal/client"
 	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/logging"
 	"github.com/yourusername/grid-cli/internal/server"
 	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
 )
 
+// CellStackMode returns the stack mode set for a cell in the space's current
+// layout, using the same precedence as ApplyLayout: state override, then
+// layout cellModes, then the cell definition, then the space default (state
+// set-stack-mode), then the settings default.
+func CellStackMode(cfg *config.Config, spaceState *state.SpaceState, cellID string) types.StackMode {
+	layoutDef, _ := SpaceLayout(cfg, spaceState)
+	if mode := configuredCellMode(layoutDef, spaceState, cellID); mode != "" {
+		return mode
+	}
+	return cfg.Settings.DefaultStackMode
+}
+
+// EffectiveCellMode returns the stack mode a cell of layoutDef is laid out
+// with: its mode from state, the layout or the space default, or else the
+// default from DefaultStackMode. In a bsp space every cell but tabbed ones
+// tiles as a binary space partition.
+func EffectiveCellMode(
+	cfg *config.Config,
+	layoutDef *types.Layout,
+	spaceState *state.SpaceState,
+	cellID string,
+	display types.Rect,
+	orientationAware bool,
+	bsp bool,
+) types.StackMode {
+	mode := configuredCellMode(layoutDef, spaceState, cellID)
+	if mode == "" {
+		mode = DefaultStackMode(cfg, display, orientationAware)
+	}
+	if bsp && mode != types.StackTabs {
+		return stackBSP
+	}
+	return mode
+}
+
+// DefaultStackMode returns settings.defaultStackMode, adjusted for the
+// display orientation when orientationAware or
+// settings.orientationAwareStacks is on.
+func DefaultStackMode(cfg *config.Config, display types.Rect, orientationAware bool) types.StackMode {
+	if orientationAware || cfg.Settings.OrientationAwareStacks {
+		return OrientedStackMode(cfg.Settings.DefaultStackMode, display)
+	}
+	return cfg.Settings.DefaultStackMode
+}
+
+// configuredCellMode returns the mode set for a cell in state, in layoutDef
+// (which may be nil) or as the space default, or "" if there is none
+func configuredCellMode(layoutDef *types.Layout, spaceState *state.SpaceState, cellID string) types.StackMode {
+	if spaceState != nil {
+		if cellState, ok := spaceState.Cells[cellID]; ok && cellState.StackMode != "" {
+			return cellState.StackMode
+		}
+	}
+
+	if layoutDef != nil {
+		if mode, ok := layoutDef.CellModes[cellID]; ok && mode != "" {
+			return mode
+		}
+		for _, cell := range layoutDef.Cells {
+			if cell.ID == cellID && cell.StackMode != "" {
+				return cell.StackMode
+			}
+		}
+	}
+
+	if spaceState != nil {
+		return spaceState.DefaultStackMode
+	}
+	return ""
+}
+
+// ResizeCyclesTabs reports whether resizing should switch tabs instead,
+// i.e. settings.resizeCyclesTabs is on and the focused cell is tabbed.
+func ResizeCyclesTabs(cfg *config.Config, spaceState *state.SpaceState) bool {
+	if !cfg.Settings.ResizeCyclesTabs || spaceState == nil || spaceState.FocusedCell == "" {
+		return false
+	}
+	return CellStackMode(cfg, spaceState, spaceState.FocusedCell) == types.StackTabs
+}
+
 // AdjustFocusedSplit grows/shrinks the focused window's split ratio.
+// With snapPoints, the resulting ratio snaps to a point within SnapTolerance.
+// Returns false (and skips the reapply) when the split is already clamped
+// at MinimumRatio and the adjustment had no effect.
 func AdjustFocusedSplit(
 	ctx context.Context,
 	c *client.Client,
@@ -18,20 +103,21 @@ func AdjustFocusedSplit(
 	cfg *config.Config,
 	rs *state.RuntimeState,
 	delta float64,
-) error {
+	snapPoints []float64,
+) (bool, error) {
 	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
 	if spaceState == nil {
-		return fmt.Errorf("no layout applied")
+		return false, fmt.Errorf("no layout applied")
 	}
 
 	cellID := spaceState.FocusedCell
 	if cellID == "" {
-		return fmt.Errorf("no focused cell")
+		return false, fmt.Errorf("no focused cell")
 	}
 
 	cell := spaceState.Cells[cellID]
 	if cell == nil || len(cell.Windows) < 2 {
-		return fmt.Errorf("need at least 2 windows to resize")
+		return false, fmt.Errorf("need at least 2 windows to resize")
 	}
 
 	// Get focused window index in cell
@@ -52,23 +138,38 @@ func AdjustFocusedSplit(
 		boundaryIdx = len(ratios) - 2
 	}
 
-	newRatios, err := AdjustSplitRatio(ratios, boundaryIdx, delta, MinimumRatio)
+	newRatios, changed, err := AdjustSplitRatio(ratios, boundaryIdx, delta, MinimumRatio)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if changed && len(snapPoints) > 0 {
+		// Don't snap back to where we started, or a small step could never leave a snap point
+		if snapped := SnapSplitRatio(newRatios, boundaryIdx, snapPoints, SnapTolerance); ratiosChanged(ratios, snapped) {
+			newRatios = snapped
+		}
+	}
+	if !changed {
+		logging.Info().
+			Str("cell", cellID).
+			Int("boundary", boundaryIdx).
+			Float64("delta", delta).
+			Msg("resize: split already at minimum, no change")
+		return false, nil
 	}
 
 	// Update state
 	mutableCell := rs.GetSpace(snap.SpaceID).GetCell(cellID)
 	mutableCell.SplitRatios = newRatios
+	mutableCell.RatiosUserSet = true
 	rs.MarkUpdated()
-	if err := rs.Save(); err != nil {
-		return fmt.Errorf("failed to save state: %w", err)
-	}
 
-	// Reapply layout to update window positions
+	// Reapply layout to update window positions; it saves the new ratios
 	opts := DefaultApplyOptions()
 	opts.Gap = float64(cfg.Settings.CellPadding)
-	return ReapplyLayout(ctx, c, snap, cfg, rs, opts)
+	if err := ReapplyLayout(ctx, c, snap, cfg, rs, opts); err != nil {
+		return true, err
+	}
+	return true, nil
 }
 
 // ResetFocusedSplits resets the focused cell's splits to equal.
@@ -97,17 +198,16 @@ func ResetFocusedSplits(
 	// Reset to equal
 	mutableCell := rs.GetSpace(snap.SpaceID).GetCell(cellID)
 	mutableCell.SplitRatios = InitializeSplitRatios(len(cell.Windows))
+	mutableCell.RatiosUserSet = false
 	rs.MarkUpdated()
-	if err := rs.Save(); err != nil {
-		return fmt.Errorf("failed to save state: %w", err)
-	}
 
 	opts := DefaultApplyOptions()
 	opts.Gap = float64(cfg.Settings.CellPadding)
 	return ReapplyLayout(ctx, c, snap, cfg, rs, opts)
 }
 
-// ResetAllSplits resets all cells' splits to equal.
+// ResetAllSplits resets all cells' splits to equal and drops the
+// space's track overrides and cell spans.
 func ResetAllSplits(
 	ctx context.Context,
 	c *client.Client,
@@ -124,13 +224,114 @@ func ResetAllSplits(
 	for cellID, cell := range spaceState.Cells {
 		mutableCell := mutableSpace.GetCell(cellID)
 		mutableCell.SplitRatios = InitializeSplitRatios(len(cell.Windows))
+		mutableCell.RatiosUserSet = false
 	}
+	mutableSpace.Tracks = nil
+	mutableSpace.Spans = nil
 	rs.MarkUpdated()
-	if err := rs.Save(); err != nil {
-		return fmt.Errorf("failed to save state: %w", err)
-	}
 
 	opts := DefaultApplyOptions()
 	opts.Gap = float64(cfg.Settings.CellPadding)
 	return ReapplyLayout(ctx, c, snap, cfg, rs, opts)
 }
+
+// DragRatioDelta converts a pointer drag into the split ratio change for a
+// cell stacking n windows in mode. Only the drag along the stack axis
+// counts: dy for vertical stacks, dx for horizontal ones. The delta is
+// relative to the space the windows share, i.e. the cell size minus the
+// padding between them, so the boundary follows the pointer.
+func DragRatioDelta(cellBounds types.Rect, mode types.StackMode, n int, padding float64, from, to types.Point) (float64, error) {
+	if n < 2 {
+		return 0, fmt.Errorf("need at least 2 windows to resize")
+	}
+
+	var drag, size float64
+	switch mode {
+	case types.StackVertical:
+		drag, size = to.Y-from.Y, cellBounds.Height
+	case types.StackHorizontal:
+		drag, size = to.X-from.X, cellBounds.Width
+	default:
+		return 0, fmt.Errorf("cannot drag splits in %s mode", mode)
+	}
+
+	available := size - padding*float64(n-1)
+	if available <= 0 {
+		return 0, fmt.Errorf("cell too small to resize")
+	}
+	return drag / available, nil
+}
+
+// DragSplitBoundary moves the split boundary after window index boundary in
+// a cell by the drag from one screen point to another. Both windows at the
+// boundary are clamped at MinimumRatio. Returns false (and skips the
+// reapply) when the drag had no effect.
+func DragSplitBoundary(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	cellID string,
+	boundary int,
+	from, to types.Point,
+) (bool, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return false, fmt.Errorf("no layout applied")
+	}
+
+	cell := spaceState.Cells[cellID]
+	if cell == nil || len(cell.Windows) < 2 {
+		return false, fmt.Errorf("need at least 2 windows in cell %s to resize", cellID)
+	}
+	if boundary < 0 || boundary >= len(cell.Windows)-1 {
+		return false, fmt.Errorf("invalid boundary %d for cell %s (0-%d)", boundary, cellID, len(cell.Windows)-2)
+	}
+
+	layoutDef, err := SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return false, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+	cellBounds, ok := calculated.CellBounds[cellID]
+	if !ok {
+		return false, fmt.Errorf("cell %s not in layout %s", cellID, layoutDef.ID)
+	}
+
+	mode := EffectiveCellMode(cfg, layoutDef, spaceState, cellID, snap.DisplayBounds, false, false)
+
+	opts := DefaultApplyOptions()
+	delta, err := DragRatioDelta(cellBounds, mode, len(cell.Windows), opts.Padding, from, to)
+	if err != nil {
+		return false, err
+	}
+
+	ratios := cell.SplitRatios
+	if len(ratios) != len(cell.Windows) {
+		ratios = InitializeSplitRatios(len(cell.Windows))
+	}
+	newRatios, changed, err := AdjustSplitRatio(ratios, boundary, delta, MinimumRatio)
+	if err != nil {
+		return false, err
+	}
+	if !changed {
+		logging.Info().
+			Str("cell", cellID).
+			Int("boundary", boundary).
+			Float64("delta", delta).
+			Msg("resize drag: no change")
+		return false, nil
+	}
+
+	mutableCell := rs.GetSpace(snap.SpaceID).GetCell(cellID)
+	mutableCell.SplitRatios = newRatios
+	mutableCell.RatiosUserSet = true
+	rs.MarkUpdated()
+
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	if err := ReapplyLayout(ctx, c, snap, cfg, rs, opts); err != nil {
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,199 @@
+package layout
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func tabsConfig(resizeCyclesTabs bool) *config.Config {
+	return &config.Config{
+		Settings: config.Settings{
+			DefaultStackMode: types.StackVertical,
+			ResizeCyclesTabs: resizeCyclesTabs,
+		},
+		Layouts: []config.LayoutConfig{
+			{
+				ID:   "main-side",
+				Grid: config.GridConfig{Columns: []string{"2fr", "1fr"}, Rows: []string{"1fr"}},
+				Cells: []config.CellConfig{
+					{ID: "main", Column: "1/2", Row: "1/2"},
+					{ID: "side", Column: "2/3", Row: "1/2", StackMode: types.StackTabs},
+				},
+			},
+		},
+	}
+}
+
+func tabsSpace(focusedCell string) *state.SpaceState {
+	space := state.NewSpaceState("1")
+	space.SetCurrentLayout("main-side", 0)
+	space.AssignWindow(1, "main")
+	space.AssignWindow(2, "side")
+	space.AssignWindow(3, "side")
+	space.SetFocus(focusedCell, 0)
+	return space
+}
+
+func TestCellStackMode(t *testing.T) {
+	cfg := tabsConfig(true)
+	space := tabsSpace("main")
+
+	if mode := CellStackMode(cfg, space, "main"); mode != types.StackVertical {
+		t.Errorf("main = %q, want settings default vertical", mode)
+	}
+	if mode := CellStackMode(cfg, space, "side"); mode != types.StackTabs {
+		t.Errorf("side = %q, want tabs from cell definition", mode)
+	}
+
+	// The space default fills in below the layout's modes
+	space.DefaultStackMode = types.StackHorizontal
+	if mode := CellStackMode(cfg, space, "main"); mode != types.StackHorizontal {
+		t.Errorf("main = %q, want horizontal from the space default", mode)
+	}
+	if mode := CellStackMode(cfg, space, "side"); mode != types.StackTabs {
+		t.Errorf("side = %q, want tabs from cell definition over the space default", mode)
+	}
+
+	// State override wins
+	space.Cells["side"].StackMode = types.StackHorizontal
+	if mode := CellStackMode(cfg, space, "side"); mode != types.StackHorizontal {
+		t.Errorf("side = %q, want horizontal from state", mode)
+	}
+}
+
+func TestEffectiveCellMode(t *testing.T) {
+	cfg := tabsConfig(true)
+	cfg.Settings.OrientationAwareStacks = true
+	space := tabsSpace("main")
+	layoutDef, err := SpaceLayout(cfg, space)
+	if err != nil {
+		t.Fatal(err)
+	}
+	portrait := types.Rect{Width: 1080, Height: 1920}
+
+	// Only the settings default is oriented
+	if mode := EffectiveCellMode(cfg, layoutDef, space, "main", portrait, false, false); mode != types.StackHorizontal {
+		t.Errorf("main = %q, want oriented default horizontal", mode)
+	}
+	if mode := EffectiveCellMode(cfg, layoutDef, space, "side", portrait, false, false); mode != types.StackTabs {
+		t.Errorf("side = %q, want tabs from cell definition", mode)
+	}
+
+	// A bsp space tiles every cell but tabbed ones as a bsp
+	if mode := EffectiveCellMode(cfg, layoutDef, space, "main", portrait, false, true); mode != stackBSP {
+		t.Errorf("main = %q, want bsp", mode)
+	}
+	if mode := EffectiveCellMode(cfg, layoutDef, space, "side", portrait, false, true); mode != types.StackTabs {
+		t.Errorf("side = %q, want tabs kept in a bsp space", mode)
+	}
+}
+
+func TestResizeCyclesTabs(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		focusedCell string
+		want        bool
+	}{
+		{"tabbed cell delegates to tab cycling", true, "side", true},
+		{"stacked cell resizes", true, "main", false},
+		{"setting off", false, "side", false},
+		{"no focused cell", true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ResizeCyclesTabs(tabsConfig(tt.enabled), tabsSpace(tt.focusedCell))
+			if got != tt.want {
+				t.Errorf("ResizeCyclesTabs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDragRatioDelta(t *testing.T) {
+	cell := types.Rect{X: 0, Y: 0, Width: 800, Height: 1004}
+	tests := []struct {
+		name     string
+		mode     types.StackMode
+		n        int
+		from, to types.Point
+		want     float64
+	}{
+		// 1004px minus one 4px gap leaves 1000px to share
+		{"vertical drag down", types.StackVertical, 2, types.Point{X: 10, Y: 500}, types.Point{X: 300, Y: 600}, 0.1},
+		{"vertical drag up", types.StackVertical, 2, types.Point{Y: 500}, types.Point{Y: 250}, -0.25},
+		// 800px minus two 4px gaps leaves 792px to share
+		{"horizontal ignores dy", types.StackHorizontal, 3, types.Point{X: 100, Y: 0}, types.Point{X: 298, Y: 400}, 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DragRatioDelta(cell, tt.mode, tt.n, 4, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("DragRatioDelta = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDragRatioDelta_Errors(t *testing.T) {
+	cell := types.Rect{Width: 800, Height: 600}
+	if _, err := DragRatioDelta(cell, types.StackTabs, 2, 4, types.Point{}, types.Point{Y: 50}); err == nil {
+		t.Error("expected an error for a tabbed cell")
+	}
+	if _, err := DragRatioDelta(cell, types.StackVertical, 1, 4, types.Point{}, types.Point{Y: 50}); err == nil {
+		t.Error("expected an error for a single window")
+	}
+}
+
+func TestDragRatioDelta_ClampsToMinimum(t *testing.T) {
+	// Dragging 900px down a 1000px cell would leave the lower window at
+	// -0.4, so both ends stop at MinimumRatio
+	delta, err := DragRatioDelta(types.Rect{Width: 800, Height: 1000}, types.StackVertical, 2, 0, types.Point{Y: 500}, types.Point{Y: 1400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratios, changed, err := AdjustSplitRatio([]float64{0.5, 0.5}, 0, delta, MinimumRatio)
+	if err != nil || !changed {
+		t.Fatalf("AdjustSplitRatio changed=%v err=%v", changed, err)
+	}
+	if math.Abs(ratios[0]-0.9) > 0.0001 || math.Abs(ratios[1]-MinimumRatio) > 0.0001 {
+		t.Errorf("ratios = %v, want [0.9 0.1]", ratios)
+	}
+}
+
+func TestResizeSplits_NotSavedWhenReapplyFails(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("gone", 0) // no longer in the config, so the reapply fails
+	space.AssignWindow(1, "a")
+	space.AssignWindow(2, "a")
+	space.SetFocus("a", 0)
+	snap := &server.Snapshot{SpaceID: "1", DisplayBounds: types.Rect{Width: 1000, Height: 1000}}
+	cfg := fullLayoutConfig()
+
+	if _, err := AdjustFocusedSplit(context.Background(), nil, snap, cfg, rs, 0.1, nil); err == nil {
+		t.Fatal("expected the reapply to fail")
+	}
+	if err := ResetFocusedSplits(context.Background(), nil, snap, cfg, rs); err == nil {
+		t.Fatal("expected the reapply to fail")
+	}
+	if err := ResetAllSplits(context.Background(), nil, snap, cfg, rs); err == nil {
+		t.Fatal("expected the reapply to fail")
+	}
+	if _, err := os.Stat(state.GetStatePath()); !os.IsNotExist(err) {
+		t.Errorf("expected no state saved, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package layout
 
 import (
 	"fmt"
+	"math"
 )
 
 const (
@@ -10,8 +11,17 @@ const (
 
 	// DefaultResizeAmount is the default resize step
 	DefaultResizeAmount = 0.1 // 10%
+
+	// ratioEpsilon is the tolerance used when comparing ratios for changes
+	ratioEpsilon = 1e-9
+
+	// SnapTolerance is how close a ratio must be to a snap point to snap
+	SnapTolerance = 0.03
 )
 
+// DefaultSnapPoints are the ratios resize snaps to when settings.resizeSnapPoints is empty
+var DefaultSnapPoints = []float64{1.0 / 3, 0.5, 2.0 / 3}
+
 // InitializeSplitRatios creates equal ratios for N windows.
 // This is exported for external use; internally windows.go uses equalRatios.
 func InitializeSplitRatios(windowCount int) []float64 {
@@ -32,14 +42,16 @@ func NormalizeSplitRatios(ratios []float64) []float64 {
 //   - delta: Change in ratio (positive = grow, negative = shrink)
 //   - minRatio: Minimum allowed ratio per window
 //
-// Returns: New ratios array and any error
-func AdjustSplitRatio(ratios []float64, index int, delta float64, minRatio float64) ([]float64, error) {
+// Returns: New ratios array, whether any ratio changed, and any error.
+// When both windows are already clamped at minRatio the ratios are returned
+// unchanged and changed is false.
+func AdjustSplitRatio(ratios []float64, index int, delta float64, minRatio float64) ([]float64, bool, error) {
 	if len(ratios) < 2 {
-		return ratios, fmt.Errorf("need at least 2 windows to adjust splits")
+		return ratios, false, fmt.Errorf("need at least 2 windows to adjust splits")
 	}
 
 	if index < 0 || index >= len(ratios)-1 {
-		return ratios, fmt.Errorf("invalid index for split adjustment: %d", index)
+		return ratios, false, fmt.Errorf("invalid index for split adjustment: %d", index)
 	}
 
 	newRatios := make([]float64, len(ratios))
@@ -65,15 +77,88 @@ func AdjustSplitRatio(ratios []float64, index int, delta float64, minRatio float
 	newRatios[index+1] = newSecond
 
 	// Normalize to ensure sum is exactly 1.0
-	return NormalizeRatios(newRatios), nil
+	newRatios = NormalizeRatios(newRatios)
+	return newRatios, ratiosChanged(ratios, newRatios), nil
 }
 
 // AdjustSplitRatioAtBoundary adjusts the split at a specific boundary.
 // boundaryIndex is the index between windows (0 = between window 0 and 1)
-func AdjustSplitRatioAtBoundary(ratios []float64, boundaryIndex int, delta float64) ([]float64, error) {
+func AdjustSplitRatioAtBoundary(ratios []float64, boundaryIndex int, delta float64) ([]float64, bool, error) {
 	return AdjustSplitRatio(ratios, boundaryIndex, delta, MinimumRatio)
 }
 
+// SnapSplitRatio snaps the ratio at index to the nearest snap point within
+// tolerance, taking the difference from (or giving it to) the window at
+// index+1 so the pair keeps its combined share. The snap is skipped if it
+// would push either window below MinimumRatio.
+func SnapSplitRatio(ratios []float64, index int, points []float64, tolerance float64) []float64 {
+	if index < 0 || index >= len(ratios)-1 {
+		return ratios
+	}
+
+	current := ratios[index]
+	best, bestDist := current, tolerance
+	for _, p := range points {
+		if dist := math.Abs(p - current); dist <= bestDist {
+			best, bestDist = p, dist
+		}
+	}
+
+	pair := ratios[index] + ratios[index+1]
+	if best == current || best < MinimumRatio || pair-best < MinimumRatio {
+		return ratios
+	}
+
+	snapped := make([]float64, len(ratios))
+	copy(snapped, ratios)
+	snapped[index] = best
+	snapped[index+1] = pair - best
+	return snapped
+}
+
+// InsertRatioWithShare returns the ratios for a cell after inserting a window
+// at index with the given share of the cell. The existing windows keep their
+// proportions to each other and split the rest. share is clamped so every
+// window can keep at least minRatio.
+func InsertRatioWithShare(ratios []float64, index int, share float64, minRatio float64) []float64 {
+	if len(ratios) == 0 {
+		return []float64{1.0}
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index > len(ratios) {
+		index = len(ratios)
+	}
+
+	maxShare := 1.0 - minRatio*float64(len(ratios))
+	share = math.Max(minRatio, math.Min(share, maxShare))
+
+	existing := NormalizeRatios(ratios)
+	result := make([]float64, 0, len(ratios)+1)
+	for _, r := range existing[:index] {
+		result = append(result, r*(1-share))
+	}
+	result = append(result, share)
+	for _, r := range existing[index:] {
+		result = append(result, r*(1-share))
+	}
+	return result
+}
+
+// ratiosChanged reports whether any ratio differs beyond ratioEpsilon.
+func ratiosChanged(before, after []float64) bool {
+	if len(before) != len(after) {
+		return true
+	}
+	for i := range before {
+		if math.Abs(before[i]-after[i]) > ratioEpsilon {
+			return true
+		}
+	}
+	return false
+}
+
 // RecalculateSplitsAfterRemoval adjusts ratios when a window is removed.
 // The removed window's ratio is distributed to remaining windows.
 func RecalculateSplitsAfterRemoval(ratios []float64, removedIndex int) []float64 {
@@ -85,7 +85,7 @@ func TestAdjustSplitRatio(t *testing.T) {
 	// Basic grow test
 	t.Run("BasicGrow", func(t *testing.T) {
 		ratios := []float64{0.5, 0.5}
-		newRatios, err := AdjustSplitRatio(ratios, 0, 0.1, 0.1)
+		newRatios, _, err := AdjustSplitRatio(ratios, 0, 0.1, 0.1)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -98,7 +98,7 @@ func TestAdjustSplitRatio(t *testing.T) {
 	// Basic shrink test
 	t.Run("BasicShrink", func(t *testing.T) {
 		ratios := []float64{0.5, 0.5}
-		newRatios, err := AdjustSplitRatio(ratios, 0, -0.1, 0.1)
+		newRatios, _, err := AdjustSplitRatio(ratios, 0, -0.1, 0.1)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -111,7 +111,7 @@ func TestAdjustSplitRatio(t *testing.T) {
 	// Three windows
 	t.Run("ThreeWindows", func(t *testing.T) {
 		ratios := []float64{0.33, 0.34, 0.33}
-		newRatios, err := AdjustSplitRatio(ratios, 1, 0.1, 0.1) // Grow middle
+		newRatios, _, err := AdjustSplitRatio(ratios, 1, 0.1, 0.1) // Grow middle
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -127,14 +127,14 @@ func TestAdjustSplitRatio(t *testing.T) {
 
 	// Error cases
 	t.Run("TooFewWindows", func(t *testing.T) {
-		_, err := AdjustSplitRatio([]float64{1.0}, 0, 0.1, 0.1)
+		_, _, err := AdjustSplitRatio([]float64{1.0}, 0, 0.1, 0.1)
 		if err == nil {
 			t.Error("expected error for single window")
 		}
 	})
 
 	t.Run("InvalidIndex", func(t *testing.T) {
-		_, err := AdjustSplitRatio([]float64{0.5, 0.5}, 1, 0.1, 0.1) // index 1 is last window
+		_, _, err := AdjustSplitRatio([]float64{0.5, 0.5}, 1, 0.1, 0.1) // index 1 is last window
 		if err == nil {
 			t.Error("expected error for invalid index")
 		}
@@ -144,7 +144,7 @@ func TestAdjustSplitRatio(t *testing.T) {
 func TestAdjustSplitRatio_MinimumEnforced(t *testing.T) {
 	// Try to shrink first window beyond minimum
 	ratios := []float64{0.15, 0.85}
-	newRatios, err := AdjustSplitRatio(ratios, 0, -0.1, 0.1)
+	newRatios, _, err := AdjustSplitRatio(ratios, 0, -0.1, 0.1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -164,7 +164,7 @@ func TestAdjustSplitRatio_MinimumEnforced(t *testing.T) {
 func TestAdjustSplitRatio_MinimumEnforced_SecondWindow(t *testing.T) {
 	// Try to shrink second window beyond minimum
 	ratios := []float64{0.85, 0.15}
-	newRatios, err := AdjustSplitRatio(ratios, 0, 0.1, 0.1) // Grow first, shrinks second
+	newRatios, _, err := AdjustSplitRatio(ratios, 0, 0.1, 0.1) // Grow first, shrinks second
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -181,6 +181,115 @@ func TestAdjustSplitRatio_MinimumEnforced_SecondWindow(t *testing.T) {
 	}
 }
 
+func TestAdjustSplitRatio_ReportsChange(t *testing.T) {
+	t.Run("Changed", func(t *testing.T) {
+		_, changed, err := AdjustSplitRatio([]float64{0.5, 0.5}, 0, 0.1, 0.1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !changed {
+			t.Error("expected changed=true for a normal grow")
+		}
+	})
+
+	t.Run("ShrinkAtMinimum", func(t *testing.T) {
+		ratios := []float64{0.1, 0.9}
+		newRatios, changed, err := AdjustSplitRatio(ratios, 0, -0.1, 0.1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changed {
+			t.Errorf("expected changed=false at minimum, got %v", newRatios)
+		}
+		if math.Abs(newRatios[0]-0.1) > 0.0001 || math.Abs(newRatios[1]-0.9) > 0.0001 {
+			t.Errorf("ratios should be unchanged, got %v", newRatios)
+		}
+	})
+
+	t.Run("GrowWhenNeighborAtMinimum", func(t *testing.T) {
+		_, changed, err := AdjustSplitRatio([]float64{0.9, 0.1}, 0, 0.1, 0.1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changed {
+			t.Error("expected changed=false when neighbor is at minimum")
+		}
+	})
+
+	t.Run("PartialClampStillChanges", func(t *testing.T) {
+		_, changed, err := AdjustSplitRatio([]float64{0.15, 0.85}, 0, -0.1, 0.1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !changed {
+			t.Error("expected changed=true when clamping still moves the split")
+		}
+	})
+}
+
+func TestSnapSplitRatio(t *testing.T) {
+	// Shrinking 0.6 by 0.11 lands near 0.5
+	adjusted, _, err := AdjustSplitRatio([]float64{0.6, 0.4}, 0, -0.11, MinimumRatio)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		snapped := SnapSplitRatio(adjusted, 0, DefaultSnapPoints, SnapTolerance)
+		if snapped[0] != 0.5 || snapped[1] != 0.5 {
+			t.Errorf("expected exact 0.5/0.5, got %v", snapped)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		snapped := SnapSplitRatio(adjusted, 0, nil, SnapTolerance)
+		if snapped[0] == 0.5 || math.Abs(snapped[0]-0.49) > 0.0001 {
+			t.Errorf("expected unsnapped 0.49, got %v", snapped)
+		}
+	})
+
+	t.Run("OutsideTolerance", func(t *testing.T) {
+		snapped := SnapSplitRatio([]float64{0.42, 0.58}, 0, DefaultSnapPoints, SnapTolerance)
+		if snapped[0] != 0.42 {
+			t.Errorf("expected 0.42 to stay put, got %v", snapped)
+		}
+	})
+
+	t.Run("KeepsPairShare", func(t *testing.T) {
+		snapped := SnapSplitRatio([]float64{0.2, 0.32, 0.48}, 1, DefaultSnapPoints, SnapTolerance)
+		if math.Abs(snapped[1]-1.0/3) > 1e-9 || math.Abs(snapped[1]+snapped[2]-0.8) > 1e-9 || snapped[0] != 0.2 {
+			t.Errorf("expected middle window snapped to 1/3 within its pair, got %v", snapped)
+		}
+	})
+}
+
+func TestInsertRatioWithShare(t *testing.T) {
+	t.Run("KeepsProportions", func(t *testing.T) {
+		got := InsertRatioWithShare([]float64{0.75, 0.25}, 0, 0.6, MinimumRatio)
+		want := []float64{0.6, 0.3, 0.1}
+		for i := range want {
+			if math.Abs(got[i]-want[i]) > 1e-9 {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+	})
+
+	t.Run("EmptyCell", func(t *testing.T) {
+		got := InsertRatioWithShare(nil, 0, 0.6, MinimumRatio)
+		if len(got) != 1 || got[0] != 1.0 {
+			t.Errorf("expected [1], got %v", got)
+		}
+	})
+
+	t.Run("ClampsShare", func(t *testing.T) {
+		// A window that filled its source cell can't crowd out the others
+		got := InsertRatioWithShare([]float64{0.5, 0.5}, 0, 1.0, MinimumRatio)
+		if math.Abs(got[0]-0.8) > 1e-9 || math.Abs(got[1]-0.1) > 1e-9 {
+			t.Errorf("expected share clamped to 0.8, got %v", got)
+		}
+	})
+}
+
 func TestRecalculateSplitsAfterRemoval(t *testing.T) {
 	t.Run("RemoveMiddle", func(t *testing.T) {
 		ratios := []float64{0.4, 0.3, 0.3}
@@ -377,7 +486,7 @@ func TestCalculateSplitBoundary(t *testing.T) {
 
 func TestAdjustSplitRatioAtBoundary(t *testing.T) {
 	ratios := []float64{0.5, 0.5}
-	newRatios, err := AdjustSplitRatioAtBoundary(ratios, 0, 0.1)
+	newRatios, _, err := AdjustSplitRatioAtBoundary(ratios, 0, 0.1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,88 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+// StickyChecker looks up whether windows are sticky (visible on all spaces).
+// The lookup needs MSS, so only windows the snapshot reports on more than one
+// space are queried. Results are cached, and the first failed lookup is taken
+// to mean MSS is unavailable, after which no further lookups are made.
+type StickyChecker struct {
+	c           *client.Client
+	cache       map[uint32]bool
+	unavailable bool
+}
+
+// NewStickyChecker creates a checker that queries the server on demand
+func NewStickyChecker(c *client.Client) *StickyChecker {
+	return &StickyChecker{c: c, cache: make(map[uint32]bool)}
+}
+
+// IsSticky reports whether a window is sticky. Windows on a single space
+// are never sticky and don't cost an RPC.
+func (sc *StickyChecker) IsSticky(ctx context.Context, w server.WindowInfo) bool {
+	if w.SpaceCount <= 1 || sc.unavailable {
+		return false
+	}
+	if sticky, ok := sc.cache[w.ID]; ok {
+		return sticky
+	}
+
+	result, err := sc.c.CallMethod(ctx, "window.isSticky", map[string]interface{}{
+		"windowId": fmt.Sprintf("%d", w.ID),
+	})
+	if err != nil {
+		logging.Debug().Err(err).Msg("sticky lookup failed, assuming MSS is unavailable")
+		sc.unavailable = true
+		return false
+	}
+
+	sticky, _ := result["sticky"].(bool)
+	sc.cache[w.ID] = sticky
+	return sticky
+}
+
+// Prepare decides whether a window should be tiled. Sticky windows are
+// skipped unless tile is set, in which case they're un-stuck first; a window
+// that can't be un-stuck is skipped.
+func (sc *StickyChecker) Prepare(ctx context.Context, w server.WindowInfo, tile bool) bool {
+	if !sc.IsSticky(ctx, w) {
+		return true
+	}
+	if !tile {
+		return false
+	}
+
+	_, err := sc.c.CallMethod(ctx, "window.setSticky", map[string]interface{}{
+		"windowId": fmt.Sprintf("%d", w.ID),
+		"sticky":   false,
+	})
+	if err != nil {
+		logging.Warn().Err(err).Uint32("windowId", w.ID).Msg("failed to un-stick window")
+		return false
+	}
+	sc.cache[w.ID] = false
+	return true
+}
+
+// PrepareStickyWindows returns the windows to tile, dropping sticky ones
+// (or un-sticking them when tile is set) and reporting which were skipped.
+// Non-tileable windows are passed through unchecked.
+func PrepareStickyWindows(ctx context.Context, sc *StickyChecker, windows []server.WindowInfo, tile bool) ([]server.WindowInfo, []uint32) {
+	kept := make([]server.WindowInfo, 0, len(windows))
+	var skipped []uint32
+	for _, w := range windows {
+		if w.IsTileable() && !sc.Prepare(ctx, w, tile) {
+			skipped = append(skipped, w.ID)
+			continue
+		}
+		kept = append(kept, w)
+	}
+	return kept, skipped
+}
@@ -0,0 +1,156 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// stickyServer answers window.isSticky from a fixed set of sticky windows
+// (or with an error when noMSS is set) and getServerInfo with capabilities,
+// recording the requests it was sent.
+type stickyServer struct {
+	sticky       map[string]bool
+	noMSS        bool
+	capabilities map[string]interface{}
+
+	testutil.Server
+}
+
+func startStickyServer(t testing.TB, ss *stickyServer) *client.Client {
+	t.Helper()
+	ss.Handle = func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+		switch req.Method {
+		case "getServerInfo":
+			return map[string]interface{}{"capabilities": ss.capabilities}, nil
+		case "window.isSticky":
+			if ss.noMSS {
+				return nil, &models.ErrorInfo{Code: -32000, Message: "MSS not available"}
+			}
+			id := fmt.Sprintf("%v", req.Params["windowId"])
+			return map[string]interface{}{"sticky": ss.sticky[id]}, nil
+		}
+		return nil, nil
+	}
+	c := client.NewClient(ss.Start(t), 0)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+// focused returns the windows the server was asked to focus, in order
+func (ss *stickyServer) focused() []string {
+	var focused []string
+	for _, r := range ss.Requests() {
+		if r.Method == "window.focus" {
+			focused = append(focused, fmt.Sprintf("%v", r.Params["windowId"]))
+		}
+	}
+	return focused
+}
+
+// stickySnapshot has a normal window (20) and a sticky one (21) that the
+// server reports on every space.
+func stickySnapshot() *server.Snapshot {
+	bounds := types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055}
+	return &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		Windows: []server.WindowInfo{
+			{ID: 20, AppName: "Safari", Frame: types.Rect{Width: 800, Height: 600}, SpaceCount: 1},
+			{ID: 21, AppName: "Notes", Frame: types.Rect{X: 900, Width: 800, Height: 600}, SpaceCount: 3},
+		},
+		WindowIDs: map[uint32]bool{20: true, 21: true},
+	}
+}
+
+func TestApplyLayout_SkipsStickyWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ss := &stickyServer{sticky: map[string]bool{"21": true}}
+	c := startStickyServer(t, ss)
+	rs := state.NewRuntimeState()
+
+	result, err := ApplyLayoutWithResult(context.Background(), c, stickySnapshot(), fullLayoutConfig(), rs, "full", DefaultApplyOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result.Sticky, []uint32{21}) {
+		t.Errorf("Sticky = %v, want [21]", result.Sticky)
+	}
+	if got := rs.GetCellWindows("1", "main"); !reflect.DeepEqual(got, []uint32{20}) {
+		t.Errorf("main cell = %v, want [20]", got)
+	}
+	if n := ss.Count("window.setSticky"); n != 0 {
+		t.Errorf("setSticky called %d times, want 0", n)
+	}
+}
+
+func TestApplyLayout_UnsticksWhenTileStickyWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ss := &stickyServer{sticky: map[string]bool{"21": true}}
+	c := startStickyServer(t, ss)
+	rs := state.NewRuntimeState()
+	cfg := fullLayoutConfig()
+	cfg.Settings.TileStickyWindows = true
+
+	result, err := ApplyLayoutWithResult(context.Background(), c, stickySnapshot(), cfg, rs, "full", DefaultApplyOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Sticky) != 0 {
+		t.Errorf("Sticky = %v, want none", result.Sticky)
+	}
+	if got := rs.GetCellWindows("1", "main"); len(got) != 2 {
+		t.Errorf("main cell = %v, want both windows", got)
+	}
+	if n := ss.Count("window.setSticky"); n != 1 {
+		t.Errorf("setSticky called %d times, want 1", n)
+	}
+}
+
+func TestStickyChecker_OnlyQueriesMultiSpaceWindows(t *testing.T) {
+	ss := &stickyServer{sticky: map[string]bool{"21": true}}
+	sc := NewStickyChecker(startStickyServer(t, ss))
+	ctx := context.Background()
+	snap := stickySnapshot()
+
+	if sc.IsSticky(ctx, snap.Windows[0]) {
+		t.Error("single-space window should not be sticky")
+	}
+	for i := 0; i < 2; i++ {
+		if !sc.IsSticky(ctx, snap.Windows[1]) {
+			t.Error("window 21 should be sticky")
+		}
+	}
+	// One lookup for window 21; window 20 and the repeat are free
+	if n := ss.Count("window.isSticky"); n != 1 {
+		t.Errorf("isSticky called %d times, want 1", n)
+	}
+}
+
+func TestStickyChecker_WithoutMSS(t *testing.T) {
+	ss := &stickyServer{noMSS: true}
+	sc := NewStickyChecker(startStickyServer(t, ss))
+	windows := []server.WindowInfo{
+		{ID: 30, AppName: "A", SpaceCount: 2},
+		{ID: 31, AppName: "B", SpaceCount: 2},
+	}
+
+	kept, skipped := PrepareStickyWindows(context.Background(), sc, windows, false)
+	if len(kept) != 2 || len(skipped) != 0 {
+		t.Errorf("expected all windows kept without MSS, got kept=%v skipped=%v", kept, skipped)
+	}
+	// The first failure marks MSS unavailable
+	if n := ss.Count("window.isSticky"); n != 1 {
+		t.Errorf("isSticky called %d times, want 1", n)
+	}
+}
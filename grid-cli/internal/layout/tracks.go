@@ -0,0 +1,330 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+const (
+	// MinimumTrackFr is the smallest fr value `resize cell` leaves on a track
+	MinimumTrackFr = 0.1
+	// DefaultTrackStep is the fr moved between tracks per `resize cell`
+	DefaultTrackStep = 0.1
+)
+
+// SpaceLayout returns the space's current layout with its track overrides applied.
+func SpaceLayout(cfg *config.Config, spaceState *state.SpaceState) (*types.Layout, error) {
+	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	if err != nil {
+		return nil, err
+	}
+	return WithTrackOverrides(layoutDef, spaceState), nil
+}
+
+// WithTrackOverrides returns a copy of layoutDef with the space's fr overrides
+// applied. The layout is returned unchanged when the overrides were made for
+// a different layout.
+func WithTrackOverrides(layoutDef *types.Layout, spaceState *state.SpaceState) *types.Layout {
+	if spaceState == nil || spaceState.Tracks == nil || spaceState.Tracks.LayoutID != layoutDef.ID {
+		return layoutDef
+	}
+
+	result := *layoutDef
+	result.Columns = overrideTracks(layoutDef.Columns, spaceState.Tracks.Columns)
+	result.Rows = overrideTracks(layoutDef.Rows, spaceState.Tracks.Rows)
+	return &result
+}
+
+func overrideTracks(tracks []types.TrackSize, overrides []float64) []types.TrackSize {
+	result := append([]types.TrackSize(nil), tracks...)
+	for i, fr := range overrides {
+		if i < len(result) && fr > 0 && result[i].Type == types.TrackFr {
+			result[i].Value = fr
+		}
+	}
+	return result
+}
+
+// ResizeCellTracks moves the edge of a cell on the given side by delta fr.
+// A positive delta grows the cell's edge track at the expense of the
+// neighbouring track; a negative delta shrinks it. The neighbour and edge
+// tracks never go below MinimumTrackFr. Overrides are recorded in tracks.
+// Returns false when the tracks are already clamped and nothing changed.
+func ResizeCellTracks(layoutDef *types.Layout, tracks *state.TrackOverrides, cellID string, direction types.Direction, delta float64) (bool, error) {
+	axis, err := cellTrackAxis(layoutDef, tracks, cellID, direction)
+	if err != nil {
+		return false, err
+	}
+
+	from, to := axis.neighbor, axis.edge
+	if delta < 0 {
+		from, to, delta = axis.edge, axis.neighbor, -delta
+	}
+	available := axis.current(from) - MinimumTrackFr
+	if available <= 0 {
+		return false, nil
+	}
+
+	fromFr := axis.current(from) - delta
+	if delta >= available {
+		// Land exactly on the minimum rather than a float just below it
+		fromFr, delta = MinimumTrackFr, available
+	}
+	axis.set(from, fromFr)
+	axis.set(to, axis.current(to)+delta)
+	return true, nil
+}
+
+// ResizeMasterTracks grows (positive delta) or shrinks the master cell by
+// delta fr on the side facing the rest of the grid (right, else left, down,
+// up). Every track beyond that side is the stack area; it gives up or takes
+// the same delta in total, split in proportion to the tracks' sizes so the
+// stack keeps its shape. No track goes below MinimumTrackFr.
+func ResizeMasterTracks(layoutDef *types.Layout, tracks *state.TrackOverrides, cellID string, delta float64) (bool, error) {
+	direction, err := masterDirection(layoutDef, cellID)
+	if err != nil {
+		return false, err
+	}
+	axis, err := cellTrackAxis(layoutDef, tracks, cellID, direction)
+	if err != nil {
+		return false, err
+	}
+
+	var stack []int
+	if axis.neighbor > axis.edge {
+		for i := axis.neighbor; i < len(axis.defs); i++ {
+			stack = append(stack, i)
+		}
+	} else {
+		for i := 0; i <= axis.neighbor; i++ {
+			stack = append(stack, i)
+		}
+	}
+	var stackTotal float64
+	for _, i := range stack {
+		if axis.defs[i].Type != types.TrackFr {
+			return false, fmt.Errorf("only fr tracks can be resized")
+		}
+		stackTotal += axis.current(i)
+	}
+
+	// Clamp so neither the master edge nor any stack track drops below the minimum
+	grow := delta > 0
+	if grow {
+		for _, i := range stack {
+			fr := axis.current(i)
+			delta = math.Min(delta, (fr-MinimumTrackFr)*stackTotal/fr)
+		}
+	} else {
+		delta = math.Max(delta, MinimumTrackFr-axis.current(axis.edge))
+	}
+	if (grow && delta < 1e-9) || (!grow && delta > -1e-9) {
+		return false, nil
+	}
+
+	axis.set(axis.edge, atLeastMinimum(axis.current(axis.edge)+delta))
+	for _, i := range stack {
+		fr := axis.current(i)
+		axis.set(i, atLeastMinimum(fr-delta*fr/stackTotal))
+	}
+	return true, nil
+}
+
+// atLeastMinimum raises fr to MinimumTrackFr, snapping float error just
+// above it onto the minimum too
+func atLeastMinimum(fr float64) float64 {
+	if fr < MinimumTrackFr+1e-9 {
+		return MinimumTrackFr
+	}
+	return fr
+}
+
+// masterDirection returns the side of a cell that faces the rest of the grid
+func masterDirection(layoutDef *types.Layout, cellID string) (types.Direction, error) {
+	cell, err := findLayoutCell(layoutDef, cellID)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case cell.ColumnEnd-1 < len(layoutDef.Columns):
+		return types.DirRight, nil
+	case cell.ColumnStart > 1:
+		return types.DirLeft, nil
+	case cell.RowEnd-1 < len(layoutDef.Rows):
+		return types.DirDown, nil
+	case cell.RowStart > 1:
+		return types.DirUp, nil
+	}
+	return 0, fmt.Errorf("cell %s fills the grid; there is no stack area to resize against", cellID)
+}
+
+// trackAxis is the row or column tracks on one side of a cell, with the
+// space's overrides for them
+type trackAxis struct {
+	defs      []types.TrackSize
+	overrides *[]float64
+	edge      int // The cell's last track on that side
+	neighbor  int // The track just beyond it
+}
+
+func (a trackAxis) current(i int) float64 {
+	if fr := (*a.overrides)[i]; fr > 0 {
+		return fr
+	}
+	return a.defs[i].Value
+}
+
+func (a trackAxis) set(i int, fr float64) {
+	(*a.overrides)[i] = fr
+}
+
+// cellTrackAxis finds the edge and neighbouring tracks of a cell on the given
+// side. Both must be fr tracks.
+func cellTrackAxis(layoutDef *types.Layout, tracks *state.TrackOverrides, cellID string, direction types.Direction) (trackAxis, error) {
+	cell, err := findLayoutCell(layoutDef, cellID)
+	if err != nil {
+		return trackAxis{}, err
+	}
+
+	// Track indices are 0-based; cell lines are 1-based with exclusive ends
+	var a trackAxis
+	switch direction {
+	case types.DirLeft:
+		a = trackAxis{defs: layoutDef.Columns, overrides: &tracks.Columns, edge: cell.ColumnStart - 1, neighbor: cell.ColumnStart - 2}
+	case types.DirRight:
+		a = trackAxis{defs: layoutDef.Columns, overrides: &tracks.Columns, edge: cell.ColumnEnd - 2, neighbor: cell.ColumnEnd - 1}
+	case types.DirUp:
+		a = trackAxis{defs: layoutDef.Rows, overrides: &tracks.Rows, edge: cell.RowStart - 1, neighbor: cell.RowStart - 2}
+	case types.DirDown:
+		a = trackAxis{defs: layoutDef.Rows, overrides: &tracks.Rows, edge: cell.RowEnd - 2, neighbor: cell.RowEnd - 1}
+	default:
+		return trackAxis{}, fmt.Errorf("cannot resize a cell diagonally")
+	}
+
+	if a.neighbor < 0 || a.neighbor >= len(a.defs) {
+		return trackAxis{}, fmt.Errorf("cell %s has no track to its %s", cellID, direction)
+	}
+	if a.defs[a.edge].Type != types.TrackFr || a.defs[a.neighbor].Type != types.TrackFr {
+		return trackAxis{}, fmt.Errorf("only fr tracks can be resized")
+	}
+
+	if len(*a.overrides) < len(a.defs) {
+		*a.overrides = append(*a.overrides, make([]float64, len(a.defs)-len(*a.overrides))...)
+	}
+	return a, nil
+}
+
+func findLayoutCell(layoutDef *types.Layout, cellID string) (*types.Cell, error) {
+	for i := range layoutDef.Cells {
+		if layoutDef.Cells[i].ID == cellID {
+			return &layoutDef.Cells[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cell %s not found in layout %s", cellID, layoutDef.ID)
+}
+
+// ResizeFocusedCell grows (positive delta) or shrinks the focused cell's
+// tracks on the given side and reapplies the layout. Returns false (and skips
+// the reapply) when the tracks are already at MinimumTrackFr.
+func ResizeFocusedCell(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+	delta float64,
+) (bool, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return false, fmt.Errorf("no layout applied")
+	}
+
+	cellID := spaceState.FocusedCell
+	if cellID == "" {
+		return false, fmt.Errorf("no focused cell")
+	}
+
+	return adjustTracks(ctx, c, snap, cfg, rs, func(layoutDef *types.Layout, tracks *state.TrackOverrides) (bool, error) {
+		return ResizeCellTracks(layoutDef, tracks, cellID, direction, delta)
+	})
+}
+
+// ResizeMaster grows (positive delta) or shrinks the cell holding the
+// space's master window against the rest of the grid, see ResizeMasterTracks,
+// and reapplies the layout. Returns false when already at the minimum.
+func ResizeMaster(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	delta float64,
+) (bool, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return false, fmt.Errorf("no layout applied")
+	}
+
+	layoutDef, err := SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return false, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+	master := spaceState.GetMaster(SortCellsByPosition(calculated.CellBounds))
+	if master == 0 {
+		return false, fmt.Errorf("no master window on space %s", snap.SpaceID)
+	}
+	cellID := spaceState.GetWindowCell(master)
+
+	return adjustTracks(ctx, c, snap, cfg, rs, func(layoutDef *types.Layout, tracks *state.TrackOverrides) (bool, error) {
+		return ResizeMasterTracks(layoutDef, tracks, cellID, delta)
+	})
+}
+
+// adjustTracks runs adjust on a copy of the space's track overrides for its
+// current layout, then stores them and reapplies the layout if it changed.
+func adjustTracks(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	adjust func(*types.Layout, *state.TrackOverrides) (bool, error),
+) (bool, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	if err != nil {
+		return false, fmt.Errorf("layout not found: %w", err)
+	}
+
+	tracks := &state.TrackOverrides{LayoutID: layoutDef.ID}
+	if spaceState.Tracks != nil && spaceState.Tracks.LayoutID == layoutDef.ID {
+		tracks = spaceState.Clone().Tracks
+	}
+
+	changed, err := adjust(layoutDef, tracks)
+	if err != nil || !changed {
+		return false, err
+	}
+
+	rs.GetSpace(snap.SpaceID).Tracks = tracks
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		return false, fmt.Errorf("failed to save state: %w", err)
+	}
+
+	opts := DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	if err := ReapplyLayout(ctx, c, snap, cfg, rs, opts); err != nil {
+		return true, err
+	}
+	return true, nil
+}
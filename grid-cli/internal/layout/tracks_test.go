@@ -0,0 +1,175 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func threeColumnLayout(t *testing.T) *types.Layout {
+	t.Helper()
+	lc := config.LayoutConfig{
+		ID:    "three",
+		Grid:  config.GridConfig{Columns: []string{"1fr", "1fr", "1fr"}, Rows: []string{"1fr", "1fr"}},
+		Areas: [][]string{{"left", "center", "right"}, {"left", "bottom", "bottom"}},
+	}
+	layoutDef, err := lc.ToLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layoutDef
+}
+
+func TestResizeCellTracks_GrowColumn(t *testing.T) {
+	layoutDef := threeColumnLayout(t)
+	space := state.NewSpaceState("1")
+	space.CurrentLayoutID = "three"
+	space.Tracks = &state.TrackOverrides{LayoutID: "three"}
+
+	changed, err := ResizeCellTracks(layoutDef, space.Tracks, "left", types.DirRight, 0.5)
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	if got := space.Tracks.Columns; len(got) != 3 || got[0] != 1.5 || got[1] != 0.5 || got[2] != 0 {
+		t.Fatalf("columns = %v, want [1.5 0.5 0]", got)
+	}
+
+	screen := types.Rect{Width: 1200, Height: 800}
+	calc := CalculateLayout(WithTrackOverrides(layoutDef, space), screen, 0)
+	want := map[string]float64{"left": 600, "center": 200, "right": 400, "bottom": 600}
+	for cellID, width := range want {
+		if got := calc.CellBounds[cellID].Width; got != width {
+			t.Errorf("%s width = %v, want %v", cellID, got, width)
+		}
+	}
+	// The bottom cell spans the shrunk column, so its left edge moves with it
+	if got := calc.CellBounds["bottom"].X; got != 600 {
+		t.Errorf("bottom x = %v, want 600", got)
+	}
+}
+
+func TestResizeCellTracks_ClampsAtMinimum(t *testing.T) {
+	layoutDef := threeColumnLayout(t)
+	tracks := &state.TrackOverrides{LayoutID: "three"}
+
+	changed, err := ResizeCellTracks(layoutDef, tracks, "center", types.DirLeft, -5)
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	if tracks.Columns[1] != MinimumTrackFr || tracks.Columns[0] != 1.9 {
+		t.Fatalf("columns = %v, want center clamped to %v", tracks.Columns, MinimumTrackFr)
+	}
+
+	changed, err = ResizeCellTracks(layoutDef, tracks, "center", types.DirLeft, -0.1)
+	if err != nil || changed {
+		t.Fatalf("expected no change at minimum, changed=%v err=%v", changed, err)
+	}
+}
+
+func TestResizeCellTracks_Errors(t *testing.T) {
+	layoutDef := threeColumnLayout(t)
+	tracks := &state.TrackOverrides{LayoutID: "three"}
+
+	if _, err := ResizeCellTracks(layoutDef, tracks, "right", types.DirRight, 0.1); err == nil {
+		t.Error("expected error growing past the grid edge")
+	}
+	if _, err := ResizeCellTracks(layoutDef, tracks, "left", types.DirUp, 0.1); err == nil {
+		t.Error("expected error with no row above")
+	}
+	if _, err := ResizeCellTracks(layoutDef, tracks, "missing", types.DirRight, 0.1); err == nil {
+		t.Error("expected error for unknown cell")
+	}
+}
+
+func TestWithTrackOverrides_IgnoresOtherLayout(t *testing.T) {
+	layoutDef := threeColumnLayout(t)
+	space := state.NewSpaceState("1")
+	space.Tracks = &state.TrackOverrides{LayoutID: "other", Columns: []float64{3, 1, 1}}
+
+	if got := WithTrackOverrides(layoutDef, space); got.Columns[0].Value != 1 {
+		t.Errorf("column 0 = %v, want the layout's own 1fr", got.Columns[0].Value)
+	}
+
+	space.SetCurrentLayout("three", 0)
+	if space.Tracks != nil {
+		t.Error("expected overrides for another layout to be dropped on layout change")
+	}
+}
+
+func masterStackLayout(t *testing.T) *types.Layout {
+	t.Helper()
+	lc := config.LayoutConfig{
+		ID:    "master-stack",
+		Grid:  config.GridConfig{Columns: []string{"2fr", "1fr", "1fr"}, Rows: []string{"1fr", "1fr"}},
+		Areas: [][]string{{"master", "top", "side"}, {"master", "bottom", "side"}},
+	}
+	layoutDef, err := lc.ToLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return layoutDef
+}
+
+func TestResizeMasterTracks_GrowShrinksStackProportionally(t *testing.T) {
+	layoutDef := masterStackLayout(t)
+	space := state.NewSpaceState("1")
+	space.Tracks = &state.TrackOverrides{LayoutID: "master-stack"}
+
+	changed, err := ResizeMasterTracks(layoutDef, space.Tracks, "master", 1)
+	if err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	// The stack columns each give up half of the 1fr, keeping their 1:1 shape
+	if got := space.Tracks.Columns; len(got) != 3 || got[0] != 3 || got[1] != 0.5 || got[2] != 0.5 {
+		t.Fatalf("columns = %v, want [3 0.5 0.5]", got)
+	}
+
+	calc := CalculateLayout(WithTrackOverrides(layoutDef, space), types.Rect{Width: 1600, Height: 1000}, 0)
+	want := map[string]float64{"master": 1200, "top": 200, "bottom": 200, "side": 200}
+	for cellID, width := range want {
+		if got := calc.CellBounds[cellID].Width; got != width {
+			t.Errorf("%s width = %v, want %v", cellID, got, width)
+		}
+	}
+}
+
+func TestResizeMasterTracks_Clamps(t *testing.T) {
+	layoutDef := masterStackLayout(t)
+	tracks := &state.TrackOverrides{LayoutID: "master-stack"}
+
+	// Growing past the stack stops with every stack track at the minimum
+	if changed, err := ResizeMasterTracks(layoutDef, tracks, "master", 10); err != nil || !changed {
+		t.Fatalf("changed=%v err=%v", changed, err)
+	}
+	if tracks.Columns[1] != MinimumTrackFr || tracks.Columns[2] != MinimumTrackFr {
+		t.Errorf("stack columns = %v, want both at %v", tracks.Columns[1:], MinimumTrackFr)
+	}
+	if changed, _ := ResizeMasterTracks(layoutDef, tracks, "master", 1); changed {
+		t.Error("expected no change once the stack is at its minimum")
+	}
+
+	// Shrinking stops at the master's own minimum
+	if _, err := ResizeMasterTracks(layoutDef, tracks, "master", -10); err != nil {
+		t.Fatal(err)
+	}
+	if tracks.Columns[0] != MinimumTrackFr {
+		t.Errorf("master column = %v, want %v", tracks.Columns[0], MinimumTrackFr)
+	}
+}
+
+func TestResizeMasterTracks_FullGrid(t *testing.T) {
+	lc := config.LayoutConfig{
+		ID:    "full",
+		Grid:  config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}},
+		Areas: [][]string{{"main"}},
+	}
+	layoutDef, err := lc.ToLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResizeMasterTracks(layoutDef, &state.TrackOverrides{LayoutID: "full"}, "main", 0.5); err == nil {
+		t.Error("expected error when the master cell fills the grid")
+	}
+}
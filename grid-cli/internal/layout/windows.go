@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"math"
+
 	"github.com/yourusername/grid-cli/internal/types"
 )
 
@@ -9,7 +11,7 @@ import (
 // Parameters:
 //   - cellBounds: The cell's bounds
 //   - windowCount: Number of windows in the cell
-//   - mode: How windows are stacked (vertical, horizontal, tabs)
+//   - mode: How windows are stacked (vertical, horizontal, tabs, grid)
 //   - ratios: Split ratios (one per window, should sum to 1.0). If nil, uses equal splits
 //   - padding: Padding between windows in pixels
 //
@@ -37,6 +39,10 @@ func CalculateWindowBounds(
 		bounds = calculateVerticalStack(cellBounds, ratios, padding)
 	case types.StackHorizontal:
 		bounds = calculateHorizontalStack(cellBounds, ratios, padding)
+	case stackBSP:
+		bounds = calculateBSP(cellBounds, windowCount, padding)
+	case types.StackGrid:
+		bounds = calculateGridStack(cellBounds, windowCount, padding)
 	case types.StackTabs:
 		// All windows get full cell bounds (only one visible at a time)
 		bounds = make([]types.Rect, windowCount)
@@ -51,6 +57,24 @@ func CalculateWindowBounds(
 	return bounds
 }
 
+// OrientedStackMode adapts a default stack mode to the display orientation.
+// On portrait displays vertical and horizontal stacking are swapped (an unset
+// mode counts as vertical); tabs and landscape displays are left unchanged.
+func OrientedStackMode(mode types.StackMode, display types.Rect) types.StackMode {
+	if display.Height <= display.Width {
+		return mode
+	}
+
+	switch mode {
+	case types.StackVertical, "":
+		return types.StackHorizontal
+	case types.StackHorizontal:
+		return types.StackVertical
+	default:
+		return mode
+	}
+}
+
 // calculateVerticalStack arranges windows top-to-bottom.
 func calculateVerticalStack(cellBounds types.Rect, ratios []float64, padding float64) []types.Rect {
 	n := len(ratios)
@@ -105,6 +129,45 @@ func calculateHorizontalStack(cellBounds types.Rect, ratios []float64, padding f
 	return bounds
 }
 
+// GridStackColumns returns the number of columns a grid-stacked cell of n
+// windows uses: ceil(sqrt(n)), so the grid stays close to square.
+func GridStackColumns(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(math.Ceil(math.Sqrt(float64(n))))
+}
+
+// calculateGridStack arranges n windows in rows of GridStackColumns(n),
+// filled left-to-right and top-to-bottom, with padding between tiles. A
+// short last row splits the full cell width between its windows so no gap
+// is left. Split ratios don't apply.
+func calculateGridStack(cellBounds types.Rect, n int, padding float64) []types.Rect {
+	if n <= 0 {
+		return nil
+	}
+
+	cols := GridStackColumns(n)
+	rows := (n + cols - 1) / cols
+	height := (cellBounds.Height - padding*float64(rows-1)) / float64(rows)
+
+	bounds := make([]types.Rect, 0, n)
+	for row := 0; row < rows; row++ {
+		inRow := min(cols, n-row*cols)
+		width := (cellBounds.Width - padding*float64(inRow-1)) / float64(inRow)
+		y := cellBounds.Y + float64(row)*(height+padding)
+		for col := 0; col < inRow; col++ {
+			bounds = append(bounds, types.Rect{
+				X:      cellBounds.X + float64(col)*(width+padding),
+				Y:      y,
+				Width:  width,
+				Height: height,
+			})
+		}
+	}
+	return bounds
+}
+
 // equalRatios returns an array of equal ratios summing to 1.0.
 func equalRatios(n int) []float64 {
 	if n <= 0 {
@@ -141,6 +204,24 @@ func NormalizeRatios(ratios []float64) []float64 {
 	return normalized
 }
 
+// FitAspectRatio returns the largest rect of the given width/height ratio
+// centered in bounds, leaving equal margins on the axis it doesn't fill.
+// A ratio of 0 returns bounds unchanged.
+func FitAspectRatio(bounds types.Rect, ratio float64) types.Rect {
+	if ratio <= 0 || bounds.Width <= 0 || bounds.Height <= 0 {
+		return bounds
+	}
+	fitted := bounds
+	if bounds.Width/bounds.Height > ratio {
+		fitted.Width = bounds.Height * ratio
+		fitted.X += (bounds.Width - fitted.Width) / 2
+	} else {
+		fitted.Height = bounds.Width / ratio
+		fitted.Y += (bounds.Height - fitted.Height) / 2
+	}
+	return fitted
+}
+
 // CalculateAllWindowPlacements computes placements for all windows in a layout.
 //
 // Parameters:
@@ -171,6 +252,7 @@ func CalculateAllWindowPlacements(
 		if !ok {
 			continue
 		}
+		cellBounds = FitAspectRatio(cellBounds, calculatedLayout.AspectRatios[cellID])
 
 		// Determine stack mode for this cell
 		mode := defaultMode
@@ -118,6 +118,75 @@ func TestCalculateWindowBounds_WithPadding(t *testing.T) {
 	}
 }
 
+func TestCalculateWindowBounds_Grid(t *testing.T) {
+	tests := []struct {
+		name string
+		cell types.Rect
+		n    int
+		want []types.Rect
+	}{
+		{
+			// 2 columns; the lone window in the short last row spans the cell
+			name: "3 windows",
+			cell: types.Rect{X: 0, Y: 0, Width: 1210, Height: 610},
+			n:    3,
+			want: []types.Rect{
+				{X: 0, Y: 0, Width: 600, Height: 300},
+				{X: 610, Y: 0, Width: 600, Height: 300},
+				{X: 0, Y: 310, Width: 1210, Height: 300},
+			},
+		},
+		{
+			name: "4 windows",
+			cell: types.Rect{X: 100, Y: 50, Width: 1210, Height: 610},
+			n:    4,
+			want: []types.Rect{
+				{X: 100, Y: 50, Width: 600, Height: 300},
+				{X: 710, Y: 50, Width: 600, Height: 300},
+				{X: 100, Y: 360, Width: 600, Height: 300},
+				{X: 710, Y: 360, Width: 600, Height: 300},
+			},
+		},
+		{
+			// 3 columns, 2 full rows
+			name: "6 windows",
+			cell: types.Rect{X: 0, Y: 0, Width: 1220, Height: 610},
+			n:    6,
+			want: []types.Rect{
+				{X: 0, Y: 0, Width: 400, Height: 300},
+				{X: 410, Y: 0, Width: 400, Height: 300},
+				{X: 820, Y: 0, Width: 400, Height: 300},
+				{X: 0, Y: 310, Width: 400, Height: 300},
+				{X: 410, Y: 310, Width: 400, Height: 300},
+				{X: 820, Y: 310, Width: 400, Height: 300},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Ratios are ignored in grid mode
+			bounds := CalculateWindowBounds(tt.cell, tt.n, types.StackGrid, []float64{0.7, 0.2, 0.1}, 10)
+			if len(bounds) != len(tt.want) {
+				t.Fatalf("expected %d bounds, got %d", len(tt.want), len(bounds))
+			}
+			for i := range tt.want {
+				if bounds[i] != tt.want[i] {
+					t.Errorf("bounds[%d] = %+v, want %+v", i, bounds[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGridStackColumns(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 1: 1, 2: 2, 3: 2, 4: 2, 5: 3, 9: 3, 10: 4} {
+		if got := GridStackColumns(n); got != want {
+			t.Errorf("GridStackColumns(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
 func TestCalculateWindowBounds_SingleWindow(t *testing.T) {
 	cellBounds := types.Rect{X: 100, Y: 200, Width: 500, Height: 500}
 	bounds := CalculateWindowBounds(cellBounds, 1, types.StackVertical, nil, 10)
@@ -291,6 +360,51 @@ func TestCalculateAllWindowPlacements(t *testing.T) {
 	}
 }
 
+func TestCalculateAllWindowPlacements_AspectRatio(t *testing.T) {
+	calculatedLayout := &types.CalculatedLayout{
+		LayoutID: "test",
+		CellBounds: map[string]types.Rect{
+			"video": {X: 100, Y: 0, Width: 1000, Height: 1000},
+		},
+		AspectRatios: map[string]float64{"video": 16.0 / 9.0},
+	}
+
+	placements := CalculateAllWindowPlacements(
+		calculatedLayout,
+		map[string][]uint32{"video": {1}},
+		nil,
+		nil,
+		types.StackVertical,
+		0,
+	)
+
+	if len(placements) != 1 {
+		t.Fatalf("expected 1 placement, got %d", len(placements))
+	}
+
+	// A 16:9 window letterboxed into a square cell keeps the full width
+	// and is centered vertically.
+	want := types.Rect{X: 100, Y: 218.75, Width: 1000, Height: 562.5}
+	if got := placements[0].Bounds; got != want {
+		t.Errorf("bounds = %+v, want %+v", got, want)
+	}
+}
+
+func TestFitAspectRatio(t *testing.T) {
+	// Wider than the ratio: pillarbox, centered horizontally.
+	got := FitAspectRatio(types.Rect{X: 0, Y: 50, Width: 2000, Height: 900}, 16.0/9.0)
+	want := types.Rect{X: 200, Y: 50, Width: 1600, Height: 900}
+	if got != want {
+		t.Errorf("FitAspectRatio() = %+v, want %+v", got, want)
+	}
+
+	// No ratio leaves bounds alone.
+	bounds := types.Rect{X: 1, Y: 2, Width: 3, Height: 4}
+	if got := FitAspectRatio(bounds, 0); got != bounds {
+		t.Errorf("FitAspectRatio(0) = %+v, want %+v", got, bounds)
+	}
+}
+
 func TestCalculateAllWindowPlacements_WithCellModes(t *testing.T) {
 	calculatedLayout := &types.CalculatedLayout{
 		LayoutID: "test",
@@ -376,3 +490,72 @@ func TestCalculateAllWindowPlacements_UnknownCell(t *testing.T) {
 }
 
 // floatEquals is defined in grid_test.go
+
+func TestOrientedStackMode(t *testing.T) {
+	landscape := types.Rect{Width: 1920, Height: 1080}
+	portrait := types.Rect{Width: 1080, Height: 1920}
+
+	tests := []struct {
+		name    string
+		mode    types.StackMode
+		display types.Rect
+		want    types.StackMode
+	}{
+		{"landscape keeps vertical", types.StackVertical, landscape, types.StackVertical},
+		{"landscape keeps horizontal", types.StackHorizontal, landscape, types.StackHorizontal},
+		{"portrait flips vertical", types.StackVertical, portrait, types.StackHorizontal},
+		{"portrait flips unset", "", portrait, types.StackHorizontal},
+		{"portrait flips horizontal", types.StackHorizontal, portrait, types.StackVertical},
+		{"portrait keeps tabs", types.StackTabs, portrait, types.StackTabs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OrientedStackMode(tt.mode, tt.display); got != tt.want {
+				t.Errorf("OrientedStackMode(%q) = %q, want %q", tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateAllWindowPlacements_PortraitDefault(t *testing.T) {
+	display := types.Rect{X: 0, Y: 0, Width: 1000, Height: 2000}
+	calculatedLayout := &types.CalculatedLayout{
+		LayoutID: "test",
+		CellBounds: map[string]types.Rect{
+			"top":    {X: 0, Y: 0, Width: 1000, Height: 1000},
+			"bottom": {X: 0, Y: 1000, Width: 1000, Height: 1000},
+		},
+	}
+	assignments := map[string][]uint32{
+		"top":    {1, 2},
+		"bottom": {3, 4},
+	}
+	// Explicit per-cell mode wins over the orientation-adjusted default
+	cellModes := map[string]types.StackMode{
+		"bottom": types.StackVertical,
+	}
+
+	placements := CalculateAllWindowPlacements(
+		calculatedLayout,
+		assignments,
+		cellModes,
+		nil,
+		OrientedStackMode(types.StackVertical, display),
+		0,
+	)
+
+	bounds := make(map[uint32]types.Rect)
+	for _, p := range placements {
+		bounds[p.WindowID] = p.Bounds
+	}
+
+	// Default flipped to horizontal: windows side by side
+	if bounds[1].Width != 500 || bounds[2].X != 500 {
+		t.Errorf("top cell should stack horizontally, got %+v and %+v", bounds[1], bounds[2])
+	}
+	// Explicit vertical mode respected: windows top to bottom
+	if bounds[3].Height != 500 || bounds[4].Y != 1500 {
+		t.Errorf("bottom cell should stack vertically, got %+v and %+v", bounds[3], bounds[4])
+	}
+}
@@ -0,0 +1,80 @@
+package layout
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// TabFronts returns the frontmost window (see CellState.Frontmost) of each
+// tabbed cell holding more than one window, in cell ID order. Cells missing
+// from cellModes use defaultMode.
+func TabFronts(spaceState *state.SpaceState, cellModes map[string]types.StackMode, defaultMode types.StackMode) []uint32 {
+	cellIDs := make([]string, 0, len(spaceState.Cells))
+	for cellID, cell := range spaceState.Cells {
+		mode, ok := cellModes[cellID]
+		if !ok {
+			mode = defaultMode
+		}
+		if mode == types.StackTabs && len(cell.Windows) > 1 {
+			cellIDs = append(cellIDs, cellID)
+		}
+	}
+	sort.Strings(cellIDs)
+
+	fronts := make([]uint32, 0, len(cellIDs))
+	for _, cellID := range cellIDs {
+		fronts = append(fronts, spaceState.Cells[cellID].Frontmost())
+	}
+	return fronts
+}
+
+// movedCellFronts keeps the front windows (from TabFronts) of cells that had
+// a window among placements
+func movedCellFronts(spaceState *state.SpaceState, fronts []uint32, placements []types.WindowPlacement) []uint32 {
+	moved := make(map[string]bool, len(placements))
+	for _, p := range placements {
+		if cellID := spaceState.GetWindowCell(p.WindowID); cellID != "" {
+			moved[cellID] = true
+		}
+	}
+	kept := make([]uint32, 0, len(fronts))
+	for _, windowID := range fronts {
+		if moved[spaceState.GetWindowCell(windowID)] {
+			kept = append(kept, windowID)
+		}
+	}
+	return kept
+}
+
+// RaiseWindows brings windows in front of the windows they overlap. The
+// server raises a window by focusing it, so focusedWindow (if any) is
+// focused again last and keeps focus. Failures are logged, not returned:
+// a window left behind its tab siblings is still placed correctly.
+func RaiseWindows(ctx context.Context, c *client.Client, windows []uint32, focusedWindow uint32) {
+	raised := false
+	for _, windowID := range windows {
+		if windowID == focusedWindow {
+			continue
+		}
+		if _, err := c.CallMethod(ctx, "window.focus", map[string]interface{}{
+			"windowId": windowID,
+		}); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to raise window")
+			continue
+		}
+		raised = true
+	}
+
+	if raised && focusedWindow != 0 {
+		if _, err := c.CallMethod(ctx, "window.focus", map[string]interface{}{
+			"windowId": focusedWindow,
+		}); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", focusedWindow).Msg("failed to restore focus after raising windows")
+		}
+	}
+}
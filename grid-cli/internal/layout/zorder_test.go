@@ -0,0 +1,165 @@
+package layout
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestTabFronts(t *testing.T) {
+	space := state.NewSpaceState("1")
+	space.AssignWindow(1, "a")
+	space.AssignWindow(2, "a")
+	space.AssignWindow(3, "b")
+	space.AssignWindow(4, "b")
+	space.AssignWindow(5, "c")
+	space.SetFocus("a", 0)
+
+	// b stacks vertically and c holds a single window: neither has tabs to raise
+	cellModes := map[string]types.StackMode{"b": types.StackVertical}
+	if got := TabFronts(space, cellModes, types.StackTabs); !reflect.DeepEqual(got, []uint32{1}) {
+		t.Errorf("TabFronts = %v, want [1]", got)
+	}
+}
+
+func TestReapplyLayout_RaisesFrontTab(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ss := &stickyServer{}
+	c := startStickyServer(t, ss)
+
+	// Tabs 20 and 21 share cell a; 22 is alone in b
+	bounds := types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		Windows: []server.WindowInfo{
+			{ID: 20, AppName: "Safari", SpaceCount: 1},
+			{ID: 21, AppName: "Safari", SpaceCount: 1},
+			{ID: 22, AppName: "Notes", SpaceCount: 1},
+		},
+		WindowIDs:       map[uint32]bool{20: true, 21: true, 22: true},
+		FocusedWindowID: 22,
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(20, "a")
+	space.AssignWindow(21, "a")
+	space.AssignWindow(22, "b")
+	space.Cells["a"].StackMode = types.StackTabs
+
+	// Viewing tab 20 puts it in front, then focus moves on to b
+	space.SetFocus("a", 0)
+	space.SetFocus("b", 0)
+
+	opts := DefaultApplyOptions()
+	opts.Strategy = types.AssignPreserve
+	if err := ReapplyLayout(context.Background(), c, snap, fullLayoutConfig(), rs, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// 20 is raised over 21, then focus goes back to 22, the OS-focused window
+	focused := ss.focused()
+	if !reflect.DeepEqual(focused, []string{"20", "22"}) {
+		t.Errorf("focused %v, want [20 22]", focused)
+	}
+	if got := rs.GetSpace("1").Cells["a"].Frontmost(); got != 20 {
+		t.Errorf("Frontmost = %d, want 20 after reapply", got)
+	}
+}
+
+func TestReapplyLayout_KeepsCellStackMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := startStickyServer(t, &stickyServer{})
+
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055},
+		Windows: []server.WindowInfo{
+			{ID: 20, AppName: "Safari", SpaceCount: 1},
+			{ID: 21, AppName: "Safari", SpaceCount: 1},
+		},
+		WindowIDs: map[uint32]bool{20: true, 21: true},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(20, "a")
+	space.AssignWindow(21, "a")
+	space.Cells["a"].StackMode = types.StackTabs
+
+	opts := DefaultApplyOptions()
+	opts.Strategy = types.AssignPreserve
+	for i := 0; i < 2; i++ {
+		if err := ReapplyLayout(context.Background(), c, snap, fullLayoutConfig(), rs, opts); err != nil {
+			t.Fatal(err)
+		}
+		if mode := rs.GetSpace("1").Cells["a"].StackMode; mode != types.StackTabs {
+			t.Fatalf("reapply %d: StackMode = %q, want tabs", i+1, mode)
+		}
+	}
+}
+
+func TestReapplyLayout_KeepsSecondTabInFront(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ss := &stickyServer{}
+	c := startStickyServer(t, ss)
+
+	bounds := types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		Windows: []server.WindowInfo{
+			{ID: 20, AppName: "Safari", SpaceCount: 1},
+			{ID: 21, AppName: "Safari", SpaceCount: 1},
+			{ID: 22, AppName: "Notes", SpaceCount: 1},
+		},
+		WindowIDs: map[uint32]bool{20: true, 21: true, 22: true},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(20, "a")
+	space.AssignWindow(21, "a")
+	space.AssignWindow(22, "b")
+	space.Cells["a"].StackMode = types.StackTabs
+
+	// Tab 21, second in the stack, is the one in front
+	space.SetFocus("a", 1)
+	space.SetFocus("b", 0)
+
+	opts := DefaultApplyOptions()
+	opts.Strategy = types.AssignPreserve
+	if err := ReapplyLayout(context.Background(), c, snap, fullLayoutConfig(), rs, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rs.GetSpace("1").Cells["a"].Frontmost(); got != 21 {
+		t.Errorf("Frontmost = %d, want 21 after reapply", got)
+	}
+	focused := ss.focused()
+	if len(focused) == 0 || focused[0] != "21" {
+		t.Errorf("focused %v, want 21 raised first", focused)
+	}
+}
+
+func TestMovedCellFronts(t *testing.T) {
+	space := state.NewSpaceState("1")
+	space.AssignWindow(20, "a")
+	space.AssignWindow(21, "a")
+	space.AssignWindow(30, "b")
+	space.AssignWindow(31, "b")
+
+	// Only b's windows moved, so a's front window is still in front
+	placements := []types.WindowPlacement{{WindowID: 31}}
+	if got := movedCellFronts(space, []uint32{20, 30}, placements); !reflect.DeepEqual(got, []uint32{30}) {
+		t.Errorf("movedCellFronts = %v, want [30]", got)
+	}
+	if got := movedCellFronts(space, []uint32{20, 30}, nil); len(got) != 0 {
+		t.Errorf("movedCellFronts = %v, want none when nothing moved", got)
+	}
+}
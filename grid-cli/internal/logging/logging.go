@@ -11,6 +11,9 @@ import (
 var (
 	Logger  zerolog.Logger
 	logFile *os.File
+
+	// defaultLevel is the level Init sets, which SetDebug(false) goes back to
+	defaultLevel = zerolog.InfoLevel
 )
 
 // timestampHook adds timestamp at the end of each log event
@@ -33,7 +36,8 @@ func Init() error {
 	logFile = f
 
 	// Set global level to Debug
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	defaultLevel = zerolog.DebugLevel
+	zerolog.SetGlobalLevel(defaultLevel)
 
 	// Configure field names
 	zerolog.MessageFieldName = "msg"
@@ -71,11 +75,12 @@ func Error() *zerolog.Event {
 	return Logger.Error()
 }
 
-// SetDebug enables or disables debug level logging
+// SetDebug enables debug level logging, or with false returns to the level
+// Init set, so it can be called on every run without lowering the default
 func SetDebug(enabled bool) {
 	if enabled {
 		zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	} else {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+		zerolog.SetGlobalLevel(defaultLevel)
 	}
 }
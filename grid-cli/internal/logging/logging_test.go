@@ -0,0 +1,21 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSetDebug_RestoresInitLevel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	SetDebug(true)
+	SetDebug(false)
+	if got := zerolog.GlobalLevel(); got != zerolog.DebugLevel {
+		t.Errorf("level after SetDebug(false) = %v, want Init's %v", got, zerolog.DebugLevel)
+	}
+}
@@ -0,0 +1,185 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field resolves a dotted path (e.g. "frame.width", "metadata.key", "spaces.0")
+// against the window's JSON representation. The raw [[x, y], [w, h]] frame
+// is exposed as an object with x, y, width and height keys.
+// Returns false if any path segment doesn't exist.
+func (w *Window) Field(path string) (interface{}, bool) {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, false
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, false
+	}
+
+	root["frame"] = map[string]interface{}{
+		"x":      w.GetX(),
+		"y":      w.GetY(),
+		"width":  w.GetWidth(),
+		"height": w.GetHeight(),
+	}
+
+	return resolvePath(root, path)
+}
+
+// resolvePath walks a dotted path through nested maps and slices.
+func resolvePath(value interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			value = v[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// FormatFieldValue renders a resolved field for shell use: strings and
+// numbers are printed raw, nested values as compact JSON.
+func FormatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+// FindWindows returns the windows whose title or app name contains pattern
+// (case-insensitive), ordered by ID. With spaceIDs set, only windows on at
+// least one of those spaces are kept.
+func (s *State) FindWindows(pattern string, spaceIDs map[string]bool) []*Window {
+	pattern = strings.ToLower(pattern)
+
+	var matches []*Window
+	for _, win := range s.Windows {
+		title := ""
+		if win.Title != nil {
+			title = *win.Title
+		}
+		appName := ""
+		if win.AppName != nil {
+			appName = *win.AppName
+		}
+		if !strings.Contains(strings.ToLower(title), pattern) &&
+			!strings.Contains(strings.ToLower(appName), pattern) {
+			continue
+		}
+		if spaceIDs != nil && !onAnySpace(win, spaceIDs) {
+			continue
+		}
+		matches = append(matches, win)
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
+}
+
+// onAnySpace reports whether the window is on one of spaceIDs
+func onAnySpace(win *Window, spaceIDs map[string]bool) bool {
+	for _, id := range win.GetSpaceIDs() {
+		if spaceIDs[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// WindowFilter narrows a window list by app name and title (case-insensitive
+// substrings) and by space. Empty fields match every window; set fields must
+// all match.
+type WindowFilter struct {
+	App   string
+	Title string
+	Space string
+}
+
+// IsZero reports whether the filter has no conditions
+func (f WindowFilter) IsZero() bool {
+	return f == WindowFilter{}
+}
+
+// Match reports whether the window satisfies every condition of the filter
+func (f WindowFilter) Match(win *Window) bool {
+	if f.App != "" && (win.AppName == nil || !containsFold(*win.AppName, f.App)) {
+		return false
+	}
+	if f.Title != "" && (win.Title == nil || !containsFold(*win.Title, f.Title)) {
+		return false
+	}
+	if f.Space != "" && !onAnySpace(win, map[string]bool{f.Space: true}) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the windows matching the filter, in their original order
+func (f WindowFilter) Apply(windows []*Window) []*Window {
+	if f.IsZero() {
+		return windows
+	}
+	matches := make([]*Window, 0, len(windows))
+	for _, win := range windows {
+		if f.Match(win) {
+			matches = append(matches, win)
+		}
+	}
+	return matches
+}
+
+// String describes the filter's conditions, e.g. `app "code", space 3`
+func (f WindowFilter) String() string {
+	var parts []string
+	if f.App != "" {
+		parts = append(parts, fmt.Sprintf("app %q", f.App))
+	}
+	if f.Title != "" {
+		parts = append(parts, fmt.Sprintf("title %q", f.Title))
+	}
+	if f.Space != "" {
+		parts = append(parts, "space "+f.Space)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// containsFold reports whether substr is in s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowField(t *testing.T) {
+	title := "README.md"
+	appName := "Code"
+	w := &Window{
+		ID:      42,
+		Title:   &title,
+		AppName: &appName,
+		Frame:   [][]interface{}{{100.0, 50.0}, {1280.0, 720.0}},
+		Spaces:  []interface{}{3.0},
+		Level:   0.0,
+		Metadata: map[string]interface{}{
+			"bundleId": "com.microsoft.VSCode",
+		},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"id", "42"},
+		{"appName", "Code"},
+		{"title", "README.md"},
+		{"level", "0"},
+		{"frame.x", "100"},
+		{"frame.width", "1280"},
+		{"frame.height", "720"},
+		{"spaces.0", "3"},
+		{"metadata.bundleId", "com.microsoft.VSCode"},
+		{"isMinimized", "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := w.Field(tt.path)
+			if !ok {
+				t.Fatalf("field %q not found", tt.path)
+			}
+			if s := FormatFieldValue(got); s != tt.want {
+				t.Errorf("field %q = %q, want %q", tt.path, s, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowField_Missing(t *testing.T) {
+	w := &Window{ID: 1, Frame: [][]interface{}{{0.0, 0.0}, {10.0, 10.0}}}
+
+	for _, path := range []string{"nope", "frame.depth", "id.value", "spaces.5"} {
+		if _, ok := w.Field(path); ok {
+			t.Errorf("expected %q to be missing", path)
+		}
+	}
+}
+
+func TestWindowField_Object(t *testing.T) {
+	w := &Window{ID: 1, Frame: [][]interface{}{{1.0, 2.0}, {3.0, 4.0}}}
+
+	got, ok := w.Field("frame")
+	if !ok {
+		t.Fatal("frame not found")
+	}
+	want := `{"height":4,"width":3,"x":1,"y":2}`
+	if s := FormatFieldValue(got); s != want {
+		t.Errorf("frame = %s, want %s", s, want)
+	}
+}
+
+func findTestState() *State {
+	str := func(s string) *string { return &s }
+	return &State{
+		Windows: map[string]*Window{
+			"1": {ID: 1, Title: str("notes.md"), AppName: str("Code"), Spaces: []interface{}{1.0}},
+			"2": {ID: 2, Title: str("main.go"), AppName: str("Code"), Spaces: []interface{}{4.0}},
+			"3": {ID: 3, Title: str("Inbox"), AppName: str("Mail"), Spaces: []interface{}{2.0}},
+			"4": {ID: 4, Title: str("scratch"), AppName: str("Code"), Spaces: []interface{}{2.0, 4.0}},
+		},
+		Displays: []*Display{
+			{UUID: "main", Spaces: []interface{}{1.0, 2.0}},
+			{UUID: "side", Spaces: []interface{}{4.0}},
+		},
+	}
+}
+
+func windowIDs(windows []*Window) []int {
+	ids := make([]int, 0, len(windows))
+	for _, w := range windows {
+		ids = append(ids, w.ID)
+	}
+	return ids
+}
+
+func TestFindWindows_DisplayFilter(t *testing.T) {
+	state := findTestState()
+
+	if got := windowIDs(state.FindWindows("code", nil)); !reflect.DeepEqual(got, []int{1, 2, 4}) {
+		t.Errorf("unfiltered matches = %v, want [1 2 4]", got)
+	}
+
+	display, err := state.FindDisplay("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spaces := make(map[string]bool)
+	for _, id := range display.GetSpaceIDs() {
+		spaces[id] = true
+	}
+	// Window 2 matches but is on the side display; 4 is on both
+	if got := windowIDs(state.FindWindows("code", spaces)); !reflect.DeepEqual(got, []int{1, 4}) {
+		t.Errorf("matches on main = %v, want [1 4]", got)
+	}
+
+	if got := windowIDs(state.FindWindows("code", map[string]bool{"4": true})); !reflect.DeepEqual(got, []int{2, 4}) {
+		t.Errorf("matches on space 4 = %v, want [2 4]", got)
+	}
+}
+
+func TestWindowFilter(t *testing.T) {
+	state := findTestState()
+	windows := []*Window{state.Windows["1"], state.Windows["2"], state.Windows["3"], state.Windows["4"]}
+
+	tests := []struct {
+		filter WindowFilter
+		want   []int
+	}{
+		{WindowFilter{}, []int{1, 2, 3, 4}},
+		{WindowFilter{App: "code"}, []int{1, 2, 4}},
+		{WindowFilter{Title: "IN"}, []int{2, 3}},
+		{WindowFilter{App: "code", Space: "4"}, []int{2, 4}},
+		{WindowFilter{App: "code", Title: "main", Space: "4"}, []int{2}},
+		{WindowFilter{App: "mail", Space: "1"}, []int{}},
+	}
+	for _, tt := range tests {
+		if got := windowIDs(tt.filter.Apply(windows)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: got %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	f := WindowFilter{App: "code", Title: "main", Space: "4"}
+	if got, want := f.String(), `app "code", title "main", space 4`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFindDisplay(t *testing.T) {
+	state := findTestState()
+
+	if d, err := state.FindDisplay("1"); err != nil || d.UUID != "side" {
+		t.Errorf("FindDisplay(1) = %v, %v; want side", d, err)
+	}
+	if _, err := state.FindDisplay("2"); err == nil {
+		t.Error("expected an error for an out-of-range index")
+	}
+	if _, err := state.FindDisplay("missing"); err == nil {
+		t.Error("expected an error for an unknown UUID")
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -99,6 +100,25 @@ func (w *Window) GetPrimarySpace() string {
 	return "-"
 }
 
+// GetSpaceIDs returns every space ID the window is on, formatted like
+// GetPrimarySpace
+func (w *Window) GetSpaceIDs() []string {
+	ids := make([]string, 0, len(w.Spaces))
+	for _, s := range w.Spaces {
+		switch v := s.(type) {
+		case int:
+			ids = append(ids, fmt.Sprintf("%d", v))
+		case float64:
+			ids = append(ids, fmt.Sprintf("%.0f", v))
+		case bool:
+			ids = append(ids, "large")
+		default:
+			ids = append(ids, fmt.Sprintf("%v", v))
+		}
+	}
+	return ids
+}
+
 // FormatFrame returns a formatted string representation of the window frame
 func (w *Window) FormatFrame() string {
 	return fmt.Sprintf("%.0fx%.0f @ (%.0f, %.0f)", w.GetWidth(), w.GetHeight(), w.GetX(), w.GetY())
@@ -319,6 +339,23 @@ func (s *State) FindWindowByID(id int) *Window {
 	return s.Windows[fmt.Sprintf("%d", id)]
 }
 
+// FindDisplay finds a display by index (as in `list displays`) or UUID
+func (s *State) FindDisplay(ref string) (*Display, error) {
+	if idx, err := strconv.Atoi(ref); err == nil {
+		if idx < 0 || idx >= len(s.Displays) {
+			return nil, fmt.Errorf("display index %d out of range (have %d displays)", idx, len(s.Displays))
+		}
+		return s.Displays[idx], nil
+	}
+
+	for _, d := range s.Displays {
+		if d.UUID == ref {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("display not found: %s", ref)
+}
+
 // FindApplicationByPID finds an application by its PID
 func (s *State) FindApplicationByPID(pid int) *Application {
 	return s.Applications[fmt.Sprintf("%d", pid)]
@@ -0,0 +1,224 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/yourusername/grid-cli/internal/models"
+)
+
+const (
+	// imageDisplayWidth is the width in image pixels each display is drawn at
+	imageDisplayWidth = 960.0
+
+	// imageMargin is the space around and between display panels
+	imageMargin = 20.0
+
+	// imageTitleHeight is the space above each display for its title
+	imageTitleHeight = 24.0
+)
+
+// imageWindow is a window rectangle in panel coordinates
+type imageWindow struct {
+	ID                  int
+	X, Y, Width, Height float64
+	Label               string
+}
+
+// imagePanel is one display drawn at image scale
+type imagePanel struct {
+	Title         string
+	Width, Height float64
+	Windows       []imageWindow
+}
+
+// NewImageScalingContext creates a scaling context that maps display pixels
+// to image pixels at the given width, keeping the display's aspect ratio.
+func NewImageScalingContext(display *models.Display, width float64) *ScalingContext {
+	pixelWidth, pixelHeight := 1920.0, 1080.0
+	if display.PixelWidth != nil && display.PixelHeight != nil {
+		pixelWidth = float64(*display.PixelWidth)
+		pixelHeight = float64(*display.PixelHeight)
+	}
+
+	scale := width / pixelWidth
+	return &ScalingContext{
+		MaxX:        pixelWidth,
+		MaxY:        pixelHeight,
+		PixelWidth:  pixelWidth,
+		PixelHeight: pixelHeight,
+		TermWidth:   int(width),
+		TermHeight:  int(pixelHeight * scale),
+		ScaleX:      scale,
+		ScaleY:      scale,
+		AspectRatio: 1.0,
+	}
+}
+
+// PixelToImage converts a display rectangle to image coordinates, clipped
+// to the display. ok is false if nothing of the rectangle is visible.
+func (sc *ScalingContext) PixelToImage(x, y, w, h float64) (ix, iy, iw, ih float64, ok bool) {
+	left := max(x, sc.MinX)
+	top := max(y, sc.MinY)
+	right := min(x+w, sc.MaxX)
+	bottom := min(y+h, sc.MaxY)
+	if right <= left || bottom <= top {
+		return 0, 0, 0, 0, false
+	}
+
+	return (left - sc.MinX) * sc.ScaleX, (top - sc.MinY) * sc.ScaleY,
+		(right - left) * sc.ScaleX, (bottom - top) * sc.ScaleY, true
+}
+
+// buildImagePanels lays out the requested display (or all if displayIndex < 0)
+func buildImagePanels(state *models.State, displayIndex int) ([]imagePanel, error) {
+	if displayIndex >= len(state.Displays) {
+		return nil, fmt.Errorf("display index %d out of range (have %d displays)", displayIndex, len(state.Displays))
+	}
+
+	var panels []imagePanel
+	for i, display := range state.Displays {
+		if displayIndex >= 0 && i != displayIndex {
+			continue
+		}
+
+		sc := NewImageScalingContext(display, imageDisplayWidth)
+		panel := imagePanel{
+			Title: fmt.Sprintf("Display %d: %s [%s] (Space %s active)",
+				i, display.GetDisplayName(), display.GetResolutionString(), display.GetCurrentSpaceIDString()),
+			Width:  float64(sc.TermWidth),
+			Height: float64(sc.TermHeight),
+		}
+
+		for _, win := range sortWindowsByLevel(getWindowsForDisplay(state, display)) {
+			if win.IsMinimized {
+				continue
+			}
+			x, y, w, h, ok := sc.PixelToImage(win.GetX(), win.GetY(), win.GetWidth(), win.GetHeight())
+			if !ok {
+				continue
+			}
+			panel.Windows = append(panel.Windows, imageWindow{
+				ID: win.ID, X: x, Y: y, Width: w, Height: h,
+				Label: createWindowLabel(win, true),
+			})
+		}
+		panels = append(panels, panel)
+	}
+
+	return panels, nil
+}
+
+// imageSize returns the total image size for panels stacked vertically
+func imageSize(panels []imagePanel) (width, height float64) {
+	height = imageMargin
+	for _, p := range panels {
+		width = max(width, p.Width)
+		height += imageTitleHeight + p.Height + imageMargin
+	}
+	return width + 2*imageMargin, height
+}
+
+// WriteSVG renders the layout of one display (or all if displayIndex < 0)
+// as an SVG image with one rect per window.
+func WriteSVG(w io.Writer, state *models.State, displayIndex int) error {
+	panels, err := buildImagePanels(state, displayIndex)
+	if err != nil {
+		return err
+	}
+
+	width, height := imageSize(panels)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+
+	y := imageMargin
+	for _, p := range panels {
+		fmt.Fprintf(&b, `<g transform="translate(%.0f,%.0f)">`+"\n", imageMargin, y)
+		fmt.Fprintf(&b, `<text x="0" y="16" font-weight="bold">%s</text>`+"\n", escapeXML(p.Title))
+		fmt.Fprintf(&b, `<rect class="display" x="0" y="%.0f" width="%.1f" height="%.1f" fill="#f4f4f4" stroke="#333333"/>`+"\n",
+			imageTitleHeight, p.Width, p.Height)
+		for i, win := range p.Windows {
+			wy := imageTitleHeight + win.Y
+			fmt.Fprintf(&b, `<rect class="window" data-id="%d" x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.6" stroke="#1f4e79"/>`+"\n",
+				win.ID, win.X, wy, win.Width, win.Height, windowColorHex(i))
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", win.X+4, wy+16, escapeXML(win.Label))
+		}
+		b.WriteString("</g>\n")
+		y += imageTitleHeight + p.Height + imageMargin
+	}
+	b.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// WritePNG renders the layout like WriteSVG as a PNG image. PNG output has
+// no text; use SVG when labels are needed.
+func WritePNG(w io.Writer, state *models.State, displayIndex int) error {
+	panels, err := buildImagePanels(state, displayIndex)
+	if err != nil {
+		return err
+	}
+
+	width, height := imageSize(panels)
+	img := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	y := imageMargin + imageTitleHeight
+	for _, p := range panels {
+		fillRect(img, imageMargin, y, p.Width, p.Height, color.RGBA{0xf4, 0xf4, 0xf4, 0xff})
+		strokeRect(img, imageMargin, y, p.Width, p.Height, color.RGBA{0x33, 0x33, 0x33, 0xff})
+		for i, win := range p.Windows {
+			fillRect(img, imageMargin+win.X, y+win.Y, win.Width, win.Height, windowColor(i))
+			strokeRect(img, imageMargin+win.X, y+win.Y, win.Width, win.Height, color.RGBA{0x1f, 0x4e, 0x79, 0xff})
+		}
+		y += p.Height + imageMargin + imageTitleHeight
+	}
+
+	return png.Encode(w, img)
+}
+
+// windowPalette cycles fill colors so overlapping windows stay distinguishable
+var windowPalette = []color.RGBA{
+	{0x9d, 0xc3, 0xe6, 0xff},
+	{0xa9, 0xd1, 0x8e, 0xff},
+	{0xf4, 0xb1, 0x83, 0xff},
+	{0xff, 0xd9, 0x66, 0xff},
+	{0xc9, 0xa0, 0xdc, 0xff},
+}
+
+func windowColor(i int) color.RGBA {
+	return windowPalette[i%len(windowPalette)]
+}
+
+func windowColorHex(i int) string {
+	c := windowColor(i)
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func fillRect(img *image.RGBA, x, y, w, h float64, c color.Color) {
+	r := image.Rect(int(x), int(y), int(x+w), int(y+h))
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func strokeRect(img *image.RGBA, x, y, w, h float64, c color.Color) {
+	fillRect(img, x, y, w, 1, c)
+	fillRect(img, x, y+h-1, w, 1, c)
+	fillRect(img, x, y, 1, h, c)
+	fillRect(img, x+w-1, y, 1, h, c)
+}
+
+// escapeXML escapes text for use in SVG element content
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/models"
+)
+
+func imageTestState() *models.State {
+	width, height := 2000, 1000
+	app := func(name string) *string { return &name }
+	win := func(id int, name string, x, y, w, h float64, minimized bool) *models.Window {
+		return &models.Window{
+			ID:          id,
+			AppName:     app(name),
+			Frame:       [][]interface{}{{x, y}, {w, h}},
+			Spaces:      []interface{}{1},
+			IsMinimized: minimized,
+		}
+	}
+
+	return &models.State{
+		Displays: []*models.Display{{
+			UUID:           "main",
+			Spaces:         []interface{}{1},
+			CurrentSpaceID: 1,
+			PixelWidth:     &width,
+			PixelHeight:    &height,
+		}},
+		Windows: map[string]*models.Window{
+			"1": win(1, "Terminal", 0, 0, 1000, 1000, false),
+			"2": win(2, "<Tom & Jerry>", 1000, 0, 1000, 500, false),
+			"3": win(3, "Editor", 1000, 500, 1000, 500, false),
+			"4": win(4, "Hidden", 0, 0, 500, 500, true),
+		},
+	}
+}
+
+func TestWriteSVG_OneRectPerWindow(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, imageTestState(), -1); err != nil {
+		t.Fatalf("WriteSVG failed: %v", err)
+	}
+
+	dec := xml.NewDecoder(&buf)
+	windowRects := 0
+	root := ""
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed XML: %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root == "" {
+			root = start.Name.Local
+		}
+		if start.Name.Local != "rect" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "class" && attr.Value == "window" {
+				windowRects++
+			}
+		}
+	}
+
+	if root != "svg" {
+		t.Errorf("root element = %q, want svg", root)
+	}
+	// Minimized windows are not drawn
+	if windowRects != 3 {
+		t.Errorf("window rects = %d, want 3", windowRects)
+	}
+}
+
+func TestWriteSVG_DisplayOutOfRange(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, imageTestState(), 1); err == nil {
+		t.Error("expected error for display index out of range")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, imageTestState(), 0); err != nil {
+		t.Fatalf("WritePNG failed: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("PNG does not decode: %v", err)
+	}
+
+	// 2000x1000 display drawn 960 wide keeps its 2:1 aspect ratio
+	want := int(imageDisplayWidth + 2*imageMargin)
+	if got := img.Bounds().Dx(); got != want {
+		t.Errorf("width = %d, want %d", got, want)
+	}
+	wantHeight := int(2*imageMargin + imageTitleHeight + imageDisplayWidth/2)
+	if got := img.Bounds().Dy(); got != wantHeight {
+		t.Errorf("height = %d, want %d", got, wantHeight)
+	}
+}
+
+func TestPixelToImage_ClipsToDisplay(t *testing.T) {
+	width, height := 2000, 1000
+	sc := NewImageScalingContext(&models.Display{PixelWidth: &width, PixelHeight: &height}, 1000)
+
+	x, y, w, h, ok := sc.PixelToImage(1800, -100, 400, 300)
+	if !ok {
+		t.Fatal("expected a visible rectangle")
+	}
+	if x != 900 || y != 0 || w != 100 || h != 100 {
+		t.Errorf("got (%v, %v, %v, %v), want (900, 0, 100, 100)", x, y, w, h)
+	}
+
+	if _, _, _, _, ok := sc.PixelToImage(2500, 0, 100, 100); ok {
+		t.Error("expected off-display rectangle to be skipped")
+	}
+}
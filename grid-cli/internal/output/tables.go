@@ -2,88 +2,127 @@ package output
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/olekukonko/tablewriter"
 	"github.com/yourusername/grid-cli/internal/models"
+	"golang.org/x/sys/unix"
 )
 
+// TableOptions controls how tables are fitted to the terminal
+type TableOptions struct {
+	Out   io.Writer // Where to render (nil = stdout)
+	Width int       // Terminal width in columns (0 = detect from Out; no truncation if it isn't a terminal)
+	Wide  bool      // Never truncate
+}
+
+// tableColumn describes a table column and how it may be truncated
+type tableColumn struct {
+	header string
+	limit  int // Default maximum width (0 = none)
+	shrink int // Order in which the column gives up width to fit (0 = never)
+}
+
+// minColumnWidth is the narrowest a shrinking column gets ("ab...")
+const minColumnWidth = 5
+
 // PrintWindowsTable prints windows in a table format
-func PrintWindowsTable(windows []*models.Window) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("ID", "Title", "App", "Space", "Size", "Minimized")
+func PrintWindowsTable(windows []*models.Window, opts TableOptions) {
+	columns := []tableColumn{
+		{header: "ID"},
+		{header: "Title", limit: 30, shrink: 1},
+		{header: "App", limit: 20, shrink: 2},
+		{header: "Space"},
+		{header: "Size"},
+		{header: "Minimized"},
+	}
 
 	// Sort by ID
 	sort.Slice(windows, func(i, j int) bool {
 		return windows[i].ID < windows[j].ID
 	})
 
+	rows := make([][]string, 0, len(windows))
 	for _, win := range windows {
 		minimized := ""
 		if win.IsMinimized {
-			minimized = ""
+			minimized = "yes"
 		}
 
 		spaces := formatIntSlice(win.Spaces)
 		title := ""
 		if win.Title != nil {
-			title = truncate(*win.Title, 30)
+			title = *win.Title
 		}
 		appName := ""
 		if win.AppName != nil {
-			appName = truncate(*win.AppName, 20)
+			appName = *win.AppName
 		}
 		size := fmt.Sprintf("%.0fx%.0f", win.GetWidth(), win.GetHeight())
 
-		table.Append(
+		rows = append(rows, []string{
 			fmt.Sprintf("%d", win.ID),
 			title,
 			appName,
 			spaces,
 			size,
 			minimized,
-		)
+		})
 	}
 
-	table.Render()
+	renderTable(columns, rows, opts)
 }
 
 // PrintSpacesTable prints spaces in a table format
-func PrintSpacesTable(spaces []*models.Space) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("ID", "UUID", "Type", "Display", "Active", "Windows")
+func PrintSpacesTable(spaces []*models.Space, opts TableOptions) {
+	columns := []tableColumn{
+		{header: "ID"},
+		{header: "UUID", limit: 12, shrink: 1},
+		{header: "Type"},
+		{header: "Display", limit: 12, shrink: 2},
+		{header: "Active"},
+		{header: "Windows"},
+	}
 
+	rows := make([][]string, 0, len(spaces))
 	for _, space := range spaces {
 		active := ""
 		if space.IsActive {
-			active = ""
+			active = "yes"
 		}
 
-		uuid := truncate(space.UUID, 12)
-		displayUUID := truncate(space.DisplayUUID, 12)
-
-		table.Append(
+		rows = append(rows, []string{
 			space.GetIDString(),
-			uuid,
+			space.UUID,
 			space.Type,
-			displayUUID,
+			space.DisplayUUID,
 			active,
 			fmt.Sprintf("%d", space.GetWindowCount()),
-		)
+		})
 	}
 
-	table.Render()
+	renderTable(columns, rows, opts)
 }
 
 // PrintDisplaysTable prints displays in a table format
-func PrintDisplaysTable(displays []*models.Display) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("Name", "ID", "Resolution", "Scale", "Type", "Refresh", "Spaces")
+func PrintDisplaysTable(displays []*models.Display, opts TableOptions) {
+	columns := []tableColumn{
+		{header: "Name", limit: 25, shrink: 1},
+		{header: "ID"},
+		{header: "Resolution"},
+		{header: "Scale"},
+		{header: "Type"},
+		{header: "Refresh"},
+		{header: "Spaces", shrink: 2},
+	}
 
+	rows := make([][]string, 0, len(displays))
 	for _, display := range displays {
-		name := truncate(display.GetDisplayName(), 25)
+		name := display.GetDisplayName()
 		displayID := display.GetDisplayIDString()
 		resolution := display.GetResolutionString()
 		scale := display.GetScaleString()
@@ -101,7 +140,7 @@ func PrintDisplaysTable(displays []*models.Display) {
 		refresh := display.GetRefreshRateString()
 		spaces := strings.Join(display.GetSpaceIDs(), ", ")
 
-		table.Append(
+		rows = append(rows, []string{
 			name,
 			displayID,
 			resolution,
@@ -109,48 +148,155 @@ func PrintDisplaysTable(displays []*models.Display) {
 			typeIndicator,
 			refresh,
 			spaces,
-		)
+		})
 	}
 
-	table.Render()
+	renderTable(columns, rows, opts)
 }
 
 // PrintApplicationsTable prints applications in a table format
-func PrintApplicationsTable(apps []*models.Application) {
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("PID", "Name", "Bundle ID", "Active", "Hidden", "Windows")
+func PrintApplicationsTable(apps []*models.Application, opts TableOptions) {
+	columns := []tableColumn{
+		{header: "PID"},
+		{header: "Name", limit: 25, shrink: 2},
+		{header: "Bundle ID", limit: 35, shrink: 1},
+		{header: "Active"},
+		{header: "Hidden"},
+		{header: "Windows"},
+	}
 
 	// Sort by name
 	sort.Slice(apps, func(i, j int) bool {
 		return apps[i].LocalizedName < apps[j].LocalizedName
 	})
 
+	rows := make([][]string, 0, len(apps))
 	for _, app := range apps {
 		active := ""
 		if app.IsActive {
-			active = ""
+			active = "yes"
 		}
 		hidden := ""
 		if app.IsHidden {
-			hidden = ""
+			hidden = "yes"
 		}
 
-		name := truncate(app.LocalizedName, 25)
-		bundleID := truncate(app.BundleIdentifier, 35)
-
-		table.Append(
+		rows = append(rows, []string{
 			fmt.Sprintf("%d", app.PID),
-			name,
-			bundleID,
+			app.LocalizedName,
+			app.BundleIdentifier,
 			active,
 			hidden,
 			fmt.Sprintf("%d", app.GetWindowCount()),
-		)
+		})
+	}
+
+	renderTable(columns, rows, opts)
+}
+
+// renderTable truncates rows to fit the terminal (unless opts.Wide or the
+// output is piped) and renders them. Columns are capped at their limit,
+// then shrunk in shrink order until the table fits; columns that never
+// shrink, like IDs, are always shown in full.
+func renderTable(columns []tableColumn, rows [][]string, opts TableOptions) {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	width := opts.Width
+	if width <= 0 {
+		width, _ = terminalWidth(out)
+	}
+	if !opts.Wide && width > 0 {
+		widths := fitColumnWidths(columns, rows, width)
+		for _, row := range rows {
+			for i := range row {
+				row[i] = truncate(row[i], widths[i])
+			}
+		}
 	}
 
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.Header(headers)
+	for _, row := range rows {
+		table.Append(row)
+	}
 	table.Render()
 }
 
+// terminalWidth returns the width in columns of the terminal out writes to,
+// or false when out isn't a terminal (e.g. piped or redirected)
+func terminalWidth(out io.Writer) (int, bool) {
+	f, ok := out.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
+
+// fitColumnWidths returns the width of each column so the rendered table is
+// at most width terminal columns wide, where possible.
+func fitColumnWidths(columns []tableColumn, rows [][]string, width int) []int {
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = runewidth.StringWidth(col.header)
+		for _, row := range rows {
+			if w := runewidth.StringWidth(row[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+		if col.limit > 0 && widths[i] > col.limit {
+			widths[i] = col.limit
+		}
+	}
+
+	// Borders and padding: "│ a │ b │"
+	total := 3*len(columns) + 1
+	for _, w := range widths {
+		total += w
+	}
+
+	shrinkOrder := make([]int, 0, len(columns))
+	for i, col := range columns {
+		if col.shrink > 0 {
+			shrinkOrder = append(shrinkOrder, i)
+		}
+	}
+	sort.SliceStable(shrinkOrder, func(a, b int) bool {
+		return columns[shrinkOrder[a]].shrink < columns[shrinkOrder[b]].shrink
+	})
+
+	for _, i := range shrinkOrder {
+		if total <= width {
+			break
+		}
+		floor := runewidth.StringWidth(columns[i].header)
+		if floor < minColumnWidth {
+			floor = minColumnWidth
+		}
+		if widths[i] <= floor {
+			continue
+		}
+		give := total - width
+		if widths[i]-give < floor {
+			give = widths[i] - floor
+		}
+		widths[i] -= give
+		total -= give
+	}
+	return widths
+}
+
 // PrintWindowDetail prints detailed information about a single window
 func PrintWindowDetail(win *models.Window, app *models.Application) {
 	fmt.Printf("Window ID: %d\n", win.ID)
@@ -180,10 +326,10 @@ func PrintWindowDetail(win *models.Window, app *models.Application) {
 // Helper functions
 
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if runewidth.StringWidth(s) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	return runewidth.Truncate(s, maxLen, "...")
 }
 
 func formatIntSlice(ints []interface{}) string {
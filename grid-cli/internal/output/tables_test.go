@@ -0,0 +1,107 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/yourusername/grid-cli/internal/models"
+)
+
+func tableTestWindows() []*models.Window {
+	str := func(s string) *string { return &s }
+	return []*models.Window{
+		{ID: 123456, Title: str("Quarterly planning notes - draft 7 (final).txt"), AppName: str("TextEdit"),
+			Frame: [][]interface{}{{0.0, 0.0}, {800.0, 600.0}}, Spaces: []interface{}{1}},
+		{ID: 42, Title: str("Inbox"), AppName: str("Mail"),
+			Frame: [][]interface{}{{0.0, 0.0}, {1200.0, 900.0}}, Spaces: []interface{}{2}},
+	}
+}
+
+func TestPrintWindowsTable_NarrowTruncatesTitles(t *testing.T) {
+	var buf bytes.Buffer
+	PrintWindowsTable(tableTestWindows(), TableOptions{Out: &buf, Width: 60})
+	out := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if w := runewidth.StringWidth(line); w > 60 {
+			t.Errorf("line is %d columns wide, want at most 60: %q", w, line)
+		}
+	}
+	for _, want := range []string{"123456", "42", "TextEdit", "Mail"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q to survive truncation:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Quarterly planning notes") {
+		t.Errorf("expected the long title to be truncated:\n%s", out)
+	}
+	if !strings.Contains(out, "...") {
+		t.Errorf("expected an ellipsis on the truncated title:\n%s", out)
+	}
+}
+
+func TestPrintWindowsTable_Wide(t *testing.T) {
+	var buf bytes.Buffer
+	PrintWindowsTable(tableTestWindows(), TableOptions{Out: &buf, Width: 60, Wide: true})
+	if !strings.Contains(buf.String(), "Quarterly planning notes - draft 7 (final).txt") {
+		t.Errorf("expected full titles with Wide:\n%s", buf.String())
+	}
+}
+
+func TestPrintWindowsTable_NotATerminal(t *testing.T) {
+	// Without a width, output that isn't a terminal is never truncated
+	var buf bytes.Buffer
+	PrintWindowsTable(tableTestWindows(), TableOptions{Out: &buf})
+	if strings.Contains(buf.String(), "...") {
+		t.Errorf("expected no truncation when not writing to a terminal:\n%s", buf.String())
+	}
+}
+
+func TestPrintTables_FlagColumns(t *testing.T) {
+	windows := tableTestWindows()
+	windows[1].IsMinimized = true
+	var buf bytes.Buffer
+	PrintWindowsTable(windows, TableOptions{Out: &buf, Width: 200})
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, "Inbox") && !strings.Contains(line, "yes") {
+			t.Errorf("expected minimized window marked yes: %q", line)
+		}
+		if strings.Contains(line, "Quarterly") && strings.Contains(line, "yes") {
+			t.Errorf("expected visible window unmarked: %q", line)
+		}
+	}
+
+	buf.Reset()
+	PrintSpacesTable([]*models.Space{{UUID: "a", IsActive: true}}, TableOptions{Out: &buf, Width: 200})
+	if !strings.Contains(buf.String(), "yes") {
+		t.Errorf("expected the active space marked yes:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	PrintApplicationsTable([]*models.Application{{PID: 1, LocalizedName: "Mail", IsActive: true, IsHidden: true}}, TableOptions{Out: &buf, Width: 200})
+	if got := strings.Count(buf.String(), "yes"); got != 2 {
+		t.Errorf("expected active and hidden marked yes, got %d marks:\n%s", got, buf.String())
+	}
+}
+
+func TestFitColumnWidths(t *testing.T) {
+	columns := []tableColumn{
+		{header: "ID"},
+		{header: "Title", limit: 30, shrink: 1},
+		{header: "App", limit: 20, shrink: 2},
+	}
+	rows := [][]string{{"123456", strings.Repeat("t", 40), strings.Repeat("a", 15)}}
+
+	// Fits: only the default limits apply
+	if got := fitColumnWidths(columns, rows, 200); got[0] != 6 || got[1] != 30 || got[2] != 15 {
+		t.Errorf("wide terminal widths = %v, want [6 30 15]", got)
+	}
+
+	// Title gives up space first, down to its floor, then App
+	got := fitColumnWidths(columns, rows, 30)
+	if got[0] != 6 || got[1] != minColumnWidth || got[2] != 9 {
+		t.Errorf("narrow terminal widths = %v, want [6 %d 9]", got, minColumnWidth)
+	}
+}
@@ -0,0 +1,177 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// TreeDisplay is a display and the spaces on it, for `list windows --tree`
+type TreeDisplay struct {
+	UUID       string      `json:"uuid"`
+	Name       string      `json:"name"`
+	Resolution string      `json:"resolution"`
+	Spaces     []TreeSpace `json:"spaces"`
+}
+
+// TreeSpace is a space with its windows grouped by cell. Windows the grid
+// doesn't track in a cell are listed under Floating.
+type TreeSpace struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name,omitempty"`
+	Layout   string       `json:"layout,omitempty"`
+	Cells    []TreeCell   `json:"cells"`
+	Floating []TreeWindow `json:"floating"`
+}
+
+// TreeCell is a layout cell and the windows assigned to it
+type TreeCell struct {
+	ID      string       `json:"id"`
+	Windows []TreeWindow `json:"windows"`
+}
+
+// TreeWindow is a window leaf in the tree
+type TreeWindow struct {
+	ID    int    `json:"id"`
+	App   string `json:"app"`
+	Title string `json:"title"`
+}
+
+// BuildWindowTree groups windows by display, then space (in the display's
+// space order), then cell using the runtime state's assignments. Spaces
+// without any of the given windows are left out. spaceNames optionally
+// names spaces by ID.
+func BuildWindowTree(st *models.State, windows []*models.Window, rs *state.RuntimeState, spaceNames map[string]string) []TreeDisplay {
+	bySpace := make(map[string][]*models.Window)
+	for _, w := range windows {
+		spaceID := w.GetPrimarySpace()
+		bySpace[spaceID] = append(bySpace[spaceID], w)
+	}
+
+	tree := make([]TreeDisplay, 0, len(st.Displays))
+	for _, d := range st.Displays {
+		td := TreeDisplay{
+			UUID:       d.UUID,
+			Name:       d.GetDisplayName(),
+			Resolution: d.GetResolutionString(),
+			Spaces:     []TreeSpace{},
+		}
+		for _, spaceID := range d.GetSpaceIDs() {
+			spaceWindows, ok := bySpace[spaceID]
+			if !ok {
+				continue
+			}
+			delete(bySpace, spaceID)
+			td.Spaces = append(td.Spaces, buildTreeSpace(spaceID, spaceNames[spaceID], spaceWindows, rs.GetSpaceReadOnly(spaceID)))
+		}
+		tree = append(tree, td)
+	}
+
+	// Windows on spaces no display reports still belong in the picture
+	if len(bySpace) > 0 {
+		unknown := TreeDisplay{Name: "(unknown display)", Resolution: "-"}
+		spaceIDs := make([]string, 0, len(bySpace))
+		for spaceID := range bySpace {
+			spaceIDs = append(spaceIDs, spaceID)
+		}
+		sort.Strings(spaceIDs)
+		for _, spaceID := range spaceIDs {
+			unknown.Spaces = append(unknown.Spaces, buildTreeSpace(spaceID, spaceNames[spaceID], bySpace[spaceID], rs.GetSpaceReadOnly(spaceID)))
+		}
+		tree = append(tree, unknown)
+	}
+
+	return tree
+}
+
+// buildTreeSpace groups a space's windows by their assigned cell
+func buildTreeSpace(spaceID, name string, windows []*models.Window, space *state.SpaceState) TreeSpace {
+	ts := TreeSpace{ID: spaceID, Name: name, Cells: []TreeCell{}, Floating: []TreeWindow{}}
+
+	byID := make(map[int]*models.Window, len(windows))
+	for _, w := range windows {
+		byID[w.ID] = w
+	}
+
+	if space != nil {
+		ts.Layout = space.CurrentLayoutID
+
+		cellIDs := make([]string, 0, len(space.Cells))
+		for cellID := range space.Cells {
+			cellIDs = append(cellIDs, cellID)
+		}
+		sort.Strings(cellIDs)
+
+		for _, cellID := range cellIDs {
+			cell := TreeCell{ID: cellID, Windows: []TreeWindow{}}
+			for _, windowID := range space.Cells[cellID].Windows {
+				if w, ok := byID[int(windowID)]; ok {
+					cell.Windows = append(cell.Windows, treeWindow(w))
+					delete(byID, w.ID)
+				}
+			}
+			if len(cell.Windows) > 0 {
+				ts.Cells = append(ts.Cells, cell)
+			}
+		}
+	}
+
+	// Whatever isn't in a cell floats, in window list order
+	for _, w := range windows {
+		if _, ok := byID[w.ID]; ok {
+			ts.Floating = append(ts.Floating, treeWindow(w))
+		}
+	}
+	return ts
+}
+
+func treeWindow(w *models.Window) TreeWindow {
+	tw := TreeWindow{ID: w.ID}
+	if w.AppName != nil {
+		tw.App = *w.AppName
+	}
+	if w.Title != nil {
+		tw.Title = *w.Title
+	}
+	return tw
+}
+
+// PrintWindowTree prints a window tree as an indented outline
+func PrintWindowTree(out io.Writer, tree []TreeDisplay) {
+	for i, d := range tree {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Display %s (%s)\n", d.Name, d.Resolution)
+		if len(d.Spaces) == 0 {
+			fmt.Fprintln(out, "  (no windows)")
+		}
+		for _, s := range d.Spaces {
+			label := "Space " + s.ID
+			if s.Name != "" {
+				label += " " + s.Name
+			}
+			if s.Layout != "" {
+				label += " [" + s.Layout + "]"
+			}
+			fmt.Fprintf(out, "  %s\n", label)
+			for _, c := range s.Cells {
+				fmt.Fprintf(out, "    %s\n", c.ID)
+				printTreeWindows(out, c.Windows)
+			}
+			if len(s.Floating) > 0 {
+				fmt.Fprintln(out, "    floating")
+				printTreeWindows(out, s.Floating)
+			}
+		}
+	}
+}
+
+func printTreeWindows(out io.Writer, windows []TreeWindow) {
+	for _, w := range windows {
+		fmt.Fprintf(out, "      %d  %s  %s\n", w.ID, w.App, truncate(w.Title, 60))
+	}
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+func treeTestState() (*models.State, []*models.Window, *state.RuntimeState) {
+	str := func(s string) *string { return &s }
+	width, height := 2560, 1440
+	windows := []*models.Window{
+		{ID: 10, AppName: str("Safari"), Title: str("Docs"), Spaces: []interface{}{1}},
+		{ID: 11, AppName: str("Terminal"), Title: str("zsh"), Spaces: []interface{}{1}},
+		{ID: 12, AppName: str("Calculator"), Title: str("Calculator"), Spaces: []interface{}{1}},
+		{ID: 20, AppName: str("Mail"), Title: str("Inbox"), Spaces: []interface{}{5}},
+	}
+	st := &models.State{
+		Displays: []*models.Display{
+			{UUID: "main-uuid", Name: str("Studio Display"), PixelWidth: &width, PixelHeight: &height, Spaces: []interface{}{1, 2}},
+			{UUID: "side-uuid", Name: str("Side"), Spaces: []interface{}{5}},
+		},
+	}
+
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(10, "left")
+	space.AssignWindow(11, "right")
+	return st, windows, rs
+}
+
+func TestBuildWindowTree_NestsByDisplaySpaceCell(t *testing.T) {
+	st, windows, rs := treeTestState()
+
+	tree := BuildWindowTree(st, windows, rs, map[string]string{"1": "Code"})
+
+	if len(tree) != 2 {
+		t.Fatalf("expected 2 displays, got %d", len(tree))
+	}
+	main := tree[0]
+	if main.Name != "Studio Display" || main.Resolution != "2560x1440" {
+		t.Errorf("unexpected display: %+v", main)
+	}
+	// Space 2 has no windows and is left out
+	if len(main.Spaces) != 1 {
+		t.Fatalf("expected 1 space on main display, got %+v", main.Spaces)
+	}
+	space := main.Spaces[0]
+	if space.ID != "1" || space.Name != "Code" || space.Layout != "half" {
+		t.Errorf("unexpected space: %+v", space)
+	}
+	if len(space.Cells) != 2 || space.Cells[0].ID != "left" || space.Cells[0].Windows[0].ID != 10 ||
+		space.Cells[1].ID != "right" || space.Cells[1].Windows[0].ID != 11 {
+		t.Errorf("unexpected cells: %+v", space.Cells)
+	}
+	if len(space.Floating) != 1 || space.Floating[0].ID != 12 {
+		t.Errorf("expected window 12 floating, got %+v", space.Floating)
+	}
+
+	// Untracked space: everything floats
+	side := tree[1].Spaces
+	if len(side) != 1 || len(side[0].Cells) != 0 || len(side[0].Floating) != 1 || side[0].Floating[0].ID != 20 {
+		t.Errorf("expected window 20 floating on the side display, got %+v", side)
+	}
+}
+
+func TestPrintWindowTree(t *testing.T) {
+	st, windows, rs := treeTestState()
+	var buf bytes.Buffer
+	PrintWindowTree(&buf, BuildWindowTree(st, windows, rs, nil))
+	out := buf.String()
+
+	want := "Display Studio Display (2560x1440)\n" +
+		"  Space 1 [half]\n" +
+		"    left\n" +
+		"      10  Safari  Docs\n"
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("unexpected tree:\n%s", out)
+	}
+	if !strings.Contains(out, "    floating\n      12  Calculator  Calculator\n") {
+		t.Errorf("missing floating group:\n%s", out)
+	}
+}
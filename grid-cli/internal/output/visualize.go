@@ -116,18 +116,7 @@ func visualizeWindowsForDisplay(windows []*models.Window, display *models.Displa
 	}
 
 	// Sort windows by level (z-order) - draw back to front
-	sortedWindows := make([]*models.Window, len(windows))
-	copy(sortedWindows, windows)
-	sort.Slice(sortedWindows, func(i, j int) bool {
-		// Try to use Level field for sorting
-		levelI, okI := sortedWindows[i].Level.(float64)
-		levelJ, okJ := sortedWindows[j].Level.(float64)
-		if okI && okJ {
-			return levelI < levelJ
-		}
-		// Fallback to ID
-		return sortedWindows[i].ID < sortedWindows[j].ID
-	})
+	sortedWindows := sortWindowsByLevel(windows)
 
 	// Create scaling context using actual display dimensions
 	sc := NewScalingContextFromDisplay(display, opts.MaxWidth, opts.MaxHeight)
@@ -143,6 +132,17 @@ func visualizeWindows(windows []*models.Window, opts VisualizationOptions) strin
 	}
 
 	// Sort windows by level (z-order) - draw back to front
+	sortedWindows := sortWindowsByLevel(windows)
+
+	// Create scaling context from windows
+	sc := NewScalingContext(sortedWindows, opts.MaxWidth, opts.MaxHeight)
+	canvas := NewCanvas(opts.MaxWidth, opts.MaxHeight, opts.UseUnicode)
+
+	return renderWindowsOnCanvas(sortedWindows, sc, canvas)
+}
+
+// sortWindowsByLevel returns a copy of windows ordered back to front
+func sortWindowsByLevel(windows []*models.Window) []*models.Window {
 	sortedWindows := make([]*models.Window, len(windows))
 	copy(sortedWindows, windows)
 	sort.Slice(sortedWindows, func(i, j int) bool {
@@ -155,12 +155,7 @@ func visualizeWindows(windows []*models.Window, opts VisualizationOptions) strin
 		// Fallback to ID
 		return sortedWindows[i].ID < sortedWindows[j].ID
 	})
-
-	// Create scaling context from windows
-	sc := NewScalingContext(sortedWindows, opts.MaxWidth, opts.MaxHeight)
-	canvas := NewCanvas(opts.MaxWidth, opts.MaxHeight, opts.UseUnicode)
-
-	return renderWindowsOnCanvas(sortedWindows, sc, canvas)
+	return sortedWindows
 }
 
 // renderWindowsOnCanvas draws windows onto a canvas
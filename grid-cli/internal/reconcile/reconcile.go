@@ -1,17 +1,29 @@
 package reconcile
 
 import (
+	"sort"
+
 	"github.com/yourusername/grid-cli/internal/logging"
 	"github.com/yourusername/grid-cli/internal/server"
 	"github.com/yourusername/grid-cli/internal/state"
 )
 
 // Sync updates runtimeState to match server reality.
-// It removes windows from cells that no longer exist on the server,
-// and syncs the focused cell to match the OS-focused window.
+// It removes windows from cells that no longer exist on the server, drops
+// their pins and floats, and syncs the focused cell to match the OS-focused window.
 // This should be called before any command execution to ensure
 // local state is accurate.
 func Sync(snap *server.Snapshot, rs *state.RuntimeState) error {
+	if !syncSpace(snap, rs) {
+		return nil
+	}
+	rs.MarkUpdated()
+	return rs.Save()
+}
+
+// syncSpace makes Sync's changes to the snapshot's space without saving and
+// reports whether anything changed
+func syncSpace(snap *server.Snapshot, rs *state.RuntimeState) bool {
 	logging.Debug().
 		Str("spaceID", snap.SpaceID).
 		Uint32("focusedWindowID", snap.FocusedWindowID).
@@ -23,23 +35,47 @@ func Sync(snap *server.Snapshot, rs *state.RuntimeState) error {
 		logging.Debug().
 			Str("spaceID", snap.SpaceID).
 			Msg("reconcile: no local state for space")
-		return nil // Nothing to reconcile - no local state for this space
+		return false // Nothing to reconcile - no local state for this space
 	}
 
 	changed := false
-	for cellID, cell := range spaceState.Cells {
-		var valid []uint32
+	var missing []uint32
+	for _, cell := range spaceState.Cells {
 		for _, wid := range cell.Windows {
-			if snap.WindowIDs[wid] {
-				valid = append(valid, wid)
+			if !snap.WindowIDs[wid] {
+				missing = append(missing, wid)
 			}
 		}
+	}
 
-		if len(valid) != len(cell.Windows) {
-			// Windows were removed, update cell
-			mutableCell := rs.GetSpace(snap.SpaceID).GetCell(cellID)
-			mutableCell.Windows = valid
-			mutableCell.SplitRatios = equalRatios(len(valid))
+	// Windows were removed: RemoveWindow also fixes up ratios, the cell's
+	// last-focused index, and promotes a new master if the master closed
+	if len(missing) > 0 {
+		mutableSpace := rs.GetSpace(snap.SpaceID)
+		for _, wid := range missing {
+			mutableSpace.RemoveWindow(wid)
+		}
+		changed = true
+	}
+
+	// Pins and floats outlive minimizing or hiding, but not the window itself
+	if len(spaceState.PinnedWindows) > 0 || len(spaceState.FloatedWindows) > 0 || len(spaceState.ReinsertWindows) > 0 {
+		present := make(map[uint32]bool, len(snap.Windows))
+		for _, w := range snap.Windows {
+			present[w.ID] = true
+		}
+		if dropped := rs.GetSpace(snap.SpaceID).PrunePins(present); len(dropped) > 0 {
+			logging.Debug().
+				Str("spaceID", snap.SpaceID).
+				Int("count", len(dropped)).
+				Msg("reconcile: dropped pins of closed windows")
+			changed = true
+		}
+		if dropped := rs.GetSpace(snap.SpaceID).PruneFloats(present); len(dropped) > 0 {
+			logging.Debug().
+				Str("spaceID", snap.SpaceID).
+				Int("count", len(dropped)).
+				Msg("reconcile: dropped floats of closed windows")
 			changed = true
 		}
 	}
@@ -51,12 +87,40 @@ func Sync(snap *server.Snapshot, rs *state.RuntimeState) error {
 		}
 	}
 
-	if changed {
-		rs.MarkUpdated()
-		return rs.Save()
+	return changed
+}
+
+// StaleWindow is a window tracked in local state that the server no longer reports
+type StaleWindow struct {
+	WindowID uint32 `json:"windowId"`
+	CellID   string `json:"cellId"` // Last-known cell
+}
+
+// StaleWindows returns windows assigned to cells in the snapshot's space that
+// are missing from the snapshot, i.e. what Sync would prune. Results are
+// ordered by cell ID, then by position within the cell.
+func StaleWindows(snap *server.Snapshot, rs *state.RuntimeState) []StaleWindow {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil {
+		return nil
+	}
+
+	cellIDs := make([]string, 0, len(spaceState.Cells))
+	for cellID := range spaceState.Cells {
+		cellIDs = append(cellIDs, cellID)
 	}
+	sort.Strings(cellIDs)
 
-	return nil
+	var stale []StaleWindow
+	for _, cellID := range cellIDs {
+		for _, wid := range spaceState.Cells[cellID].Windows {
+			if !snap.WindowIDs[wid] {
+				stale = append(stale, StaleWindow{WindowID: wid, CellID: cellID})
+			}
+		}
+	}
+
+	return stale
 }
 
 // syncFocus updates local focus state to match the OS-focused window.
@@ -123,16 +187,3 @@ func syncFocus(snap *server.Snapshot, rs *state.RuntimeState) bool {
 	rs.GetSpace(snap.SpaceID).SetFocus(focusedCell, windowIndex)
 	return true
 }
-
-// equalRatios returns equal split ratios for n windows.
-func equalRatios(n int) []float64 {
-	if n <= 0 {
-		return nil
-	}
-	ratio := 1.0 / float64(n)
-	ratios := make([]float64, n)
-	for i := range ratios {
-		ratios[i] = ratio
-	}
-	return ratios
-}
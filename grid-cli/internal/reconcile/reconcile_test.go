@@ -0,0 +1,221 @@
+package reconcile
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+func TestStaleWindows(t *testing.T) {
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.AssignWindow(100, "left")
+	space.AssignWindow(101, "left")
+	space.AssignWindow(200, "right")
+	space.AssignWindow(300, "bottom")
+
+	snap := &server.Snapshot{
+		SpaceID:   "1",
+		WindowIDs: map[uint32]bool{100: true, 200: true},
+	}
+
+	stale := StaleWindows(snap, rs)
+
+	want := []StaleWindow{
+		{WindowID: 300, CellID: "bottom"},
+		{WindowID: 101, CellID: "left"},
+	}
+	if len(stale) != len(want) {
+		t.Fatalf("expected %d stale windows, got %v", len(want), stale)
+	}
+	for i := range want {
+		if stale[i] != want[i] {
+			t.Errorf("stale[%d] = %+v, want %+v", i, stale[i], want[i])
+		}
+	}
+
+	// Listing must not prune state
+	if space.GetWindowCell(101) != "left" {
+		t.Error("StaleWindows should not modify state")
+	}
+}
+
+func TestStaleWindows_NoSpaceState(t *testing.T) {
+	rs := state.NewRuntimeState()
+	snap := &server.Snapshot{SpaceID: "1", WindowIDs: map[uint32]bool{}}
+
+	if stale := StaleWindows(snap, rs); len(stale) != 0 {
+		t.Errorf("expected no stale windows, got %v", stale)
+	}
+}
+
+func TestSync_DropsFloatsOfClosedWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.FloatWindow(100)
+	space.FloatWindow(300) // closed
+
+	snap := &server.Snapshot{
+		SpaceID:   "1",
+		Windows:   []server.WindowInfo{{ID: 100}},
+		WindowIDs: map[uint32]bool{100: true},
+	}
+	if err := Sync(snap, rs); err != nil {
+		t.Fatal(err)
+	}
+
+	floated := rs.GetSpaceReadOnly("1").FloatedWindows
+	if len(floated) != 1 || !floated[100] {
+		t.Errorf("expected only the float of 100 to survive, got %v", floated)
+	}
+}
+
+func TestSync_DropsPinsOfClosedWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.AssignWindow(100, "left")
+	space.PinWindow(100, "left")
+	space.PinWindow(200, "right") // minimized: not tileable, but still there
+	space.PinWindow(300, "right") // closed
+
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 100},
+			{ID: 200, IsMinimized: true},
+		},
+		WindowIDs: map[uint32]bool{100: true},
+	}
+	if err := Sync(snap, rs); err != nil {
+		t.Fatal(err)
+	}
+
+	pins := rs.GetSpaceReadOnly("1").PinnedWindows
+	if len(pins) != 2 || pins[100] != "left" || pins[200] != "right" {
+		t.Errorf("expected pins of 100 and 200 to survive, got %v", pins)
+	}
+}
+
+func TestStatus_ReportsDriftWithoutChangingState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{
+		AppRules: []config.AppRule{{App: "Calculator", Float: true}},
+		Layouts: []config.LayoutConfig{
+			{ID: "half", Grid: config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"a", "b"}}},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(100, "a")
+	space.AssignWindow(101, "a") // closed
+	space.AssignWindow(200, "b")
+	space.AssignWindow(300, "old") // cell from a previous layout
+	space.PinWindow(400, "a")      // closed
+	space.FloatWindow(500)         // closed
+	space.SetFocus("a", 0)
+
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 100}, {ID: 200}, {ID: 300},
+			{ID: 600},                    // new
+			{ID: 700, IsMinimized: true}, // not tileable
+			{ID: 800, Level: 3},          // overlay, never tiled
+			{ID: 900, AppName: "Calculator"},
+		},
+		WindowIDs:       map[uint32]bool{100: true, 200: true, 300: true, 600: true},
+		FocusedWindowID: 200,
+	}
+
+	drift := Status(context.Background(), snap, cfg, rs, nil)
+
+	want := &Drift{
+		SpaceID:      "1",
+		LayoutID:     "half",
+		Stale:        []StaleWindow{{WindowID: 101, CellID: "a"}},
+		StalePins:    []uint32{400},
+		StaleFloats:  []uint32{500},
+		FocusedCell:  "b",
+		Unassigned:   []uint32{600},
+		UnknownCells: []string{"old"},
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("drift = %+v, want %+v", drift, want)
+	}
+	if drift.InSync() {
+		t.Error("expected drift to be reported")
+	}
+
+	// Nothing changed or saved
+	if space.GetWindowCell(101) != "a" || space.FocusedCell != "a" || len(space.PinnedWindows) != 1 {
+		t.Errorf("Status modified state: %+v", space)
+	}
+	if _, err := os.Stat(state.GetStatePath()); !os.IsNotExist(err) {
+		t.Errorf("expected no state file, got %v", err)
+	}
+}
+
+func TestStatus_InSync(t *testing.T) {
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{ID: "full", Grid: config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"main"}}},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 0)
+	space.AssignWindow(100, "main")
+	space.SetFocus("main", 0)
+
+	snap := &server.Snapshot{
+		SpaceID:         "1",
+		Windows:         []server.WindowInfo{{ID: 100}},
+		WindowIDs:       map[uint32]bool{100: true},
+		FocusedWindowID: 100,
+	}
+	if drift := Status(context.Background(), snap, cfg, rs, nil); !drift.InSync() {
+		t.Errorf("expected no drift, got %+v", drift)
+	}
+}
+
+func TestStatus_FloatedWindowsAreNotUnassigned(t *testing.T) {
+	cfg := &config.Config{
+		AppRules: []config.AppRule{{App: "com.apple.calculator", Float: true}},
+		Layouts: []config.LayoutConfig{
+			{ID: "full", Grid: config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}}, Areas: [][]string{{"main"}}},
+		},
+	}
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 100},
+			{ID: 200}, // floated with window float
+			{ID: 300, BundleID: "com.apple.calculator"}, // floated by an app rule
+		},
+		WindowIDs:       map[uint32]bool{100: true, 200: true, 300: true},
+		FocusedWindowID: 100,
+	}
+
+	// A space with no state has no floated windows, only app rules
+	rs := state.NewRuntimeState()
+	if drift := Status(context.Background(), snap, cfg, rs, nil); !reflect.DeepEqual(drift.Unassigned, []uint32{100, 200}) {
+		t.Errorf("unassigned without state = %v, want [100 200]", drift.Unassigned)
+	}
+
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 0)
+	space.AssignWindow(100, "main")
+	space.SetFocus("main", 0)
+	space.FloatWindow(200)
+	if drift := Status(context.Background(), snap, cfg, rs, nil); !drift.InSync() {
+		t.Errorf("expected floated windows to leave the space in sync, got %+v", drift)
+	}
+}
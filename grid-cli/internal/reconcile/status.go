@@ -0,0 +1,127 @@
+package reconcile
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// Drift describes how a space's local state differs from the server: what
+// Sync would change, and what it leaves for the next layout apply
+type Drift struct {
+	SpaceID  string `json:"spaceId"`
+	LayoutID string `json:"layoutId"` // Layout in state ("" if none applied)
+
+	// Changes Sync would make
+	Stale       []StaleWindow `json:"stale"`                 // Windows in cells but gone from the server
+	StalePins   []uint32      `json:"stalePins"`             // Pins of windows that closed
+	StaleFloats []uint32      `json:"staleFloats"`           // Floats of windows that closed
+	FocusedCell string        `json:"focusedCell,omitempty"` // Cell focus would move to ("" if in sync)
+
+	// Drift Sync doesn't fix
+	Unassigned    []uint32 `json:"unassigned"`    // Windows a layout apply would tile that are in no cell
+	MissingLayout bool     `json:"missingLayout"` // LayoutID isn't in the config
+	UnknownCells  []string `json:"unknownCells"`  // Cells in state the layout doesn't define
+}
+
+// InSync reports whether state and server agree
+func (d *Drift) InSync() bool {
+	return len(d.Stale) == 0 && len(d.StalePins) == 0 && len(d.StaleFloats) == 0 &&
+		d.FocusedCell == "" && len(d.Unassigned) == 0 && !d.MissingLayout && len(d.UnknownCells) == 0
+}
+
+// Status compares the snapshot's space in rs with the server without
+// changing rs: Sync runs against a copy of the space, never saved. Windows
+// count as unassigned by the same rules layout application tiles them by;
+// sc, if not nil, leaves out sticky windows as well.
+func Status(ctx context.Context, snap *server.Snapshot, cfg *config.Config, rs *state.RuntimeState, sc *layout.StickyChecker) *Drift {
+	drift := &Drift{
+		SpaceID:      snap.SpaceID,
+		Stale:        []StaleWindow{},
+		StalePins:    []uint32{},
+		StaleFloats:  []uint32{},
+		Unassigned:   []uint32{},
+		UnknownCells: []string{},
+	}
+	space := rs.GetSpaceReadOnly(snap.SpaceID)
+	tiled := func(w server.WindowInfo) bool {
+		if !layout.Tileable(w, cfg.AppRules) || (space != nil && space.IsFloated(w.ID)) {
+			return false
+		}
+		return sc == nil || cfg.Settings.TileStickyWindows || !sc.IsSticky(ctx, w)
+	}
+	if space == nil {
+		for _, w := range snap.Windows {
+			if tiled(w) {
+				drift.Unassigned = append(drift.Unassigned, w.ID)
+			}
+		}
+		return drift
+	}
+	drift.LayoutID = space.CurrentLayoutID
+	drift.Stale = append(drift.Stale, StaleWindows(snap, rs)...)
+
+	// Pins and floats go with closed windows, as in Sync
+	present := make(map[uint32]bool, len(snap.Windows))
+	for _, w := range snap.Windows {
+		present[w.ID] = true
+	}
+	for windowID := range space.PinnedWindows {
+		if !present[windowID] {
+			drift.StalePins = append(drift.StalePins, windowID)
+		}
+	}
+	for windowID := range space.FloatedWindows {
+		if !present[windowID] {
+			drift.StaleFloats = append(drift.StaleFloats, windowID)
+		}
+	}
+	sortIDs(drift.StalePins)
+	sortIDs(drift.StaleFloats)
+
+	// Dry-run Sync on a copy of the space to see where focus would go
+	scratch := state.NewRuntimeState()
+	scratch.Spaces[snap.SpaceID] = space.Clone()
+	syncSpace(snap, scratch)
+	if synced := scratch.Spaces[snap.SpaceID]; synced.FocusedCell != space.FocusedCell || synced.FocusedWindow != space.FocusedWindow {
+		drift.FocusedCell = synced.FocusedCell
+	}
+
+	for _, w := range snap.Windows {
+		if !tiled(w) {
+			continue
+		}
+		if space.GetWindowCell(w.ID) == "" {
+			drift.Unassigned = append(drift.Unassigned, w.ID)
+		}
+	}
+
+	if space.CurrentLayoutID != "" {
+		layoutDef, err := cfg.GetLayout(space.CurrentLayoutID)
+		if err != nil {
+			drift.MissingLayout = true
+		} else {
+			defined := make(map[string]bool, len(layoutDef.Cells))
+			for _, cell := range layoutDef.Cells {
+				defined[cell.ID] = true
+			}
+			for cellID := range space.Cells {
+				if !defined[cellID] {
+					drift.UnknownCells = append(drift.UnknownCells, cellID)
+				}
+			}
+			sort.Strings(drift.UnknownCells)
+		}
+	}
+
+	return drift
+}
+
+// sortIDs sorts window IDs ascending
+func sortIDs(ids []uint32) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/yourusername/grid-cli/internal/client"
 	"github.com/yourusername/grid-cli/internal/types"
@@ -26,18 +27,30 @@ type Snapshot struct {
 	WindowIDs       map[uint32]bool   // Quick lookup: does window exist?
 	FocusedWindowID uint32            // OS-focused window ID (from metadata)
 	AllDisplays     []DisplayInfo     // All connected displays with global frames
+	Urgent          []UrgentWindow    // Windows requesting attention, on any space
 }
 
 // WindowInfo contains window data needed for layout operations.
 type WindowInfo struct {
-	ID        uint32
-	AppName   string
-	BundleID  string
-	Title     string
-	Frame     types.Rect
-	Level     int
+	ID          uint32
+	AppName     string
+	BundleID    string
+	Title       string
+	Frame       types.Rect
+	Level       int
 	IsMinimized bool
 	IsHidden    bool
+	SpaceCount  int     // Spaces the window is reported on (sticky windows are on all of them)
+	IsUrgent    bool    // App has marked the window as needing attention
+	UrgentSince float64 // Unix time the window became urgent (0 if the server doesn't say)
+
+	IsFullscreen bool // Window is in native fullscreen (false if the server doesn't say)
+}
+
+// UrgentWindow is a window requesting attention and the space it's on
+type UrgentWindow struct {
+	WindowInfo
+	SpaceID string
 }
 
 // IsTileable returns true if the window should be included in tiling.
@@ -54,17 +67,104 @@ func Fetch(ctx context.Context, c *client.Client) (*Snapshot, error) {
 	return parseSnapshot(raw)
 }
 
-func parseSnapshot(raw map[string]interface{}) (*Snapshot, error) {
-	snap := &Snapshot{
-		WindowIDs: make(map[uint32]bool),
+// FetchForDisplay calls dump ONCE and parses a Snapshot for the current space
+// of the given display instead of the active one. The display is referenced
+// by index (as in `show display`) or UUID.
+func FetchForDisplay(ctx context.Context, c *client.Client, displayRef string) (*Snapshot, error) {
+	raw, err := c.Dump(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dump failed: %w", err)
+	}
+
+	display, err := ResolveDisplay(parseAllDisplays(raw), displayRef)
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotForDisplay(raw, display.UUID)
+}
+
+// FetchAllDisplays calls dump ONCE and parses a Snapshot for the current
+// space of every connected display, with the active display's last. Displays
+// showing the same space yield a single snapshot.
+func FetchAllDisplays(ctx context.Context, c *client.Client) ([]*Snapshot, error) {
+	raw, err := c.Dump(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dump failed: %w", err)
+	}
+	return parseAllSnapshots(raw)
+}
+
+func parseAllSnapshots(raw map[string]interface{}) ([]*Snapshot, error) {
+	activeDisplayUUID, _ := getActiveDisplayUUID(raw)
+
+	var snaps []*Snapshot
+	var active *Snapshot
+	seen := make(map[string]bool)
+	for _, d := range parseAllDisplays(raw) {
+		snap, err := parseSnapshotForDisplay(raw, d.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("display %s: %w", d.UUID, err)
+		}
+		if seen[snap.SpaceID] {
+			continue
+		}
+		seen[snap.SpaceID] = true
+		if d.UUID == activeDisplayUUID {
+			active = snap
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	if active != nil {
+		snaps = append(snaps, active)
+	}
+	return snaps, nil
+}
+
+// FetchDisplays calls dump and returns only the connected displays, for
+// callers that need to re-check the display list without a full snapshot.
+func FetchDisplays(ctx context.Context, c *client.Client) ([]DisplayInfo, error) {
+	raw, err := c.Dump(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dump failed: %w", err)
+	}
+	return parseAllDisplays(raw), nil
+}
+
+// ResolveDisplay finds a display by index or UUID.
+func ResolveDisplay(displays []DisplayInfo, ref string) (*DisplayInfo, error) {
+	if idx, err := strconv.Atoi(ref); err == nil {
+		if idx < 0 || idx >= len(displays) {
+			return nil, fmt.Errorf("display index %d out of range (have %d displays)", idx, len(displays))
+		}
+		return &displays[idx], nil
+	}
+
+	for i := range displays {
+		if displays[i].UUID == ref {
+			return &displays[i], nil
+		}
 	}
+	return nil, fmt.Errorf("display not found: %s", ref)
+}
 
+func parseSnapshot(raw map[string]interface{}) (*Snapshot, error) {
 	// 1. Get active display UUID first - this determines everything else
 	activeDisplayUUID, err := getActiveDisplayUUID(raw)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active display: %w", err)
 	}
 
+	return parseSnapshotForDisplay(raw, activeDisplayUUID)
+}
+
+// parseSnapshotForDisplay builds a Snapshot around the given display's
+// current space and bounds.
+func parseSnapshotForDisplay(raw map[string]interface{}, activeDisplayUUID string) (*Snapshot, error) {
+	snap := &Snapshot{
+		WindowIDs: make(map[uint32]bool),
+	}
+
 	// 2. Find current active space using the display UUID
 	spaceID, err := findActiveSpaceID(raw, activeDisplayUUID)
 	if err != nil {
@@ -95,6 +195,9 @@ func parseSnapshot(raw map[string]interface{}) (*Snapshot, error) {
 	// 7. Parse all displays for cross-monitor navigation
 	snap.AllDisplays = parseAllDisplays(raw)
 
+	// 8. Collect urgent windows from every space
+	snap.Urgent = parseUrgentWindows(raw)
+
 	return snap, nil
 }
 
@@ -250,6 +353,36 @@ func parseWindows(raw map[string]interface{}, spaceID string) []WindowInfo {
 	return windows
 }
 
+// parseUrgentWindows returns the windows flagged isUrgent, with the first
+// space each is on
+func parseUrgentWindows(raw map[string]interface{}) []UrgentWindow {
+	var rawWindows []interface{}
+	switch ws := raw["windows"].(type) {
+	case []interface{}:
+		rawWindows = ws
+	case map[string]interface{}:
+		for _, w := range ws {
+			rawWindows = append(rawWindows, w)
+		}
+	}
+
+	var urgent []UrgentWindow
+	for _, w := range rawWindows {
+		win, ok := w.(map[string]interface{})
+		if !ok || !toBool(win["isUrgent"]) {
+			continue
+		}
+		spaceID := ""
+		if spaces, ok := win["spaces"].([]interface{}); ok && len(spaces) > 0 {
+			spaceID = fmt.Sprintf("%v", interfaceToInt(spaces[0]))
+		}
+		if info := parseWindow(w, spaceID); info != nil {
+			urgent = append(urgent, UrgentWindow{WindowInfo: *info, SpaceID: spaceID})
+		}
+	}
+	return urgent
+}
+
 func parseWindow(w interface{}, spaceID string) *WindowInfo {
 	win, ok := w.(map[string]interface{})
 	if !ok {
@@ -287,6 +420,11 @@ func parseWindow(w interface{}, spaceID string) *WindowInfo {
 		IsMinimized: toBool(win["isMinimized"]),
 		IsHidden:    toBool(win["isHidden"]),
 		Level:       int(toFloat64(win["level"])),
+		SpaceCount:  len(spaces),
+		IsUrgent:    toBool(win["isUrgent"]),
+		UrgentSince: toFloat64(win["urgentSince"]),
+
+		IsFullscreen: toBool(win["isFullscreen"]),
 	}
 
 	// Parse frame
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func twoDisplayDump() map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"activeDisplayUUID": "main",
+		},
+		"displays": []interface{}{
+			map[string]interface{}{
+				"uuid":           "main",
+				"currentSpaceID": 1.0,
+				"frame":          map[string]interface{}{"x": 0.0, "y": 0.0, "width": 1920.0, "height": 1080.0},
+			},
+			map[string]interface{}{
+				"uuid":           "side",
+				"currentSpaceID": 4.0,
+				"frame":          map[string]interface{}{"x": 1920.0, "y": 0.0, "width": 1080.0, "height": 1920.0},
+				"visibleFrame":   map[string]interface{}{"x": 1920.0, "y": 25.0, "width": 1080.0, "height": 1895.0},
+			},
+		},
+		"windows": []interface{}{
+			map[string]interface{}{"id": 10.0, "appName": "Terminal", "spaces": []interface{}{1.0}},
+			map[string]interface{}{"id": 20.0, "appName": "Safari", "spaces": []interface{}{4.0}, "isFullscreen": true},
+		},
+	}
+}
+
+func TestResolveDisplay(t *testing.T) {
+	displays := parseAllDisplays(twoDisplayDump())
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"0", "main"},
+		{"1", "side"},
+		{"side", "side"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			d, err := ResolveDisplay(displays, tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.UUID != tt.want {
+				t.Errorf("ResolveDisplay(%q) = %s, want %s", tt.ref, d.UUID, tt.want)
+			}
+		})
+	}
+
+	for _, ref := range []string{"2", "-1", "missing"} {
+		if _, err := ResolveDisplay(displays, ref); err == nil {
+			t.Errorf("expected error for %q", ref)
+		}
+	}
+}
+
+func TestParseSnapshotForDisplay(t *testing.T) {
+	snap, err := parseSnapshotForDisplay(twoDisplayDump(), "side")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snap.SpaceID != "4" {
+		t.Errorf("SpaceID = %s, want 4", snap.SpaceID)
+	}
+	want := types.Rect{X: 1920, Y: 25, Width: 1080, Height: 1895}
+	if snap.DisplayBounds != want {
+		t.Errorf("DisplayBounds = %+v, want %+v", snap.DisplayBounds, want)
+	}
+	if len(snap.Windows) != 1 || snap.Windows[0].ID != 20 {
+		t.Fatalf("expected only the side display's window, got %+v", snap.Windows)
+	}
+	if !snap.Windows[0].IsFullscreen {
+		t.Error("expected isFullscreen to be parsed")
+	}
+}
+
+func TestParseSnapshot_ActiveDisplay(t *testing.T) {
+	snap, err := parseSnapshot(twoDisplayDump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.SpaceID != "1" || !snap.WindowIDs[10] || snap.WindowIDs[20] {
+		t.Errorf("expected active display's space 1 with window 10, got %+v", snap)
+	}
+}
+
+func TestParseSnapshot_UrgentWindows(t *testing.T) {
+	raw := twoDisplayDump()
+	raw["windows"] = append(raw["windows"].([]interface{}),
+		map[string]interface{}{"id": 30.0, "appName": "Slack", "spaces": []interface{}{4.0}, "isUrgent": true, "urgentSince": 1700000000.0},
+		map[string]interface{}{"id": 40.0, "appName": "Mail", "spaces": []interface{}{7.0}, "isUrgent": false},
+	)
+
+	snap, err := parseSnapshot(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Urgent windows are collected from every space, not just the current one
+	if len(snap.Urgent) != 1 {
+		t.Fatalf("expected 1 urgent window, got %+v", snap.Urgent)
+	}
+	got := snap.Urgent[0]
+	if got.ID != 30 || got.SpaceID != "4" || !got.IsUrgent || got.UrgentSince != 1700000000 {
+		t.Errorf("unexpected urgent window: %+v", got)
+	}
+}
+
+func TestParseAllSnapshots_ActiveLast(t *testing.T) {
+	snaps, err := parseAllSnapshots(twoDisplayDump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("expected a snapshot per display, got %d", len(snaps))
+	}
+	if snaps[0].SpaceID != "4" || snaps[1].SpaceID != "1" {
+		t.Errorf("expected spaces [4 1] with the active display last, got [%s %s]", snaps[0].SpaceID, snaps[1].SpaceID)
+	}
+	if !snaps[0].WindowIDs[20] || snaps[0].WindowIDs[10] {
+		t.Errorf("expected the side display's window only, got %+v", snaps[0].WindowIDs)
+	}
+}
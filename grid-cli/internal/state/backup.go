@@ -0,0 +1,190 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBackupDir is the backup directory name, next to the state file
+	DefaultBackupDir = "backups"
+	// DefaultBackupCount is how many backups are kept when not configured
+	DefaultBackupCount = 5
+
+	backupPrefix     = "state-"
+	backupSuffix     = ".json"
+	backupTimeFormat = "20060102T150405.000000000"
+)
+
+// backupPolicy controls automatic backups taken by SaveTo.
+// Backups are taken at most once per state path until ResetBackups, so a
+// command that saves several times still produces a single pre-mutation
+// backup.
+var backupPolicy = struct {
+	enabled  bool
+	keep     int
+	load     func() (bool, int) // Overrides enabled and keep when set
+	backedUp map[string]bool
+}{
+	backedUp: make(map[string]bool),
+}
+
+// SetBackupPolicy enables or disables automatic backups before saves.
+// keep <= 0 uses DefaultBackupCount.
+func SetBackupPolicy(enabled bool, keep int) {
+	backupPolicy.enabled = enabled
+	backupPolicy.keep = keep
+	backupPolicy.load = nil
+}
+
+// SetBackupPolicyFunc makes automatic backups ask load whether they're
+// enabled and how many to keep, when a save is about to back up a path.
+// Commands that never save don't pay for it.
+func SetBackupPolicyFunc(load func() (enabled bool, keep int)) {
+	backupPolicy.load = load
+}
+
+// ResetBackups forgets which state files were backed up, so the next save of
+// each backs it up again. A long-running process calls it per command.
+func ResetBackups() {
+	backupPolicy.backedUp = make(map[string]bool)
+}
+
+// GetBackupDir returns the backup directory for a state file path
+func GetBackupDir(statePath string) string {
+	return filepath.Join(filepath.Dir(statePath), DefaultBackupDir)
+}
+
+// autoBackup copies the on-disk state file aside before it is overwritten,
+// if automatic backups are enabled and this path hasn't been backed up yet.
+func autoBackup(path string) error {
+	if backupPolicy.backedUp[path] {
+		return nil
+	}
+	enabled, keep := backupPolicy.enabled, backupPolicy.keep
+	if backupPolicy.load != nil {
+		enabled, keep = backupPolicy.load()
+	}
+	if !enabled {
+		return nil
+	}
+	if keep <= 0 {
+		keep = DefaultBackupCount
+	}
+	backupPolicy.backedUp[path] = true
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil // Nothing to back up yet
+	}
+
+	_, err := BackupStateFile(path, keep)
+	return err
+}
+
+// BackupStateFile copies the state file at path into its backup directory
+// with a timestamped name, then prunes all but the newest keep backups.
+// Returns the backup name.
+func BackupStateFile(path string, keep int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	dir := GetBackupDir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := backupPrefix + time.Now().Format(backupTimeFormat) + backupSuffix
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := pruneBackups(path, keep); err != nil {
+		return name, err
+	}
+
+	return name, nil
+}
+
+// ListBackups returns backup names for a state file path, oldest first.
+func ListBackups(path string) ([]string, error) {
+	entries, err := os.ReadDir(GetBackupDir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(e.Name(), backupPrefix) && strings.HasSuffix(e.Name(), backupSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+
+	// Timestamped names sort chronologically
+	sort.Strings(names)
+	return names, nil
+}
+
+// RestoreBackup loads the named backup (or the newest if name is empty)
+// and saves it as the current state at path.
+func RestoreBackup(path, name string) (*RuntimeState, error) {
+	if name == "" {
+		names, err := ListBackups(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no backups found in %s", GetBackupDir(path))
+		}
+		name = names[len(names)-1]
+	}
+
+	// Guard against path traversal through the backup name
+	if filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid backup name: %s", name)
+	}
+
+	backupPath := filepath.Join(GetBackupDir(path), name)
+	if _, err := os.Stat(backupPath); err != nil {
+		return nil, fmt.Errorf("backup not found: %s", name)
+	}
+
+	rs, err := readStateFile(backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rs.SaveTo(path); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// pruneBackups removes the oldest backups beyond keep.
+func pruneBackups(path string, keep int) error {
+	names, err := ListBackups(path)
+	if err != nil {
+		return err
+	}
+
+	for len(names) > keep {
+		if err := os.Remove(filepath.Join(GetBackupDir(path), names[0])); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		names = names[1:]
+	}
+
+	return nil
+}
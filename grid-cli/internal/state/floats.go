@@ -0,0 +1,67 @@
+package state
+
+// FloatWindow takes a window out of the space's cells and keeps it out of
+// every apply and reapply, leaving it wherever it is, until it's unfloated
+func (ss *SpaceState) FloatWindow(windowID uint32) {
+	if ss.FloatedWindows == nil {
+		ss.FloatedWindows = make(map[uint32]bool)
+	}
+	ss.FloatedWindows[windowID] = true
+	ss.dropReinsert(windowID)
+	ss.RemoveWindow(windowID)
+}
+
+// UnfloatWindow returns a floated window to tiling. The next apply puts it in
+// the cell it overlaps most (see ReinsertWindows). Returns false if the
+// window wasn't floated.
+func (ss *SpaceState) UnfloatWindow(windowID uint32) bool {
+	if !ss.FloatedWindows[windowID] {
+		return false
+	}
+	delete(ss.FloatedWindows, windowID)
+	if len(ss.FloatedWindows) == 0 {
+		ss.FloatedWindows = nil
+	}
+	if ss.ReinsertWindows == nil {
+		ss.ReinsertWindows = make(map[uint32]bool)
+	}
+	ss.ReinsertWindows[windowID] = true
+	return true
+}
+
+// IsFloated reports whether a window was floated with FloatWindow
+func (ss *SpaceState) IsFloated(windowID uint32) bool {
+	return ss.FloatedWindows[windowID]
+}
+
+// PruneFloats drops the floats and pending reinserts of windows not in
+// present. Returns the window IDs that were dropped.
+func (ss *SpaceState) PruneFloats(present map[uint32]bool) []uint32 {
+	var dropped []uint32
+	for windowID := range ss.FloatedWindows {
+		if !present[windowID] {
+			dropped = append(dropped, windowID)
+		}
+	}
+	for windowID := range ss.ReinsertWindows {
+		if !present[windowID] {
+			dropped = append(dropped, windowID)
+		}
+	}
+	for _, windowID := range dropped {
+		delete(ss.FloatedWindows, windowID)
+		ss.dropReinsert(windowID)
+	}
+	if len(ss.FloatedWindows) == 0 {
+		ss.FloatedWindows = nil
+	}
+	return dropped
+}
+
+// dropReinsert forgets a pending reinsert
+func (ss *SpaceState) dropReinsert(windowID uint32) {
+	delete(ss.ReinsertWindows, windowID)
+	if len(ss.ReinsertWindows) == 0 {
+		ss.ReinsertWindows = nil
+	}
+}
@@ -0,0 +1,108 @@
+package state
+
+import (
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// FocusHistorySize is how many previous focus targets a space remembers
+const FocusHistorySize = 16
+
+// FocusEntry is a previously focused window and where it was
+type FocusEntry struct {
+	CellID      string `json:"cellId"`
+	WindowIndex int    `json:"windowIndex"`
+	WindowID    uint32 `json:"windowId"`
+}
+
+// PushFocusHistory records the currently focused window before focus moves
+// away from it. Nothing is recorded without a focused window, or when it's
+// already the latest entry. The oldest entry is dropped once the history
+// holds FocusHistorySize entries.
+func (ss *SpaceState) PushFocusHistory() {
+	windowID := ss.GetFocusedWindow()
+	if windowID == 0 {
+		return
+	}
+	if n := len(ss.FocusHistory); n > 0 && ss.FocusHistory[n-1].WindowID == windowID {
+		return
+	}
+
+	ss.FocusHistory = append(ss.FocusHistory, FocusEntry{
+		CellID:      ss.FocusedCell,
+		WindowIndex: ss.FocusedWindow,
+		WindowID:    windowID,
+	})
+	if over := len(ss.FocusHistory) - FocusHistorySize; over > 0 {
+		ss.FocusHistory = append([]FocusEntry(nil), ss.FocusHistory[over:]...)
+	}
+}
+
+// PopFocusHistory removes and returns the latest focus entry whose window is
+// still in a cell, with CellID and WindowIndex updated to where the window is
+// now. Entries for windows that are gone, or for the focused window itself,
+// are dropped on the way. Returns false when the history runs out.
+func (ss *SpaceState) PopFocusHistory() (FocusEntry, bool) {
+	focused := ss.GetFocusedWindow()
+	for len(ss.FocusHistory) > 0 {
+		entry := ss.FocusHistory[len(ss.FocusHistory)-1]
+		ss.FocusHistory = ss.FocusHistory[:len(ss.FocusHistory)-1]
+
+		if entry.WindowID == focused {
+			continue
+		}
+		cell, idx := ss.windowPosition(entry.WindowID)
+		if cell == nil {
+			continue
+		}
+		entry.CellID, entry.WindowIndex = cell.CellID, idx
+		return entry, true
+	}
+	return FocusEntry{}, false
+}
+
+// MoveHistorySize is how many window moves a space remembers for undo
+const MoveHistorySize = 32
+
+// MoveEntry is a window move between cells, with the window's frame before
+// the move (from the snapshot) and the placement it was given after it. A
+// move to another display's space is recorded on the space it left.
+type MoveEntry struct {
+	WindowID    uint32     `json:"windowId"`
+	SourceCell  string     `json:"sourceCell"`
+	SourceIndex int        `json:"sourceIndex"` // Position in the source cell's stack
+	TargetCell  string     `json:"targetCell"`
+	TargetSpace string     `json:"targetSpace,omitempty"` // Space the window moved to ("" if it stayed)
+	Before      types.Rect `json:"before"`
+	After       types.Rect `json:"after"`
+	Time        time.Time  `json:"time"`
+}
+
+// PushMoveHistory records a window move, dropping the oldest entry once the
+// history holds MoveHistorySize entries
+func (ss *SpaceState) PushMoveHistory(entry MoveEntry) {
+	ss.MoveHistory = append(ss.MoveHistory, entry)
+	if over := len(ss.MoveHistory) - MoveHistorySize; over > 0 {
+		ss.MoveHistory = append([]MoveEntry(nil), ss.MoveHistory[over:]...)
+	}
+}
+
+// PopMoveHistory removes and returns the latest move whose window is still in
+// a cell. Entries for windows that are gone are dropped on the way; moves to
+// another space are returned for the caller to check against that space.
+// Returns false when the history runs out.
+func (ss *SpaceState) PopMoveHistory() (MoveEntry, bool) {
+	for len(ss.MoveHistory) > 0 {
+		entry := ss.MoveHistory[len(ss.MoveHistory)-1]
+		ss.MoveHistory = ss.MoveHistory[:len(ss.MoveHistory)-1]
+
+		if entry.TargetSpace != "" {
+			return entry, true
+		}
+		if cell, _ := ss.windowPosition(entry.WindowID); cell != nil {
+			return entry, true
+		}
+	}
+	return MoveEntry{}, false
+}
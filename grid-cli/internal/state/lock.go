@@ -0,0 +1,98 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// lockSuffix is appended to the state path for its lock file
+	lockSuffix = ".lock"
+	// lockRetryInterval is how often a held lock is retried
+	lockRetryInterval = 10 * time.Millisecond
+)
+
+// lockTimeout is how long to wait for another grid process to release the
+// state lock before giving up
+var lockTimeout = 3 * time.Second
+
+// stateLocks holds the state file locks owned by this process, by state path.
+// The lock is per process: loading the same path again while it is held
+// doesn't wait on itself.
+var stateLocks = struct {
+	sync.Mutex
+	files map[string]*os.File
+}{
+	files: make(map[string]*os.File),
+}
+
+// acquireLock takes the cross-process lock for a state file, retrying for up
+// to lockTimeout while another process holds it. It does nothing if this
+// process already holds the lock or the state directory doesn't exist yet.
+func acquireLock(path string) error {
+	stateLocks.Lock()
+	defer stateLocks.Unlock()
+
+	if _, held := stateLocks.files[path]; held {
+		return nil
+	}
+
+	f, err := os.OpenFile(path+lockSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // No state directory yet; SaveTo creates it and locks then
+		}
+		return fmt.Errorf("failed to open state lock: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			f.Close()
+			return fmt.Errorf("failed to lock state: %w", err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return fmt.Errorf("state is locked by another grid process (%s)", filepath.Base(path)+lockSuffix)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	stateLocks.files[path] = f
+	return nil
+}
+
+// releaseLock drops this process's lock on a state file, if held
+func releaseLock(path string) {
+	stateLocks.Lock()
+	defer stateLocks.Unlock()
+
+	if f, held := stateLocks.files[path]; held {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+		delete(stateLocks.files, path)
+	}
+}
+
+// ReleaseLock drops every state lock this process holds. Long-running
+// processes call it after a command that may have loaded state without
+// saving it, such as the watch daemon after each forwarded command.
+func ReleaseLock() {
+	stateLocks.Lock()
+	defer stateLocks.Unlock()
+
+	for path, f := range stateLocks.files {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+		delete(stateLocks.files, path)
+	}
+}
@@ -3,8 +3,10 @@ package state
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
@@ -13,21 +15,57 @@ const (
 	DefaultStateDir = ".local/state/thegrid"
 	// DefaultStateFile is the state file name
 	DefaultStateFile = "state.json"
+	// ReadOnlyEnv turns on read-only mode when set to a true value (1, true)
+	ReadOnlyEnv = "GRID_READ_ONLY"
 )
 
+// readOnly is set by SetReadOnly
+var readOnly bool
+
+// SetReadOnly turns read-only mode on or off. In read-only mode LoadState
+// returns fresh empty state without touching the state file and Save does
+// nothing, so commands work from the server alone.
+func SetReadOnly(on bool) {
+	readOnly = on
+}
+
+// ReadOnly reports whether read-only mode is on, through SetReadOnly or the
+// GRID_READ_ONLY environment variable
+func ReadOnly() bool {
+	if readOnly {
+		return true
+	}
+	on, _ := strconv.ParseBool(os.Getenv(ReadOnlyEnv))
+	return on
+}
+
 // GetStatePath returns the full path to the state file
 func GetStatePath() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, DefaultStateDir, DefaultStateFile)
 }
 
-// LoadState loads state from the default path, creating new state if file doesn't exist
+// LoadState loads state from the default path, creating new state if file
+// doesn't exist. In read-only mode the file isn't read and state starts empty.
 func LoadState() (*RuntimeState, error) {
+	if ReadOnly() {
+		return NewRuntimeState(), nil
+	}
 	return LoadStateFrom(GetStatePath())
 }
 
-// LoadStateFrom loads state from a specific path
+// LoadStateFrom loads state from a specific path. It takes the state lock,
+// held until the next save, so concurrent grid processes serialize their
+// read-modify-write instead of overwriting each other.
 func LoadStateFrom(path string) (*RuntimeState, error) {
+	if err := acquireLock(path); err != nil {
+		return nil, err
+	}
+	return readStateFile(path)
+}
+
+// readStateFile parses a state file without locking it
+func readStateFile(path string) (*RuntimeState, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -62,8 +100,11 @@ func LoadStateFrom(path string) (*RuntimeState, error) {
 	return &state, nil
 }
 
-// Save persists state to the default path
+// Save persists state to the default path. It does nothing in read-only mode.
 func (rs *RuntimeState) Save() error {
+	if ReadOnly() {
+		return nil
+	}
 	return rs.SaveTo(GetStatePath())
 }
 
@@ -81,6 +122,18 @@ func (rs *RuntimeState) SaveTo(path string) error {
 		return fmt.Errorf("failed to create state directory: %w", err)
 	}
 
+	// Hold the lock for the write (taking it if LoadStateFrom didn't), then
+	// let the next process in
+	if err := acquireLock(path); err != nil {
+		return err
+	}
+	defer releaseLock(path)
+
+	// Keep a copy of the previous state before the first overwrite
+	if err := autoBackup(path); err != nil {
+		return fmt.Errorf("failed to back up state: %w", err)
+	}
+
 	// Marshal with indentation for readability
 	data, err := json.MarshalIndent(rs, "", "  ")
 	if err != nil {
@@ -113,10 +166,33 @@ func (rs *RuntimeState) Reset() error {
 
 // migrateState handles migration from older state versions
 func migrateState(old *RuntimeState) *RuntimeState {
-	// Currently no migrations needed - just update version
-	// Future migrations would go here (e.g., v1 -> v2 field changes)
+	// v1 -> v2: ratios only survive a reapply when RatiosUserSet, which v1
+	// didn't record. Unequal ratios can only have come from a resize.
+	if old.Version < 2 {
+		for _, space := range old.Spaces {
+			if space == nil {
+				continue
+			}
+			for _, cell := range space.Cells {
+				if cell != nil && !evenRatios(cell.SplitRatios) {
+					cell.RatiosUserSet = true
+				}
+			}
+		}
+	}
+
 	new := NewRuntimeState()
 	new.Spaces = old.Spaces
 	new.LastUpdated = old.LastUpdated
 	return new
 }
+
+// evenRatios reports whether ratios are all the same, as in an even split
+func evenRatios(ratios []float64) bool {
+	for _, r := range ratios {
+		if math.Abs(r-ratios[0]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package state
+
+// PinWindow pins a window to a cell, so every apply and reapply of the
+// space's layout puts it there ahead of the assignment strategy
+func (ss *SpaceState) PinWindow(windowID uint32, cellID string) {
+	if ss.PinnedWindows == nil {
+		ss.PinnedWindows = make(map[uint32]string)
+	}
+	ss.PinnedWindows[windowID] = cellID
+}
+
+// UnpinWindow removes a window's pin. Returns false if it wasn't pinned.
+func (ss *SpaceState) UnpinWindow(windowID uint32) bool {
+	if _, ok := ss.PinnedWindows[windowID]; !ok {
+		return false
+	}
+	delete(ss.PinnedWindows, windowID)
+	if len(ss.PinnedWindows) == 0 {
+		ss.PinnedWindows = nil
+	}
+	return true
+}
+
+// PrunePins drops the pins of windows not in present. Returns the window IDs
+// whose pins were dropped.
+func (ss *SpaceState) PrunePins(present map[uint32]bool) []uint32 {
+	var dropped []uint32
+	for windowID := range ss.PinnedWindows {
+		if !present[windowID] {
+			dropped = append(dropped, windowID)
+		}
+	}
+	for _, windowID := range dropped {
+		ss.UnpinWindow(windowID)
+	}
+	return dropped
+}
@@ -1,6 +1,10 @@
 package state
 
-import "github.com/yourusername/grid-cli/internal/types"
+import (
+	"sort"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
 
 // GetAllWindowIDs returns all window IDs across all spaces
 func (rs *RuntimeState) GetAllWindowIDs() []uint32 {
@@ -99,6 +103,31 @@ func (rs *RuntimeState) SetCellStackMode(spaceID, cellID string, mode types.Stac
 	cell.StackMode = mode
 }
 
+// SetSpaceStackMode sets the stack mode a space's cells use when neither
+// they nor the layout set one. With allCells, every existing cell is
+// switched to mode as well.
+func (rs *RuntimeState) SetSpaceStackMode(spaceID string, mode types.StackMode, allCells bool) {
+	rs.mu.Lock()
+	space, ok := rs.Spaces[spaceID]
+	if !ok {
+		space = NewSpaceState(spaceID)
+		rs.Spaces[spaceID] = space
+	}
+	space.DefaultStackMode = mode
+
+	var cellIDs []string
+	if allCells {
+		for cellID := range space.Cells {
+			cellIDs = append(cellIDs, cellID)
+		}
+	}
+	rs.mu.Unlock()
+
+	for _, cellID := range cellIDs {
+		rs.SetCellStackMode(spaceID, cellID, mode)
+	}
+}
+
 // GetCurrentLayoutForSpace returns the current layout ID for a space
 func (rs *RuntimeState) GetCurrentLayoutForSpace(spaceID string) string {
 	rs.mu.RLock()
@@ -135,7 +164,8 @@ func (rs *RuntimeState) GetWindowAssignments(spaceID string) map[string][]uint32
 	return assignments
 }
 
-// SetWindowAssignments bulk-sets window assignments for a space
+// SetWindowAssignments bulk-sets window assignments for a space. Cells
+// keep their stack mode and the z-order of windows that stay in them.
 func (rs *RuntimeState) SetWindowAssignments(spaceID string, assignments map[string][]uint32) {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
@@ -145,16 +175,93 @@ func (rs *RuntimeState) SetWindowAssignments(spaceID string, assignments map[str
 		space = NewSpaceState(spaceID)
 		rs.Spaces[spaceID] = space
 	}
+	space.setAssignments(assignments, space.Cells)
+}
 
-	// Clear existing cells
-	space.Cells = make(map[string]*CellState)
+// SetWindowAssignmentsFrom is SetWindowAssignments for callers that reset
+// the space's layout (which clears its cells) first: cell state carries
+// over from previous, the cells captured before the reset.
+func (rs *RuntimeState) SetWindowAssignmentsFrom(spaceID string, assignments map[string][]uint32, previous map[string]*CellState) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	space, ok := rs.Spaces[spaceID]
+	if !ok {
+		space = NewSpaceState(spaceID)
+		rs.Spaces[spaceID] = space
+	}
+	space.setAssignments(assignments, previous)
+}
+
+// setAssignments replaces the space's cells with assignments, carrying
+// per-cell state over from previous
+func (ss *SpaceState) setAssignments(assignments map[string][]uint32, previous map[string]*CellState) {
+	ss.Cells = make(map[string]*CellState)
 
-	// Set new assignments
 	for cellID, windowIDs := range assignments {
-		cell := space.GetCell(cellID)
+		cell := ss.GetCell(cellID)
 		cell.Windows = make([]uint32, len(windowIDs))
 		copy(cell.Windows, windowIDs)
 		cell.SplitRatios = equalRatios(len(windowIDs))
+
+		// Windows that stay in the cell keep their z-order, so the same
+		// tab is in front after a reapply; a mode set on the cell stays too
+		if prev, ok := previous[cellID]; ok {
+			if prev.StackMode != "" {
+				cell.StackMode = prev.StackMode
+			}
+			cell.ZOrder = cell.keepZOrder(prev.ZOrder)
+			if len(cell.ZOrder) > 0 {
+				cell.LastFocusedIdx = cell.indexOf(cell.ZOrder[0])
+			}
+		}
+	}
+}
+
+// RemoveWindowFromOtherSpaces removes a window from every space except keepSpaceID
+func (rs *RuntimeState) RemoveWindowFromOtherSpaces(windowID uint32, keepSpaceID string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for spaceID, space := range rs.Spaces {
+		if spaceID != keepSpaceID {
+			space.RemoveWindow(windowID)
+		}
+	}
+}
+
+// SnapshotSpaces copies the given spaces' state so it can be restored with
+// RestoreSpaces. Spaces without state are recorded as nil; empty IDs are skipped.
+func (rs *RuntimeState) SnapshotSpaces(spaceIDs ...string) map[string]*SpaceState {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	snapshot := make(map[string]*SpaceState)
+	for _, spaceID := range spaceIDs {
+		if spaceID == "" {
+			continue
+		}
+		if space, ok := rs.Spaces[spaceID]; ok {
+			snapshot[spaceID] = space.Clone()
+		} else {
+			snapshot[spaceID] = nil
+		}
+	}
+	return snapshot
+}
+
+// RestoreSpaces puts back spaces recorded by SnapshotSpaces, removing those
+// that had no state when the snapshot was taken.
+func (rs *RuntimeState) RestoreSpaces(snapshot map[string]*SpaceState) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for spaceID, space := range snapshot {
+		if space == nil {
+			delete(rs.Spaces, spaceID)
+			continue
+		}
+		rs.Spaces[spaceID] = space.Clone()
 	}
 }
 
@@ -178,17 +285,7 @@ func (rs *RuntimeState) Summary() map[string]interface{} {
 
 	spaces := make(map[string]interface{})
 	for spaceID, space := range rs.Spaces {
-		windowCount := 0
-		for _, cell := range space.Cells {
-			windowCount += len(cell.Windows)
-		}
-
-		spaces[spaceID] = map[string]interface{}{
-			"currentLayout": space.CurrentLayoutID,
-			"cellCount":     len(space.Cells),
-			"windowCount":   windowCount,
-			"focusedCell":   space.FocusedCell,
-		}
+		spaces[spaceID] = spaceSummary(space)
 	}
 
 	return map[string]interface{}{
@@ -198,3 +295,65 @@ func (rs *RuntimeState) Summary() map[string]interface{} {
 		"spaces":      spaces,
 	}
 }
+
+// SpaceSummary returns a detailed summary of one space: the Summary fields
+// plus focus, master window, track overrides, pinned and floated windows,
+// the default stack mode and each cell's windows, split ratios and stack
+// mode. Returns false when there is no state for the space.
+func (rs *RuntimeState) SpaceSummary(spaceID string) (map[string]interface{}, bool) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	space, ok := rs.Spaces[spaceID]
+	if !ok {
+		return nil, false
+	}
+
+	cellIDs := make([]string, 0, len(space.Cells))
+	for cellID := range space.Cells {
+		cellIDs = append(cellIDs, cellID)
+	}
+	sort.Strings(cellIDs)
+
+	cells := make([]map[string]interface{}, 0, len(cellIDs))
+	for _, cellID := range cellIDs {
+		cell := space.Cells[cellID]
+		cells = append(cells, map[string]interface{}{
+			"cellId":         cellID,
+			"windows":        cell.Windows,
+			"splitRatios":    cell.SplitRatios,
+			"ratiosUserSet":  cell.RatiosUserSet,
+			"stackMode":      cell.StackMode,
+			"lastFocusedIdx": cell.LastFocusedIdx,
+			"zOrder":         cell.ZOrder,
+		})
+	}
+
+	summary := spaceSummary(space)
+	summary["spaceId"] = spaceID
+	summary["layoutIndex"] = space.LayoutIndex
+	summary["focusedWindow"] = space.FocusedWindow
+	summary["masterWindow"] = space.MasterWindow
+	summary["tracks"] = space.Tracks
+	summary["spans"] = space.Spans
+	summary["pinnedWindows"] = space.PinnedWindows
+	summary["floatedWindows"] = space.FloatedWindows
+	summary["defaultStackMode"] = space.DefaultStackMode
+	summary["cells"] = cells
+	return summary, true
+}
+
+// spaceSummary returns the per-space fields shown by Summary
+func spaceSummary(space *SpaceState) map[string]interface{} {
+	windowCount := 0
+	for _, cell := range space.Cells {
+		windowCount += len(cell.Windows)
+	}
+
+	return map[string]interface{}{
+		"currentLayout": space.CurrentLayoutID,
+		"cellCount":     len(space.Cells),
+		"windowCount":   windowCount,
+		"focusedCell":   space.FocusedCell,
+	}
+}
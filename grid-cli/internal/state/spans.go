@@ -0,0 +1,58 @@
+package state
+
+// AddSpan records that a cell spans into an empty neighbour (resize absorb)
+func (ss *SpaceState) AddSpan(cellID, absorbedID string) {
+	if ss.Spans == nil {
+		ss.Spans = make(map[string][]string)
+	}
+	ss.Spans[cellID] = append(ss.Spans[cellID], absorbedID)
+}
+
+// SpanningCell returns the cell that absorbed cellID, or "" if none did
+func (ss *SpaceState) SpanningCell(cellID string) string {
+	for spanning, absorbed := range ss.Spans {
+		for _, id := range absorbed {
+			if id == cellID {
+				return spanning
+			}
+		}
+	}
+	return ""
+}
+
+// PruneSpans drops spans into cells that hold windows again, and the spans
+// of cells that are empty themselves. Returns true if any span was dropped.
+func (ss *SpaceState) PruneSpans() bool {
+	pruned := false
+	for cellID, absorbed := range ss.Spans {
+		if ss.cellEmpty(cellID) {
+			delete(ss.Spans, cellID)
+			pruned = true
+			continue
+		}
+		kept := absorbed[:0]
+		for _, id := range absorbed {
+			if ss.cellEmpty(id) {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) != len(absorbed) {
+			pruned = true
+		}
+		if len(kept) == 0 {
+			delete(ss.Spans, cellID)
+		} else {
+			ss.Spans[cellID] = kept
+		}
+	}
+	if len(ss.Spans) == 0 {
+		ss.Spans = nil
+	}
+	return pruned
+}
+
+// cellEmpty reports whether a cell holds no windows
+func (ss *SpaceState) cellEmpty(cellID string) bool {
+	cell, ok := ss.Cells[cellID]
+	return !ok || len(cell.Windows) == 0
+}
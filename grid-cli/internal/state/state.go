@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	// StateVersion is the current state file format version
-	StateVersion = 1
+	// StateVersion is the current state file format version. Version 2
+	// added CellState.RatiosUserSet.
+	StateVersion = 2
 )
 
 // RuntimeState is the root state structure persisted to disk
@@ -23,21 +24,41 @@ type RuntimeState struct {
 
 // SpaceState tracks layout state for a single macOS Space
 type SpaceState struct {
-	SpaceID         string                `json:"spaceId"`
-	CurrentLayoutID string                `json:"currentLayoutId"`
-	LayoutIndex     int                   `json:"layoutIndex"`     // Index in the space's layout cycle
-	Cells           map[string]*CellState `json:"cells"`           // cellID -> state
-	FocusedCell     string                `json:"focusedCell"`     // Currently focused cell ID
-	FocusedWindow   int                   `json:"focusedWindow"`   // Index of focused window in cell
+	SpaceID          string                `json:"spaceId"`
+	CurrentLayoutID  string                `json:"currentLayoutId"`
+	LayoutIndex      int                   `json:"layoutIndex"`                // Index in the space's layout cycle
+	Cells            map[string]*CellState `json:"cells"`                      // cellID -> state
+	FocusedCell      string                `json:"focusedCell"`                // Currently focused cell ID
+	FocusedWindow    int                   `json:"focusedWindow"`              // Index of focused window in cell
+	MasterWindow     uint32                `json:"masterWindow,omitempty"`     // Designated master window (0 = none)
+	Tracks           *TrackOverrides       `json:"tracks,omitempty"`           // Track fr values set by `resize cell`
+	FocusHistory     []FocusEntry          `json:"focusHistory,omitempty"`     // Previously focused windows, oldest first
+	MoveHistory      []MoveEntry           `json:"moveHistory,omitempty"`      // Window moves between cells, oldest first (window undo)
+	BSP              bool                  `json:"bsp,omitempty"`              // Cells tile as a binary space partition (layout apply --assignment bsp)
+	PinnedWindows    map[uint32]string     `json:"pinnedWindows,omitempty"`    // windowID -> cellID, placed there on every apply (window pin)
+	FloatedWindows   map[uint32]bool       `json:"floatedWindows,omitempty"`   // Windows kept out of cells (window float)
+	ReinsertWindows  map[uint32]bool       `json:"reinsertWindows,omitempty"`  // Unfloated windows the next apply places by position
+	DefaultStackMode types.StackMode       `json:"defaultStackMode,omitempty"` // Stack mode for cells the layout sets no mode for (state set-stack-mode)
+	Spans            map[string][]string   `json:"spans,omitempty"`            // cellID -> empty cells it spans into (resize absorb)
+}
+
+// TrackOverrides replaces the fr values of a layout's tracks for one space.
+// A zero entry keeps the layout's own size for that track.
+type TrackOverrides struct {
+	LayoutID string    `json:"layoutId"`          // Layout the overrides were made for
+	Columns  []float64 `json:"columns,omitempty"` // Per-column fr
+	Rows     []float64 `json:"rows,omitempty"`    // Per-row fr
 }
 
 // CellState tracks state for a single cell
 type CellState struct {
 	CellID         string          `json:"cellId"`
-	Windows        []uint32        `json:"windows"`        // Ordered list of window IDs
-	SplitRatios    []float64       `json:"splitRatios"`    // One per window, sum to 1.0
-	StackMode      types.StackMode `json:"stackMode"`      // Override stack mode (empty = use default)
-	LastFocusedIdx int             `json:"lastFocusedIdx"` // Last focused window index in this cell
+	Windows        []uint32        `json:"windows"`                 // Ordered list of window IDs
+	SplitRatios    []float64       `json:"splitRatios"`             // One per window, sum to 1.0
+	RatiosUserSet  bool            `json:"ratiosUserSet,omitempty"` // SplitRatios come from a resize and survive reapply
+	StackMode      types.StackMode `json:"stackMode"`               // Override stack mode (empty = use default)
+	LastFocusedIdx int             `json:"lastFocusedIdx"`          // Last focused window index in this cell
+	ZOrder         []uint32        `json:"zOrder,omitempty"`        // Window IDs front to back, raised on focus (the MRU order)
 }
 
 // NewRuntimeState creates a new empty runtime state
@@ -105,6 +126,76 @@ func (rs *RuntimeState) MarkUpdated() {
 	rs.LastUpdated = time.Now()
 }
 
+// Clone returns a deep copy of the space state
+func (ss *SpaceState) Clone() *SpaceState {
+	clone := *ss
+	clone.Cells = make(map[string]*CellState, len(ss.Cells))
+	for cellID, cell := range ss.Cells {
+		cellCopy := *cell
+		cellCopy.Windows = append([]uint32(nil), cell.Windows...)
+		cellCopy.SplitRatios = append([]float64(nil), cell.SplitRatios...)
+		cellCopy.ZOrder = append([]uint32(nil), cell.ZOrder...)
+		clone.Cells[cellID] = &cellCopy
+	}
+	clone.FocusHistory = append([]FocusEntry(nil), ss.FocusHistory...)
+	clone.MoveHistory = append([]MoveEntry(nil), ss.MoveHistory...)
+	if ss.PinnedWindows != nil {
+		clone.PinnedWindows = make(map[uint32]string, len(ss.PinnedWindows))
+		for windowID, cellID := range ss.PinnedWindows {
+			clone.PinnedWindows[windowID] = cellID
+		}
+	}
+	clone.FloatedWindows = cloneWindowSet(ss.FloatedWindows)
+	clone.ReinsertWindows = cloneWindowSet(ss.ReinsertWindows)
+	if ss.Spans != nil {
+		clone.Spans = make(map[string][]string, len(ss.Spans))
+		for cellID, absorbed := range ss.Spans {
+			clone.Spans[cellID] = append([]string(nil), absorbed...)
+		}
+	}
+	if ss.Tracks != nil {
+		clone.Tracks = &TrackOverrides{
+			LayoutID: ss.Tracks.LayoutID,
+			Columns:  append([]float64(nil), ss.Tracks.Columns...),
+			Rows:     append([]float64(nil), ss.Tracks.Rows...),
+		}
+	}
+	return &clone
+}
+
+// cloneWindowSet copies a set of window IDs, keeping nil as nil
+func cloneWindowSet(set map[uint32]bool) map[uint32]bool {
+	if set == nil {
+		return nil
+	}
+	clone := make(map[uint32]bool, len(set))
+	for windowID := range set {
+		clone[windowID] = true
+	}
+	return clone
+}
+
+// UserRatios returns the split ratios set by resizing, by cell ID
+func (ss *SpaceState) UserRatios() map[string][]float64 {
+	ratios := make(map[string][]float64)
+	for cellID, cell := range ss.Cells {
+		if cell.RatiosUserSet && len(cell.SplitRatios) > 0 {
+			ratios[cellID] = append([]float64(nil), cell.SplitRatios...)
+		}
+	}
+	return ratios
+}
+
+// RestoreUserRatios puts back user-set split ratios (from UserRatios) on the
+// cells that still hold the same number of windows
+func (ss *SpaceState) RestoreUserRatios(ratios map[string][]float64) {
+	for cellID, r := range ratios {
+		if cell, ok := ss.Cells[cellID]; ok && len(cell.Windows) == len(r) {
+			cell.SplitRatios = append([]float64(nil), r...)
+			cell.RatiosUserSet = true
+		}
+	}
+}
 
 // GetCell returns the state for a cell, creating it if needed
 func (ss *SpaceState) GetCell(cellID string) *CellState {
@@ -119,6 +210,12 @@ func (ss *SpaceState) GetCell(cellID string) *CellState {
 
 // SetCurrentLayout sets the current layout and resets cell state
 func (ss *SpaceState) SetCurrentLayout(layoutID string, layoutIndex int) {
+	if ss.Tracks != nil && ss.Tracks.LayoutID != layoutID {
+		ss.Tracks = nil
+	}
+	if ss.CurrentLayoutID != layoutID {
+		ss.Spans = nil
+	}
 	ss.CurrentLayoutID = layoutID
 	ss.LayoutIndex = layoutIndex
 	// Clear cell state when layout changes
@@ -154,7 +251,8 @@ func (ss *SpaceState) PreviousLayout(availableLayouts []string) string {
 }
 
 // AssignWindow adds a window to a cell (appends to end).
-// Sets LastFocusedIdx to the new window so it becomes the "top" (focused) window.
+// Sets LastFocusedIdx to the new window and raises it, so it becomes the
+// "top" (focused) window.
 // If the window is already in another cell, it's moved.
 func (ss *SpaceState) AssignWindow(windowID uint32, cellID string) {
 	cell := ss.GetCell(cellID)
@@ -166,21 +264,47 @@ func (ss *SpaceState) AssignWindow(windowID uint32, cellID string) {
 		}
 	}
 
-	// Remove from any other cell first
+	// Remove from any other cell first (a moved master stays master)
+	isMaster := ss.MasterWindow != 0 && ss.MasterWindow == windowID
 	ss.RemoveWindow(windowID)
+	if isMaster {
+		ss.MasterWindow = windowID
+	}
+
+	// A floated window put in a cell is tiled again
+	if ss.FloatedWindows[windowID] {
+		delete(ss.FloatedWindows, windowID)
+		if len(ss.FloatedWindows) == 0 {
+			ss.FloatedWindows = nil
+		}
+	}
 
 	// Append to cell
 	cell.Windows = append(cell.Windows, windowID)
 	// New window becomes "top" (focused) via LastFocusedIdx
 	cell.LastFocusedIdx = len(cell.Windows) - 1
+	cell.Raise(windowID)
 
 	// Update split ratios to be equal
 	cell.SplitRatios = equalRatios(len(cell.Windows))
+	cell.RatiosUserSet = false
 }
 
 // PrependWindowToCell adds a window to a cell (prepends to start).
 // If the window is already in another cell, it's moved.
+// The cell's split ratios are reset to equal.
 func (ss *SpaceState) PrependWindowToCell(windowID uint32, cellID string) {
+	ss.prependWindow(windowID, cellID, false)
+}
+
+// PrependWindowToCellKeepRatios is PrependWindowToCell without rebalancing:
+// the window gets a 1/n share of the cell and the windows already there
+// keep their proportions of the rest.
+func (ss *SpaceState) PrependWindowToCellKeepRatios(windowID uint32, cellID string) {
+	ss.prependWindow(windowID, cellID, true)
+}
+
+func (ss *SpaceState) prependWindow(windowID uint32, cellID string, keepRatios bool) {
 	cell := ss.GetCell(cellID)
 
 	// Check if already in this cell at position 0
@@ -189,17 +313,58 @@ func (ss *SpaceState) PrependWindowToCell(windowID uint32, cellID string) {
 	}
 
 	// Remove from any other cell first (including this cell if not at position 0)
+	isMaster := ss.MasterWindow != 0 && ss.MasterWindow == windowID
 	ss.RemoveWindow(windowID)
+	if isMaster {
+		ss.MasterWindow = windowID
+	}
+	prevRatios := cell.SplitRatios
+	if len(prevRatios) != len(cell.Windows) {
+		prevRatios = equalRatios(len(cell.Windows))
+	}
 
 	// Prepend to cell
 	cell.Windows = append([]uint32{windowID}, cell.Windows...)
 	cell.LastFocusedIdx = 0 // Prepended window becomes top
+	cell.Raise(windowID)
+
+	if keepRatios {
+		// Mark them user-set so a reapply doesn't even them out
+		cell.SplitRatios = prependShare(prevRatios)
+		cell.RatiosUserSet = true
+		return
+	}
 
 	// Update split ratios to be equal
 	cell.SplitRatios = equalRatios(len(cell.Windows))
+	cell.RatiosUserSet = false
 }
 
-// RemoveWindow removes a window from all cells
+// prependShare returns ratios for a cell that had ratios and gained a window
+// at the front: the new window gets 1/n and the others are scaled to fill
+// the remainder in their existing proportions
+func prependShare(ratios []float64) []float64 {
+	n := len(ratios) + 1
+	share := 1.0 / float64(n)
+
+	sum := 0.0
+	for _, r := range ratios {
+		sum += r
+	}
+	result := make([]float64, 0, n)
+	result = append(result, share)
+	for _, r := range ratios {
+		if sum > 0 {
+			result = append(result, r/sum*(1-share))
+		} else {
+			result = append(result, share)
+		}
+	}
+	return result
+}
+
+// RemoveWindow removes a window from all cells.
+// If the window was the master, the next window in its cell is promoted.
 func (ss *SpaceState) RemoveWindow(windowID uint32) {
 	for _, cell := range ss.Cells {
 		for i, wid := range cell.Windows {
@@ -207,25 +372,100 @@ func (ss *SpaceState) RemoveWindow(windowID uint32) {
 				// Remove window
 				cell.Windows = append(cell.Windows[:i], cell.Windows[i+1:]...)
 
-				// Adjust LastFocusedIdx if needed
-				if len(cell.Windows) == 0 {
-					cell.LastFocusedIdx = 0
-				} else if cell.LastFocusedIdx >= len(cell.Windows) {
-					cell.LastFocusedIdx = len(cell.Windows) - 1
+				// Promote the window that took the master's place in its cell
+				if ss.MasterWindow == windowID {
+					ss.MasterWindow = 0
+					if len(cell.Windows) > 0 {
+						ss.MasterWindow = cell.Windows[min(i, len(cell.Windows)-1)]
+					}
 				}
 
+				// Adjust LastFocusedIdx so it keeps pointing at a sensible window
+				cell.LastFocusedIdx = adjustFocusedIdx(cell.LastFocusedIdx, i, len(cell.Windows))
+				cell.dropFromZOrder(windowID)
+
 				// Update split ratios
 				if len(cell.Windows) > 0 {
 					cell.SplitRatios = equalRatios(len(cell.Windows))
 				} else {
 					cell.SplitRatios = nil
 				}
+				cell.RatiosUserSet = false
 				return
 			}
 		}
 	}
 }
 
+// SwapInCell swaps the windows at positions i and j of a cell. Split ratios
+// stay with their positions; LastFocusedIdx follows the focused window.
+// Returns false if the cell or either index doesn't exist.
+func (ss *SpaceState) SwapInCell(cellID string, i, j int) bool {
+	cell, ok := ss.Cells[cellID]
+	if !ok || i < 0 || j < 0 || i >= len(cell.Windows) || j >= len(cell.Windows) {
+		return false
+	}
+
+	cell.Windows[i], cell.Windows[j] = cell.Windows[j], cell.Windows[i]
+	switch cell.LastFocusedIdx {
+	case i:
+		cell.LastFocusedIdx = j
+	case j:
+		cell.LastFocusedIdx = i
+	}
+	return true
+}
+
+// SwapWindows exchanges two windows, each taking the other's cell and stack
+// position. Split ratios stay with their positions. Returns false if either
+// window isn't assigned to a cell.
+func (ss *SpaceState) SwapWindows(a, b uint32) bool {
+	cellA, idxA := ss.windowPosition(a)
+	cellB, idxB := ss.windowPosition(b)
+	if cellA == nil || cellB == nil {
+		return false
+	}
+	cellA.Windows[idxA], cellB.Windows[idxB] = b, a
+	return true
+}
+
+// windowPosition returns the cell holding a window and its index there
+func (ss *SpaceState) windowPosition(windowID uint32) (*CellState, int) {
+	for _, cell := range ss.Cells {
+		for i, wid := range cell.Windows {
+			if wid == windowID {
+				return cell, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// SetMaster designates a window as the space's master window.
+// Returns false if the window isn't assigned to any cell.
+func (ss *SpaceState) SetMaster(windowID uint32) bool {
+	if ss.GetWindowCell(windowID) == "" {
+		return false
+	}
+	ss.MasterWindow = windowID
+	return true
+}
+
+// GetMaster returns the master window, falling back to the first window of
+// the first non-empty cell in cellOrder when none is set. Returns 0 if there
+// are no windows.
+func (ss *SpaceState) GetMaster(cellOrder []string) uint32 {
+	if ss.MasterWindow != 0 && ss.GetWindowCell(ss.MasterWindow) != "" {
+		return ss.MasterWindow
+	}
+	for _, cellID := range cellOrder {
+		if cell, ok := ss.Cells[cellID]; ok && len(cell.Windows) > 0 {
+			return cell.Windows[0]
+		}
+	}
+	return 0
+}
+
 // GetWindowCell returns the cell ID containing a window, or empty string if not found
 func (ss *SpaceState) GetWindowCell(windowID uint32) string {
 	for cellID, cell := range ss.Cells {
@@ -244,12 +484,32 @@ func (ss *SpaceState) SetFocus(cellID string, windowIndex int) {
 	ss.FocusedCell = cellID
 	ss.FocusedWindow = windowIndex
 
-	// Also update the cell's LastFocusedIdx for persistence
+	// Also update the cell's LastFocusedIdx for persistence, and bring the
+	// window to the front of the cell
 	if cell, ok := ss.Cells[cellID]; ok {
 		cell.LastFocusedIdx = windowIndex
+		if windowIndex >= 0 && windowIndex < len(cell.Windows) {
+			cell.Raise(cell.Windows[windowIndex])
+		}
 	}
 }
 
+// SetFocusedWindow focuses the cell and stack position holding windowID.
+// Returns false if the space doesn't track the window.
+func (ss *SpaceState) SetFocusedWindow(windowID uint32) bool {
+	cellID := ss.GetWindowCell(windowID)
+	if cellID == "" {
+		return false
+	}
+	for i, wid := range ss.Cells[cellID].Windows {
+		if wid == windowID {
+			ss.SetFocus(cellID, i)
+			return true
+		}
+	}
+	return false
+}
+
 // GetFocusedWindow returns the currently focused window ID, or 0 if none
 func (ss *SpaceState) GetFocusedWindow() uint32 {
 	if ss.FocusedCell == "" {
@@ -280,3 +540,20 @@ func equalRatios(n int) []float64 {
 	}
 	return ratios
 }
+
+// adjustFocusedIdx returns the last-focused index after the window at
+// removedIdx left a cell that now holds remaining windows. A focused window
+// that leaves hands focus to its predecessor; other indices shift to keep
+// pointing at the same window.
+func adjustFocusedIdx(focusedIdx, removedIdx, remaining int) int {
+	if remaining == 0 {
+		return 0
+	}
+	if removedIdx <= focusedIdx && focusedIdx > 0 {
+		focusedIdx--
+	}
+	if focusedIdx >= remaining {
+		focusedIdx = remaining - 1
+	}
+	return focusedIdx
+}
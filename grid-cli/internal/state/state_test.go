@@ -1,9 +1,17 @@
 package state
 
 import (
+	"fmt"
+	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/yourusername/grid-cli/internal/types"
 )
@@ -167,6 +175,53 @@ func TestRemoveWindow(t *testing.T) {
 	}
 }
 
+func TestRemoveWindow_LastFocusedIdx(t *testing.T) {
+	tests := []struct {
+		name       string
+		focusedIdx int
+		remove     uint32
+		wantIdx    int
+		wantWindow uint32
+	}{
+		{"focused middle window leaves", 1, 2, 0, 1},
+		{"focused first window leaves", 0, 1, 0, 2},
+		{"focused last window leaves", 2, 3, 1, 2},
+		{"window before focused leaves", 2, 1, 1, 3},
+		{"window after focused leaves", 0, 3, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := NewSpaceState("1")
+			space.AssignWindow(1, "left")
+			space.AssignWindow(2, "left")
+			space.AssignWindow(3, "left")
+			space.SetFocus("left", tt.focusedIdx)
+
+			space.RemoveWindow(tt.remove)
+
+			cell := space.Cells["left"]
+			if cell.LastFocusedIdx != tt.wantIdx {
+				t.Errorf("LastFocusedIdx = %d, want %d", cell.LastFocusedIdx, tt.wantIdx)
+			}
+			if cell.Windows[cell.LastFocusedIdx] != tt.wantWindow {
+				t.Errorf("last focused window = %d, want %d", cell.Windows[cell.LastFocusedIdx], tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestRemoveWindow_LastFocusedIdxEmptiesCell(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+
+	space.RemoveWindow(1)
+
+	if space.Cells["left"].LastFocusedIdx != 0 {
+		t.Errorf("LastFocusedIdx = %d, want 0 for empty cell", space.Cells["left"].LastFocusedIdx)
+	}
+}
+
 func TestGetWindowCell(t *testing.T) {
 	state := NewRuntimeState()
 	space := state.GetSpace("1")
@@ -324,6 +379,122 @@ func TestSplitRatios(t *testing.T) {
 	}
 }
 
+func TestSetMaster(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+
+	if space.SetMaster(99) {
+		t.Error("SetMaster should reject an unassigned window")
+	}
+	if !space.SetMaster(1) {
+		t.Fatal("SetMaster should accept an assigned window")
+	}
+	if space.MasterWindow != 1 {
+		t.Errorf("MasterWindow = %d, want 1", space.MasterWindow)
+	}
+}
+
+func TestGetMaster_Fallback(t *testing.T) {
+	space := NewSpaceState("1")
+	if got := space.GetMaster([]string{"left", "right"}); got != 0 {
+		t.Errorf("GetMaster on empty space = %d, want 0", got)
+	}
+
+	space.AssignWindow(2, "right")
+	space.AssignWindow(3, "right")
+	if got := space.GetMaster([]string{"left", "right"}); got != 2 {
+		t.Errorf("GetMaster fallback = %d, want 2", got)
+	}
+
+	space.SetMaster(3)
+	if got := space.GetMaster([]string{"left", "right"}); got != 3 {
+		t.Errorf("GetMaster = %d, want 3", got)
+	}
+}
+
+func TestRemoveWindow_PromotesMaster(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.AssignWindow(3, "left")
+	space.SetMaster(2)
+
+	space.RemoveWindow(2)
+	if space.MasterWindow != 3 {
+		t.Errorf("MasterWindow = %d, want 3 (next window promoted)", space.MasterWindow)
+	}
+
+	space.RemoveWindow(3)
+	if space.MasterWindow != 1 {
+		t.Errorf("MasterWindow = %d, want 1 (previous window promoted)", space.MasterWindow)
+	}
+
+	space.RemoveWindow(1)
+	if space.MasterWindow != 0 {
+		t.Errorf("MasterWindow = %d, want 0 after cell emptied", space.MasterWindow)
+	}
+}
+
+func TestAssignWindow_MovedMasterStaysMaster(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.SetMaster(1)
+
+	space.AssignWindow(1, "right")
+	if space.MasterWindow != 1 {
+		t.Errorf("MasterWindow = %d after move, want 1", space.MasterWindow)
+	}
+
+	space.PrependWindowToCell(1, "left")
+	if space.MasterWindow != 1 {
+		t.Errorf("MasterWindow = %d after prepend, want 1", space.MasterWindow)
+	}
+}
+
+func TestPrependWindowToCell_EqualizesRatios(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.Cells["left"].SplitRatios = []float64{0.8, 0.2}
+	space.Cells["left"].RatiosUserSet = true
+
+	space.PrependWindowToCell(3, "left")
+
+	cell := space.Cells["left"]
+	for _, r := range cell.SplitRatios {
+		if math.Abs(r-1.0/3) > 1e-9 {
+			t.Fatalf("expected equal ratios, got %v", cell.SplitRatios)
+		}
+	}
+	if cell.RatiosUserSet {
+		t.Error("equalized ratios should not be marked user set")
+	}
+}
+
+func TestPrependWindowToCellKeepRatios(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.Cells["left"].SplitRatios = []float64{0.8, 0.2}
+
+	space.PrependWindowToCellKeepRatios(3, "left")
+
+	cell := space.Cells["left"]
+	if len(cell.Windows) != 3 || cell.Windows[0] != 3 {
+		t.Fatalf("expected window 3 prepended, got %v", cell.Windows)
+	}
+	want := []float64{1.0 / 3, 0.8 * 2 / 3, 0.2 * 2 / 3}
+	for i, r := range want {
+		if math.Abs(cell.SplitRatios[i]-r) > 1e-9 {
+			t.Fatalf("expected ratios %v, got %v", want, cell.SplitRatios)
+		}
+	}
+	if !cell.RatiosUserSet {
+		t.Error("kept ratios should be marked user set")
+	}
+}
+
 // === Persistence Tests ===
 
 func TestLoadState_NoFile(t *testing.T) {
@@ -366,6 +537,34 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestLoadState_MigratesResizedRatios(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+	v1 := `{"version": 1, "spaces": {"1": {"spaceId": "1", "cells": {
+		"left": {"windows": [1, 2], "splitRatios": [0.7, 0.3]},
+		"right": {"windows": [3, 4], "splitRatios": [0.5, 0.5]}
+	}}}}`
+	if err := os.WriteFile(tmpFile, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadStateFrom(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ReleaseLock()
+
+	if loaded.Version != StateVersion {
+		t.Errorf("Version = %d, want %d", loaded.Version, StateVersion)
+	}
+	cells := loaded.Spaces["1"].Cells
+	if !cells["left"].RatiosUserSet {
+		t.Error("unequal v1 ratios should be marked user-set")
+	}
+	if cells["right"].RatiosUserSet {
+		t.Error("an even v1 split should not be marked user-set")
+	}
+}
+
 func TestSave_CreatesDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 	nestedPath := filepath.Join(tmpDir, "nested", "dirs", "state.json")
@@ -485,6 +684,49 @@ func TestSetCellStackMode(t *testing.T) {
 	}
 }
 
+func TestSetSpaceStackModeAllCells(t *testing.T) {
+	state := NewRuntimeState()
+	state.SetCellStackMode("1", "left", types.StackHorizontal)
+	state.SetCellStackMode("1", "right", "")
+	state.SetCellStackMode("2", "left", types.StackVertical)
+
+	state.SetSpaceStackMode("1", types.StackTabs, true)
+
+	for _, cellID := range []string{"left", "right"} {
+		if mode := state.GetCellStackMode("1", cellID); mode != types.StackTabs {
+			t.Errorf("cell %s: expected tabs, got %q", cellID, mode)
+		}
+	}
+	if mode := state.GetCellStackMode("2", "left"); mode != types.StackVertical {
+		t.Errorf("other space changed: expected vertical, got %q", mode)
+	}
+}
+
+func TestSetSpaceStackModeDefaultAppliesToNewCells(t *testing.T) {
+	state := NewRuntimeState()
+	state.SetCellStackMode("1", "left", types.StackHorizontal)
+
+	state.SetSpaceStackMode("1", types.StackTabs, false)
+
+	// Existing cells keep their mode without --all-cells
+	if mode := state.GetCellStackMode("1", "left"); mode != types.StackHorizontal {
+		t.Errorf("existing cell: expected horizontal, got %q", mode)
+	}
+
+	// The default is resolved when the layout is applied, not copied into
+	// new cells, so it doesn't override the layout's own cell modes
+	space := state.GetSpace("1")
+	if cell := space.GetCell("new"); cell.StackMode != "" {
+		t.Errorf("new cell: expected no mode of its own, got %q", cell.StackMode)
+	}
+
+	// The default survives a layout change, which clears the cells
+	space.SetCurrentLayout("other", 1)
+	if space.DefaultStackMode != types.StackTabs {
+		t.Errorf("default after layout change: expected tabs, got %q", space.DefaultStackMode)
+	}
+}
+
 func TestGetCurrentLayoutForSpace(t *testing.T) {
 	state := NewRuntimeState()
 	space := state.GetSpace("1")
@@ -579,3 +821,774 @@ func TestSummary(t *testing.T) {
 		t.Error("windowCount incorrect")
 	}
 }
+
+func TestSpaceSummary(t *testing.T) {
+	state := NewRuntimeState()
+	space := state.GetSpace("1")
+	space.SetCurrentLayout("test-layout", 0)
+	space.AssignWindow(123, "right")
+	space.AssignWindow(456, "left")
+	space.GetCell("left").StackMode = types.StackTabs
+	state.GetSpace("2").AssignWindow(789, "main")
+
+	summary, ok := state.SpaceSummary("1")
+	if !ok {
+		t.Fatal("expected summary for space 1")
+	}
+	if summary["windowCount"] != 2 || summary["currentLayout"] != "test-layout" {
+		t.Errorf("unexpected summary fields: %v", summary)
+	}
+
+	cells := summary["cells"].([]map[string]interface{})
+	if len(cells) != 2 || cells[0]["cellId"] != "left" || cells[1]["cellId"] != "right" {
+		t.Fatalf("cells not sorted by ID: %v", cells)
+	}
+	if cells[0]["stackMode"] != types.StackTabs {
+		t.Errorf("stackMode = %v, want tabs", cells[0]["stackMode"])
+	}
+}
+
+// === Backup Tests ===
+
+// withAutoBackup enables automatic backups for the duration of a test
+func withAutoBackup(t *testing.T, keep int) {
+	t.Helper()
+	SetBackupPolicy(true, keep)
+	ResetBackups()
+	t.Cleanup(func() {
+		SetBackupPolicy(false, 0)
+		ResetBackups()
+	})
+}
+
+func TestSave_BackupPolicyFuncAndReset(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+	state := NewRuntimeState()
+	if err := state.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+
+	withAutoBackup(t, 5)
+	loads := 0
+	SetBackupPolicyFunc(func() (bool, int) {
+		loads++
+		return loads > 1, 0
+	})
+
+	// The first command's config has backups off
+	if err := state.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+	// The next command (after a reset) has them on, once per command
+	ResetBackups()
+	for i := 0; i < 2; i++ {
+		if err := state.SaveTo(tmpFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := ListBackups(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || loads != 2 {
+		t.Errorf("expected 1 backup after 2 policy loads, got %d backups and %d loads", len(names), loads)
+	}
+}
+
+func TestSave_AutoBackupBeforeMutation(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+
+	state := NewRuntimeState()
+	state.GetSpace("1").AssignWindow(123, "left")
+	if err := state.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+
+	withAutoBackup(t, 5)
+
+	state.GetSpace("1").AssignWindow(456, "right")
+	if err := state.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+	// A second save in the same process should not add another backup
+	if err := state.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := ListBackups(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 {
+		t.Fatalf("expected 1 backup, got %d", len(names))
+	}
+
+	backup, err := LoadStateFrom(filepath.Join(GetBackupDir(tmpFile), names[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup.Spaces["1"].GetWindowCell(456) != "" {
+		t.Error("backup should hold the state from before the mutation")
+	}
+}
+
+func TestSave_NoBackupWhenDisabled(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+
+	state := NewRuntimeState()
+	state.SaveTo(tmpFile)
+	state.SaveTo(tmpFile)
+
+	names, _ := ListBackups(tmpFile)
+	if len(names) != 0 {
+		t.Errorf("expected no backups, got %v", names)
+	}
+}
+
+func TestBackupStateFile_Rotation(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+	NewRuntimeState().SaveTo(tmpFile)
+
+	for i := 0; i < 5; i++ {
+		if _, err := BackupStateFile(tmpFile, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err := ListBackups(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 {
+		t.Errorf("expected rotation to keep 3 backups, got %d", len(names))
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+
+	state := NewRuntimeState()
+	state.GetSpace("1").SetCurrentLayout("first", 0)
+	state.SaveTo(tmpFile)
+	first, err := BackupStateFile(tmpFile, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state.GetSpace("1").SetCurrentLayout("second", 1)
+	state.SaveTo(tmpFile)
+	if _, err := BackupStateFile(tmpFile, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Named", func(t *testing.T) {
+		if _, err := RestoreBackup(tmpFile, first); err != nil {
+			t.Fatal(err)
+		}
+		loaded, _ := LoadStateFrom(tmpFile)
+		if loaded.Spaces["1"].CurrentLayoutID != "first" {
+			t.Errorf("expected layout 'first', got %q", loaded.Spaces["1"].CurrentLayoutID)
+		}
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		if _, err := RestoreBackup(tmpFile, ""); err != nil {
+			t.Fatal(err)
+		}
+		loaded, _ := LoadStateFrom(tmpFile)
+		if loaded.Spaces["1"].CurrentLayoutID != "second" {
+			t.Errorf("expected layout 'second', got %q", loaded.Spaces["1"].CurrentLayoutID)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		if _, err := RestoreBackup(tmpFile, "state-missing.json"); err == nil {
+			t.Error("expected error for missing backup")
+		}
+	})
+}
+
+// === Locking Tests ===
+
+// TestConcurrentSaves_NoLostUpdates runs several processes that each load
+// the state, add a window and save, as rapid keybindings would. The state
+// lock serializes them, so every window survives.
+func TestConcurrentSaves_NoLostUpdates(t *testing.T) {
+	if path := os.Getenv("GRID_STATE_LOCK_PATH"); path != "" {
+		windowID, _ := strconv.Atoi(os.Getenv("GRID_STATE_LOCK_WINDOW"))
+		rs, err := LoadStateFrom(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond) // Widen the read-modify-write window
+		rs.GetSpace("1").AssignWindow(uint32(windowID), "main")
+		if err := rs.SaveTo(path); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := NewRuntimeState().SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+
+	const procs = 8
+	cmds := make([]*exec.Cmd, procs)
+	outputs := make([]*strings.Builder, procs)
+	for i := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestConcurrentSaves_NoLostUpdates$")
+		cmd.Env = append(os.Environ(),
+			"GRID_STATE_LOCK_PATH="+path,
+			fmt.Sprintf("GRID_STATE_LOCK_WINDOW=%d", i+1),
+		)
+		outputs[i] = &strings.Builder{}
+		cmd.Stdout = outputs[i]
+		cmd.Stderr = outputs[i]
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		cmds[i] = cmd
+	}
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("saver %d failed: %v\n%s", i+1, err, outputs[i])
+		}
+	}
+
+	loaded, err := readStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	windows := loaded.Spaces["1"].Cells["main"].Windows
+	if len(windows) != procs {
+		t.Errorf("expected %d windows after concurrent saves, got %v", procs, windows)
+	}
+}
+
+func TestLoadState_WaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := NewRuntimeState().SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process holding the lock (a separate open file conflicts too)
+	other, err := os.OpenFile(path+lockSuffix, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := syscall.Flock(int(other.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := lockTimeout
+	lockTimeout = 50 * time.Millisecond
+	defer func() { lockTimeout = saved }()
+
+	if _, err := LoadStateFrom(path); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+
+	// Released shortly after: the retry picks it up
+	lockTimeout = time.Second
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		syscall.Flock(int(other.Fd()), syscall.LOCK_UN)
+	}()
+	rs, err := LoadStateFrom(path)
+	if err != nil {
+		t.Fatalf("expected lock after release, got %v", err)
+	}
+	if err := rs.SaveTo(path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwapInCell(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "main")
+	ss.AssignWindow(2, "main")
+	ss.AssignWindow(3, "main")
+	ss.SetFocus("main", 0)
+
+	if !ss.SwapInCell("main", 0, 1) {
+		t.Fatal("expected swap to succeed")
+	}
+	cell := ss.Cells["main"]
+	if cell.Windows[0] != 2 || cell.Windows[1] != 1 || cell.Windows[2] != 3 {
+		t.Errorf("expected [2 1 3], got %v", cell.Windows)
+	}
+	if cell.LastFocusedIdx != 1 {
+		t.Errorf("expected focus to follow window 1 to index 1, got %d", cell.LastFocusedIdx)
+	}
+
+	if ss.SwapInCell("main", 2, 3) {
+		t.Error("expected out-of-range swap to fail")
+	}
+	if ss.SwapInCell("missing", 0, 1) {
+		t.Error("expected swap in unknown cell to fail")
+	}
+}
+
+func TestSwapWindows(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "left")
+	ss.AssignWindow(2, "left")
+	ss.AssignWindow(3, "right")
+
+	if !ss.SwapWindows(2, 3) {
+		t.Fatal("expected swap to succeed")
+	}
+	if got := ss.Cells["left"].Windows; got[0] != 1 || got[1] != 3 {
+		t.Errorf("expected left [1 3], got %v", got)
+	}
+	if got := ss.Cells["right"].Windows; got[0] != 2 {
+		t.Errorf("expected right [2], got %v", got)
+	}
+
+	if ss.SwapWindows(1, 99) {
+		t.Error("expected swap with unassigned window to fail")
+	}
+}
+
+func TestSnapshotAndRestoreSpaces(t *testing.T) {
+	rs := NewRuntimeState()
+	rs.GetSpace("1").AssignWindow(1, "main")
+
+	snapshot := rs.SnapshotSpaces("1", "2", "")
+	if _, ok := snapshot[""]; ok {
+		t.Error("empty space IDs should be skipped")
+	}
+
+	rs.GetSpace("1").AssignWindow(2, "main")
+	rs.GetSpace("2").AssignWindow(3, "side")
+
+	rs.RestoreSpaces(snapshot)
+
+	if windows := rs.GetSpaceReadOnly("1").Cells["main"].Windows; len(windows) != 1 || windows[0] != 1 {
+		t.Errorf("expected space 1 restored to [1], got %v", windows)
+	}
+	if rs.GetSpaceReadOnly("2") != nil {
+		t.Error("space 2 had no state in the snapshot and should be removed")
+	}
+}
+
+func TestRestoreUserRatios(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "a")
+	ss.AssignWindow(2, "a")
+	ss.AssignWindow(3, "b")
+	ss.Cells["a"].SplitRatios = []float64{0.7, 0.3}
+	ss.Cells["a"].RatiosUserSet = true
+
+	ratios := ss.UserRatios()
+	if len(ratios) != 1 || ratios["a"] == nil {
+		t.Fatalf("expected only cell a's ratios, got %v", ratios)
+	}
+
+	// Reassignment equalizes; restoring brings the user ratios back
+	ss.Cells = make(map[string]*CellState)
+	ss.AssignWindow(1, "a")
+	ss.AssignWindow(2, "a")
+	ss.RestoreUserRatios(ratios)
+	if a := ss.Cells["a"]; !a.RatiosUserSet || a.SplitRatios[0] != 0.7 {
+		t.Errorf("cell a = %+v, want user ratios restored", a)
+	}
+
+	// A cell that gained a window can't take its old ratios back
+	ss.AssignWindow(4, "a")
+	if ss.Cells["a"].RatiosUserSet {
+		t.Error("adding a window should re-equalize and clear the user flag")
+	}
+	ss.RestoreUserRatios(ratios)
+	if ss.Cells["a"].RatiosUserSet {
+		t.Error("ratios for two windows restored onto a cell with three")
+	}
+}
+
+func TestSetFocusedWindow(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "a")
+	ss.AssignWindow(2, "b")
+	ss.AssignWindow(3, "b")
+
+	if !ss.SetFocusedWindow(3) {
+		t.Fatal("expected window 3 to be found")
+	}
+	if ss.FocusedCell != "b" || ss.FocusedWindow != 1 || ss.Cells["b"].LastFocusedIdx != 1 {
+		t.Errorf("focus = %s[%d], want b[1]", ss.FocusedCell, ss.FocusedWindow)
+	}
+
+	if ss.SetFocusedWindow(99) {
+		t.Error("untracked window should not take focus")
+	}
+	if ss.GetFocusedWindow() != 3 {
+		t.Errorf("focus changed to %d for an untracked window", ss.GetFocusedWindow())
+	}
+}
+
+func TestFocusHistory_WalksBackWithoutRepushing(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "left")
+	ss.AssignWindow(2, "right")
+	ss.AssignWindow(3, "right")
+
+	// Focus 1, then 2, then 3
+	ss.SetFocus("left", 0)
+	ss.PushFocusHistory()
+	ss.SetFocus("right", 0)
+	ss.PushFocusHistory()
+	ss.SetFocus("right", 1)
+
+	entry, ok := ss.PopFocusHistory()
+	if !ok || entry.WindowID != 2 || entry.CellID != "right" || entry.WindowIndex != 0 {
+		t.Fatalf("expected window 2 in right, got %+v (ok=%v)", entry, ok)
+	}
+	ss.SetFocus(entry.CellID, entry.WindowIndex)
+
+	entry, ok = ss.PopFocusHistory()
+	if !ok || entry.WindowID != 1 {
+		t.Fatalf("expected window 1, got %+v (ok=%v)", entry, ok)
+	}
+	ss.SetFocus(entry.CellID, entry.WindowIndex)
+
+	if _, ok := ss.PopFocusHistory(); ok {
+		t.Error("expected history to be exhausted")
+	}
+}
+
+func TestFocusHistory_SkipsClosedWindows(t *testing.T) {
+	ss := NewSpaceState("1")
+	ss.AssignWindow(1, "left")
+	ss.AssignWindow(2, "left")
+	ss.AssignWindow(3, "right")
+
+	ss.SetFocus("left", 0)
+	ss.PushFocusHistory()
+	ss.SetFocus("left", 1)
+	ss.PushFocusHistory()
+	ss.SetFocus("right", 0)
+
+	// Window 2 closes and window 1 moves cells
+	ss.RemoveWindow(2)
+	ss.AssignWindow(1, "right")
+
+	entry, ok := ss.PopFocusHistory()
+	if !ok || entry.WindowID != 1 || entry.CellID != "right" || entry.WindowIndex != 1 {
+		t.Fatalf("expected window 1 at its new position right/1, got %+v (ok=%v)", entry, ok)
+	}
+}
+
+func TestFocusHistory_Bounded(t *testing.T) {
+	ss := NewSpaceState("1")
+	for i := 0; i < FocusHistorySize+5; i++ {
+		ss.AssignWindow(uint32(i+1), "main")
+	}
+	for i := 0; i < FocusHistorySize+5; i++ {
+		ss.SetFocus("main", i)
+		ss.PushFocusHistory()
+	}
+
+	if len(ss.FocusHistory) != FocusHistorySize {
+		t.Fatalf("expected %d entries, got %d", FocusHistorySize, len(ss.FocusHistory))
+	}
+	if ss.FocusHistory[0].WindowID != 6 {
+		t.Errorf("expected oldest entries dropped, first is window %d", ss.FocusHistory[0].WindowID)
+	}
+}
+
+func TestZOrder_FocusRaises(t *testing.T) {
+	space := NewSpaceState("1")
+	space.AssignWindow(1, "tabs")
+	space.AssignWindow(2, "tabs")
+	space.AssignWindow(3, "tabs")
+
+	cell := space.Cells["tabs"]
+	if !reflect.DeepEqual(cell.ZOrder, []uint32{3, 2, 1}) {
+		t.Fatalf("ZOrder = %v, want newest window in front [3 2 1]", cell.ZOrder)
+	}
+
+	space.SetFocus("tabs", 0)
+	if !reflect.DeepEqual(cell.ZOrder, []uint32{1, 3, 2}) {
+		t.Errorf("ZOrder = %v, want focused tab in front [1 3 2]", cell.ZOrder)
+	}
+	if front := cell.Frontmost(); front != 1 {
+		t.Errorf("Frontmost = %d, want 1", front)
+	}
+
+	space.RemoveWindow(1)
+	if front := cell.Frontmost(); front != 3 {
+		t.Errorf("Frontmost = %d, want 3 after the front tab closed", front)
+	}
+}
+
+func TestZOrder_FrontmostFallsBackToLastFocused(t *testing.T) {
+	cell := &CellState{Windows: []uint32{1, 2}, LastFocusedIdx: 1}
+	if front := cell.Frontmost(); front != 2 {
+		t.Errorf("Frontmost = %d, want 2 from LastFocusedIdx", front)
+	}
+	if front := (&CellState{}).Frontmost(); front != 0 {
+		t.Errorf("Frontmost of an empty cell = %d, want 0", front)
+	}
+}
+
+func TestSetWindowAssignments_KeepsZOrder(t *testing.T) {
+	rs := NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.AssignWindow(1, "tabs")
+	space.AssignWindow(2, "tabs")
+	space.AssignWindow(3, "tabs")
+	space.SetFocus("tabs", 1)
+
+	// Reapply keeps 2 and 3, drops 1 and adds 4
+	rs.SetWindowAssignments("1", map[string][]uint32{"tabs": {2, 3, 4}})
+
+	cell := rs.GetSpace("1").Cells["tabs"]
+	if !reflect.DeepEqual(cell.ZOrder, []uint32{2, 3}) {
+		t.Errorf("ZOrder = %v, want [2 3]", cell.ZOrder)
+	}
+	if cell.LastFocusedIdx != 0 {
+		t.Errorf("LastFocusedIdx = %d, want 0 (window 2)", cell.LastFocusedIdx)
+	}
+}
+
+func TestSetWindowAssignments_KeepsStackMode(t *testing.T) {
+	rs := NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.AssignWindow(1, "main")
+	space.Cells["main"].StackMode = types.StackTabs
+
+	rs.SetWindowAssignments("1", map[string][]uint32{"main": {1, 2}})
+
+	if mode := rs.GetSpace("1").Cells["main"].StackMode; mode != types.StackTabs {
+		t.Errorf("StackMode = %q, want tabs", mode)
+	}
+}
+
+func TestMRUCycle_WalksBackThroughFocusOrder(t *testing.T) {
+	space := NewSpaceState("1")
+	for _, id := range []uint32{1, 2, 3, 4} {
+		space.AssignWindow(id, "main")
+	}
+	// Forget the order windows were added in, then focus 3, 1 and 4; 2 is
+	// never focused and comes last
+	cell := space.Cells["main"]
+	cell.ZOrder = nil
+	space.SetFocus("main", 2)
+	space.SetFocus("main", 0)
+	space.SetFocus("main", 3)
+
+	if got := cell.MRUOrder(); !reflect.DeepEqual(got, []uint32{4, 1, 3, 2}) {
+		t.Fatalf("MRUOrder = %v, want [4 1 3 2]", got)
+	}
+	if got := cell.MRUCycle(true); !reflect.DeepEqual(got, []uint32{1, 3, 2, 4}) {
+		t.Errorf("forward = %v, want [1 3 2 4]", got)
+	}
+	if got := cell.MRUCycle(false); !reflect.DeepEqual(got, []uint32{2, 4, 1, 3}) {
+		t.Errorf("backward = %v, want [2 4 1 3]", got)
+	}
+}
+
+func TestPinWindow_UnpinAndPrune(t *testing.T) {
+	ss := NewRuntimeState().GetSpace("1")
+	ss.PinWindow(100, "left")
+	ss.PinWindow(200, "right")
+
+	if !ss.UnpinWindow(100) || ss.UnpinWindow(100) {
+		t.Error("expected window 100 to unpin exactly once")
+	}
+
+	dropped := ss.PrunePins(map[uint32]bool{300: true})
+	if !reflect.DeepEqual(dropped, []uint32{200}) {
+		t.Errorf("dropped = %v, want [200]", dropped)
+	}
+	if ss.PinnedWindows != nil {
+		t.Errorf("expected no pins left, got %v", ss.PinnedWindows)
+	}
+}
+
+func TestFloatWindow_UnfloatAndPrune(t *testing.T) {
+	ss := NewRuntimeState().GetSpace("1")
+	ss.AssignWindow(100, "left")
+	ss.AssignWindow(101, "left")
+
+	ss.FloatWindow(100)
+	if ss.GetWindowCell(100) != "" || !ss.IsFloated(100) {
+		t.Fatalf("expected window 100 floated and out of its cell, cell = %q", ss.GetWindowCell(100))
+	}
+	if !reflect.DeepEqual(ss.Cells["left"].Windows, []uint32{101}) {
+		t.Errorf("left = %v, want [101]", ss.Cells["left"].Windows)
+	}
+
+	if !ss.UnfloatWindow(100) || ss.UnfloatWindow(100) {
+		t.Error("expected window 100 to unfloat exactly once")
+	}
+	if !ss.ReinsertWindows[100] {
+		t.Error("expected the unfloated window to be queued for reinsertion")
+	}
+
+	ss.FloatWindow(200)
+	dropped := ss.PruneFloats(map[uint32]bool{300: true})
+	if len(dropped) != 2 {
+		t.Errorf("dropped = %v, want 100 and 200", dropped)
+	}
+	if ss.FloatedWindows != nil || ss.ReinsertWindows != nil {
+		t.Errorf("expected no floats left, got %v / %v", ss.FloatedWindows, ss.ReinsertWindows)
+	}
+}
+
+func TestAssignWindow_UnfloatsWindow(t *testing.T) {
+	ss := NewRuntimeState().GetSpace("1")
+	ss.FloatWindow(100)
+
+	ss.AssignWindow(100, "left")
+	if ss.IsFloated(100) {
+		t.Error("a window assigned to a cell should stop floating")
+	}
+}
+
+func TestPinnedWindows_SaveLoadAndClone(t *testing.T) {
+	tmpFile := filepath.Join(t.TempDir(), "state.json")
+
+	rs := NewRuntimeState()
+	rs.GetSpace("1").PinWindow(123, "left")
+	if err := rs.SaveTo(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadStateFrom(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	space := loaded.Spaces["1"]
+	if space.PinnedWindows[123] != "left" {
+		t.Fatalf("pin not preserved: %v", space.PinnedWindows)
+	}
+
+	clone := space.Clone()
+	clone.PinWindow(123, "right")
+	if space.PinnedWindows[123] != "left" {
+		t.Error("changing a clone's pin changed the original")
+	}
+}
+
+func TestPruneSpans(t *testing.T) {
+	ss := NewRuntimeState().GetSpace("1")
+	ss.AssignWindow(100, "left")
+	ss.AddSpan("left", "right")
+	ss.AddSpan("left", "bottom")
+
+	if ss.PruneSpans() {
+		t.Error("nothing should be pruned while the absorbed cells are empty")
+	}
+	if got := ss.SpanningCell("right"); got != "left" {
+		t.Errorf("SpanningCell(right) = %q, want left", got)
+	}
+
+	ss.AssignWindow(101, "right")
+	if !ss.PruneSpans() || !reflect.DeepEqual(ss.Spans["left"], []string{"bottom"}) {
+		t.Errorf("expected only the span into bottom left, got %v", ss.Spans)
+	}
+	if clone := ss.Clone(); !reflect.DeepEqual(clone.Spans, ss.Spans) {
+		t.Errorf("clone spans = %v, want %v", clone.Spans, ss.Spans)
+	}
+
+	ss.RemoveWindow(100)
+	ss.PruneSpans()
+	if ss.Spans != nil {
+		t.Errorf("an empty cell keeps no spans, got %v", ss.Spans)
+	}
+
+	ss.AssignWindow(100, "left")
+	ss.AddSpan("left", "bottom")
+	ss.SetCurrentLayout("other", 1)
+	if ss.Spans != nil {
+		t.Errorf("changing layout should drop spans, got %v", ss.Spans)
+	}
+}
+
+func TestMoveHistory_CapsAndSkipsGoneWindows(t *testing.T) {
+	ss := &SpaceState{Cells: make(map[string]*CellState)}
+	ss.AssignWindow(1, "a")
+
+	for i := 0; i < MoveHistorySize+3; i++ {
+		ss.PushMoveHistory(MoveEntry{WindowID: 1, SourceIndex: i})
+	}
+	if len(ss.MoveHistory) != MoveHistorySize {
+		t.Fatalf("expected %d entries, got %d", MoveHistorySize, len(ss.MoveHistory))
+	}
+	if ss.MoveHistory[0].SourceIndex != 3 {
+		t.Errorf("expected the oldest entries dropped, first is %d", ss.MoveHistory[0].SourceIndex)
+	}
+
+	ss.MoveHistory = []MoveEntry{{WindowID: 1, SourceCell: "b"}, {WindowID: 2, SourceCell: "c"}}
+	entry, ok := ss.PopMoveHistory()
+	if !ok || entry.WindowID != 1 {
+		t.Errorf("expected the entry for window 1, got %+v, %v", entry, ok)
+	}
+	if _, ok := ss.PopMoveHistory(); ok {
+		t.Error("expected the history to be empty")
+	}
+}
+
+func TestReadOnly_NeverTouchesStateFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	rs, err := LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.GetSpace("1").SetCurrentLayout("full", 1)
+	if err := rs.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Dir(GetStatePath())); !os.IsNotExist(err) {
+		t.Errorf("expected no state directory, got %v", err)
+	}
+}
+
+func TestReadOnly_IgnoresExistingStateFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	existing := NewRuntimeState()
+	existing.GetSpace("1").SetCurrentLayout("half", 0)
+	if err := existing.Save(); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(GetStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	rs, err := LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.Spaces) != 0 {
+		t.Errorf("expected empty state, got %d spaces", len(rs.Spaces))
+	}
+	rs.GetSpace("1").SetCurrentLayout("full", 1)
+	if err := rs.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(GetStatePath()); string(after) != string(before) {
+		t.Error("state file changed in read-only mode")
+	}
+}
+
+func TestReadOnly_Env(t *testing.T) {
+	t.Setenv(ReadOnlyEnv, "1")
+	if !ReadOnly() {
+		t.Error("expected GRID_READ_ONLY=1 to turn on read-only mode")
+	}
+	t.Setenv(ReadOnlyEnv, "false")
+	if ReadOnly() {
+		t.Error("expected GRID_READ_ONLY=false to leave read-only mode off")
+	}
+}
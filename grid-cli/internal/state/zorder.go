@@ -0,0 +1,106 @@
+package state
+
+// Raise moves a window to the front of the cell's z-order
+func (c *CellState) Raise(windowID uint32) {
+	zOrder := make([]uint32, 0, len(c.ZOrder)+1)
+	zOrder = append(zOrder, windowID)
+	for _, wid := range c.ZOrder {
+		if wid != windowID {
+			zOrder = append(zOrder, wid)
+		}
+	}
+	c.ZOrder = zOrder
+}
+
+// Frontmost returns the cell's frontmost window: the first window in ZOrder
+// still in the cell, else the window at LastFocusedIdx. Returns 0 for an
+// empty cell.
+func (c *CellState) Frontmost() uint32 {
+	if len(c.Windows) == 0 {
+		return 0
+	}
+	for _, wid := range c.ZOrder {
+		if c.indexOf(wid) >= 0 {
+			return wid
+		}
+	}
+	if c.LastFocusedIdx >= 0 && c.LastFocusedIdx < len(c.Windows) {
+		return c.Windows[c.LastFocusedIdx]
+	}
+	return c.Windows[0]
+}
+
+// MRUOrder returns every window in the cell, most recently focused first.
+// ZOrder doubles as the cell's MRU list, as focusing a window raises it;
+// windows never focused follow in stack order.
+func (c *CellState) MRUOrder() []uint32 {
+	order := c.keepZOrder(c.ZOrder)
+	for _, wid := range c.Windows {
+		if !containsID(order, wid) {
+			order = append(order, wid)
+		}
+	}
+	return order
+}
+
+// MRUCycle returns the MRU order after one step of cycling, with the window
+// to focus in front. Forward steps to the window focused before the front
+// one and sends the front one to the back, so repeating it walks back
+// through the windows in last-focused order and a full cycle returns to the
+// start. Backward undoes a forward step.
+func (c *CellState) MRUCycle(forward bool) []uint32 {
+	order := c.MRUOrder()
+	if len(order) < 2 {
+		return order
+	}
+
+	cycled := make([]uint32, 0, len(order))
+	if forward {
+		cycled = append(cycled, order[1:]...)
+		cycled = append(cycled, order[0])
+	} else {
+		cycled = append(cycled, order[len(order)-1])
+		cycled = append(cycled, order[:len(order)-1]...)
+	}
+	return cycled
+}
+
+// dropFromZOrder removes a window that left the cell from its z-order
+func (c *CellState) dropFromZOrder(windowID uint32) {
+	for i, wid := range c.ZOrder {
+		if wid == windowID {
+			c.ZOrder = append(c.ZOrder[:i], c.ZOrder[i+1:]...)
+			return
+		}
+	}
+}
+
+// keepZOrder returns the windows of zOrder that are in the cell, in order
+func (c *CellState) keepZOrder(zOrder []uint32) []uint32 {
+	var kept []uint32
+	for _, wid := range zOrder {
+		if c.indexOf(wid) >= 0 {
+			kept = append(kept, wid)
+		}
+	}
+	return kept
+}
+
+// indexOf returns a window's position in the cell, or -1
+func (c *CellState) indexOf(windowID uint32) int {
+	for i, wid := range c.Windows {
+		if wid == windowID {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsID(ids []uint32, id uint32) bool {
+	for _, wid := range ids {
+		if wid == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Package testutil provides a fake grid server for tests that talk to the
+// server through internal/client.
+package testutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/models"
+)
+
+// batchUpdateMethod mirrors client.BatchUpdateMethod; client's own tests use
+// this package, so it can't import client.
+const batchUpdateMethod = "window.batchUpdate"
+
+// Server is a fake grid server on a unix socket. It answers each request
+// with Handle and records the requests it was sent.
+type Server struct {
+	// Handle answers a request with a result or a server error. When Handle
+	// is nil, or returns neither, the request gets an empty result.
+	Handle func(req *models.Request) (map[string]interface{}, *models.ErrorInfo)
+	// Drop closes the first Drop connections without answering them
+	Drop int
+
+	mu       sync.Mutex
+	conns    int
+	requests []*models.Request
+}
+
+// Start serves on a socket in a temp dir until the test ends and returns
+// the socket path.
+func (s *Server) Start(t testing.TB) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "grid.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			dropped := s.conns <= s.Drop
+			s.mu.Unlock()
+			if dropped {
+				conn.Close()
+				continue
+			}
+			go s.serve(conn)
+		}
+	}()
+	return socket
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var env models.MessageEnvelope
+		if err := json.Unmarshal(line, &env); err != nil || env.Request == nil {
+			return
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, env.Request)
+		s.mu.Unlock()
+
+		var result map[string]interface{}
+		var errInfo *models.ErrorInfo
+		if s.Handle != nil {
+			result, errInfo = s.Handle(env.Request)
+		}
+		resp := &models.Response{ID: env.Request.ID, Result: result, Error: errInfo}
+		if errInfo == nil && result == nil {
+			resp.Result = map[string]interface{}{}
+		}
+		data, _ := json.Marshal(models.MessageEnvelope{Type: "response", Response: resp})
+		conn.Write(append(data, '\n'))
+	}
+}
+
+// Conns returns the number of connections accepted, dropped ones included
+func (s *Server) Conns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns
+}
+
+// Requests returns the requests sent so far, in order
+func (s *Server) Requests() []*models.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*models.Request(nil), s.requests...)
+}
+
+// Methods returns the method of each request sent so far, in order
+func (s *Server) Methods() []string {
+	requests := s.Requests()
+	methods := make([]string, len(requests))
+	for i, r := range requests {
+		methods[i] = r.Method
+	}
+	return methods
+}
+
+// Count returns how many times method was called
+func (s *Server) Count(method string) int {
+	n := 0
+	for _, m := range s.Methods() {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+// Called reports whether method was called
+func (s *Server) Called(method string) bool {
+	return s.Count(method) > 0
+}
+
+// Request returns the first request for method, or nil
+func (s *Server) Request(method string) *models.Request {
+	for _, r := range s.Requests() {
+		if r.Method == method {
+			return r
+		}
+	}
+	return nil
+}
+
+// WindowUpdates returns the params of every window update sent, through
+// updateWindow or one entry of window.batchUpdate, in order
+func (s *Server) WindowUpdates() []map[string]interface{} {
+	var updates []map[string]interface{}
+	for _, r := range s.Requests() {
+		switch r.Method {
+		case "updateWindow":
+			updates = append(updates, r.Params)
+		case batchUpdateMethod:
+			list, _ := r.Params["updates"].([]interface{})
+			for _, u := range list {
+				update, _ := u.(map[string]interface{})
+				updates = append(updates, update)
+			}
+		}
+	}
+	return updates
+}
@@ -7,8 +7,25 @@ const (
 	StackVertical   StackMode = "vertical"
 	StackHorizontal StackMode = "horizontal"
 	StackTabs       StackMode = "tabs"
+	StackGrid       StackMode = "grid" // Near-square grid, ceil(sqrt(n)) columns
 )
 
+// ParseStackMode converts a string to StackMode
+func ParseStackMode(s string) (StackMode, bool) {
+	switch s {
+	case "vertical":
+		return StackVertical, true
+	case "horizontal":
+		return StackHorizontal, true
+	case "tabs":
+		return StackTabs, true
+	case "grid":
+		return StackGrid, true
+	default:
+		return "", false
+	}
+}
+
 // TrackSize represents a grid track dimension (column or row)
 // Supports: "1fr", "2fr", "300px", "auto", "minmax(200px, 1fr)"
 type TrackSize struct {
@@ -36,6 +53,11 @@ type Cell struct {
 	RowStart    int       // 1-indexed row start
 	RowEnd      int       // 1-indexed row end (exclusive)
 	StackMode   StackMode // How windows stack in this cell (optional override)
+	Launch      string    // App (bundle ID or name) to launch when the cell is empty (optional)
+	Weight      int       // Auto-flow share relative to other cells (0 = 1)
+	AspectRatio float64   // Width/height the cell's windows keep, centered in the cell (0 = fill it)
+
+	SkipNavigation bool // Directional focus/move jumps over this cell
 }
 
 // Layout defines a complete grid layout configuration
@@ -47,6 +69,10 @@ type Layout struct {
 	Rows        []TrackSize          // Row track definitions
 	Cells       []Cell               // Cell definitions
 	CellModes   map[string]StackMode // Per-cell stack mode overrides
+
+	// Per-axis gaps between cells (nil = the gap passed to CalculateLayout)
+	ColumnGap *float64
+	RowGap    *float64
 }
 
 // Rect represents pixel bounds on screen
@@ -106,10 +132,14 @@ type WindowPlacement struct {
 type CalculatedLayout struct {
 	LayoutID    string          // Reference to layout definition
 	ScreenRect  Rect            // Screen bounds used for calculation
-	Gap         float64         // Gap between cells in pixels
+	ColumnGap   float64         // Gap between columns in pixels
+	RowGap      float64         // Gap between rows in pixels
 	ColumnSizes []float64       // Calculated column widths
 	RowSizes    []float64       // Calculated row heights
 	CellBounds  map[string]Rect // cellID -> calculated bounds
+
+	// AspectRatios holds the width/height of cells that keep an aspect ratio
+	AspectRatios map[string]float64
 }
 
 // Direction represents navigation direction
@@ -120,6 +150,10 @@ const (
 	DirRight
 	DirUp
 	DirDown
+	DirUpLeft
+	DirUpRight
+	DirDownLeft
+	DirDownRight
 )
 
 // String returns the string representation of a Direction
@@ -133,11 +167,41 @@ func (d Direction) String() string {
 		return "up"
 	case DirDown:
 		return "down"
+	case DirUpLeft:
+		return "up-left"
+	case DirUpRight:
+		return "up-right"
+	case DirDownLeft:
+		return "down-left"
+	case DirDownRight:
+		return "down-right"
 	default:
 		return "unknown"
 	}
 }
 
+// IsDiagonal reports whether the direction combines a horizontal and a vertical step
+func (d Direction) IsDiagonal() bool {
+	return d >= DirUpLeft && d <= DirDownRight
+}
+
+// Components splits a diagonal direction into its horizontal and vertical parts.
+// Orthogonal directions are returned unchanged in both positions.
+func (d Direction) Components() (horizontal, vertical Direction) {
+	switch d {
+	case DirUpLeft:
+		return DirLeft, DirUp
+	case DirUpRight:
+		return DirRight, DirUp
+	case DirDownLeft:
+		return DirLeft, DirDown
+	case DirDownRight:
+		return DirRight, DirDown
+	default:
+		return d, d
+	}
+}
+
 // ParseDirection converts a string to Direction
 func ParseDirection(s string) (Direction, bool) {
 	switch s {
@@ -149,6 +213,14 @@ func ParseDirection(s string) (Direction, bool) {
 		return DirUp, true
 	case "down":
 		return DirDown, true
+	case "up-left":
+		return DirUpLeft, true
+	case "up-right":
+		return DirUpRight, true
+	case "down-left":
+		return DirDownLeft, true
+	case "down-right":
+		return DirDownRight, true
 	default:
 		return 0, false
 	}
@@ -162,4 +234,41 @@ const (
 	AssignPinned                             // Use app rules
 	AssignPreserve                           // Maintain previous assignments
 	AssignPosition                           // Assign based on current window position
+	AssignBSP                                // Preserve, tiling cells as a binary space partition
 )
+
+// String returns the string representation of an AssignmentStrategy
+func (a AssignmentStrategy) String() string {
+	switch a {
+	case AssignAutoFlow:
+		return "autoflow"
+	case AssignPinned:
+		return "pinned"
+	case AssignPreserve:
+		return "preserve"
+	case AssignPosition:
+		return "position"
+	case AssignBSP:
+		return "bsp"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAssignmentStrategy converts a string to AssignmentStrategy
+func ParseAssignmentStrategy(s string) (AssignmentStrategy, bool) {
+	switch s {
+	case "autoflow":
+		return AssignAutoFlow, true
+	case "pinned":
+		return AssignPinned, true
+	case "preserve":
+		return AssignPreserve, true
+	case "position":
+		return AssignPosition, true
+	case "bsp":
+		return AssignBSP, true
+	default:
+		return 0, false
+	}
+}
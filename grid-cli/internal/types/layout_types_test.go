@@ -71,6 +71,8 @@ func TestDirectionString(t *testing.T) {
 		{DirRight, "right"},
 		{DirUp, "up"},
 		{DirDown, "down"},
+		{DirUpLeft, "up-left"},
+		{DirDownRight, "down-right"},
 		{Direction(99), "unknown"},
 	}
 
@@ -93,6 +95,10 @@ func TestParseDirection(t *testing.T) {
 		{"right", DirRight, true},
 		{"up", DirUp, true},
 		{"down", DirDown, true},
+		{"up-left", DirUpLeft, true},
+		{"up-right", DirUpRight, true},
+		{"down-left", DirDownLeft, true},
+		{"down-right", DirDownRight, true},
 		{"invalid", 0, false},
 		{"LEFT", 0, false}, // case sensitive
 		{"", 0, false},
@@ -109,6 +115,59 @@ func TestParseDirection(t *testing.T) {
 	}
 }
 
+func TestParseStackMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantMode StackMode
+		wantOK   bool
+	}{
+		{"vertical", StackVertical, true},
+		{"horizontal", StackHorizontal, true},
+		{"tabs", StackTabs, true},
+		{"grid", StackGrid, true},
+		{"Tabs", "", false}, // case sensitive
+		{"matrix", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotMode, gotOK := ParseStackMode(tt.input)
+			if gotMode != tt.wantMode || gotOK != tt.wantOK {
+				t.Errorf("ParseStackMode(%q) = (%q, %v), want (%q, %v)",
+					tt.input, gotMode, gotOK, tt.wantMode, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDirectionComponents(t *testing.T) {
+	tests := []struct {
+		dir            Direction
+		wantDiagonal   bool
+		wantHorizontal Direction
+		wantVertical   Direction
+	}{
+		{DirUpLeft, true, DirLeft, DirUp},
+		{DirUpRight, true, DirRight, DirUp},
+		{DirDownLeft, true, DirLeft, DirDown},
+		{DirDownRight, true, DirRight, DirDown},
+		{DirLeft, false, DirLeft, DirLeft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dir.String(), func(t *testing.T) {
+			if got := tt.dir.IsDiagonal(); got != tt.wantDiagonal {
+				t.Errorf("IsDiagonal() = %v, want %v", got, tt.wantDiagonal)
+			}
+			h, v := tt.dir.Components()
+			if h != tt.wantHorizontal || v != tt.wantVertical {
+				t.Errorf("Components() = (%v, %v), want (%v, %v)", h, v, tt.wantHorizontal, tt.wantVertical)
+			}
+		})
+	}
+}
+
 func TestStackModeConstants(t *testing.T) {
 	// Verify constant values match spec
 	if StackVertical != "vertical" {
@@ -166,3 +225,15 @@ func TestDirectionIota(t *testing.T) {
 		t.Errorf("DirDown = %d, want 3", DirDown)
 	}
 }
+
+func TestParseAssignmentStrategy(t *testing.T) {
+	for _, strategy := range []AssignmentStrategy{AssignAutoFlow, AssignPinned, AssignPreserve, AssignPosition, AssignBSP} {
+		got, ok := ParseAssignmentStrategy(strategy.String())
+		if !ok || got != strategy {
+			t.Errorf("ParseAssignmentStrategy(%q) = (%v, %v), want (%v, true)", strategy.String(), got, ok, strategy)
+		}
+	}
+	if _, ok := ParseAssignmentStrategy("scatter"); ok {
+		t.Error("expected unknown strategy to fail")
+	}
+}
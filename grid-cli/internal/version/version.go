@@ -0,0 +1,145 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semver-style version: major.minor.patch with an optional
+// pre-release suffix. Build metadata is dropped.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+}
+
+// Parse parses "1.2.3", "v1.2" or "1.2.3-rc.1+build". Missing minor and
+// patch numbers are zero.
+func Parse(s string) (Version, error) {
+	var v Version
+	raw := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(raw, '+'); i >= 0 {
+		raw = raw[:i]
+	}
+	if i := strings.IndexByte(raw, '-'); i >= 0 {
+		v.Pre = raw[i+1:]
+		raw = raw[:i]
+	}
+
+	parts := strings.Split(raw, ".")
+	if raw == "" || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+// A pre-release is older than its release.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	case v.Pre < o.Pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Status is the outcome of a compatibility check
+type Status string
+
+const (
+	StatusCompatible Status = "compatible"
+	StatusOutdated   Status = "outdated" // Works, but older than the server recommends
+	StatusTooOld     Status = "too-old"  // Older than the server's minimum client version
+	StatusTooNew     Status = "too-new"  // Newer than the server; some commands may be missing
+)
+
+// Check is the result of comparing the CLI version with a server's
+type Check struct {
+	Client            string `json:"client"`
+	Server            string `json:"server"`
+	MinClient         string `json:"minClientVersion,omitempty"`
+	RecommendedClient string `json:"recommendedClientVersion,omitempty"`
+	Status            Status `json:"status"`
+	Message           string `json:"message,omitempty"`
+}
+
+// CheckCompatibility compares the CLI version against the server info from
+// getServerInfo. A server may advertise minClientVersion and
+// recommendedClientVersion; a CLI with a newer major.minor than the server
+// itself is reported as too new.
+func CheckCompatibility(client string, info map[string]interface{}) (*Check, error) {
+	cli, err := Parse(client)
+	if err != nil {
+		return nil, fmt.Errorf("client version: %w", err)
+	}
+	serverStr, _ := info["version"].(string)
+	server, err := Parse(serverStr)
+	if err != nil {
+		return nil, fmt.Errorf("server version: %w", err)
+	}
+
+	check := &Check{Client: cli.String(), Server: server.String(), Status: StatusCompatible}
+
+	if s, ok := info["minClientVersion"].(string); ok && s != "" {
+		min, err := Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("minimum client version: %w", err)
+		}
+		check.MinClient = min.String()
+		if cli.Compare(min) < 0 {
+			check.Status = StatusTooOld
+			check.Message = fmt.Sprintf("grid %s is older than the minimum %s required by the server; update the CLI", cli, min)
+			return check, nil
+		}
+	}
+
+	if cli.Major > server.Major || (cli.Major == server.Major && cli.Minor > server.Minor) {
+		check.Status = StatusTooNew
+		check.Message = fmt.Sprintf("grid %s is newer than GridServer %s; update the server", cli, server)
+		return check, nil
+	}
+
+	if s, ok := info["recommendedClientVersion"].(string); ok && s != "" {
+		rec, err := Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("recommended client version: %w", err)
+		}
+		check.RecommendedClient = rec.String()
+		if cli.Compare(rec) < 0 {
+			check.Status = StatusOutdated
+			check.Message = fmt.Sprintf("grid %s is older than the recommended %s", cli, rec)
+		}
+	}
+	return check, nil
+}
@@ -0,0 +1,84 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.2.3", Version{1, 2, 3, ""}},
+		{"v0.4", Version{0, 4, 0, ""}},
+		{"2.0.0-rc.1+build.7", Version{2, 0, 0, "rc.1"}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "1.x", "1.2.3.4", "-1.0"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.9.9", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-beta", "1.0.0-alpha", 1},
+	}
+	for _, tt := range tests {
+		a, _ := Parse(tt.a)
+		b, _ := Parse(tt.b)
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("%s vs %s = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name   string
+		client string
+		info   map[string]interface{}
+		want   Status
+	}{
+		{"same version", "0.1.0", map[string]interface{}{"version": "0.1.0"}, StatusCompatible},
+		{"newer patch", "0.1.2", map[string]interface{}{"version": "0.1.0"}, StatusCompatible},
+		{"meets minimum", "0.2.0", map[string]interface{}{"version": "0.3.0", "minClientVersion": "0.2.0"}, StatusCompatible},
+		{"too old", "0.1.0", map[string]interface{}{"version": "0.3.0", "minClientVersion": "0.2.0"}, StatusTooOld},
+		{"too new", "0.2.0", map[string]interface{}{"version": "0.1.5"}, StatusTooNew},
+		{"outdated", "0.3.0", map[string]interface{}{"version": "0.3.1", "recommendedClientVersion": "0.3.1"}, StatusOutdated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := CheckCompatibility(tt.client, tt.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if check.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", check.Status, tt.want, check.Message)
+			}
+			if tt.want != StatusCompatible && check.Message == "" {
+				t.Error("expected a message on mismatch")
+			}
+		})
+	}
+
+	if _, err := CheckCompatibility("0.1.0", map[string]interface{}{}); err == nil {
+		t.Error("expected error when the server reports no version")
+	}
+}
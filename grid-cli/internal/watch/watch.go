@@ -0,0 +1,171 @@
+// Package watch polls the server for window changes on the active space, so
+// `grid watch` can re-apply layouts when windows open or close.
+package watch
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+const (
+	// DefaultInterval is how often the server is polled
+	DefaultInterval = time.Second
+
+	// DefaultDebounce is how long the window set must stay unchanged before
+	// pending changes are handled, so a burst of opens is one re-apply
+	DefaultDebounce = 500 * time.Millisecond
+)
+
+// Changes is the difference in tileable windows between two snapshots of
+// the same space, with window IDs in ascending order
+type Changes struct {
+	SpaceID string
+	Added   []uint32 // Windows that appeared
+	Removed []uint32 // Windows that closed or left the space
+}
+
+// Empty reports whether nothing changed
+func (ch Changes) Empty() bool {
+	return len(ch.Added) == 0 && len(ch.Removed) == 0
+}
+
+// Diff compares the window sets of two snapshots. Snapshots of different
+// spaces (the user switched spaces) aren't compared and give no changes, as
+// switching spaces doesn't open or close anything. A nil prev gives no
+// changes either.
+func Diff(prev, next *server.Snapshot) Changes {
+	if prev == nil || next == nil || prev.SpaceID != next.SpaceID {
+		return Changes{}
+	}
+
+	// WindowIDs holds only tileable windows, so a window being minimized
+	// counts as removed and restoring it as added
+	added := make(map[uint32]bool)
+	for id := range next.WindowIDs {
+		if !prev.WindowIDs[id] {
+			added[id] = true
+		}
+	}
+	removed := make(map[uint32]bool)
+	for id := range prev.WindowIDs {
+		if !next.WindowIDs[id] {
+			removed[id] = true
+		}
+	}
+	return Changes{SpaceID: next.SpaceID, Added: fromSet(added), Removed: fromSet(removed)}
+}
+
+// Merge folds later changes into ch. A window that appears and closes again
+// within the pending changes cancels out.
+func (ch Changes) Merge(later Changes) Changes {
+	added := toSet(ch.Added)
+	removed := toSet(ch.Removed)
+	for _, id := range later.Added {
+		if removed[id] {
+			delete(removed, id)
+		} else {
+			added[id] = true
+		}
+	}
+	for _, id := range later.Removed {
+		if added[id] {
+			delete(added, id)
+		} else {
+			removed[id] = true
+		}
+	}
+
+	spaceID := later.SpaceID
+	if spaceID == "" {
+		spaceID = ch.SpaceID
+	}
+	return Changes{SpaceID: spaceID, Added: fromSet(added), Removed: fromSet(removed)}
+}
+
+func toSet(ids []uint32) map[uint32]bool {
+	set := make(map[uint32]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func fromSet(set map[uint32]bool) []uint32 {
+	if len(set) == 0 {
+		return nil
+	}
+	ids := make([]uint32, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Poller fetches snapshots on an interval and hands debounced window
+// changes to OnChange
+type Poller struct {
+	Interval time.Duration // Time between fetches (DefaultInterval if 0)
+	Debounce time.Duration // Quiet time before changes are handled
+
+	Fetch    func(ctx context.Context) (*server.Snapshot, error)
+	OnChange func(ctx context.Context, snap *server.Snapshot, ch Changes)
+
+	prev        *server.Snapshot
+	pending     Changes
+	lastChanged time.Time
+}
+
+// Run polls until ctx is done. Fetch errors are logged and retried on the
+// next tick, so a restarting server doesn't stop the watcher.
+func (p *Poller) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches one snapshot and handles pending changes once they've been
+// quiet for the debounce time
+func (p *Poller) poll(ctx context.Context, now time.Time) {
+	snap, err := p.Fetch(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.Warn().Err(err).Msg("watch: failed to fetch server state")
+		}
+		return
+	}
+
+	// Changes pending for a space the user has left are dropped
+	if p.prev != nil && p.prev.SpaceID != snap.SpaceID {
+		p.pending = Changes{}
+	}
+	if ch := Diff(p.prev, snap); !ch.Empty() {
+		p.pending = p.pending.Merge(ch)
+		p.lastChanged = now
+	}
+	p.prev = snap
+
+	if p.pending.Empty() || now.Sub(p.lastChanged) < p.Debounce {
+		return
+	}
+	ch := p.pending
+	p.pending = Changes{}
+	p.OnChange(ctx, snap, ch)
+}
@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+func snapshot(spaceID string, ids ...uint32) *server.Snapshot {
+	snap := &server.Snapshot{SpaceID: spaceID, WindowIDs: make(map[uint32]bool)}
+	for _, id := range ids {
+		snap.Windows = append(snap.Windows, server.WindowInfo{ID: id})
+		snap.WindowIDs[id] = true
+	}
+	return snap
+}
+
+func TestDiff(t *testing.T) {
+	prev := snapshot("1", 10, 11, 12)
+	next := snapshot("1", 11, 12, 13, 14)
+
+	ch := Diff(prev, next)
+	if !reflect.DeepEqual(ch.Added, []uint32{13, 14}) || !reflect.DeepEqual(ch.Removed, []uint32{10}) {
+		t.Errorf("Diff = %+v, want added [13 14], removed [10]", ch)
+	}
+	if ch.SpaceID != "1" {
+		t.Errorf("SpaceID = %q, want 1", ch.SpaceID)
+	}
+}
+
+func TestDiff_IgnoresNonTileable(t *testing.T) {
+	prev := snapshot("1", 10)
+	next := snapshot("1", 10)
+	// A new window that isn't tileable is in Windows but not WindowIDs
+	next.Windows = append(next.Windows, server.WindowInfo{ID: 20, IsMinimized: true})
+
+	if ch := Diff(prev, next); !ch.Empty() {
+		t.Errorf("expected no changes, got %+v", ch)
+	}
+}
+
+func TestDiff_SpaceSwitch(t *testing.T) {
+	if ch := Diff(snapshot("1", 10), snapshot("2", 20)); !ch.Empty() {
+		t.Errorf("expected no changes across spaces, got %+v", ch)
+	}
+	if ch := Diff(nil, snapshot("1", 10)); !ch.Empty() {
+		t.Errorf("expected no changes without a previous snapshot, got %+v", ch)
+	}
+}
+
+func TestChanges_MergeCancelsOut(t *testing.T) {
+	pending := Changes{SpaceID: "1", Added: []uint32{5}, Removed: []uint32{6}}
+	merged := pending.Merge(Changes{SpaceID: "1", Added: []uint32{6, 7}, Removed: []uint32{5}})
+
+	if !reflect.DeepEqual(merged.Added, []uint32{7}) || merged.Removed != nil {
+		t.Errorf("Merge = %+v, want added [7] only", merged)
+	}
+}
+
+func TestPoller_DebouncesBursts(t *testing.T) {
+	snaps := []*server.Snapshot{
+		snapshot("1", 10),
+		snapshot("1", 10, 11),
+		snapshot("1", 10, 11, 12),
+		snapshot("1", 10, 11, 12),
+		snapshot("1", 10, 11, 12),
+	}
+	var calls []Changes
+	p := &Poller{
+		Debounce: 500 * time.Millisecond,
+		Fetch: func(ctx context.Context) (*server.Snapshot, error) {
+			snap := snaps[0]
+			snaps = snaps[1:]
+			return snap, nil
+		},
+		OnChange: func(ctx context.Context, snap *server.Snapshot, ch Changes) {
+			calls = append(calls, ch)
+		},
+	}
+
+	start := time.Now()
+	ctx := context.Background()
+	p.poll(ctx, start)                           // Baseline
+	p.poll(ctx, start.Add(100*time.Millisecond)) // 11 opens
+	p.poll(ctx, start.Add(200*time.Millisecond)) // 12 opens right after
+	p.poll(ctx, start.Add(400*time.Millisecond)) // Still settling
+	if len(calls) != 0 {
+		t.Fatalf("expected no call before the debounce time, got %+v", calls)
+	}
+
+	p.poll(ctx, start.Add(800*time.Millisecond))
+	if len(calls) != 1 || !reflect.DeepEqual(calls[0].Added, []uint32{11, 12}) {
+		t.Fatalf("expected one call adding [11 12], got %+v", calls)
+	}
+}
+
+func TestPoller_DropsChangesOnSpaceSwitch(t *testing.T) {
+	snaps := []*server.Snapshot{
+		snapshot("1", 10),
+		snapshot("1", 10, 11),
+		snapshot("2", 20),
+	}
+	called := false
+	p := &Poller{
+		Debounce: time.Second,
+		Fetch: func(ctx context.Context) (*server.Snapshot, error) {
+			snap := snaps[0]
+			snaps = snaps[1:]
+			return snap, nil
+		},
+		OnChange: func(ctx context.Context, snap *server.Snapshot, ch Changes) { called = true },
+	}
+
+	start := time.Now()
+	ctx := context.Background()
+	p.poll(ctx, start)
+	p.poll(ctx, start.Add(100*time.Millisecond))
+	p.poll(ctx, start.Add(5*time.Second))
+	if called {
+		t.Error("changes on a space the user left should not be handled")
+	}
+}
@@ -0,0 +1,79 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// AssignWindow records a window in a cell of a space's state, creating the
+// space and cell state as needed and removing the window from every other
+// space. The window doesn't need to be focused or on the active space. If the
+// space has a layout, the cell must be one of its cells.
+func AssignWindow(cfg *config.Config, rs *state.RuntimeState, windowID uint32, spaceID, cellID string) error {
+	if cellID == "" {
+		return fmt.Errorf("cell ID is required")
+	}
+
+	if space := rs.GetSpaceReadOnly(spaceID); space != nil && space.CurrentLayoutID != "" {
+		layoutDef, err := layout.SpaceLayout(cfg, space)
+		if err != nil {
+			return fmt.Errorf("layout not found: %w", err)
+		}
+		found := false
+		for _, cell := range layoutDef.Cells {
+			if cell.ID == cellID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("cell %s not found in layout %s", cellID, space.CurrentLayoutID)
+		}
+	}
+
+	rs.RemoveWindowFromOtherSpaces(windowID, spaceID)
+	rs.GetSpace(spaceID).AssignWindow(windowID, cellID)
+	return nil
+}
+
+// ApplyAssignedCell re-places the windows of one cell of a space, using the
+// snapshot's display bounds for the current space and the display showing
+// the space otherwise. Returns the number of windows placed.
+func ApplyAssignedCell(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	spaceID string,
+	cellID string,
+) (int, error) {
+	space := rs.GetSpaceReadOnly(spaceID)
+	if space == nil || space.CurrentLayoutID == "" {
+		return 0, fmt.Errorf("space %s has no layout", spaceID)
+	}
+
+	display := server.DisplayInfo{Frame: snap.DisplayBounds}
+	if spaceID != snap.SpaceID {
+		target, _, err := ResolveTargetSpaceDisplay(snap, cfg, rs, spaceID, false)
+		if err != nil {
+			return 0, err
+		}
+		display = *target
+	}
+
+	placements, err := cellPlacements(cfg, space, cellID, display, snap.Windows)
+	if err != nil {
+		return 0, err
+	}
+	if err := layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
+		return 0, err
+	}
+	return len(placements), nil
+}
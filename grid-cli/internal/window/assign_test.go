@@ -0,0 +1,64 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestAssignWindow_CreatesState(t *testing.T) {
+	_, cfg, rs := stackedCellFixture()
+
+	// Space 7 has never been seen: its state is created on the fly
+	if err := AssignWindow(cfg, rs, 100, "7", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rs.GetSpaceReadOnly("7").GetWindowCell(100); got != "main" {
+		t.Errorf("window tracked in %q on space 7, want main", got)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(100); got != "" {
+		t.Errorf("window still tracked in %q on space 1", got)
+	}
+}
+
+func TestAssignWindow_ValidatesCellAgainstLayout(t *testing.T) {
+	_, cfg, rs := stackedCellFixture()
+
+	if err := AssignWindow(cfg, rs, 200, "1", "missing"); err == nil {
+		t.Error("expected error for a cell not in the space's layout")
+	}
+	if err := AssignWindow(cfg, rs, 200, "1", ""); err == nil {
+		t.Error("expected error for an empty cell ID")
+	}
+	if err := AssignWindow(cfg, rs, 200, "1", "bottom"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(200); got != "bottom" {
+		t.Errorf("window tracked in %q, want bottom", got)
+	}
+}
+
+func TestApplyAssignedCell(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	c, fs := startFakeServer(t)
+
+	if err := AssignWindow(cfg, rs, 200, "1", "bottom"); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ApplyAssignedCell(context.Background(), c, snap, cfg, rs, "1", "bottom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 placement, got %d", n)
+	}
+	got, ok := fs.frame(200)
+	if want := (types.Rect{X: 0, Y: 540, Width: 1920, Height: 540}); !ok || got != want {
+		t.Errorf("window 200 frame = %+v (set: %v), want %+v", got, ok, want)
+	}
+
+	if _, err := ApplyAssignedCell(context.Background(), c, snap, cfg, rs, "9", "main"); err == nil {
+		t.Error("expected error for a space without a layout")
+	}
+}
@@ -0,0 +1,43 @@
+package window
+
+import (
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// FloatWindow floats a window on the snapshot's space: it leaves its cell
+// and stays where it is through every apply and reapply until unfloated.
+// Returns the cell the window left ("" if it wasn't in one).
+func FloatWindow(snap *server.Snapshot, rs *state.RuntimeState, windowID uint32) (string, error) {
+	found := false
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("window %d is not on space %s", windowID, snap.SpaceID)
+	}
+
+	space := rs.GetSpace(snap.SpaceID)
+	if space.IsFloated(windowID) {
+		return "", fmt.Errorf("window %d is already floating", windowID)
+	}
+	cellID := space.GetWindowCell(windowID)
+	space.FloatWindow(windowID)
+	return cellID, nil
+}
+
+// UnfloatWindow returns a floated window on a space to tiling; the next
+// apply or reapply puts it in the cell it overlaps most
+func UnfloatWindow(rs *state.RuntimeState, spaceID string, windowID uint32) error {
+	space := rs.GetSpaceReadOnly(spaceID)
+	if space == nil || !space.IsFloated(windowID) {
+		return fmt.Errorf("window %d is not floating on space %s", windowID, spaceID)
+	}
+	rs.GetSpace(spaceID).UnfloatWindow(windowID)
+	return nil
+}
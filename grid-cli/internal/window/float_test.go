@@ -0,0 +1,38 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+func TestFloatWindow(t *testing.T) {
+	snap, _, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 100}, {ID: 101}}
+
+	if _, err := FloatWindow(snap, rs, 999); err == nil {
+		t.Error("expected error for a window not on the space")
+	}
+	cellID, err := FloatWindow(snap, rs, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cellID != "top" {
+		t.Errorf("left cell %q, want top", cellID)
+	}
+	if _, err := FloatWindow(snap, rs, 100); err == nil {
+		t.Error("expected error floating a window twice")
+	}
+}
+
+func TestUnfloatWindow(t *testing.T) {
+	_, _, rs := stackedCellFixture()
+	rs.GetSpace("1").FloatWindow(100)
+
+	if err := UnfloatWindow(rs, "1", 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnfloatWindow(rs, "1", 100); err == nil {
+		t.Error("expected error unfloating a window that isn't floating")
+	}
+}
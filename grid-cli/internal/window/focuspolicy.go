@@ -0,0 +1,54 @@
+package window
+
+import (
+	"context"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// withFocusPolicy runs move, which focuses the window it moves unless told
+// to keep focus. When focus doesn't follow moves (settings.focusFollowsMove
+// or opts.FocusFollow), move is told to keep focus on the window that had
+// it, unless that is the moved window itself, and focus in state goes back
+// to that window. A move that switches to another space still focuses the
+// moved window.
+func withFocusPolicy(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	opts MoveWindowOpts,
+	move func(opts MoveWindowOpts) (*MoveResult, error),
+) (*MoveResult, error) {
+	if cfg.Settings.FocusFollowsMoves(opts.FocusFollow) {
+		return move(opts)
+	}
+
+	var previous uint32
+	if space := rs.GetSpaceReadOnly(snap.SpaceID); space != nil {
+		previous = space.GetFocusedWindow()
+	}
+	if previous == 0 || opts.WindowID == 0 || opts.WindowID == previous {
+		// The moved window is the focused one
+		return move(opts)
+	}
+
+	opts.keepFocus = true
+	result, err := move(opts)
+	if err != nil || result.Followed {
+		return result, err
+	}
+
+	logging.Debug().Uint32("windowId", previous).Msg("focus doesn't follow moves, keeping focus on previous window")
+	rs.GetSpace(snap.SpaceID).SetFocusedWindow(previous)
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+	return result, nil
+}
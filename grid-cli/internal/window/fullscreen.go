@@ -0,0 +1,95 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// fullscreenTolerance is how far (in points) a frame's edges may fall inside
+// a display's and still count as filling it
+const fullscreenTolerance = 2.0
+
+// ScreenState describes whether a window fills its display
+type ScreenState int
+
+const (
+	ScreenNormal     ScreenState = iota // Window is smaller than the display
+	ScreenMaximized                     // Window fills the visible frame (below the menu bar)
+	ScreenFullscreen                    // Window fills the whole display, as native fullscreen does
+)
+
+// FillsRect reports whether frame covers rect, within fullscreenTolerance.
+func FillsRect(frame, rect types.Rect) bool {
+	if rect.Width <= 0 || rect.Height <= 0 {
+		return false
+	}
+	return frame.X <= rect.X+fullscreenTolerance &&
+		frame.Y <= rect.Y+fullscreenTolerance &&
+		frame.X+frame.Width >= rect.X+rect.Width-fullscreenTolerance &&
+		frame.Y+frame.Height >= rect.Y+rect.Height-fullscreenTolerance
+}
+
+// WindowScreenState reports whether a window fills the display showing the
+// snapshot's space: fullscreen when the server says so, otherwise judged
+// from its frame. A frame filling the whole display is only a guess at
+// fullscreen; a maximized window looks the same when the menu bar
+// auto-hides.
+func WindowScreenState(snap *server.Snapshot, windowID uint32) ScreenState {
+	w, found := snapshotWindow(snap, windowID)
+	if !found {
+		return ScreenNormal
+	}
+	if w.IsFullscreen {
+		return ScreenFullscreen
+	}
+
+	frame := w.Frame
+	for _, d := range snap.AllDisplays {
+		if fmt.Sprintf("%v", d.CurrentSpaceID) == snap.SpaceID && FillsRect(frame, d.Frame) {
+			return ScreenFullscreen
+		}
+	}
+	if FillsRect(frame, snap.DisplayBounds) {
+		return ScreenMaximized
+	}
+	return ScreenNormal
+}
+
+func snapshotWindow(snap *server.Snapshot, windowID uint32) (server.WindowInfo, bool) {
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			return w, true
+		}
+	}
+	return server.WindowInfo{}, false
+}
+
+// FullscreenCapability is the getServerInfo capability a server reports
+// when it handles window.setFullscreen
+const FullscreenCapability = "windowFullscreen"
+
+// exitFullscreen takes a window that looks fullscreen (ScreenFullscreen) out
+// of native fullscreen, and reports whether it did. Servers without
+// FullscreenCapability can't: a window they report fullscreen gets an error
+// telling the user to leave it, and one that only fills the display by its
+// frame is left to move as a normal window.
+func exitFullscreen(ctx context.Context, c *client.Client, snap *server.Snapshot, windowID uint32) (bool, error) {
+	if !c.HasCapability(ctx, FullscreenCapability) {
+		if w, _ := snapshotWindow(snap, windowID); w.IsFullscreen {
+			return false, fmt.Errorf("window %d is fullscreen; exit fullscreen first", windowID)
+		}
+		return false, nil
+	}
+	_, err := c.CallMethod(ctx, "window.setFullscreen", map[string]interface{}{
+		"windowId":   fmt.Sprintf("%d", windowID),
+		"fullscreen": false,
+	})
+	if err != nil {
+		return false, fmt.Errorf("window %d is fullscreen and could not leave it (%v); exit fullscreen first", windowID, err)
+	}
+	return true, nil
+}
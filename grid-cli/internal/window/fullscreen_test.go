@@ -0,0 +1,123 @@
+package window
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestWindowScreenState(t *testing.T) {
+	full := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	visible := types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: visible,
+		AllDisplays:   []server.DisplayInfo{{UUID: "main", CurrentSpaceID: 1, Frame: full, VisibleFrame: visible}},
+		Windows: []server.WindowInfo{
+			{ID: 1, Frame: full},
+			{ID: 2, Frame: types.Rect{X: 1, Y: 26, Width: 1918, Height: 1053}},
+			{ID: 3, Frame: types.Rect{X: 0, Y: 25, Width: 960, Height: 1055}},
+			{ID: 4, Frame: types.Rect{X: 0, Y: 25, Width: 960, Height: 1055}, IsFullscreen: true},
+		},
+	}
+
+	tests := []struct {
+		windowID uint32
+		want     ScreenState
+	}{
+		{1, ScreenFullscreen},
+		{2, ScreenMaximized},
+		{3, ScreenNormal},
+		{4, ScreenFullscreen},
+		{99, ScreenNormal},
+	}
+	for _, tt := range tests {
+		if got := WindowScreenState(snap, tt.windowID); got != tt.want {
+			t.Errorf("WindowScreenState(%d) = %v, want %v", tt.windowID, got, tt.want)
+		}
+	}
+}
+
+func TestMoveWindow_ExitsFullscreenThenMoves(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 101, Frame: snap.DisplayBounds}}
+	c, fs := startFakeServerWithResults(t, map[string]map[string]interface{}{
+		"getServerInfo": {"capabilities": map[string]interface{}{FullscreenCapability: true}},
+	})
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !fs.Called("window.setFullscreen") {
+		t.Fatal("expected the window to leave fullscreen before moving")
+	}
+	requests := fs.Requests()
+	if requests[1].Method != "window.setFullscreen" {
+		t.Errorf("expected setFullscreen right after the capability check, got %v", fs.Methods())
+	}
+	if got := requests[1].Params["fullscreen"]; got != false {
+		t.Errorf("fullscreen param = %v, want false", got)
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "bottom" {
+		t.Errorf("window 101 should move to bottom, got %q", cell)
+	}
+}
+
+func TestMoveWindow_FullscreenWithoutMSS(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 101, Frame: snap.DisplayBounds, IsFullscreen: true}}
+	c := client.NewClient(filepath.Join(t.TempDir(), "missing.sock"), 0)
+
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101})
+	if err == nil || !strings.Contains(err.Error(), "exit fullscreen first") {
+		t.Fatalf("expected guidance to exit fullscreen, got %v", err)
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "top" {
+		t.Errorf("window 101 should stay in top, got %q", cell)
+	}
+}
+
+func TestMoveWindow_FullscreenWithoutCapability(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 101, Frame: snap.DisplayBounds, IsFullscreen: true}}
+	c, fs := startFakeServer(t)
+
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101})
+	if err == nil || !strings.Contains(err.Error(), "exit fullscreen first") {
+		t.Fatalf("expected guidance to exit fullscreen, got %v", err)
+	}
+	if fs.Called("window.setFullscreen") {
+		t.Error("setFullscreen should not be sent to a server without the capability")
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "top" {
+		t.Errorf("window 101 should stay in top, got %q", cell)
+	}
+}
+
+func TestMoveWindow_FillingFrameWithoutCapabilityMovesNormally(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	// Filling the display by frame alone: with an auto-hiding menu bar this
+	// is also what a maximized window looks like
+	snap.Windows = []server.WindowInfo{{ID: 101, Frame: snap.DisplayBounds}}
+	c, fs := startFakeServer(t)
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Called("window.setFullscreen") {
+		t.Error("setFullscreen should not be sent to a server without the capability")
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "bottom" {
+		t.Errorf("window 101 should move to bottom, got %q", cell)
+	}
+}
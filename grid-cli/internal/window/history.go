@@ -0,0 +1,195 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/focus"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// UndoMove reverts the latest recorded window move on the snapshot's space:
+// the window goes back to its old position in the source cell and the layout
+// is reapplied. A window that moved to another display's space is brought
+// back from it first.
+func UndoMove(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+) (*MoveResult, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, fmt.Errorf("no layout applied")
+	}
+
+	mutableSpace := rs.GetSpace(snap.SpaceID)
+	entry, ok := mutableSpace.PopMoveHistory()
+	if !ok {
+		return nil, fmt.Errorf("no window moves to undo")
+	}
+	// The source cell may have emptied since; it only has to be in the layout
+	layoutDef, err := layout.SpaceLayout(cfg, mutableSpace)
+	if err != nil {
+		return nil, fmt.Errorf("layout not found: %w", err)
+	}
+	if !layoutHasCell(layoutDef, entry.SourceCell) {
+		return nil, fmt.Errorf("cell %s no longer exists", entry.SourceCell)
+	}
+	currentCell := mutableSpace.GetWindowCell(entry.WindowID)
+	currentSpace := snap.SpaceID
+	if entry.TargetSpace != "" {
+		currentSpace, currentCell = entry.TargetSpace, entry.TargetCell
+		if err := returnFromSpace(ctx, c, snap, cfg, rs, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	logging.Info().
+		Uint32("windowId", entry.WindowID).
+		Str("from", currentCell).
+		Str("to", entry.SourceCell).
+		Msg("undoing window move")
+
+	// Put the window back where it was in the source cell's stack
+	mutableSpace.AssignWindow(entry.WindowID, entry.SourceCell)
+	for i := windowIndex(mutableSpace, entry.SourceCell, entry.WindowID); i > entry.SourceIndex; i-- {
+		mutableSpace.SwapInCell(entry.SourceCell, i, i-1)
+	}
+	mutableSpace.SetFocusedWindow(entry.WindowID)
+	rs.MarkUpdated()
+
+	// Keep the restored assignment rather than sorting windows by position;
+	// the reapply saves it
+	opts := layout.DefaultApplyOptions()
+	opts.Gap = float64(cfg.Settings.CellPadding)
+	opts.Strategy = types.AssignPreserve
+	if err := layout.ReapplyLayout(ctx, c, withReturnedWindow(snap, entry), cfg, rs, opts); err != nil {
+		return nil, err
+	}
+
+	if err := focus.FocusWindow(ctx, c, entry.WindowID); err != nil {
+		logging.Warn().Err(err).Uint32("windowId", entry.WindowID).Msg("failed to focus restored window")
+	}
+
+	return &MoveResult{
+		WindowID:     entry.WindowID,
+		SourceCell:   currentCell,
+		TargetCell:   entry.SourceCell,
+		SourceSpace:  currentSpace,
+		TargetSpace:  snap.SpaceID,
+		CrossDisplay: currentSpace != snap.SpaceID,
+	}, nil
+}
+
+// returnFromSpace moves the window of a cross-display move entry back from
+// the space it went to, dropping it from that space's state and re-placing
+// the cell it leaves there if that space is showing
+func returnFromSpace(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	entry state.MoveEntry,
+) error {
+	target := rs.GetSpaceReadOnly(entry.TargetSpace)
+	if target == nil || target.GetWindowCell(entry.WindowID) == "" {
+		return fmt.Errorf("window %d is no longer on space %s", entry.WindowID, entry.TargetSpace)
+	}
+	targetCell := target.GetWindowCell(entry.WindowID)
+
+	if _, err := c.UpdateWindow(ctx, int(entry.WindowID), map[string]interface{}{
+		"spaceId": snap.SpaceID,
+	}); err != nil {
+		return fmt.Errorf("failed to move window back to space %s: %w", snap.SpaceID, err)
+	}
+	rs.RemoveWindowFromOtherSpaces(entry.WindowID, snap.SpaceID)
+
+	// The windows left behind expand again, if their space is on screen
+	for _, d := range snap.AllDisplays {
+		if fmt.Sprintf("%v", d.CurrentSpaceID) != entry.TargetSpace {
+			continue
+		}
+		placements, err := cellPlacements(cfg, target, targetCell, d, snap.Windows)
+		if err == nil {
+			err = layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration)
+		}
+		if err != nil {
+			logging.Warn().Err(err).Str("space", entry.TargetSpace).Msg("failed to reflow space the window left")
+		}
+		break
+	}
+	return nil
+}
+
+// withReturnedWindow returns snap with the entry's window in it. A window
+// brought back from another space isn't in the snapshot taken before it
+// returned, and the reapply would leave it out.
+func withReturnedWindow(snap *server.Snapshot, entry state.MoveEntry) *server.Snapshot {
+	if _, ok := snapshotWindow(snap, entry.WindowID); ok {
+		return snap
+	}
+	returned := *snap
+	returned.Windows = append(append([]server.WindowInfo(nil), snap.Windows...), server.WindowInfo{
+		ID:    entry.WindowID,
+		Frame: entry.After,
+	})
+	returned.WindowIDs = make(map[uint32]bool, len(snap.WindowIDs)+1)
+	for id := range snap.WindowIDs {
+		returned.WindowIDs[id] = true
+	}
+	returned.WindowIDs[entry.WindowID] = true
+	return &returned
+}
+
+// layoutHasCell reports whether the layout defines cellID
+func layoutHasCell(layoutDef *types.Layout, cellID string) bool {
+	for _, cell := range layoutDef.Cells {
+		if cell.ID == cellID {
+			return true
+		}
+	}
+	return false
+}
+
+// windowIndex returns the window's position in a cell's stack, or 0 if it
+// isn't there
+func windowIndex(space *state.SpaceState, cellID string, windowID uint32) int {
+	if cell := space.Cells[cellID]; cell != nil {
+		for i, wid := range cell.Windows {
+			if wid == windowID {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// windowFrame returns the window's frame in the snapshot (zero if missing)
+func windowFrame(snap *server.Snapshot, windowID uint32) types.Rect {
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			return w.Frame
+		}
+	}
+	return types.Rect{}
+}
+
+// placedBounds returns the bounds the window was given among placements
+// (zero if it wasn't placed)
+func placedBounds(placements []types.WindowPlacement, windowID uint32) types.Rect {
+	for _, p := range placements {
+		if p.WindowID == windowID {
+			return p.Bounds
+		}
+	}
+	return types.Rect{}
+}
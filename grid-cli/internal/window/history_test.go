@@ -0,0 +1,155 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestMoveWindow_RecordsFramesInHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	before := types.Rect{X: 40, Y: 300, Width: 800, Height: 500}
+	snap.Windows = []server.WindowInfo{{ID: 100}, {ID: 101, Frame: before}}
+	c, fs := startFakeServer(t)
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+
+	history := rs.GetSpaceReadOnly("1").MoveHistory
+	if len(history) != 1 {
+		t.Fatalf("expected 1 history entry, got %v", history)
+	}
+	entry := history[0]
+	if entry.WindowID != 101 || entry.SourceCell != "top" || entry.SourceIndex != 1 || entry.TargetCell != "bottom" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if entry.Before != before {
+		t.Errorf("before = %+v, want %+v", entry.Before, before)
+	}
+	placed, ok := fs.frame(101)
+	if !ok {
+		t.Fatal("window 101 was not placed")
+	}
+	if entry.After != placed || entry.After.Y < 540 {
+		t.Errorf("after = %+v, want the bottom cell placement %+v", entry.After, placed)
+	}
+}
+
+func TestUndoMove_RestoresCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	before := types.Rect{X: 40, Y: 300, Width: 800, Height: 500}
+	snap.Windows = []server.WindowInfo{{ID: 100}, {ID: 101, Frame: before}}
+	c, fs := startFakeServer(t)
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := UndoMove(context.Background(), c, snap, cfg, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SourceCell != "bottom" || result.TargetCell != "top" {
+		t.Errorf("expected bottom -> top, got %s -> %s", result.SourceCell, result.TargetCell)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if top := space.Cells["top"].Windows; len(top) != 2 || top[0] != 100 || top[1] != 101 {
+		t.Errorf("expected top cell [100 101], got %v", top)
+	}
+	// Back in a stack of two, 101 takes its stack slot, not its old frame
+	if frame, _ := fs.frame(101); frame == before || frame.Width != 1920 || frame.Y < 270 {
+		t.Errorf("window 101 frame = %+v, want the lower half of top", frame)
+	}
+	if len(space.MoveHistory) != 0 {
+		t.Errorf("expected the undone move to leave the history, got %v", space.MoveHistory)
+	}
+
+	if _, err := UndoMove(context.Background(), c, snap, cfg, rs); err == nil {
+		t.Error("expected an error with nothing left to undo")
+	}
+}
+
+func TestUndoMove_IntoEmptiedCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("1").RemoveWindow(100)
+	snap.Windows = []server.WindowInfo{{ID: 101}}
+	c, _ := startFakeServer(t)
+
+	// 101 leaves top empty when it moves down
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+	// A reapply keeps only cells that were assigned windows
+	rs.SetWindowAssignments("1", map[string][]uint32{"bottom": {101}})
+
+	if _, err := UndoMove(context.Background(), c, snap, cfg, rs); err != nil {
+		t.Fatal(err)
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "top" {
+		t.Errorf("window 101 should be back in top, got %q", cell)
+	}
+}
+
+func TestUndoMove_ReappliesLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("1").RemoveWindow(100)
+	before := types.Rect{X: 40, Y: 60, Width: 800, Height: 400}
+	snap.Windows = []server.WindowInfo{{ID: 101, Frame: before}}
+	c, fs := startFakeServer(t)
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UndoMove(context.Background(), c, snap, cfg, rs); err != nil {
+		t.Fatal(err)
+	}
+	// Alone in top again, 101 fills the cell rather than its pre-move frame
+	if frame, ok := fs.frame(101); !ok || frame == before || frame.Width != 1920 || frame.Y >= 270 {
+		t.Errorf("window 101 frame = %+v (set: %v), want the top cell", frame, ok)
+	}
+}
+
+func TestUndoMove_CrossDisplay(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := rightDisplayFixture()
+	rs.GetSpace("2").AssignWindow(200, "main")
+	c, fs := startFakeServerWithResults(t, dumpWithDisplays("main", "right"))
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, Extend: true}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := UndoMove(context.Background(), c, snap, cfg, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.CrossDisplay || result.SourceSpace != "2" || result.TargetSpace != "1" {
+		t.Errorf("expected an undo from space 2 to 1, got %+v", result)
+	}
+
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(100); cell != "main" {
+		t.Errorf("window 100 should be back in space 1 main, got %q", cell)
+	}
+	if cell := rs.GetSpaceReadOnly("2").GetWindowCell(100); cell != "" {
+		t.Errorf("window 100 should have left space 2, still in %q", cell)
+	}
+	sentBack := false
+	for _, req := range fs.Requests() {
+		if req.Method == "updateWindow" && req.Params["spaceId"] == "1" {
+			sentBack = true
+		}
+	}
+	if !sentBack {
+		t.Error("expected the window to be sent back to space 1")
+	}
+	// The window left behind on the right display fills its cell again
+	if frame, ok := fs.frame(200); !ok || frame.X < 1920 || frame.Width < 1900 {
+		t.Errorf("window 200 frame = %+v (set: %v), want the whole right display", frame, ok)
+	}
+}
@@ -3,6 +3,9 @@ package window
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/yourusername/grid-cli/internal/client"
 	"github.com/yourusername/grid-cli/internal/config"
@@ -14,21 +17,85 @@ import (
 	"github.com/yourusername/grid-cli/internal/types"
 )
 
+// windowPadding is the space between windows stacked in a cell when only
+// some cells are re-placed, as in a full layout apply
+const windowPadding = 4
+
 // MoveWindowOpts configures window movement behavior
 type MoveWindowOpts struct {
-	WrapAround bool   // Wrap within current monitor
-	Extend     bool   // Allow crossing to adjacent monitors
-	WindowID   uint32 // Specific window to move (0 = use focused)
+	WrapAround      bool   // Wrap within current monitor
+	Extend          bool   // Allow crossing to adjacent monitors
+	WindowID        uint32 // Specific window to move (0 = use focused)
+	WithAppSiblings bool   // Also move other windows of the same app
+	TargetSpace     string // Fallback space when Extend finds no adjacent display
+	AutoLayout      bool   // Apply the target space's default layout if it has none
+	Follow          bool   // Switch to the target space if a cross-display move leaves it inactive
+	KeepRelative    bool   // Keep the window's share of its cell in the target cell
+	AutoExpand      bool   // Switch to a layout with more cells instead of overstacking the target cell
+	ReflowSource    bool   // Re-place the windows left in the source cell after a cross-display move
+
+	// KeepTargetRatios keeps the target cell's split ratios, giving the
+	// incoming window a 1/n share, instead of equalizing the cell
+	KeepTargetRatios bool
+
+	// Split sets the target cell's stack mode (vertical or horizontal) as
+	// part of the move ("" keeps the cell's mode)
+	Split types.StackMode
+
+	// FocusFollow overrides settings.focusFollowsMove for this move (nil =
+	// use the setting), see withFocusPolicy
+	FocusFollow *bool
+
+	// keepFocus leaves OS focus where it is instead of focusing the moved
+	// window; set by withFocusPolicy
+	keepFocus bool
+
+	// App and Nth pick the window to move instead of WindowID: the Nth
+	// window of the app on the space (see NthAppWindow). Ignored if App is "".
+	App string
+	Nth int
 }
 
 // MoveResult contains the outcome of a window move
 type MoveResult struct {
-	WindowID     uint32 // Window that was moved
-	SourceCell   string // Original cell ID
-	TargetCell   string // Destination cell ID
-	SourceSpace  string // Original space ID (for cross-display)
-	TargetSpace  string // Destination space ID (for cross-display)
-	CrossDisplay bool   // Whether move crossed displays
+	WindowID     uint32   // Window that was moved
+	SourceCell   string   // Original cell ID
+	TargetCell   string   // Destination cell ID
+	SourceSpace  string   // Original space ID (for cross-display)
+	TargetSpace  string   // Destination space ID (for cross-display)
+	CrossDisplay bool     // Whether move crossed displays
+	Siblings     []uint32 // Same-app windows moved along with the window
+	Reordered    bool     // Window moved within its cell's stack instead of between cells
+	StackIndex   int      // New position in the stack when Reordered
+
+	TargetDisplay  string // Display UUID the window moved to (for cross-display)
+	TargetInactive bool   // Target space isn't showing on any display after the move
+	Followed       bool   // Switched to the target space after the move
+
+	// SwappedWith is the window that took the moved window's place in a
+	// swap (0 if none)
+	SwappedWith uint32
+
+	// ExpandedLayout is the layout switched to by AutoExpand ("" if none)
+	ExpandedLayout string
+
+	// SourceReflowed counts the windows re-placed in the source cell after
+	// the window left the space
+	SourceReflowed int
+
+	// Unchanged says why nothing was moved, e.g. promoting the window
+	// already in the main cell ("" if the window moved)
+	Unchanged string
+}
+
+// InactiveSpaceNote describes where a cross-display move left a window that
+// isn't visible, or returns "" if the window is on an active space.
+func InactiveSpaceNote(result *MoveResult) string {
+	if result == nil || !result.CrossDisplay || !result.TargetInactive || result.Followed {
+		return ""
+	}
+	return fmt.Sprintf("window %d is now on inactive space %s of display %s (use --follow to switch to it)",
+		result.WindowID, result.TargetSpace, result.TargetDisplay)
 }
 
 // MoveWindow moves a window to an adjacent cell in the given direction.
@@ -43,6 +110,38 @@ func MoveWindow(
 	direction types.Direction,
 	opts MoveWindowOpts,
 ) (*MoveResult, error) {
+	if opts.App != "" {
+		if opts.WindowID != 0 {
+			return nil, fmt.Errorf("give either a window ID or an app, not both")
+		}
+		windowID, err := NthAppWindow(snap, opts.App, opts.Nth)
+		if err != nil {
+			return nil, err
+		}
+		opts.WindowID = windowID
+	}
+	return withFocusPolicy(ctx, c, snap, cfg, rs, opts, func(opts MoveWindowOpts) (*MoveResult, error) {
+		return moveWindow(ctx, c, snap, cfg, rs, direction, opts)
+	})
+}
+
+func moveWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	if opts.Split != "" && opts.Split != types.StackVertical && opts.Split != types.StackHorizontal {
+		return nil, fmt.Errorf("invalid split %q (must be vertical or horizontal)", opts.Split)
+	}
+	// An expansion puts the window in whichever cell the larger layout picks
+	if opts.Split != "" && opts.AutoExpand {
+		return nil, fmt.Errorf("--split can't be combined with --auto-expand")
+	}
+
 	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
 	if spaceState == nil || spaceState.CurrentLayoutID == "" {
 		return nil, fmt.Errorf("no layout applied")
@@ -63,32 +162,101 @@ func MoveWindow(
 		return nil, fmt.Errorf("window %d not assigned to any cell", windowID)
 	}
 
+	// Sticky windows are left alone unless settings.tileStickyWindows un-sticks them
+	sc := layout.NewStickyChecker(c)
+	for _, w := range snap.Windows {
+		if w.ID == windowID && !sc.Prepare(ctx, w, cfg.Settings.TileStickyWindows) {
+			return nil, fmt.Errorf("window %d is sticky; set settings.tileStickyWindows to tile it", windowID)
+		}
+	}
+
+	var siblings []uint32
+	if opts.WithAppSiblings {
+		siblings = FindAppSiblings(snap, spaceState, windowID)
+	}
+
 	logging.Info().
 		Uint32("windowId", windowID).
 		Str("sourceCell", sourceCell).
 		Str("direction", direction.String()).
+		Int("siblings", len(siblings)).
 		Msg("moving window")
 
 	// Get current layout and calculate bounds
-	layoutDef, err := cfg.GetLayout(spaceState.CurrentLayoutID)
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
 	if err != nil {
 		return nil, fmt.Errorf("layout not found: %w", err)
 	}
-	calculated := layout.CalculateLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding))
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	// A window filling the display though its cell doesn't is fullscreen or
+	// maximized: leave native fullscreen first, then tile it as usual
+	if !FillsRect(calculated.CellBounds[sourceCell], snap.DisplayBounds) {
+		switch WindowScreenState(snap, windowID) {
+		case ScreenFullscreen:
+			exited, err := exitFullscreen(ctx, c, snap, windowID)
+			if err != nil {
+				return nil, err
+			}
+			if exited {
+				logging.Info().Uint32("windowId", windowID).Msg("exited fullscreen before move")
+			} else {
+				logging.Info().Uint32("windowId", windowID).Msg("moving window that fills the display")
+			}
+		case ScreenMaximized:
+			logging.Info().Uint32("windowId", windowID).Msg("moving maximized window")
+		}
+	}
+
+	// Along a stack's axis, move within the stack until the window reaches its
+	// edge. A split always leaves the cell.
+	if !opts.WithAppSiblings && opts.Split == "" {
+		mode := layout.EffectiveCellMode(cfg, layoutDef, spaceState, sourceCell, snap.DisplayBounds, false, false)
+		cellWindows := spaceState.Cells[sourceCell].Windows
+		for idx, wid := range cellWindows {
+			if wid != windowID {
+				continue
+			}
+			if to, ok := StackStep(mode, direction, idx, len(cellWindows)); ok {
+				return reorderInCell(ctx, c, snap, cfg, rs, windowID, sourceCell, idx, to, !opts.keepFocus)
+			}
+			break
+		}
+	}
+
+	// A single-cell layout has no neighbours; only another display can take the window
+	if len(calculated.CellBounds) == 1 {
+		if opts.Extend {
+			return moveWindowCrossDisplay(ctx, c, snap, cfg, rs, direction, nil, windowID, siblings, sourceCell, calculated.CellBounds, opts)
+		}
+		return nil, fmt.Errorf("layout %s has only one cell (use --extend to move across displays)", layoutDef.ID)
+	}
 
-	// Find adjacent cells on current display
-	adjacentMap := layout.GetAdjacentCells(sourceCell, calculated.CellBounds)
+	// Find adjacent cells on current display, jumping over skipNavigation cells
+	navBounds := layout.NavigableCells(layoutDef, calculated.CellBounds, sourceCell)
+	adjacentMap := layout.GetAdjacentCells(sourceCell, navBounds)
 	candidates := adjacentMap[direction]
 
+	if len(candidates) == 0 && direction.IsDiagonal() {
+		// No true diagonal neighbour - fall back to two orthogonal steps.
+		// Diagonals never cross displays or wrap.
+		targetCell := focus.FindDiagonalStep(direction, sourceCell, navBounds)
+		if targetCell == "" {
+			return nil, fmt.Errorf("no cell in direction %s", direction.String())
+		}
+		return moveWithinSpace(ctx, c, snap, cfg, rs, calculated, windowID, siblings, sourceCell, targetCell, opts)
+	}
+
 	if len(candidates) == 0 {
 		// No adjacent cell on current display - try cross-monitor if extend is enabled
 		if opts.Extend {
-			result, err := moveWindowCrossDisplay(ctx, c, snap, cfg, rs, direction, windowID, sourceCell, calculated.CellBounds, opts.WrapAround)
+			result, err := moveWindowCrossDisplay(ctx, c, snap, cfg, rs, direction, nil, windowID, siblings, sourceCell, calculated.CellBounds, opts)
 			if err == nil {
 				return result, nil
 			}
-			// If cross-display failed and wrap is not enabled, return the error
-			if !opts.WrapAround {
+			// If cross-display failed and wrap is not enabled (or an explicit
+			// target space was requested), return the error
+			if !opts.WrapAround || opts.TargetSpace != "" {
 				return nil, err
 			}
 		}
@@ -97,17 +265,454 @@ func MoveWindow(
 			return nil, fmt.Errorf("no cell in direction %s", direction.String())
 		}
 		// Wrap: find cell on opposite edge of current display
-		candidates = focus.FindWrapTarget(direction, sourceCell, calculated.CellBounds)
+		candidates = focus.FindWrapTarget(direction, sourceCell, navBounds)
 		if len(candidates) == 0 {
 			return nil, fmt.Errorf("no cell in direction %s (wrap)", direction.String())
 		}
 	}
 
 	// Pick closest candidate
-	targetCell := focus.PickClosestCell(sourceCell, candidates, calculated.CellBounds)
+	targetCell := focus.PickClosestCell(sourceCell, candidates, navBounds)
 
 	// Move window to target cell (same display/space)
-	return moveWindowToCell(ctx, c, snap, cfg, rs, windowID, sourceCell, targetCell, snap.SpaceID)
+	return moveWithinSpace(ctx, c, snap, cfg, rs, calculated, windowID, siblings, sourceCell, targetCell, opts)
+}
+
+// ThrowWindow moves a window to the current space of the display referenced
+// by index (as in `list displays`) or UUID. The window lands in the cell
+// closest to its position on the current display.
+func ThrowWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	displayRef string,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	return withFocusPolicy(ctx, c, snap, cfg, rs, opts, func(opts MoveWindowOpts) (*MoveResult, error) {
+		return throwWindow(ctx, c, snap, cfg, rs, displayRef, opts)
+	})
+}
+
+func throwWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	displayRef string,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	if opts.Split != "" && opts.Split != types.StackVertical && opts.Split != types.StackHorizontal {
+		return nil, fmt.Errorf("invalid split %q (must be vertical or horizontal)", opts.Split)
+	}
+
+	target, err := server.ResolveDisplay(snap.AllDisplays, displayRef)
+	if err != nil {
+		return nil, err
+	}
+	if fmt.Sprintf("%v", target.CurrentSpaceID) == snap.SpaceID {
+		return nil, fmt.Errorf("window is already on display %s", displayRef)
+	}
+
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, fmt.Errorf("no layout applied")
+	}
+
+	windowID := opts.WindowID
+	if windowID == 0 {
+		windowID = spaceState.GetFocusedWindow()
+		if windowID == 0 {
+			return nil, fmt.Errorf("no focused window")
+		}
+	}
+
+	sourceCell := spaceState.GetWindowCell(windowID)
+	if sourceCell == "" {
+		return nil, fmt.Errorf("window %d not assigned to any cell", windowID)
+	}
+
+	var siblings []uint32
+	if opts.WithAppSiblings {
+		siblings = FindAppSiblings(snap, spaceState, windowID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return nil, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	logging.Info().
+		Uint32("windowId", windowID).
+		Str("sourceCell", sourceCell).
+		Str("display", target.UUID).
+		Msg("throwing window to display")
+
+	// The direction is unused with an explicit target display
+	return moveWindowCrossDisplay(ctx, c, snap, cfg, rs, types.DirRight, target, windowID, siblings, sourceCell, calculated.CellBounds, opts)
+}
+
+// moveWithinSpace moves a window to another cell on the snapshot's space.
+// With AutoExpand, a move that would overstack the target cell switches to a
+// larger layout instead, if the space's cycle has one.
+func moveWithinSpace(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	calculated *types.CalculatedLayout,
+	windowID uint32,
+	siblings []uint32,
+	sourceCell string,
+	targetCell string,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	if opts.AutoExpand {
+		space := rs.GetSpaceReadOnly(snap.SpaceID)
+		layoutDef, err := layout.SpaceLayout(cfg, space)
+		if err != nil {
+			return nil, fmt.Errorf("layout not found: %w", err)
+		}
+		if overstacks(cfg, layoutDef, space, calculated.CellBounds[targetCell], targetCell, 1+len(siblings), snap.DisplayBounds) {
+			if larger := layout.NextLargerLayout(cfg, snap.SpaceID, layout.SpaceDisplay(snap, snap.SpaceID), space.CurrentLayoutID); larger != "" {
+				return expandLayout(ctx, c, snap, cfg, rs, windowID, siblings, sourceCell, larger, !opts.keepFocus)
+			}
+			logging.Info().Str("cell", targetCell).Msg("cell is full but no larger layout is in the cycle")
+		}
+	}
+	return moveWindowToCell(ctx, c, snap, cfg, rs, windowID, siblings, sourceCell, targetCell, snap.SpaceID, opts.KeepRelative, opts.KeepTargetRatios, opts.Split, !opts.keepFocus)
+}
+
+// overstacks reports whether adding windows to a cell would squeeze its
+// stacked windows below settings.minWindowWidth/Height
+func overstacks(cfg *config.Config, layoutDef *types.Layout, space *state.SpaceState, cellBounds types.Rect, cellID string, adding int, display types.Rect) bool {
+	minWidth, minHeight := float64(cfg.Settings.MinWindowWidth), float64(cfg.Settings.MinWindowHeight)
+	if minWidth <= 0 && minHeight <= 0 {
+		return false
+	}
+
+	mode := layout.EffectiveCellMode(cfg, layoutDef, space, cellID, display, false, space.BSP)
+	count := adding
+	if cell, ok := space.Cells[cellID]; ok {
+		count += len(cell.Windows)
+	}
+
+	for _, bounds := range layout.CalculateWindowBounds(cellBounds, count, mode, nil, windowPadding) {
+		if (minWidth > 0 && bounds.Width < minWidth) || (minHeight > 0 && bounds.Height < minHeight) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandLayout switches the space to layoutID and re-applies it with the
+// preserve strategy. The moving window and its siblings leave their cells
+// first, so they land in the new layout's least populated cell while every
+// other window keeps its cell. State is rolled back if the apply fails.
+func expandLayout(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+	siblings []uint32,
+	sourceCell string,
+	layoutID string,
+	focusMoved bool,
+) (*MoveResult, error) {
+	logging.Info().Uint32("windowId", windowID).Str("layout", layoutID).Msg("expanding to larger layout")
+
+	preMove := rs.SnapshotSpaces(snap.SpaceID)
+	space := rs.GetSpace(snap.SpaceID)
+	space.RemoveWindow(windowID)
+	for _, wid := range siblings {
+		space.RemoveWindow(wid)
+	}
+
+	applyOpts := layout.DefaultApplyOptions()
+	applyOpts.Gap = float64(cfg.Settings.CellPadding)
+	applyOpts.Strategy = types.AssignPreserve
+	if _, err := layout.ApplyLayoutWithResult(ctx, c, snap, cfg, rs, layoutID, applyOpts); err != nil {
+		rs.RestoreSpaces(preMove)
+		return nil, fmt.Errorf("failed to switch to layout %s: %w", layoutID, err)
+	}
+
+	// Focus follows the window into its new cell
+	space = rs.GetSpace(snap.SpaceID)
+	space.SetFocusedWindow(windowID)
+	targetCell := space.GetWindowCell(windowID)
+	if focusMoved {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
+		}
+	}
+
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &MoveResult{
+		WindowID:       windowID,
+		SourceCell:     sourceCell,
+		TargetCell:     targetCell,
+		SourceSpace:    snap.SpaceID,
+		TargetSpace:    snap.SpaceID,
+		Siblings:       siblings,
+		ExpandedLayout: layoutID,
+	}, nil
+}
+
+// StackStep returns the stack position a window at idx (of n) moves to when
+// moved in direction, if the direction runs along the stack's axis and the
+// window isn't already at that end. Tabbed cells have no axis; grid cells
+// move along both (see gridStep).
+func StackStep(mode types.StackMode, direction types.Direction, idx, n int) (int, bool) {
+	if mode == types.StackGrid {
+		return gridStep(direction, idx, n)
+	}
+
+	step := 0
+	switch {
+	case (mode == types.StackVertical || mode == "") && direction == types.DirUp,
+		mode == types.StackHorizontal && direction == types.DirLeft:
+		step = -1
+	case (mode == types.StackVertical || mode == "") && direction == types.DirDown,
+		mode == types.StackHorizontal && direction == types.DirRight:
+		step = 1
+	default:
+		return 0, false
+	}
+
+	to := idx + step
+	if to < 0 || to >= n {
+		return 0, false
+	}
+	return to, true
+}
+
+// gridStep is StackStep for a grid-stacked cell: left and right move within
+// the window's row, up and down to the same column of the row above or
+// below (or the last window of a shorter last row).
+func gridStep(direction types.Direction, idx, n int) (int, bool) {
+	cols := layout.GridStackColumns(n)
+	if cols == 0 || idx < 0 || idx >= n {
+		return 0, false
+	}
+	row, col := idx/cols, idx%cols
+
+	to := idx
+	switch direction {
+	case types.DirLeft:
+		if col == 0 {
+			return 0, false
+		}
+		to = idx - 1
+	case types.DirRight:
+		if col == cols-1 || idx+1 >= n {
+			return 0, false
+		}
+		to = idx + 1
+	case types.DirUp:
+		if row == 0 {
+			return 0, false
+		}
+		to = idx - cols
+	case types.DirDown:
+		if (row+1)*cols >= n {
+			return 0, false
+		}
+		to = min(idx+cols, n-1)
+	default:
+		return 0, false
+	}
+	return to, true
+}
+
+// reorderInCell swaps a window with its stack neighbour and re-places the cell.
+func reorderInCell(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+	cellID string,
+	from, to int,
+	focusMoved bool,
+) (*MoveResult, error) {
+	logging.Info().
+		Uint32("windowId", windowID).
+		Str("cell", cellID).
+		Int("from", from).
+		Int("to", to).
+		Msg("reordering window within stack")
+
+	mutableSpace := rs.GetSpace(snap.SpaceID)
+	mutableSpace.SwapInCell(cellID, from, to)
+	mutableSpace.SetFocus(cellID, to)
+
+	placements, err := cellPlacements(cfg, mutableSpace, cellID, server.DisplayInfo{VisibleFrame: snap.DisplayBounds}, snap.Windows)
+	if err != nil {
+		return nil, fmt.Errorf("layout not found: %w", err)
+	}
+	if err := layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
+		return nil, fmt.Errorf("failed to apply placements: %w", err)
+	}
+
+	// Focus the window
+	if focusMoved {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
+		}
+	}
+
+	// Save state
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &MoveResult{
+		WindowID:    windowID,
+		SourceCell:  cellID,
+		TargetCell:  cellID,
+		SourceSpace: snap.SpaceID,
+		TargetSpace: snap.SpaceID,
+		Reordered:   true,
+		StackIndex:  to,
+	}, nil
+}
+
+// NthAppWindow returns the nth (1-based) window on the snapshot's space whose
+// app name or bundle ID is app (ignoring case), counting in window ID order
+// so the same window is picked on every run.
+func NthAppWindow(snap *server.Snapshot, app string, n int) (uint32, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("window index must be 1 or more, got %d", n)
+	}
+
+	var matches []uint32
+	for _, w := range snap.Windows {
+		if strings.EqualFold(w.AppName, app) || (w.BundleID != "" && strings.EqualFold(w.BundleID, app)) {
+			matches = append(matches, w.ID)
+		}
+	}
+	if n > len(matches) {
+		return 0, fmt.Errorf("wanted %s window #%d but space %s has %d", app, n, snap.SpaceID, len(matches))
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i] < matches[j] })
+	return matches[n-1], nil
+}
+
+// FindAppSiblings returns the other tiled windows on the snapshot's space
+// that belong to the same app (by bundle ID, falling back to app name).
+func FindAppSiblings(snap *server.Snapshot, spaceState *state.SpaceState, windowID uint32) []uint32 {
+	var source *server.WindowInfo
+	for i := range snap.Windows {
+		if snap.Windows[i].ID == windowID {
+			source = &snap.Windows[i]
+			break
+		}
+	}
+	if source == nil {
+		return nil
+	}
+
+	var siblings []uint32
+	for _, w := range snap.Windows {
+		if w.ID == windowID || !snap.WindowIDs[w.ID] {
+			continue
+		}
+		sameApp := false
+		if source.BundleID != "" && w.BundleID != "" {
+			sameApp = w.BundleID == source.BundleID
+		} else {
+			sameApp = w.AppName == source.AppName
+		}
+		if !sameApp || spaceState.GetWindowCell(w.ID) == "" {
+			continue
+		}
+		siblings = append(siblings, w.ID)
+	}
+	return siblings
+}
+
+// CollectIntoCell moves a window and its siblings into targetCell, with the
+// window itself on top. The cell is rebalanced to equal ratios unless
+// keepRatios is set, when each arrival gets a proportional share instead.
+// Returns the source cells the siblings came from.
+func CollectIntoCell(space *state.SpaceState, targetCell string, windowID uint32, siblings []uint32, keepRatios bool) []string {
+	prepend := space.PrependWindowToCell
+	if keepRatios {
+		prepend = space.PrependWindowToCellKeepRatios
+	}
+
+	var sourceCells []string
+	seen := map[string]bool{targetCell: true}
+
+	for _, sid := range siblings {
+		if cellID := space.GetWindowCell(sid); cellID != "" && !seen[cellID] {
+			seen[cellID] = true
+			sourceCells = append(sourceCells, cellID)
+		}
+		prepend(sid, targetCell)
+	}
+	prepend(windowID, targetCell)
+
+	return sourceCells
+}
+
+// windowShare returns the window's split ratio within its cell, or 0 if the
+// window isn't in a cell.
+func windowShare(space *state.SpaceState, windowID uint32) float64 {
+	cellID := space.GetWindowCell(windowID)
+	if cellID == "" {
+		return 0
+	}
+	ratios := cellRatios(space, cellID)
+	for i, wid := range space.Cells[cellID].Windows {
+		if wid == windowID {
+			return ratios[i]
+		}
+	}
+	return 0
+}
+
+// cellRatios returns a copy of a cell's split ratios, one per window, falling
+// back to equal ratios when they're missing or out of step with the windows.
+func cellRatios(space *state.SpaceState, cellID string) []float64 {
+	cell := space.Cells[cellID]
+	if cell == nil {
+		return nil
+	}
+	if len(cell.SplitRatios) != len(cell.Windows) {
+		return layout.InitializeSplitRatios(len(cell.Windows))
+	}
+	return append([]float64(nil), cell.SplitRatios...)
+}
+
+// applyShare gives the window just collected at the top of a cell the given
+// share of it. prevRatios are the cell's ratios before the window arrived;
+// the windows already there keep their proportions. Windows arriving with it
+// (app siblings) reset the cell to equal proportions instead.
+func applyShare(space *state.SpaceState, cellID string, share float64, prevRatios []float64) {
+	cell := space.Cells[cellID]
+	if cell == nil || share <= 0 {
+		return
+	}
+	others := prevRatios
+	if len(others) != len(cell.Windows)-1 {
+		others = layout.InitializeSplitRatios(len(cell.Windows) - 1)
+	}
+	cell.SplitRatios = layout.InsertRatioWithShare(others, 0, share, layout.MinimumRatio)
+	cell.RatiosUserSet = true
 }
 
 // moveWindowToCell handles the actual window movement within the same space.
@@ -118,9 +723,14 @@ func moveWindowToCell(
 	cfg *config.Config,
 	rs *state.RuntimeState,
 	windowID uint32,
+	siblings []uint32,
 	sourceCell string,
 	targetCell string,
 	spaceID string,
+	keepRelative bool,
+	keepTargetRatios bool,
+	split types.StackMode,
+	focusMoved bool,
 ) (*MoveResult, error) {
 	logging.Info().
 		Uint32("windowId", windowID).
@@ -129,56 +739,96 @@ func moveWindowToCell(
 		Str("space", spaceID).
 		Msg("moving window to cell")
 
-	// Update state: move window from source to target cell
+	// Update state: move window (and any siblings) from source to target cell
 	mutableSpace := rs.GetSpace(spaceID)
-	mutableSpace.PrependWindowToCell(windowID, targetCell)
+	entry := state.MoveEntry{
+		WindowID:    windowID,
+		SourceCell:  sourceCell,
+		SourceIndex: windowIndex(mutableSpace, sourceCell, windowID),
+		TargetCell:  targetCell,
+		Before:      windowFrame(snap, windowID),
+		Time:        time.Now(),
+	}
+	share, targetRatios := windowShare(mutableSpace, windowID), cellRatios(mutableSpace, targetCell)
+	siblingCells := CollectIntoCell(mutableSpace, targetCell, windowID, siblings, keepTargetRatios)
+	if keepRelative {
+		applyShare(mutableSpace, targetCell, share, targetRatios)
+	}
+	if split != "" {
+		mutableSpace.GetCell(targetCell).StackMode = split
+	}
 
 	// Update focus to follow the window
 	mutableSpace.SetFocus(targetCell, 0)
 
-	// Calculate placements for affected cells only (not full layout re-assignment)
-	layoutDef, err := cfg.GetLayout(mutableSpace.CurrentLayoutID)
+	// Re-place the affected cells only (not full layout re-assignment)
+	affected := append([]string{sourceCell, targetCell}, siblingCells...)
+	placements, err := placeCells(ctx, c, snap, cfg, mutableSpace, affected)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the move with the frame the window was given
+	entry.After = placedBounds(placements, windowID)
+	mutableSpace.PushMoveHistory(entry)
+
+	// Focus the window
+	if focusMoved {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
+			// Non-fatal - window was moved successfully
+		}
+	}
+
+	// Save state
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &MoveResult{
+		WindowID:     windowID,
+		SourceCell:   sourceCell,
+		TargetCell:   targetCell,
+		SourceSpace:  spaceID,
+		TargetSpace:  spaceID,
+		CrossDisplay: false,
+		Siblings:     siblings,
+	}, nil
+}
+
+// placeCells calculates and applies placements for the windows in the given
+// cells of a space on the snapshot's display, leaving other cells alone.
+// Returns the placements it applied.
+func placeCells(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	space *state.SpaceState,
+	cellIDs []string,
+) ([]types.WindowPlacement, error) {
+	layoutDef, err := layout.SpaceLayout(cfg, space)
 	if err != nil {
 		return nil, fmt.Errorf("layout not found: %w", err)
 	}
-	calculated := layout.CalculateLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding))
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), space)
 
 	// Build assignments for just the affected cells
 	affectedAssignments := make(map[string][]uint32)
-	if sourceCell != "" {
-		if cellState := mutableSpace.Cells[sourceCell]; cellState != nil {
-			affectedAssignments[sourceCell] = cellState.Windows
+	for _, cellID := range cellIDs {
+		if cellState := space.Cells[cellID]; cellState != nil {
+			affectedAssignments[cellID] = cellState.Windows
 		}
 	}
-	if cellState := mutableSpace.Cells[targetCell]; cellState != nil {
-		affectedAssignments[targetCell] = cellState.Windows
-	}
 
 	// Get cell modes from layout config AND state (matching ApplyLayout hierarchy)
 	cellModes := make(map[string]types.StackMode)
 	cellRatios := make(map[string][]float64)
 	for cellID := range affectedAssignments {
-		// 1. Check layout definition's per-cell StackMode
-		for _, cell := range layoutDef.Cells {
-			if cell.ID == cellID && cell.StackMode != "" {
-				cellModes[cellID] = cell.StackMode
-				break
-			}
-		}
-		// 2. Check layout's CellModes map (overrides per-cell)
-		if layoutDef.CellModes != nil {
-			if mode, ok := layoutDef.CellModes[cellID]; ok {
-				cellModes[cellID] = mode
-			}
-		}
-		// 3. State override (highest priority)
-		if cellState, ok := mutableSpace.Cells[cellID]; ok {
-			if cellState.StackMode != "" {
-				cellModes[cellID] = cellState.StackMode
-			}
-			if len(cellState.SplitRatios) > 0 {
-				cellRatios[cellID] = cellState.SplitRatios
-			}
+		cellModes[cellID] = layout.EffectiveCellMode(cfg, layoutDef, space, cellID, snap.DisplayBounds, false, space.BSP)
+		if cellState, ok := space.Cells[cellID]; ok && len(cellState.SplitRatios) > 0 {
+			cellRatios[cellID] = cellState.SplitRatios
 		}
 	}
 
@@ -188,37 +838,41 @@ func moveWindowToCell(
 		affectedAssignments,
 		cellModes,
 		cellRatios,
-		cfg.Settings.DefaultStackMode,
-		4, // padding
+		layout.DefaultStackMode(cfg, snap.DisplayBounds, false),
+		windowPadding,
 	)
+	placements = layout.SnapPlacements(cfg, calculated, placements, snap.Windows, windowPadding)
 
-	if err := layout.ApplyPlacements(ctx, c, placements); err != nil {
+	if err := layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
 		return nil, fmt.Errorf("failed to apply placements: %w", err)
 	}
+	return placements, nil
+}
 
-	// Focus the window
-	if err := focus.FocusWindow(ctx, c, windowID); err != nil {
-		logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
-		// Non-fatal - window was moved successfully
+// verifyDisplayConnected re-fetches the display list and fails if uuid is no
+// longer among them. A failed or display-less dump is inconclusive and only
+// logged; the move RPC that follows reports connection problems itself.
+func verifyDisplayConnected(ctx context.Context, c *client.Client, uuid string) error {
+	displays, err := server.FetchDisplays(ctx, c)
+	if err != nil {
+		logging.Warn().Err(err).Str("display", uuid).Msg("could not re-check target display")
+		return nil
 	}
-
-	// Save state
-	rs.MarkUpdated()
-	if err := rs.Save(); err != nil {
-		logging.Warn().Err(err).Msg("failed to save state")
+	if len(displays) == 0 {
+		return nil
 	}
-
-	return &MoveResult{
-		WindowID:     windowID,
-		SourceCell:   sourceCell,
-		TargetCell:   targetCell,
-		SourceSpace:  spaceID,
-		TargetSpace:  spaceID,
-		CrossDisplay: false,
-	}, nil
+	for _, d := range displays {
+		if d.UUID == uuid {
+			return nil
+		}
+	}
+	return fmt.Errorf("target display %s is no longer connected", uuid)
 }
 
-// moveWindowCrossDisplay handles moving a window to an adjacent display.
+// moveWindowCrossDisplay handles moving a window to an adjacent display, or
+// to target if given (direction is then unused).
+// State is only changed once the server has moved the window to the target
+// space; any earlier failure leaves both spaces as they were.
 func moveWindowCrossDisplay(
 	ctx context.Context,
 	c *client.Client,
@@ -226,10 +880,12 @@ func moveWindowCrossDisplay(
 	cfg *config.Config,
 	rs *state.RuntimeState,
 	direction types.Direction,
+	target *server.DisplayInfo,
 	windowID uint32,
+	siblings []uint32,
 	currentCell string,
 	currentCellBounds map[string]types.Rect,
-	wrapAround bool,
+	opts MoveWindowOpts,
 ) (*MoveResult, error) {
 	// Find current display UUID from snapshot
 	currentDisplayUUID := ""
@@ -245,9 +901,23 @@ func moveWindowCrossDisplay(
 	}
 
 	// Find adjacent display in direction
-	adjacentDisplay := focus.FindAdjacentDisplay(currentDisplayUUID, direction, snap.AllDisplays)
+	var pendingLayout *TargetLayout
+	adjacentDisplay := target
 	if adjacentDisplay == nil {
-		if wrapAround {
+		adjacentDisplay = focus.FindAdjacentDisplay(currentDisplayUUID, direction, snap.AllDisplays)
+	}
+	if adjacentDisplay == nil && opts.TargetSpace != "" {
+		// Explicit fallback space takes precedence over wrapping
+		// A default layout it picks is only set once the window has moved
+		target, picked, err := ResolveTargetSpaceDisplay(snap, cfg, rs, opts.TargetSpace, opts.AutoLayout)
+		if err != nil {
+			return nil, err
+		}
+		adjacentDisplay = target
+		pendingLayout = picked
+	}
+	if adjacentDisplay == nil {
+		if opts.WrapAround {
 			// Try to find display on opposite edge
 			adjacentDisplay = focus.FindOppositeDisplay(currentDisplayUUID, direction, snap.AllDisplays)
 		}
@@ -257,7 +927,7 @@ func moveWindowCrossDisplay(
 	}
 
 	// Get cells on the target display
-	targetCellBounds, targetSpaceID, err := focus.GetDisplayCells(*adjacentDisplay, cfg, rs)
+	targetCellBounds, targetSpaceID, err := focus.GetDisplayCells(*adjacentDisplay, cfg, pendingLayout.preview(rs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cells on adjacent display: %w", err)
 	}
@@ -274,17 +944,18 @@ func moveWindowCrossDisplay(
 		}
 	}
 
-	// Map visual position from current cell to target display
-	currentBounds := currentCellBounds[currentCell]
+	// Map the current cell to a cell on the target display
 	targetDisplayBounds := adjacentDisplay.VisibleFrame
 	if targetDisplayBounds == (types.Rect{}) {
 		targetDisplayBounds = adjacentDisplay.Frame
 	}
 
-	targetPoint := focus.MatchVisualPosition(currentBounds, currentDisplayBounds, targetDisplayBounds)
-
-	// Find closest cell to target point
-	targetCell := focus.FindClosestCellToPoint(targetPoint, targetCellBounds)
+	// An explicit target display (throw) has no direction to map along
+	mapping := cfg.Settings.CrossDisplayMapping
+	if target != nil {
+		mapping = config.CrossDisplayProportional
+	}
+	targetCell := focus.CrossDisplayCell(mapping, direction, currentCell, currentCellBounds, currentDisplayBounds, targetDisplayBounds, targetCellBounds)
 	if targetCell == "" {
 		return nil, fmt.Errorf("no cells on adjacent display")
 	}
@@ -300,6 +971,12 @@ func moveWindowCrossDisplay(
 		Str("targetDisplay", adjacentDisplay.UUID).
 		Msg("moving window cross-display")
 
+	// The snapshot may predate a display being disconnected; don't send the
+	// window to a screen that no longer exists
+	if err := verifyDisplayConnected(ctx, c, adjacentDisplay.UUID); err != nil {
+		return nil, err
+	}
+
 	// Move window to target space via server RPC
 	_, err = c.UpdateWindow(ctx, int(windowID), map[string]interface{}{
 		"spaceId": targetSpaceID,
@@ -307,76 +984,92 @@ func moveWindowCrossDisplay(
 	if err != nil {
 		return nil, fmt.Errorf("failed to move window to space %v: %w", targetSpaceID, err)
 	}
+	pendingLayout.Commit(rs)
+
+	// Siblings follow on a best-effort basis
+	var movedSiblings []uint32
+	for _, sid := range siblings {
+		if _, err := c.UpdateWindow(ctx, int(sid), map[string]interface{}{
+			"spaceId": targetSpaceID,
+		}); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", sid).Msg("failed to move app sibling to space")
+			continue
+		}
+		movedSiblings = append(movedSiblings, sid)
+	}
 
 	// Update state on both source and target spaces
 	sourceSpace := rs.GetSpace(snap.SpaceID)
+	share := windowShare(sourceSpace, windowID)
+	entry := state.MoveEntry{
+		WindowID:    windowID,
+		SourceCell:  currentCell,
+		SourceIndex: windowIndex(sourceSpace, currentCell, windowID),
+		TargetCell:  targetCell,
+		TargetSpace: targetSpaceIDStr,
+		Before:      windowFrame(snap, windowID),
+		Time:        time.Now(),
+	}
+	sourceCells := []string{currentCell}
 	sourceSpace.RemoveWindow(windowID)
+	for _, sid := range movedSiblings {
+		sourceCells = append(sourceCells, sourceSpace.GetWindowCell(sid))
+		sourceSpace.RemoveWindow(sid)
+	}
 
 	targetSpace := rs.GetSpace(targetSpaceIDStr)
-	targetSpace.PrependWindowToCell(windowID, targetCell)
+	targetRatios := cellRatios(targetSpace, targetCell)
+	CollectIntoCell(targetSpace, targetCell, windowID, movedSiblings, opts.KeepTargetRatios)
+	if opts.KeepRelative {
+		applyShare(targetSpace, targetCell, share, targetRatios)
+	}
+	if opts.Split != "" {
+		targetSpace.GetCell(targetCell).StackMode = opts.Split
+	}
 	targetSpace.SetFocus(targetCell, 0)
 
-	// Calculate placements for just the target cell (not full layout re-assignment)
-	layoutDef, err := cfg.GetLayout(targetSpace.CurrentLayoutID)
+	// Calculate placements for just the target cell (not full layout re-assignment).
+	// The window is on the target space either way; if it couldn't be placed,
+	// drop the target assignment and let the next reconcile pick it up.
+	placements, err := cellPlacements(cfg, targetSpace, targetCell, *adjacentDisplay, snap.Windows)
+	if err == nil {
+		err = layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration)
+	}
 	if err != nil {
-		logging.Warn().Err(err).Msg("layout not found for target space")
-	} else {
-		targetDisplayBounds := adjacentDisplay.VisibleFrame
-		if targetDisplayBounds == (types.Rect{}) {
-			targetDisplayBounds = adjacentDisplay.Frame
-		}
-		calculated := layout.CalculateLayout(layoutDef, targetDisplayBounds, float64(cfg.Settings.CellPadding))
-
-		// Build assignments for just the target cell
-		affectedAssignments := make(map[string][]uint32)
-		if cellState := targetSpace.Cells[targetCell]; cellState != nil {
-			affectedAssignments[targetCell] = cellState.Windows
-		}
-
-		// Get cell modes from layout config AND state (matching ApplyLayout hierarchy)
-		cellModes := make(map[string]types.StackMode)
-		cellRatios := make(map[string][]float64)
-		// 1. Check layout definition's per-cell StackMode
-		for _, cell := range layoutDef.Cells {
-			if cell.ID == targetCell && cell.StackMode != "" {
-				cellModes[targetCell] = cell.StackMode
-				break
-			}
-		}
-		// 2. Check layout's CellModes map (overrides per-cell)
-		if layoutDef.CellModes != nil {
-			if mode, ok := layoutDef.CellModes[targetCell]; ok {
-				cellModes[targetCell] = mode
-			}
-		}
-		// 3. State override (highest priority)
-		if cellState, ok := targetSpace.Cells[targetCell]; ok {
-			if cellState.StackMode != "" {
-				cellModes[targetCell] = cellState.StackMode
-			}
-			if len(cellState.SplitRatios) > 0 {
-				cellRatios[targetCell] = cellState.SplitRatios
-			}
+		logging.Warn().Err(err).Str("space", targetSpaceIDStr).Msg("failed to place window on target space, rolling back target assignment")
+		targetSpace.RemoveWindow(windowID)
+		for _, sid := range movedSiblings {
+			targetSpace.RemoveWindow(sid)
 		}
+	} else {
+		// Record the move on the space the window left, for undo
+		entry.After = placedBounds(placements, windowID)
+		sourceSpace.PushMoveHistory(entry)
+	}
 
-		// Calculate and apply placements for target cell only
-		placements := layout.CalculateAllWindowPlacements(
-			calculated,
-			affectedAssignments,
-			cellModes,
-			cellRatios,
-			cfg.Settings.DefaultStackMode,
-			4, // padding
-		)
+	reflowed := 0
+	if opts.ReflowSource {
+		reflowed = reflowSourceCells(ctx, c, snap, cfg, rs, sourceCells)
+	}
 
-		if err := layout.ApplyPlacements(ctx, c, placements); err != nil {
-			logging.Warn().Err(err).Msg("failed to apply placements on target space")
+	// A fallback target space may not be showing anywhere; switch to it on request
+	inactive := !spaceVisible(snap.AllDisplays, targetSpaceIDStr)
+	followed := false
+	if inactive && opts.Follow {
+		if _, err := c.CallMethod(ctx, "space.focus", map[string]interface{}{
+			"spaceId": targetSpaceIDStr,
+		}); err != nil {
+			logging.Warn().Err(err).Str("space", targetSpaceIDStr).Msg("failed to switch to target space")
+		} else {
+			followed = true
 		}
 	}
 
-	// Focus the window
-	if err := focus.FocusWindow(ctx, c, windowID); err != nil {
-		logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
+	// Focus the window; switching to the target space takes focus along
+	if !opts.keepFocus || followed {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus moved window")
+		}
 	}
 
 	// Save state
@@ -386,11 +1079,168 @@ func moveWindowCrossDisplay(
 	}
 
 	return &MoveResult{
-		WindowID:     windowID,
-		SourceCell:   currentCell,
-		TargetCell:   targetCell,
-		SourceSpace:  snap.SpaceID,
-		TargetSpace:  targetSpaceIDStr,
-		CrossDisplay: true,
+		WindowID:       windowID,
+		SourceCell:     currentCell,
+		TargetCell:     targetCell,
+		SourceSpace:    snap.SpaceID,
+		TargetSpace:    targetSpaceIDStr,
+		CrossDisplay:   true,
+		Siblings:       movedSiblings,
+		TargetDisplay:  adjacentDisplay.UUID,
+		TargetInactive: inactive,
+		Followed:       followed,
+		SourceReflowed: reflowed,
 	}, nil
 }
+
+// spaceVisible reports whether any display currently shows the space.
+func spaceVisible(displays []server.DisplayInfo, spaceID string) bool {
+	for _, d := range displays {
+		if fmt.Sprintf("%v", d.CurrentSpaceID) == spaceID {
+			return true
+		}
+	}
+	return false
+}
+
+// cellPlacements calculates placements for the windows of a single cell on
+// the given display, using the same stack mode hierarchy as ApplyLayout.
+// windows supplies the app names for snapping to size increments.
+func cellPlacements(cfg *config.Config, space *state.SpaceState, cellID string, display server.DisplayInfo, windows []server.WindowInfo) ([]types.WindowPlacement, error) {
+	layoutDef, err := layout.SpaceLayout(cfg, space)
+	if err != nil {
+		return nil, err
+	}
+
+	displayBounds := display.VisibleFrame
+	if displayBounds == (types.Rect{}) {
+		displayBounds = display.Frame
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, displayBounds, float64(cfg.Settings.CellPadding), space)
+
+	// Build assignments for just the target cell
+	affectedAssignments := make(map[string][]uint32)
+	if cellState := space.Cells[cellID]; cellState != nil {
+		affectedAssignments[cellID] = cellState.Windows
+	}
+
+	// Get cell modes from layout config AND state (matching ApplyLayout hierarchy)
+	cellModes := map[string]types.StackMode{
+		cellID: layout.EffectiveCellMode(cfg, layoutDef, space, cellID, displayBounds, false, space.BSP),
+	}
+	cellRatios := make(map[string][]float64)
+	if cellState, ok := space.Cells[cellID]; ok && len(cellState.SplitRatios) > 0 {
+		cellRatios[cellID] = cellState.SplitRatios
+	}
+
+	placements := layout.CalculateAllWindowPlacements(
+		calculated,
+		affectedAssignments,
+		cellModes,
+		cellRatios,
+		layout.DefaultStackMode(cfg, displayBounds, false),
+		windowPadding,
+	)
+	return layout.SnapPlacements(cfg, calculated, placements, windows, windowPadding), nil
+}
+
+// ResolveTargetSpaceDisplay returns the display to treat as the destination
+// when moving a window to an explicit space. The returned DisplayInfo carries
+// the target space as its current space, with the frame of the display that
+// shows it (or the current display if the space isn't visible anywhere).
+// The space must have a layout; with autoLayout a space without one gets its
+// default layout, returned as a TargetLayout for the caller to commit once
+// the window has moved. rs is not changed.
+func ResolveTargetSpaceDisplay(
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	spaceID string,
+	autoLayout bool,
+) (*server.DisplayInfo, *TargetLayout, error) {
+	if spaceID == snap.SpaceID {
+		return nil, nil, fmt.Errorf("target space %s is the current space", spaceID)
+	}
+
+	var host, current *server.DisplayInfo
+	for i := range snap.AllDisplays {
+		switch fmt.Sprintf("%v", snap.AllDisplays[i].CurrentSpaceID) {
+		case spaceID:
+			host = &snap.AllDisplays[i]
+		case snap.SpaceID:
+			current = &snap.AllDisplays[i]
+		}
+	}
+
+	spaceState := rs.GetSpaceReadOnly(spaceID)
+	var pending *TargetLayout
+	if host == nil {
+		if spaceState == nil && cfg.GetSpaceConfig(spaceID) == nil {
+			return nil, nil, fmt.Errorf("space %s not found", spaceID)
+		}
+		if current == nil {
+			return nil, nil, fmt.Errorf("could not determine display for space %s", spaceID)
+		}
+		// Spaces that aren't visible share the current display's geometry
+		host = current
+	}
+
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		if !autoLayout {
+			return nil, nil, fmt.Errorf("space %s has no layout (use --auto-layout to apply its default)", spaceID)
+		}
+
+		layoutID := ""
+		if spaceConfig := cfg.GetSpaceConfig(spaceID); spaceConfig != nil {
+			layoutID = spaceConfig.DefaultLayout
+		}
+		layoutIDs := cfg.GetLayoutIDs()
+		if layoutID == "" && len(layoutIDs) > 0 {
+			layoutID = layoutIDs[0]
+		}
+		if layoutID == "" {
+			return nil, nil, fmt.Errorf("no layout available for space %s", spaceID)
+		}
+
+		layoutIndex := 0
+		for i, id := range layoutIDs {
+			if id == layoutID {
+				layoutIndex = i
+				break
+			}
+		}
+
+		logging.Info().Str("space", spaceID).Str("layout", layoutID).Msg("auto-applying default layout to target space")
+		pending = &TargetLayout{SpaceID: spaceID, LayoutID: layoutID, Index: layoutIndex}
+	}
+
+	target := *host
+	target.CurrentSpaceID = spaceID
+	return &target, pending, nil
+}
+
+// TargetLayout is the default layout ResolveTargetSpaceDisplay picked for a
+// target space without one
+type TargetLayout struct {
+	SpaceID  string
+	LayoutID string
+	Index    int // Index in the layout cycle
+}
+
+// Commit sets the layout on the space in rs. A nil TargetLayout does nothing.
+func (t *TargetLayout) Commit(rs *state.RuntimeState) {
+	if t != nil {
+		rs.GetSpace(t.SpaceID).SetCurrentLayout(t.LayoutID, t.Index)
+	}
+}
+
+// preview returns rs as it will be after Commit without changing it: a
+// scratch state holding just the target space with the layout set
+func (t *TargetLayout) preview(rs *state.RuntimeState) *state.RuntimeState {
+	if t == nil {
+		return rs
+	}
+	scratch := state.NewRuntimeState()
+	t.Commit(scratch)
+	return scratch
+}
@@ -0,0 +1,1344 @@
+package window
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/models"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/testutil"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestFindAppSiblings(t *testing.T) {
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 1, AppName: "Terminal", BundleID: "com.apple.Terminal"},
+			{ID: 2, AppName: "Safari", BundleID: "com.apple.Safari"},
+			{ID: 3, AppName: "Terminal", BundleID: "com.apple.Terminal"},
+			{ID: 4, AppName: "Terminal", BundleID: "com.apple.Terminal"},
+		},
+		WindowIDs: map[uint32]bool{1: true, 2: true, 3: true, 4: true},
+	}
+	space := state.NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.AssignWindow(3, "right")
+	// Window 4 is not tiled in any cell
+
+	siblings := FindAppSiblings(snap, space, 1)
+
+	if len(siblings) != 1 || siblings[0] != 3 {
+		t.Errorf("expected siblings [3], got %v", siblings)
+	}
+}
+
+func TestCollectIntoCell(t *testing.T) {
+	space := state.NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.AssignWindow(3, "right")
+	space.AssignWindow(4, "bottom")
+	space.AssignWindow(5, "bottom")
+
+	// Move window 1 right, carrying its same-app sibling 4
+	sourceCells := CollectIntoCell(space, "right", 1, []uint32{4}, false)
+
+	right := space.Cells["right"].Windows
+	if len(right) != 3 || right[0] != 1 {
+		t.Fatalf("expected window 1 on top of right cell with 3 windows, got %v", right)
+	}
+	if space.GetWindowCell(4) != "right" {
+		t.Errorf("sibling 4 should be in right, got %q", space.GetWindowCell(4))
+	}
+
+	// Other apps' windows stay put
+	if space.GetWindowCell(2) != "left" {
+		t.Errorf("window 2 should stay in left, got %q", space.GetWindowCell(2))
+	}
+	if space.GetWindowCell(5) != "bottom" {
+		t.Errorf("window 5 should stay in bottom, got %q", space.GetWindowCell(5))
+	}
+
+	if len(sourceCells) != 1 || sourceCells[0] != "bottom" {
+		t.Errorf("expected sibling source cells [bottom], got %v", sourceCells)
+	}
+}
+
+func TestCollectIntoCell_SourceLastFocused(t *testing.T) {
+	space := state.NewSpaceState("1")
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.AssignWindow(3, "left")
+	space.SetFocus("left", 1)
+
+	// Move the focused middle window out of the source cell
+	CollectIntoCell(space, "right", 2, nil, false)
+
+	source := space.Cells["left"]
+	if source.LastFocusedIdx != 0 || source.Windows[source.LastFocusedIdx] != 1 {
+		t.Errorf("expected source to restore window 1 at index 0, got index %d in %v",
+			source.LastFocusedIdx, source.Windows)
+	}
+}
+
+func targetSpaceFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		AllDisplays: []server.DisplayInfo{
+			{
+				UUID:           "main",
+				CurrentSpaceID: 1,
+				Frame:          types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+				VisibleFrame:   types.Rect{X: 0, Y: 25, Width: 1920, Height: 1055},
+			},
+		},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{{ID: "two-column"}, {ID: "ide"}},
+		Spaces: map[string]config.SpaceConfig{
+			"7": {DefaultLayout: "ide"},
+		},
+	}
+	rs := state.NewRuntimeState()
+	rs.GetSpace("1").SetCurrentLayout("two-column", 0)
+	rs.GetSpace("5").SetCurrentLayout("two-column", 0)
+	return snap, cfg, rs
+}
+
+func TestResolveTargetSpaceDisplay(t *testing.T) {
+	snap, cfg, rs := targetSpaceFixture()
+
+	target, pending, err := ResolveTargetSpaceDisplay(snap, cfg, rs, "5", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != nil {
+		t.Errorf("expected no layout to set on space 5, got %+v", pending)
+	}
+
+	// Hidden space borrows the current display's geometry
+	if target.VisibleFrame != snap.AllDisplays[0].VisibleFrame {
+		t.Errorf("expected current display frame, got %+v", target.VisibleFrame)
+	}
+	if target.CurrentSpaceID != "5" {
+		t.Errorf("expected target space 5, got %v", target.CurrentSpaceID)
+	}
+	// The snapshot's display must not be modified
+	if snap.AllDisplays[0].CurrentSpaceID != 1 {
+		t.Error("snapshot display was modified")
+	}
+}
+
+func TestResolveTargetSpaceDisplay_Errors(t *testing.T) {
+	snap, cfg, rs := targetSpaceFixture()
+
+	tests := []struct {
+		name    string
+		spaceID string
+	}{
+		{"current space", "1"},
+		{"unknown space", "99"},
+		{"no layout without auto-layout", "7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ResolveTargetSpaceDisplay(snap, cfg, rs, tt.spaceID, false); err == nil {
+				t.Errorf("expected error for space %s", tt.spaceID)
+			}
+		})
+	}
+}
+
+func TestResolveTargetSpaceDisplay_AutoLayout(t *testing.T) {
+	snap, cfg, rs := targetSpaceFixture()
+
+	_, pending, err := ResolveTargetSpaceDisplay(snap, cfg, rs, "7", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is set until the caller commits
+	if space := rs.GetSpaceReadOnly("7"); space != nil && space.CurrentLayoutID != "" {
+		t.Errorf("expected space 7 untouched before commit, got %+v", space)
+	}
+	pending.Commit(rs)
+	space := rs.GetSpaceReadOnly("7")
+	if space == nil || space.CurrentLayoutID != "ide" || space.LayoutIndex != 1 {
+		t.Errorf("expected default layout ide at index 1 on space 7, got %+v", space)
+	}
+}
+
+func singleCellFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		AllDisplays: []server.DisplayInfo{
+			{UUID: "main", CurrentSpaceID: 1, Frame: bounds, VisibleFrame: bounds},
+		},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "full",
+				Grid:  config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"main"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 0)
+	space.AssignWindow(100, "main")
+	return snap, cfg, rs
+}
+
+func TestMoveWindow_SingleCellLayout(t *testing.T) {
+	snap, cfg, rs := singleCellFixture()
+
+	_, err := MoveWindow(context.Background(), nil, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100})
+	if err == nil || !strings.Contains(err.Error(), "only one cell") {
+		t.Fatalf("expected single-cell error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "no cell in direction") {
+		t.Errorf("single-cell error should be distinct from the no-neighbour error: %v", err)
+	}
+}
+
+func TestMoveWindow_SingleCellLayoutExtend(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := singleCellFixture()
+	opts := MoveWindowOpts{WindowID: 100, Extend: true}
+
+	// No display to the right: the cross-display path reports it
+	_, err := MoveWindow(context.Background(), nil, snap, cfg, rs, types.DirRight, opts)
+	if err == nil || !strings.Contains(err.Error(), "no display in direction") {
+		t.Fatalf("expected cross-display error, got %v", err)
+	}
+
+	// Second display to the right: the move targets its space
+	right := types.Rect{X: 1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays, server.DisplayInfo{
+		UUID: "right", CurrentSpaceID: 2, Frame: right, VisibleFrame: right,
+	})
+	rs.GetSpace("2").SetCurrentLayout("full", 0)
+
+	c, fs := startFakeServer(t)
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.CrossDisplay || result.TargetSpace != "2" || result.TargetCell != "main" {
+		t.Errorf("expected a move to space 2 main, got %+v", result)
+	}
+	if frame, ok := fs.frame(100); !ok || frame.X < right.X {
+		t.Errorf("expected window 100 placed on the right display, got %+v", frame)
+	}
+}
+
+func TestStackStep(t *testing.T) {
+	tests := []struct {
+		mode   types.StackMode
+		dir    types.Direction
+		idx, n int
+		want   int
+		ok     bool
+	}{
+		{types.StackVertical, types.DirDown, 0, 2, 1, true},
+		{types.StackVertical, types.DirDown, 1, 2, 0, false},
+		{types.StackVertical, types.DirUp, 1, 2, 0, true},
+		{types.StackVertical, types.DirUp, 0, 2, 0, false},
+		{types.StackVertical, types.DirLeft, 1, 2, 0, false},
+		{types.StackHorizontal, types.DirRight, 0, 3, 1, true},
+		{types.StackHorizontal, types.DirLeft, 2, 3, 1, true},
+		{types.StackHorizontal, types.DirDown, 0, 3, 0, false},
+		{types.StackTabs, types.DirDown, 0, 2, 0, false},
+		{types.StackVertical, types.DirDownRight, 0, 2, 0, false},
+		// Grid of 5: rows [0 1 2] [3 4]
+		{types.StackGrid, types.DirRight, 0, 5, 1, true},
+		{types.StackGrid, types.DirRight, 2, 5, 0, false},
+		{types.StackGrid, types.DirRight, 4, 5, 0, false},
+		{types.StackGrid, types.DirLeft, 3, 5, 0, false},
+		{types.StackGrid, types.DirDown, 1, 5, 4, true},
+		{types.StackGrid, types.DirDown, 2, 5, 4, true},
+		{types.StackGrid, types.DirDown, 4, 5, 0, false},
+		{types.StackGrid, types.DirUp, 4, 5, 1, true},
+		{types.StackGrid, types.DirUp, 1, 5, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := StackStep(tt.mode, tt.dir, tt.idx, tt.n)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("StackStep(%s, %s, %d, %d) = %d, %v; want %d, %v",
+				tt.mode, tt.dir, tt.idx, tt.n, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func stackedCellFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		AllDisplays: []server.DisplayInfo{
+			{UUID: "main", CurrentSpaceID: 1, Frame: bounds, VisibleFrame: bounds},
+		},
+	}
+	cfg := &config.Config{
+		Settings: config.Settings{DefaultStackMode: types.StackVertical},
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "rows",
+				Grid:  config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr", "1fr"}},
+				Areas: [][]string{{"top"}, {"bottom"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("rows", 0)
+	space.AssignWindow(100, "top")
+	space.AssignWindow(101, "top")
+	return snap, cfg, rs
+}
+
+func TestPlaceCells_BSPSpace(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	space := rs.GetSpace("1")
+	space.BSP = true
+	c, fs := startFakeServer(t)
+
+	if _, err := placeCells(context.Background(), c, snap, cfg, space, []string{"top"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The wide top cell is halved side by side, not stacked
+	first, _ := fs.frame(100)
+	second, ok := fs.frame(101)
+	if !ok || second.X <= first.X || second.Y != first.Y {
+		t.Errorf("expected 100 and 101 side by side, got %+v and %+v", first, second)
+	}
+}
+
+func TestPlaceCells_UsesCellSpans(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	space := rs.GetSpace("1")
+	space.AddSpan("top", "bottom")
+	c, fs := startFakeServer(t)
+
+	if _, err := placeCells(context.Background(), c, snap, cfg, space, []string{"top"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The top cell covers the empty bottom row it absorbed
+	second, ok := fs.frame(101)
+	if !ok || second.Y+second.Height <= snap.DisplayBounds.Height/2 {
+		t.Errorf("expected window 101 to reach into the absorbed bottom row, got %+v", second)
+	}
+}
+
+func TestPlaceCells_SnapsToSizeIncrements(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 100, AppName: "Terminal"}, {ID: 101, AppName: "Safari"}}
+	cfg.AppRules = []config.AppRule{{App: "Terminal", SizeIncrement: []float64{0, 100}}}
+	c, fs := startFakeServer(t)
+
+	if _, err := placeCells(context.Background(), c, snap, cfg, rs.GetSpace("1"), []string{"top"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The terminal snaps to whole rows and the window below takes the rest
+	terminal, _ := fs.frame(100)
+	below, ok := fs.frame(101)
+	if terminal.Height != 200 {
+		t.Errorf("expected the terminal snapped to 200px, got %+v", terminal)
+	}
+	if !ok || below.Y != terminal.Y+terminal.Height+4 {
+		t.Errorf("expected window 101 to start below the snapped terminal, got %+v", below)
+	}
+}
+
+func TestMoveWindow_ReordersWithinStack(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, fs := startFakeServer(t)
+
+	// Top window moving down stays in its cell and swaps with the one below
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Reordered || result.StackIndex != 1 || result.TargetCell != "top" {
+		t.Errorf("expected a reorder to index 1 of top, got %+v", result)
+	}
+	moved, _ := fs.frame(100)
+	other, _ := fs.frame(101)
+	if moved.Y <= other.Y {
+		t.Errorf("expected window 100 placed below 101, got %+v and %+v", moved, other)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	top := space.Cells["top"].Windows
+	if len(top) != 2 || top[0] != 101 || top[1] != 100 {
+		t.Fatalf("expected top cell [101 100], got %v", top)
+	}
+	if space.GetWindowCell(100) != "top" {
+		t.Errorf("window 100 should stay in top, got %q", space.GetWindowCell(100))
+	}
+}
+
+func TestMoveWindow_CrossesCellAtStackEdge(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, fs := startFakeServer(t)
+
+	// Bottom window of the stack has nowhere further down in its cell
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 101})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Reordered || result.SourceCell != "top" || result.TargetCell != "bottom" {
+		t.Errorf("expected a move from top to bottom, got %+v", result)
+	}
+	if !fs.Called("updateWindow") && !fs.Called(client.BatchUpdateMethod) {
+		t.Errorf("expected the move to be placed, got %v", fs.Methods())
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if space.GetWindowCell(101) != "bottom" {
+		t.Errorf("window 101 should cross to bottom, got %q", space.GetWindowCell(101))
+	}
+	if top := space.Cells["top"].Windows; len(top) != 1 || top[0] != 100 {
+		t.Errorf("expected top cell [100], got %v", top)
+	}
+}
+
+// fakeServer answers every request with an empty result (or a canned one
+// from results, keyed by method) and records the requests it was sent.
+type fakeServer struct {
+	*testutil.Server
+}
+
+func startFakeServer(t *testing.T) (*client.Client, *fakeServer) {
+	t.Helper()
+	return startFakeServerWithResults(t, nil)
+}
+
+func startFakeServerWithResults(t *testing.T, results map[string]map[string]interface{}) (*client.Client, *fakeServer) {
+	t.Helper()
+	fs := &fakeServer{&testutil.Server{
+		Handle: func(req *models.Request) (map[string]interface{}, *models.ErrorInfo) {
+			return results[req.Method], nil
+		},
+	}}
+	c := client.NewClient(fs.Start(t), 0)
+	t.Cleanup(func() { c.Close() })
+	return c, fs
+}
+
+// frame returns the last frame set on a window through updateWindow or
+// window.batchUpdate
+func (fs *fakeServer) frame(windowID uint32) (types.Rect, bool) {
+	updates := fs.WindowUpdates()
+	for i := len(updates) - 1; i >= 0; i-- {
+		p := updates[i]
+		if p["windowId"] != float64(windowID) {
+			continue
+		}
+		if _, ok := p["width"]; !ok {
+			continue
+		}
+		return types.Rect{
+			X:      p["x"].(float64),
+			Y:      p["y"].(float64),
+			Width:  p["width"].(float64),
+			Height: p["height"].(float64),
+		}, true
+	}
+	return types.Rect{}, false
+}
+
+// inactiveTargetFixture has one display showing space 1 and a hidden space 3
+// with a layout, reachable only through --target-space.
+func inactiveTargetFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	snap, cfg, rs := singleCellFixture()
+	rs.GetSpace("3").SetCurrentLayout("full", 0)
+	return snap, cfg, rs
+}
+
+func TestMoveWindow_InactiveTargetSpaceNote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := inactiveTargetFixture()
+	c, fs := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, TargetSpace: "3"}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.TargetInactive || result.Followed {
+		t.Errorf("expected inactive target without follow, got %+v", result)
+	}
+	if fs.Called("space.focus") {
+		t.Error("space should not be switched without --follow")
+	}
+	note := InactiveSpaceNote(result)
+	if !strings.Contains(note, "inactive space 3") || !strings.Contains(note, "display main") {
+		t.Errorf("unexpected note: %q", note)
+	}
+}
+
+func TestMoveWindow_FollowSwitchesToTargetSpace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := inactiveTargetFixture()
+	c, fs := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, TargetSpace: "3", Follow: true}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Followed {
+		t.Errorf("expected move to follow into space 3, got %+v", result)
+	}
+	if !fs.Called("space.focus") {
+		t.Error("expected space.focus to be called")
+	}
+	if note := InactiveSpaceNote(result); note != "" {
+		t.Errorf("expected no note after following, got %q", note)
+	}
+}
+
+func TestInactiveSpaceNote_ActiveTarget(t *testing.T) {
+	result := &MoveResult{WindowID: 1, CrossDisplay: true, TargetSpace: "2", TargetDisplay: "right"}
+	if note := InactiveSpaceNote(result); note != "" {
+		t.Errorf("expected no note for an active target space, got %q", note)
+	}
+}
+
+func TestMoveWindow_CrossDisplayFailedRPCLeavesState(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := singleCellFixture()
+	right := types.Rect{X: 1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays, server.DisplayInfo{
+		UUID: "right", CurrentSpaceID: 2, Frame: right, VisibleFrame: right,
+	})
+	rs.GetSpace("2").SetCurrentLayout("full", 0)
+	rs.GetSpace("2").AssignWindow(200, "main")
+	before := rs.SnapshotSpaces("1", "2")
+
+	c := client.NewClient(filepath.Join(t.TempDir(), "missing.sock"), 0)
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, Extend: true})
+	if err == nil || !strings.Contains(err.Error(), "failed to move window to space 2") {
+		t.Fatalf("expected failed space move, got %v", err)
+	}
+
+	for id, want := range before {
+		got := rs.GetSpaceReadOnly(id)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("space %s changed by failed move: got %+v, want %+v", id, got, want)
+		}
+	}
+}
+
+// rightDisplayFixture adds a second display to the right showing space 2
+func rightDisplayFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	snap, cfg, rs := singleCellFixture()
+	right := types.Rect{X: 1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays, server.DisplayInfo{
+		UUID: "right", CurrentSpaceID: 2, Frame: right, VisibleFrame: right,
+	})
+	rs.GetSpace("2").SetCurrentLayout("full", 0)
+	return snap, cfg, rs
+}
+
+func dumpWithDisplays(uuids ...string) map[string]map[string]interface{} {
+	var displays []interface{}
+	for _, uuid := range uuids {
+		displays = append(displays, map[string]interface{}{"uuid": uuid})
+	}
+	return map[string]map[string]interface{}{
+		"dump": {"displays": displays},
+	}
+}
+
+func TestMoveWindow_CrossDisplayAbortsWhenTargetDisconnected(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := rightDisplayFixture()
+	before := rs.SnapshotSpaces("1", "2")
+
+	// The snapshot still lists "right", but the server no longer does
+	c, fs := startFakeServerWithResults(t, dumpWithDisplays("main"))
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, Extend: true})
+	if err == nil || !strings.Contains(err.Error(), "target display right is no longer connected") {
+		t.Fatalf("expected disconnected display error, got %v", err)
+	}
+
+	if fs.Called("updateWindow") {
+		t.Error("window should not be moved to a disconnected display")
+	}
+	for id, want := range before {
+		got := rs.GetSpaceReadOnly(id)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("space %s changed by aborted move: got %+v, want %+v", id, got, want)
+		}
+	}
+}
+
+func TestMoveWindow_CrossDisplayTargetStillConnected(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := rightDisplayFixture()
+
+	c, fs := startFakeServerWithResults(t, dumpWithDisplays("main", "right"))
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, Extend: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.CrossDisplay || result.TargetDisplay != "right" {
+		t.Errorf("expected cross-display move to right, got %+v", result)
+	}
+	if !fs.Called("dump") || !fs.Called("updateWindow") {
+		t.Error("expected the display re-check followed by the move")
+	}
+	if rs.GetSpaceReadOnly("2").GetWindowCell(100) != "main" {
+		t.Error("window 100 should be in space 2 after the move")
+	}
+}
+
+func TestMoveWindow_CrossDisplayFailedRPCLeavesAutoLayoutUnset(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := singleCellFixture()
+	cfg.Spaces = map[string]config.SpaceConfig{"3": {DefaultLayout: "full"}}
+
+	// The fallback space's default layout is only set once the RPC succeeds
+	c := client.NewClient(filepath.Join(t.TempDir(), "missing.sock"), 0)
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, TargetSpace: "3", AutoLayout: true}
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err == nil || !strings.Contains(err.Error(), "failed to move window to space 3") {
+		t.Fatalf("expected failed space move, got %v", err)
+	}
+
+	if space := rs.GetSpaceReadOnly("3"); space != nil {
+		t.Errorf("expected no layout set on space 3, got %+v", space)
+	}
+	if rs.GetSpaceReadOnly("1").GetWindowCell(100) != "main" {
+		t.Error("window 100 should still be in its source cell")
+	}
+}
+
+func TestMoveWindow_CrossDisplayCommitsAutoLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := singleCellFixture()
+	cfg.Spaces = map[string]config.SpaceConfig{"3": {DefaultLayout: "full"}}
+	c, _ := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, TargetSpace: "3", AutoLayout: true}
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	space := rs.GetSpaceReadOnly("3")
+	if space == nil || space.CurrentLayoutID != "full" || space.GetWindowCell(100) != "main" {
+		t.Errorf("expected window 100 in main under the full layout on space 3, got %+v", space)
+	}
+}
+
+func TestMoveWindow_CrossDisplayRecordsHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := inactiveTargetFixture()
+	c, _ := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, TargetSpace: "3"}
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// The move is recorded on the space the window left
+	history := rs.GetSpaceReadOnly("1").MoveHistory
+	if len(history) != 1 {
+		t.Fatalf("expected 1 history entry on space 1, got %+v", history)
+	}
+	if e := history[0]; e.WindowID != 100 || e.SourceCell != "main" || e.TargetSpace != "3" || e.TargetCell != "main" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if rs.GetSpaceReadOnly("3").GetWindowCell(100) != "main" {
+		t.Error("window 100 should be in space 3 after the move")
+	}
+}
+
+func TestMoveWindow_KeepRelative(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "cols",
+				Grid:  config.GridConfig{Columns: []string{"2fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"left", "right"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("cols", 0)
+	space.AssignWindow(1, "left")
+	space.AssignWindow(2, "left")
+	space.Cells["left"].SplitRatios = []float64{0.6, 0.4}
+	space.AssignWindow(3, "right")
+	space.AssignWindow(4, "right")
+
+	c, fs := startFakeServer(t)
+	opts := MoveWindowOpts{WindowID: 1, KeepRelative: true}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetCell != "right" {
+		t.Errorf("target cell = %s, want right", result.TargetCell)
+	}
+	// Window 1 keeps the 0.6 share it had of its old cell
+	if frame, ok := fs.frame(1); !ok || math.Abs(frame.Height-0.6*1080) > 10 {
+		t.Errorf("expected window 1 placed at 60%% of the cell height, got %+v", frame)
+	}
+
+	right := space.Cells["right"]
+	if len(right.Windows) != 3 || right.Windows[0] != 1 {
+		t.Fatalf("expected window 1 on top of right cell, got %v", right.Windows)
+	}
+	want := []float64{0.6, 0.2, 0.2}
+	for i, r := range want {
+		if math.Abs(right.SplitRatios[i]-r) > 1e-9 {
+			t.Fatalf("expected right cell ratios %v, got %v", want, right.SplitRatios)
+		}
+	}
+}
+
+// unequalTargetFixture has window 1 alone in the left cell and windows 3
+// and 4 in the right cell at 0.75/0.25
+func unequalTargetFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080},
+	}
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "cols",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"left", "right"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("cols", 0)
+	space.AssignWindow(1, "left")
+	space.AssignWindow(3, "right")
+	space.AssignWindow(4, "right")
+	space.Cells["right"].SplitRatios = []float64{0.75, 0.25}
+	space.Cells["right"].RatiosUserSet = true
+	return snap, cfg, rs
+}
+
+func TestMoveWindow_BalancesTargetByDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := unequalTargetFixture()
+
+	c, fs := startFakeServer(t)
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetCell != "right" {
+		t.Errorf("target cell = %s, want right", result.TargetCell)
+	}
+	if frame, ok := fs.frame(3); !ok || math.Abs(frame.Height-1080.0/3) > 10 {
+		t.Errorf("expected window 3 placed at a third of the cell height, got %+v", frame)
+	}
+
+	right := rs.GetSpaceReadOnly("1").Cells["right"]
+	if len(right.Windows) != 3 || right.Windows[0] != 1 {
+		t.Fatalf("expected window 1 on top of right cell, got %v", right.Windows)
+	}
+	for _, r := range right.SplitRatios {
+		if math.Abs(r-1.0/3) > 1e-9 {
+			t.Fatalf("expected equal ratios, got %v", right.SplitRatios)
+		}
+	}
+}
+
+func TestMoveWindow_KeepTargetRatios(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := unequalTargetFixture()
+
+	c, fs := startFakeServer(t)
+	opts := MoveWindowOpts{WindowID: 1, KeepTargetRatios: true}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetCell != "right" {
+		t.Errorf("target cell = %s, want right", result.TargetCell)
+	}
+	if frame, ok := fs.frame(3); !ok || math.Abs(frame.Height-1080.0/2) > 10 {
+		t.Errorf("expected window 3 placed at half the cell height, got %+v", frame)
+	}
+
+	right := rs.GetSpaceReadOnly("1").Cells["right"]
+	if len(right.Windows) != 3 || right.Windows[0] != 1 {
+		t.Fatalf("expected window 1 on top of right cell, got %v", right.Windows)
+	}
+	// 1/3 for the newcomer; 3 and 4 keep 3:1 of the remaining 2/3
+	want := []float64{1.0 / 3, 0.5, 1.0 / 6}
+	for i, r := range want {
+		if math.Abs(right.SplitRatios[i]-r) > 1e-9 {
+			t.Fatalf("expected right cell ratios %v, got %v", want, right.SplitRatios)
+		}
+	}
+	if !right.RatiosUserSet {
+		t.Error("kept ratios should stay user set")
+	}
+}
+
+// stickyWindowFixture is stackedCellFixture with window 100 reported on
+// every space, as the server does for sticky windows.
+func stickyWindowFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{
+		{ID: 100, AppName: "Notes", SpaceCount: 3},
+		{ID: 101, AppName: "Safari", SpaceCount: 1},
+	}
+	snap.WindowIDs = map[uint32]bool{100: true, 101: true}
+	return snap, cfg, rs
+}
+
+func TestMoveWindow_SkipsStickyWindow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stickyWindowFixture()
+	c, fs := startFakeServerWithResults(t, map[string]map[string]interface{}{
+		"window.isSticky": {"sticky": true},
+	})
+
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 100})
+	if err == nil || !strings.Contains(err.Error(), "sticky") {
+		t.Fatalf("expected sticky error, got %v", err)
+	}
+	if fs.Called("window.setSticky") {
+		t.Error("sticky window should not be un-stuck by default")
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(100); cell != "top" {
+		t.Errorf("window 100 should stay in top, got %q", cell)
+	}
+}
+
+func TestMoveWindow_UnsticksWhenTileStickyWindows(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stickyWindowFixture()
+	cfg.Settings.TileStickyWindows = true
+	c, fs := startFakeServerWithResults(t, map[string]map[string]interface{}{
+		"window.isSticky": {"sticky": true},
+	})
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if !fs.Called("window.setSticky") {
+		t.Error("expected window to be un-stuck before moving")
+	}
+}
+
+// autoExpandFixture has a two-column "half" layout with two windows stacked in
+// each cell, a three-column "thirds" layout after it in the cycle, and a
+// minimum window height a third stacked window would fall below.
+func autoExpandFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		AllDisplays: []server.DisplayInfo{
+			{UUID: "main", CurrentSpaceID: 1, Frame: bounds, VisibleFrame: bounds},
+		},
+		Windows: []server.WindowInfo{
+			{ID: 100, AppName: "Notes"},
+			{ID: 101, AppName: "Safari"},
+			{ID: 102, AppName: "Mail"},
+			{ID: 103, AppName: "Music"},
+		},
+		WindowIDs: map[uint32]bool{100: true, 101: true, 102: true, 103: true},
+	}
+	cfg := &config.Config{
+		Settings: config.Settings{DefaultStackMode: types.StackVertical, MinWindowHeight: 400},
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "half",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"a", "b"}},
+			},
+			{
+				ID:    "thirds",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"a", "b", "c"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("half", 0)
+	space.AssignWindow(100, "a")
+	space.AssignWindow(103, "a")
+	space.AssignWindow(101, "b")
+	space.AssignWindow(102, "b")
+	return snap, cfg, rs
+}
+
+func TestMoveWindow_AutoExpandSwitchesToLargerLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := autoExpandFixture()
+	c, _ := startFakeServer(t)
+
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, AutoExpand: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpandedLayout != "thirds" {
+		t.Fatalf("expected switch to thirds, got %q", result.ExpandedLayout)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if space.CurrentLayoutID != "thirds" {
+		t.Errorf("space layout = %q, want thirds", space.CurrentLayoutID)
+	}
+	// Other windows keep their cells; the moved window takes the new one
+	want := map[uint32]string{100: "c", 101: "b", 102: "b", 103: "a"}
+	for wid, cell := range want {
+		if got := space.GetWindowCell(wid); got != cell {
+			t.Errorf("window %d in %q, want %q", wid, got, cell)
+		}
+	}
+	if result.TargetCell != "c" {
+		t.Errorf("result target cell = %q, want c", result.TargetCell)
+	}
+}
+
+func TestMoveWindow_AutoExpandRejectsSplit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := autoExpandFixture()
+	c, fs := startFakeServer(t)
+
+	_, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, AutoExpand: true, Split: types.StackHorizontal})
+	if err == nil || !strings.Contains(err.Error(), "--auto-expand") {
+		t.Fatalf("expected --split with --auto-expand to be rejected, got %v", err)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(100); got != "a" {
+		t.Errorf("window 100 moved to %q despite the error", got)
+	}
+	if fs.Called("updateWindow") || fs.Called(client.BatchUpdateMethod) {
+		t.Error("expected no window updates")
+	}
+}
+
+func TestMoveWindow_AutoExpandStacksWhenRoomLeft(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := autoExpandFixture()
+	rs.GetSpace("1").RemoveWindow(102)
+	c, _ := startFakeServer(t)
+
+	// Two stacked windows still clear the minimum height
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100, AutoExpand: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpandedLayout != "" {
+		t.Errorf("expected no layout switch, got %q", result.ExpandedLayout)
+	}
+	space := rs.GetSpaceReadOnly("1")
+	if space.CurrentLayoutID != "half" || space.GetWindowCell(100) != "b" {
+		t.Errorf("expected window 100 stacked in b on half, got %q on %q", space.GetWindowCell(100), space.CurrentLayoutID)
+	}
+}
+
+func TestMoveWindow_WithoutAutoExpandOverstacks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := autoExpandFixture()
+	c, _ := startFakeServer(t)
+
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpandedLayout != "" || rs.GetSpaceReadOnly("1").GetWindowCell(100) != "b" {
+		t.Errorf("expected a plain move into b, got %+v", result)
+	}
+}
+
+func TestMoveWindow_SplitSetsTargetStackMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("1").AssignWindow(102, "bottom")
+	c, fs := startFakeServer(t)
+
+	// Moving down along the vertical stack would reorder; a split leaves the cell
+	opts := MoveWindowOpts{WindowID: 100, Split: types.StackHorizontal}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Reordered || result.TargetCell != "bottom" {
+		t.Fatalf("expected a move into bottom, got %+v", result)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if cell := space.GetWindowCell(100); cell != "bottom" {
+		t.Errorf("window 100 in %q, want bottom", cell)
+	}
+	if mode := space.Cells["bottom"].StackMode; mode != types.StackHorizontal {
+		t.Errorf("bottom stack mode = %q, want horizontal", mode)
+	}
+
+	// The incoming arrangement is side by side
+	moved, _ := fs.frame(100)
+	other, _ := fs.frame(102)
+	if moved.Y != other.Y || moved.Width >= 1920/2+1 {
+		t.Errorf("expected side-by-side frames, got %+v and %+v", moved, other)
+	}
+}
+
+func TestMoveWindow_SplitCrossDisplay(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	right := types.Rect{X: 1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays, server.DisplayInfo{
+		UUID: "right", CurrentSpaceID: 2, Frame: right, VisibleFrame: right,
+	})
+	rs.GetSpace("2").SetCurrentLayout("rows", 0)
+	c, _ := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, Split: types.StackHorizontal}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := rs.GetCellStackMode("2", result.TargetCell); mode != types.StackHorizontal {
+		t.Errorf("target cell %s stack mode = %q, want horizontal", result.TargetCell, mode)
+	}
+}
+
+func TestMoveWindow_InvalidSplit(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+
+	_, err := MoveWindow(context.Background(), nil, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 100, Split: types.StackTabs})
+	if err == nil || !strings.Contains(err.Error(), "invalid split") {
+		t.Fatalf("expected invalid split error, got %v", err)
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(100); cell != "top" {
+		t.Errorf("window 100 should stay in top, got %q", cell)
+	}
+}
+
+func TestThrowWindow_ResolvesDisplayIndex(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	left := types.Rect{X: -1920, Y: 0, Width: 1920, Height: 1080}
+	far := types.Rect{X: 3840, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays,
+		server.DisplayInfo{UUID: "left", CurrentSpaceID: 3, Frame: left, VisibleFrame: left},
+		server.DisplayInfo{UUID: "far", CurrentSpaceID: 2, Frame: far, VisibleFrame: far},
+	)
+	rs.GetSpace("2").SetCurrentLayout("rows", 0)
+	c, fs := startFakeServer(t)
+
+	// Display 2 isn't adjacent; throwing goes straight there
+	result, err := ThrowWindow(context.Background(), c, snap, cfg, rs, "2", MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetDisplay != "far" || result.TargetSpace != "2" {
+		t.Errorf("expected display far / space 2, got %s / %s", result.TargetDisplay, result.TargetSpace)
+	}
+	// The window keeps its visual position: top cell on the target display
+	if result.TargetCell != "top" {
+		t.Errorf("target cell = %s, want top", result.TargetCell)
+	}
+	if got := rs.GetSpaceReadOnly("2").GetWindowCell(100); got != "top" {
+		t.Errorf("window tracked in %q on space 2, want top", got)
+	}
+	got, ok := fs.frame(100)
+	if want := (types.Rect{X: 3840, Y: 0, Width: 1920, Height: 540}); !ok || got != want {
+		t.Errorf("window 100 frame = %+v (set: %v), want %+v", got, ok, want)
+	}
+}
+
+func TestThrowWindow_ResolvesDisplayUUID(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	left := types.Rect{X: -1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays,
+		server.DisplayInfo{UUID: "left", CurrentSpaceID: 3, Frame: left, VisibleFrame: left},
+	)
+	rs.GetSpace("3").SetCurrentLayout("rows", 0)
+	c, _ := startFakeServer(t)
+
+	result, err := ThrowWindow(context.Background(), c, snap, cfg, rs, "left", MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetDisplay != "left" || result.TargetSpace != "3" {
+		t.Errorf("expected display left / space 3, got %s / %s", result.TargetDisplay, result.TargetSpace)
+	}
+}
+
+func TestThrowWindow_Errors(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	c := client.NewClient(filepath.Join(t.TempDir(), "missing.sock"), 0)
+
+	if _, err := ThrowWindow(context.Background(), c, snap, cfg, rs, "0", MoveWindowOpts{WindowID: 100}); err == nil {
+		t.Error("expected error throwing to the current display")
+	}
+	if _, err := ThrowWindow(context.Background(), c, snap, cfg, rs, "5", MoveWindowOpts{WindowID: 100}); err == nil {
+		t.Error("expected error for an out-of-range display index")
+	}
+	if _, err := ThrowWindow(context.Background(), c, snap, cfg, rs, "missing", MoveWindowOpts{WindowID: 100}); err == nil {
+		t.Error("expected error for an unknown display UUID")
+	}
+}
+
+func TestMoveWindow_JumpsOverSkipNavigationCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, _, rs := stackedCellFixture()
+	cfg := &config.Config{
+		Settings: config.Settings{DefaultStackMode: types.StackVertical},
+		Layouts: []config.LayoutConfig{
+			{
+				ID:   "three",
+				Grid: config.GridConfig{Columns: []string{"1fr", "1fr", "1fr"}, Rows: []string{"1fr"}},
+				Cells: []config.CellConfig{
+					{ID: "left", Column: "1/2", Row: "1/2"},
+					{ID: "video", Column: "2/3", Row: "1/2", SkipNavigation: true},
+					{ID: "right", Column: "3/4", Row: "1/2"},
+				},
+			},
+		},
+	}
+	rs = state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("three", 0)
+	space.AssignWindow(100, "left")
+	space.AssignWindow(101, "video")
+	c, _ := startFakeServer(t)
+
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetCell != "right" {
+		t.Errorf("target cell = %s, want right (jumping over video)", result.TargetCell)
+	}
+}
+
+// lastFocused returns the window of the last window.focus request
+func (fs *fakeServer) lastFocused() uint32 {
+	requests := fs.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Method == "window.focus" {
+			id, _ := requests[i].Params["windowId"].(float64)
+			return uint32(id)
+		}
+	}
+	return 0
+}
+
+func TestMoveWindow_FocusFollowsMoveSetting(t *testing.T) {
+	off, on := false, true
+	// Keeping focus sends no focus request at all (serverFocus 0)
+	tests := []struct {
+		name        string
+		setting     *bool
+		override    *bool
+		want        uint32
+		serverFocus uint32
+	}{
+		{"default follows", nil, nil, 101, 101},
+		{"setting keeps focus", &off, nil, 100, 0},
+		{"then-focus overrides setting", &off, &on, 101, 101},
+		{"no-focus overrides default", nil, &off, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			snap, cfg, rs := stackedCellFixture()
+			cfg.Settings.FocusFollowsMove = tt.setting
+			rs.GetSpace("1").SetFocusedWindow(100)
+			c, fs := startFakeServer(t)
+
+			// Move the unfocused window 101 down into the bottom cell
+			opts := MoveWindowOpts{WindowID: 101, FocusFollow: tt.override}
+			if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := rs.GetSpaceReadOnly("1").GetFocusedWindow(); got != tt.want {
+				t.Errorf("focused window in state = %d, want %d", got, tt.want)
+			}
+			if got := fs.lastFocused(); got != tt.serverFocus {
+				t.Errorf("last focused on the server = %d, want %d", got, tt.serverFocus)
+			}
+		})
+	}
+}
+
+func TestMoveWindow_ReadOnlyUsesWindowPositions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	state.SetReadOnly(true)
+	defer state.SetReadOnly(false)
+
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{
+		SpaceID:       "1",
+		DisplayBounds: bounds,
+		Windows: []server.WindowInfo{
+			{ID: 100, Frame: types.Rect{Width: 960, Height: 1080}},
+			{ID: 101, Frame: types.Rect{X: 960, Width: 960, Height: 1080}},
+		},
+		WindowIDs:       map[uint32]bool{100: true, 101: true},
+		FocusedWindowID: 100,
+		AllDisplays:     []server.DisplayInfo{{UUID: "main", CurrentSpaceID: 1, Frame: bounds, VisibleFrame: bounds}},
+	}
+	cfg := &config.Config{
+		Settings: config.Settings{DefaultStackMode: types.StackVertical, DefaultLayout: "cols"},
+		Layouts: []config.LayoutConfig{{
+			ID:    "cols",
+			Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+			Areas: [][]string{{"left", "right"}},
+		}},
+	}
+	rs, err := state.LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := layout.AdoptWindowPositions(snap, cfg, rs); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := startFakeServer(t)
+
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SourceCell != "left" || result.TargetCell != "right" {
+		t.Errorf("expected left -> right, got %s -> %s", result.SourceCell, result.TargetCell)
+	}
+	if _, err := os.Stat(state.GetStatePath()); !os.IsNotExist(err) {
+		t.Errorf("expected no state file, got %v", err)
+	}
+}
+
+func TestNthAppWindow(t *testing.T) {
+	snap := &server.Snapshot{
+		SpaceID: "1",
+		Windows: []server.WindowInfo{
+			{ID: 30, AppName: "Safari", BundleID: "com.apple.Safari"},
+			{ID: 12, AppName: "Safari", BundleID: "com.apple.Safari"},
+			{ID: 20, AppName: "Notes"},
+			{ID: 25, AppName: "Safari", BundleID: "com.apple.Safari"},
+		},
+	}
+
+	for _, tt := range []struct {
+		app  string
+		n    int
+		want uint32
+	}{
+		{"Safari", 1, 12},
+		{"safari", 2, 25},
+		{"com.apple.Safari", 3, 30},
+		{"Notes", 1, 20},
+	} {
+		got, err := NthAppWindow(snap, tt.app, tt.n)
+		if err != nil || got != tt.want {
+			t.Errorf("NthAppWindow(%q, %d) = %d, %v; want %d", tt.app, tt.n, got, err, tt.want)
+		}
+	}
+
+	if _, err := NthAppWindow(snap, "Safari", 4); err == nil || !strings.Contains(err.Error(), "has 3") {
+		t.Errorf("expected an out-of-range error naming the count, got %v", err)
+	}
+	if _, err := NthAppWindow(snap, "Mail", 1); err == nil {
+		t.Error("expected an error for an app with no windows")
+	}
+	if _, err := NthAppWindow(snap, "Safari", 0); err == nil {
+		t.Error("expected an error for index 0")
+	}
+}
+
+func TestMoveWindow_NthAppWindow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 100, AppName: "Safari"}, {ID: 101, AppName: "Safari"}}
+	rs.GetSpace("1").SetFocus("top", 0) // window 100 has focus
+	c, _ := startFakeServer(t)
+
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{App: "safari", Nth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WindowID != 101 || result.TargetCell != "bottom" {
+		t.Errorf("expected window 101 moved to bottom, got %d to %s", result.WindowID, result.TargetCell)
+	}
+
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{App: "Safari", Nth: 3}); err == nil {
+		t.Error("expected an error with only two Safari windows")
+	}
+	if _, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{App: "Safari", Nth: 1, WindowID: 100}); err == nil {
+		t.Error("expected an error combining --app with a window ID")
+	}
+}
+
+func TestOverstacks_UsesCellModeAndWindowPadding(t *testing.T) {
+	_, cfg, rs := autoExpandFixture()
+	space := rs.GetSpaceReadOnly("1")
+	layoutDef, err := cfg.GetLayout("half")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cell := types.Rect{X: 0, Y: 0, Width: 960, Height: 1080}
+
+	// With placeCells' padding, three stacked windows are 357.3 tall each
+	cfg.Settings.MinWindowHeight = 357
+	if overstacks(cfg, layoutDef, space, cell, "b", 1, cell) {
+		t.Error("expected three windows to fit at the minimum")
+	}
+	cfg.Settings.MinWindowHeight = 358
+	if !overstacks(cfg, layoutDef, space, cell, "b", 1, cell) {
+		t.Error("expected the padding to squeeze the windows below the minimum")
+	}
+	// The gap between cells doesn't apply within one
+	cfg.Settings.MinWindowHeight = 357
+	cfg.Settings.CellPadding = 40
+	if overstacks(cfg, layoutDef, space, cell, "b", 1, cell) {
+		t.Error("expected settings.cellPadding to be ignored")
+	}
+
+	// A cell the layout tabs never overstacks
+	layoutDef.CellModes = map[string]types.StackMode{"b": types.StackTabs}
+	if overstacks(cfg, layoutDef, space, cell, "b", 1, cell) {
+		t.Error("expected the layout's tabs mode for b to be used")
+	}
+}
@@ -0,0 +1,59 @@
+package window
+
+import (
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+)
+
+// PinWindow pins a window on the snapshot's space to a cell of the space's
+// current layout. The pin is honored on every apply and reapply until the
+// window is unpinned or closes.
+func PinWindow(snap *server.Snapshot, cfg *config.Config, rs *state.RuntimeState, windowID uint32, cellID string) error {
+	space := rs.GetSpaceReadOnly(snap.SpaceID)
+	if space == nil || space.CurrentLayoutID == "" {
+		return fmt.Errorf("no layout applied on space %s", snap.SpaceID)
+	}
+
+	found := false
+	for _, w := range snap.Windows {
+		if w.ID == windowID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("window %d is not on space %s", windowID, snap.SpaceID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, space)
+	if err != nil {
+		return fmt.Errorf("layout not found: %w", err)
+	}
+	found = false
+	for _, cell := range layoutDef.Cells {
+		if cell.ID == cellID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("cell %s not found in layout %s", cellID, space.CurrentLayoutID)
+	}
+
+	rs.GetSpace(snap.SpaceID).PinWindow(windowID, cellID)
+	return nil
+}
+
+// UnpinWindow removes a window's pin on a space
+func UnpinWindow(rs *state.RuntimeState, spaceID string, windowID uint32) error {
+	space := rs.GetSpaceReadOnly(spaceID)
+	if space == nil || space.PinnedWindows[windowID] == "" {
+		return fmt.Errorf("window %d is not pinned on space %s", windowID, spaceID)
+	}
+	rs.GetSpace(spaceID).UnpinWindow(windowID)
+	return nil
+}
@@ -0,0 +1,37 @@
+package window
+
+import (
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/server"
+)
+
+func TestPinWindow_Validates(t *testing.T) {
+	snap, cfg, rs := stackedCellFixture()
+	snap.Windows = []server.WindowInfo{{ID: 100}, {ID: 101}}
+
+	if err := PinWindow(snap, cfg, rs, 100, "missing"); err == nil {
+		t.Error("expected error for a cell not in the space's layout")
+	}
+	if err := PinWindow(snap, cfg, rs, 999, "bottom"); err == nil {
+		t.Error("expected error for a window not on the space")
+	}
+	if err := PinWindow(snap, cfg, rs, 100, "bottom"); err != nil {
+		t.Fatal(err)
+	}
+	if got := rs.GetSpaceReadOnly("1").PinnedWindows[100]; got != "bottom" {
+		t.Errorf("window pinned to %q, want bottom", got)
+	}
+}
+
+func TestUnpinWindow(t *testing.T) {
+	_, _, rs := stackedCellFixture()
+	rs.GetSpace("1").PinWindow(100, "top")
+
+	if err := UnpinWindow(rs, "1", 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnpinWindow(rs, "1", 100); err == nil {
+		t.Error("expected error unpinning a window that isn't pinned")
+	}
+}
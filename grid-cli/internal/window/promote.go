@@ -0,0 +1,167 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// PromoteWindow moves a window (0 = the focused one) to the top of the
+// layout's main cell (see layout.MainCell), swapping the window there into
+// the cell it left. Only the two cells are re-placed. A designated master
+// window that gets displaced hands its role to the promoted window.
+//
+// Nothing changes if the layout has a single cell or the window is already
+// on top of the main cell; the result's Unchanged then says which.
+func PromoteWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+) (*MoveResult, error) {
+	spaceState, windowID, sourceCell, cellBounds, err := promoteContext(snap, cfg, rs, windowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cellBounds) == 1 {
+		return unchanged(snap, windowID, sourceCell, "the layout has only one cell"), nil
+	}
+	mainCell := layout.MainCell(cfg.Settings.MainCell, cellBounds)
+	if isTopOfCell(spaceState, mainCell, windowID) {
+		return unchanged(snap, windowID, sourceCell, "it is already the main window"), nil
+	}
+
+	logging.Info().Uint32("windowId", windowID).Str("mainCell", mainCell).Msg("promoting window")
+	return swapKeepingMaster(ctx, c, snap, cfg, rs, windowID, sourceCell, mainCell)
+}
+
+// DemoteWindow is the inverse of PromoteWindow: it swaps a window (0 = the
+// focused one) in the main cell with the top window of the first other cell,
+// top-left first. Nothing changes in a single-cell layout.
+func DemoteWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+) (*MoveResult, error) {
+	_, windowID, sourceCell, cellBounds, err := promoteContext(snap, cfg, rs, windowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cellBounds) == 1 {
+		return unchanged(snap, windowID, sourceCell, "the layout has only one cell"), nil
+	}
+	mainCell := layout.MainCell(cfg.Settings.MainCell, cellBounds)
+	if sourceCell != mainCell {
+		return nil, fmt.Errorf("window %d is not in the main cell %s", windowID, mainCell)
+	}
+
+	var targetCell string
+	for _, id := range layout.SortCellsByPosition(cellBounds) {
+		if id != mainCell {
+			targetCell = id
+			break
+		}
+	}
+
+	logging.Info().Uint32("windowId", windowID).Str("targetCell", targetCell).Msg("demoting window")
+	return swapKeepingMaster(ctx, c, snap, cfg, rs, windowID, sourceCell, targetCell)
+}
+
+// promoteContext resolves the window and the current layout's cell bounds
+// for PromoteWindow and DemoteWindow
+func promoteContext(
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+) (*state.SpaceState, uint32, string, map[string]types.Rect, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, 0, "", nil, fmt.Errorf("no layout applied")
+	}
+
+	if windowID == 0 {
+		windowID = spaceState.GetFocusedWindow()
+		if windowID == 0 {
+			return nil, 0, "", nil, fmt.Errorf("no focused window")
+		}
+	}
+
+	sourceCell := spaceState.GetWindowCell(windowID)
+	if sourceCell == "" {
+		return nil, 0, "", nil, fmt.Errorf("window %d not assigned to any cell", windowID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return nil, 0, "", nil, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+	return spaceState, windowID, sourceCell, calculated.CellBounds, nil
+}
+
+// swapKeepingMaster swaps windowID into targetCell like SwapWindow and moves
+// the designated master role along with the master's position
+func swapKeepingMaster(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+	sourceCell string,
+	targetCell string,
+) (*MoveResult, error) {
+	result, err := swapIntoCell(ctx, c, snap, cfg, rs, windowID, sourceCell, targetCell, true)
+	if err != nil {
+		return nil, err
+	}
+
+	space := rs.GetSpace(snap.SpaceID)
+	if master := space.MasterWindow; master != 0 && result.SwappedWith != 0 {
+		switch master {
+		case result.SwappedWith:
+			space.MasterWindow = windowID
+		case windowID:
+			space.MasterWindow = result.SwappedWith
+		default:
+			return result, nil
+		}
+		if err := rs.Save(); err != nil {
+			logging.Warn().Err(err).Msg("failed to save state")
+		}
+	}
+	return result, nil
+}
+
+// isTopOfCell reports whether windowID is the first window of cellID
+func isTopOfCell(space *state.SpaceState, cellID string, windowID uint32) bool {
+	cell := space.Cells[cellID]
+	return cell != nil && len(cell.Windows) > 0 && cell.Windows[0] == windowID
+}
+
+// unchanged is the result of a promote or demote with nothing to do
+func unchanged(snap *server.Snapshot, windowID uint32, cell, reason string) *MoveResult {
+	return &MoveResult{
+		WindowID:    windowID,
+		SourceCell:  cell,
+		TargetCell:  cell,
+		SourceSpace: snap.SpaceID,
+		TargetSpace: snap.SpaceID,
+		Unchanged:   reason,
+	}
+}
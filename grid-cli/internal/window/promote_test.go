@@ -0,0 +1,132 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// masterStackFixture has a large "big" cell left of two stacked cells, one
+// window each: 100 in big, 101 in a, 102 in b (focused)
+func masterStackFixture() (*server.Snapshot, *config.Config, *state.RuntimeState) {
+	bounds := types.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+	snap := &server.Snapshot{SpaceID: "1", DisplayBounds: bounds}
+	cfg := &config.Config{
+		Settings: config.Settings{DefaultStackMode: types.StackVertical},
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "tall",
+				Grid:  config.GridConfig{Columns: []string{"2fr", "1fr"}, Rows: []string{"1fr", "1fr"}},
+				Areas: [][]string{{"big", "a"}, {"big", "b"}},
+			},
+			{
+				ID:    "full",
+				Grid:  config.GridConfig{Columns: []string{"1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"only"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("tall", 0)
+	space.AssignWindow(100, "big")
+	space.AssignWindow(101, "a")
+	space.AssignWindow(102, "b")
+	space.SetFocus("b", 0)
+	return snap, cfg, rs
+}
+
+func TestPromoteWindow_SwapsWithMainCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := masterStackFixture()
+	rs.GetSpace("1").SetMaster(100)
+	c, fs := startFakeServer(t)
+
+	result, err := PromoteWindow(context.Background(), c, snap, cfg, rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WindowID != 102 || result.TargetCell != "big" || result.SwappedWith != 100 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	space := rs.GetSpaceReadOnly("1")
+	if got := space.GetWindowCell(102); got != "big" {
+		t.Errorf("window 102 in %q, want big", got)
+	}
+	if got := space.GetWindowCell(100); got != "b" {
+		t.Errorf("window 100 in %q, want b", got)
+	}
+	if space.MasterWindow != 102 {
+		t.Errorf("master = %d, want the promoted window 102", space.MasterWindow)
+	}
+	// Only the two swapped cells are re-placed
+	if _, ok := fs.frame(101); ok {
+		t.Error("window 101 should not be placed")
+	}
+	if got := fs.lastFocused(); got != 102 {
+		t.Errorf("last focused = %d, want 102", got)
+	}
+}
+
+func TestPromoteWindow_AlreadyMain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := masterStackFixture()
+	c, fs := startFakeServer(t)
+
+	result, err := PromoteWindow(context.Background(), c, snap, cfg, rs, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Unchanged == "" || result.TargetCell != "big" {
+		t.Errorf("expected no change, got %+v", result)
+	}
+	if _, ok := fs.frame(100); ok {
+		t.Error("nothing should be placed")
+	}
+}
+
+func TestPromoteWindow_SingleCellLayout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := masterStackFixture()
+	space := rs.GetSpace("1")
+	space.SetCurrentLayout("full", 1)
+	space.AssignWindow(100, "only")
+	space.AssignWindow(101, "only")
+	space.SetFocus("only", 1)
+	c, _ := startFakeServer(t)
+
+	result, err := PromoteWindow(context.Background(), c, snap, cfg, rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WindowID != 101 || result.Unchanged == "" {
+		t.Errorf("expected a no-op for a single-cell layout, got %+v", result)
+	}
+}
+
+func TestDemoteWindow_SwapsWithFirstOtherCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := masterStackFixture()
+	c, _ := startFakeServer(t)
+
+	result, err := DemoteWindow(context.Background(), c, snap, cfg, rs, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetCell != "a" || result.SwappedWith != 101 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(101); got != "big" {
+		t.Errorf("window 101 in %q, want big", got)
+	}
+
+	// Only windows in the main cell can be demoted
+	if _, err := DemoteWindow(context.Background(), c, snap, cfg, rs, 102); err == nil {
+		t.Error("expected an error demoting a window outside the main cell")
+	}
+}
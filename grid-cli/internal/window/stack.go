@@ -0,0 +1,114 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/focus"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// stackModeCycle is the order CycleCellStackMode rotates through
+var stackModeCycle = []types.StackMode{types.StackVertical, types.StackHorizontal, types.StackTabs, types.StackGrid}
+
+// StackModeResult describes a cell whose stack mode was changed
+type StackModeResult struct {
+	Cell     string          `json:"cell"`
+	Mode     types.StackMode `json:"mode"`
+	Previous types.StackMode `json:"previous"`
+}
+
+// SetCellStackMode overrides the stack mode of the focused cell and re-places
+// only that cell's windows. The focused window keeps focus.
+func SetCellStackMode(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	mode types.StackMode,
+) (*StackModeResult, error) {
+	return setCellStackMode(ctx, c, snap, cfg, rs, func(types.StackMode) types.StackMode { return mode })
+}
+
+// CycleCellStackMode switches the focused cell to the stack mode after its
+// current one (vertical, horizontal, tabs, grid, then vertical again).
+func CycleCellStackMode(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+) (*StackModeResult, error) {
+	return setCellStackMode(ctx, c, snap, cfg, rs, NextStackMode)
+}
+
+// NextStackMode returns the mode following current in the cycle. An unset
+// mode counts as vertical; any other mode outside the cycle starts it over.
+func NextStackMode(current types.StackMode) types.StackMode {
+	for i, mode := range stackModeCycle {
+		if mode == current {
+			return stackModeCycle[(i+1)%len(stackModeCycle)]
+		}
+	}
+	if current == "" {
+		// An unset default stacks vertically
+		return types.StackHorizontal
+	}
+	return stackModeCycle[0]
+}
+
+func setCellStackMode(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	pick func(current types.StackMode) types.StackMode,
+) (*StackModeResult, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, fmt.Errorf("no layout applied")
+	}
+	cellID := spaceState.FocusedCell
+	if cellID == "" {
+		return nil, fmt.Errorf("no focused cell")
+	}
+
+	// The mode the cell is laid out with now, as placeCells resolves it
+	layoutDef, _ := layout.SpaceLayout(cfg, spaceState)
+	current := layout.EffectiveCellMode(cfg, layoutDef, spaceState, cellID, snap.DisplayBounds, false, false)
+	mode := pick(current)
+
+	logging.Info().
+		Str("cell", cellID).
+		Str("from", string(current)).
+		Str("to", string(mode)).
+		Msg("setting cell stack mode")
+
+	rs.SetCellStackMode(snap.SpaceID, cellID, mode)
+	mutableSpace := rs.GetSpace(snap.SpaceID)
+	if _, err := placeCells(ctx, c, snap, cfg, mutableSpace, []string{cellID}); err != nil {
+		return nil, err
+	}
+
+	// Keep the focused window on top, which matters most for tabs
+	if windowID := mutableSpace.GetFocusedWindow(); windowID != 0 {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to refocus window")
+		}
+	}
+
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &StackModeResult{Cell: cellID, Mode: mode, Previous: current}, nil
+}
@@ -0,0 +1,89 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestSetCellStackMode_ReplacesCellAndKeepsFocus(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	space := rs.GetSpace("1")
+	space.AssignWindow(102, "bottom")
+	space.SetFocus("top", 1)
+	c, fs := startFakeServer(t)
+
+	result, err := SetCellStackMode(context.Background(), c, snap, cfg, rs, types.StackHorizontal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Cell != "top" || result.Mode != types.StackHorizontal || result.Previous != types.StackVertical {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := rs.GetCellStackMode("1", "top"); got != types.StackHorizontal {
+		t.Errorf("state stack mode = %q, want horizontal", got)
+	}
+
+	// Side by side in the top half
+	left, ok := fs.frame(100)
+	if !ok {
+		t.Fatal("window 100 was not placed")
+	}
+	right, _ := fs.frame(101)
+	if left.Y != right.Y || left.X >= right.X {
+		t.Errorf("expected windows side by side, got %+v and %+v", left, right)
+	}
+	// Only the focused cell is re-placed
+	if _, ok := fs.frame(102); ok {
+		t.Error("window 102 in the bottom cell should not be placed")
+	}
+	if got := fs.lastFocused(); got != 101 {
+		t.Errorf("last focused = %d, want 101", got)
+	}
+}
+
+func TestCycleCellStackMode(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("1").SetFocus("top", 0)
+	c, _ := startFakeServer(t)
+
+	for _, want := range []types.StackMode{types.StackHorizontal, types.StackTabs, types.StackGrid, types.StackVertical} {
+		result, err := CycleCellStackMode(context.Background(), c, snap, cfg, rs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Mode != want {
+			t.Errorf("cycled to %q, want %q", result.Mode, want)
+		}
+	}
+}
+
+func TestNextStackMode(t *testing.T) {
+	tests := []struct {
+		current types.StackMode
+		want    types.StackMode
+	}{
+		{types.StackVertical, types.StackHorizontal},
+		{types.StackGrid, types.StackVertical},
+		{"", types.StackHorizontal},
+		{"bsp", types.StackVertical},
+	}
+	for _, tt := range tests {
+		if got := NextStackMode(tt.current); got != tt.want {
+			t.Errorf("NextStackMode(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestSetCellStackMode_NoFocusedCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, _ := startFakeServer(t)
+
+	if _, err := SetCellStackMode(context.Background(), c, snap, cfg, rs, types.StackTabs); err == nil {
+		t.Error("expected an error without a focused cell")
+	}
+}
@@ -0,0 +1,166 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/focus"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// SwapWindow exchanges a window with the top window of the adjacent cell in
+// the given direction: each takes the other's cell and stack position, so
+// both cells keep their split ratios. An empty target cell makes this a
+// plain move. Only the two cells are re-placed, and focus stays with the
+// swapped window. Swaps stay on the current display (opts.WrapAround wraps
+// to the opposite edge); opts.WindowID picks a window other than the
+// focused one.
+func SwapWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	return withFocusPolicy(ctx, c, snap, cfg, rs, opts, func(opts MoveWindowOpts) (*MoveResult, error) {
+		return swapWindow(ctx, c, snap, cfg, rs, direction, opts)
+	})
+}
+
+func swapWindow(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	direction types.Direction,
+	opts MoveWindowOpts,
+) (*MoveResult, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	if spaceState == nil || spaceState.CurrentLayoutID == "" {
+		return nil, fmt.Errorf("no layout applied")
+	}
+
+	windowID := opts.WindowID
+	if windowID == 0 {
+		windowID = spaceState.GetFocusedWindow()
+		if windowID == 0 {
+			return nil, fmt.Errorf("no focused window")
+		}
+	}
+
+	sourceCell := spaceState.GetWindowCell(windowID)
+	if sourceCell == "" {
+		return nil, fmt.Errorf("window %d not assigned to any cell", windowID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, spaceState)
+	if err != nil {
+		return nil, fmt.Errorf("layout not found: %w", err)
+	}
+	calculated := layout.CalculateSpaceLayout(layoutDef, snap.DisplayBounds, float64(cfg.Settings.CellPadding), spaceState)
+
+	targetCell, err := swapTarget(layoutDef, calculated, sourceCell, direction, opts.WrapAround)
+	if err != nil {
+		return nil, err
+	}
+	return swapIntoCell(ctx, c, snap, cfg, rs, windowID, sourceCell, targetCell, !opts.keepFocus)
+}
+
+// swapIntoCell exchanges a window with the top window of targetCell, or
+// moves it there if the cell is empty, re-placing just the two cells.
+// Focus goes to the window in its new place.
+func swapIntoCell(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+	sourceCell string,
+	targetCell string,
+	focusMoved bool,
+) (*MoveResult, error) {
+	spaceState := rs.GetSpaceReadOnly(snap.SpaceID)
+	var other uint32
+	if cell := spaceState.Cells[targetCell]; cell != nil && len(cell.Windows) > 0 {
+		other = cell.Windows[0]
+	}
+	if other == 0 {
+		logging.Info().Str("cell", targetCell).Msg("swap target is empty, moving window instead")
+		return moveWindowToCell(ctx, c, snap, cfg, rs, windowID, nil, sourceCell, targetCell, snap.SpaceID, false, false, "", focusMoved)
+	}
+
+	logging.Info().
+		Uint32("windowId", windowID).
+		Uint32("otherId", other).
+		Str("sourceCell", sourceCell).
+		Str("targetCell", targetCell).
+		Msg("swapping windows")
+
+	mutableSpace := rs.GetSpace(snap.SpaceID)
+	mutableSpace.SwapWindows(windowID, other)
+	mutableSpace.SetFocus(targetCell, 0)
+
+	if _, err := placeCells(ctx, c, snap, cfg, mutableSpace, []string{sourceCell, targetCell}); err != nil {
+		return nil, err
+	}
+
+	if focusMoved {
+		if err := focus.FocusWindow(ctx, c, windowID); err != nil {
+			logging.Warn().Err(err).Uint32("windowId", windowID).Msg("failed to focus swapped window")
+		}
+	}
+
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &MoveResult{
+		WindowID:    windowID,
+		SourceCell:  sourceCell,
+		TargetCell:  targetCell,
+		SourceSpace: snap.SpaceID,
+		TargetSpace: snap.SpaceID,
+		SwappedWith: other,
+	}, nil
+}
+
+// swapTarget finds the cell next to sourceCell in direction, the same way
+// MoveWindow does within a display
+func swapTarget(layoutDef *types.Layout, calculated *types.CalculatedLayout, sourceCell string, direction types.Direction, wrap bool) (string, error) {
+	if len(calculated.CellBounds) == 1 {
+		return "", fmt.Errorf("layout %s has only one cell", layoutDef.ID)
+	}
+
+	navBounds := layout.NavigableCells(layoutDef, calculated.CellBounds, sourceCell)
+	candidates := layout.GetAdjacentCells(sourceCell, navBounds)[direction]
+
+	if len(candidates) == 0 && direction.IsDiagonal() {
+		if targetCell := focus.FindDiagonalStep(direction, sourceCell, navBounds); targetCell != "" {
+			return targetCell, nil
+		}
+		return "", fmt.Errorf("no cell in direction %s", direction.String())
+	}
+
+	if len(candidates) == 0 {
+		if !wrap {
+			return "", fmt.Errorf("no cell in direction %s", direction.String())
+		}
+		candidates = focus.FindWrapTarget(direction, sourceCell, navBounds)
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("no cell in direction %s (wrap)", direction.String())
+		}
+	}
+
+	return focus.PickClosestCell(sourceCell, candidates, navBounds), nil
+}
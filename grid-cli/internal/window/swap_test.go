@@ -0,0 +1,98 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func TestSwapWindow_ExchangesCellsAndKeepsSplits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	space := rs.GetSpace("1")
+	space.AssignWindow(102, "bottom")
+	space.Cells["top"].SplitRatios = []float64{0.7, 0.3}
+	space.SetFocus("top", 1)
+	c, fs := startFakeServer(t)
+
+	result, err := SwapWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.WindowID != 101 || result.SwappedWith != 102 || result.TargetCell != "bottom" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	top := space.Cells["top"]
+	if len(top.Windows) != 2 || top.Windows[0] != 100 || top.Windows[1] != 102 {
+		t.Errorf("expected top cell [100 102], got %v", top.Windows)
+	}
+	if len(top.SplitRatios) != 2 || top.SplitRatios[0] != 0.7 {
+		t.Errorf("expected top split ratios kept, got %v", top.SplitRatios)
+	}
+	if bottom := space.Cells["bottom"].Windows; len(bottom) != 1 || bottom[0] != 101 {
+		t.Errorf("expected bottom cell [101], got %v", bottom)
+	}
+	if space.GetFocusedWindow() != 101 {
+		t.Errorf("expected focus to follow window 101, got %d", space.GetFocusedWindow())
+	}
+
+	// Window 102 takes 101's slot at the bottom of the top cell's 70/30 split
+	frame, ok := fs.frame(102)
+	if !ok || frame.Y < 540*0.7-10 || frame.Y+frame.Height > 540 {
+		t.Errorf("expected window 102 in the lower 30%% of the top cell, got %+v", frame)
+	}
+	if frame, ok := fs.frame(101); !ok || frame.Y < 540 {
+		t.Errorf("expected window 101 in the bottom cell, got %+v", frame)
+	}
+}
+
+func TestSwapWindow_EmptyTargetMoves(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, _ := startFakeServer(t)
+
+	result, err := SwapWindow(context.Background(), c, snap, cfg, rs, types.DirDown, MoveWindowOpts{WindowID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SwappedWith != 0 {
+		t.Errorf("expected a plain move, got swap with %d", result.SwappedWith)
+	}
+	space := rs.GetSpaceReadOnly("1")
+	if bottom := space.Cells["bottom"].Windows; len(bottom) != 1 || bottom[0] != 100 {
+		t.Errorf("expected bottom cell [100], got %v", bottom)
+	}
+}
+
+func TestSwapWindow_NoCellInDirection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, _ := startFakeServer(t)
+
+	if _, err := SwapWindow(context.Background(), c, snap, cfg, rs, types.DirLeft, MoveWindowOpts{WindowID: 100}); err == nil {
+		t.Error("expected error with no cell to the left")
+	}
+}
+
+func TestSwapWindow_KeepFocusSendsNoFocus(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	space := rs.GetSpace("1")
+	space.AssignWindow(102, "bottom")
+	space.SetFocusedWindow(100)
+	c, fs := startFakeServer(t)
+
+	keep := false
+	opts := MoveWindowOpts{WindowID: 101, FocusFollow: &keep}
+	if _, err := SwapWindow(context.Background(), c, snap, cfg, rs, types.DirDown, opts); err != nil {
+		t.Fatal(err)
+	}
+	if fs.Called("window.focus") {
+		t.Error("expected no focus request when focus stays put")
+	}
+	if got := space.GetFocusedWindow(); got != 100 {
+		t.Errorf("focused window in state = %d, want 100", got)
+	}
+}
@@ -0,0 +1,217 @@
+package window
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/grid-cli/internal/client"
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/layout"
+	"github.com/yourusername/grid-cli/internal/logging"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+// MoveWindowToSpace moves a window to another space and tiles it into that
+// space's layout: into cellID if given, otherwise the least-populated cell.
+// Only the target cell is re-placed, plus the source cell with reflowSource.
+func MoveWindowToSpace(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+	spaceID string,
+	cellID string,
+	reflowSource bool,
+) (*MoveResult, error) {
+	target, _, err := ResolveTargetSpaceDisplay(snap, cfg, rs, spaceID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceCell := ""
+	if sourceSpace := rs.GetSpaceReadOnly(snap.SpaceID); sourceSpace != nil {
+		sourceCell = sourceSpace.GetWindowCell(windowID)
+	}
+
+	// Check the target layout and cell before moving anything
+	cellID, err = resolveTileCell(cfg, rs, spaceID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move window to target space via server RPC
+	if _, err := c.UpdateWindow(ctx, int(windowID), map[string]interface{}{
+		"spaceId": spaceID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to move window to space %s: %w", spaceID, err)
+	}
+
+	targetCell, placements, err := TileIntoSpace(cfg, rs, spaceID, *target, windowID, cellID, snap.Windows)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Info().
+		Uint32("windowId", windowID).
+		Str("sourceSpace", snap.SpaceID).
+		Str("targetSpace", spaceID).
+		Str("targetCell", targetCell).
+		Msg("tiled window into space")
+
+	if err := layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
+		logging.Warn().Err(err).Msg("failed to apply placements on target space")
+	}
+
+	reflowed := 0
+	if reflowSource {
+		reflowed = reflowSourceCells(ctx, c, snap, cfg, rs, []string{sourceCell})
+	}
+
+	// Save state
+	rs.MarkUpdated()
+	if err := rs.Save(); err != nil {
+		logging.Warn().Err(err).Msg("failed to save state")
+	}
+
+	return &MoveResult{
+		WindowID:       windowID,
+		SourceCell:     sourceCell,
+		TargetCell:     targetCell,
+		SourceSpace:    snap.SpaceID,
+		TargetSpace:    spaceID,
+		SourceReflowed: reflowed,
+	}, nil
+}
+
+// ReflowAfterDeparture drops a window that moved off the snapshot's space
+// from that space's state and re-places the windows left in its cell.
+// Does nothing if the space doesn't track the window. Returns the number of
+// windows re-placed.
+func ReflowAfterDeparture(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	windowID uint32,
+) int {
+	space := rs.GetSpaceReadOnly(snap.SpaceID)
+	if space == nil {
+		return 0
+	}
+	cellID := space.GetWindowCell(windowID)
+	if cellID == "" {
+		return 0
+	}
+	rs.GetSpace(snap.SpaceID).RemoveWindow(windowID)
+	return reflowSourceCells(ctx, c, snap, cfg, rs, []string{cellID})
+}
+
+// reflowSourceCells re-places the windows left in cells of the snapshot's
+// space after others moved out, so they expand into the freed space. Spaces
+// without a layout are left alone. Returns the number of windows re-placed.
+func reflowSourceCells(
+	ctx context.Context,
+	c *client.Client,
+	snap *server.Snapshot,
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	cellIDs []string,
+) int {
+	space := rs.GetSpaceReadOnly(snap.SpaceID)
+	if space == nil || space.CurrentLayoutID == "" {
+		return 0
+	}
+
+	display := server.DisplayInfo{Frame: snap.DisplayBounds}
+	var placements []types.WindowPlacement
+	seen := make(map[string]bool, len(cellIDs))
+	for _, cellID := range cellIDs {
+		if cellID == "" || seen[cellID] {
+			continue
+		}
+		seen[cellID] = true
+
+		cellPlaced, err := cellPlacements(cfg, space, cellID, display, snap.Windows)
+		if err != nil {
+			logging.Warn().Err(err).Str("space", snap.SpaceID).Msg("failed to reflow source space")
+			return 0
+		}
+		placements = append(placements, cellPlaced...)
+	}
+	if len(placements) == 0 {
+		return 0
+	}
+
+	if err := layout.ApplyPlacementsAnimated(ctx, c, placements, cfg.Settings.AnimationDuration); err != nil {
+		logging.Warn().Err(err).Str("space", snap.SpaceID).Msg("failed to reflow source space")
+		return 0
+	}
+	return len(placements)
+}
+
+// TileIntoSpace records a window in a cell of the space's current layout,
+// removing it from every other space, and returns the chosen cell with the
+// placements for that cell on the given display. An empty cellID picks the
+// least-populated cell. windows supplies the app names for snapping to size
+// increments.
+func TileIntoSpace(
+	cfg *config.Config,
+	rs *state.RuntimeState,
+	spaceID string,
+	display server.DisplayInfo,
+	windowID uint32,
+	cellID string,
+	windows []server.WindowInfo,
+) (string, []types.WindowPlacement, error) {
+	cellID, err := resolveTileCell(cfg, rs, spaceID, cellID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	space := rs.GetSpaceReadOnly(spaceID)
+	rs.RemoveWindowFromOtherSpaces(windowID, spaceID)
+	space.AssignWindow(windowID, cellID)
+
+	placements, err := cellPlacements(cfg, space, cellID, display, windows)
+	if err != nil {
+		return "", nil, err
+	}
+	return cellID, placements, nil
+}
+
+// resolveTileCell checks that the space has a layout defining cellID and
+// returns it, or the least-populated cell of the layout if cellID is empty
+func resolveTileCell(cfg *config.Config, rs *state.RuntimeState, spaceID, cellID string) (string, error) {
+	space := rs.GetSpaceReadOnly(spaceID)
+	if space == nil || space.CurrentLayoutID == "" {
+		return "", fmt.Errorf("space %s has no layout", spaceID)
+	}
+
+	layoutDef, err := layout.SpaceLayout(cfg, space)
+	if err != nil {
+		return "", fmt.Errorf("layout not found: %w", err)
+	}
+
+	if cellID == "" {
+		occupancy := make(map[string][]uint32, len(layoutDef.Cells))
+		for _, cell := range layoutDef.Cells {
+			occupancy[cell.ID] = nil
+			if cellState, ok := space.Cells[cell.ID]; ok {
+				occupancy[cell.ID] = cellState.Windows
+			}
+		}
+		return layout.FindLeastPopulatedCell(occupancy), nil
+	}
+
+	for _, cell := range layoutDef.Cells {
+		if cell.ID == cellID {
+			return cellID, nil
+		}
+	}
+	return "", fmt.Errorf("cell %s not found in layout %s", cellID, space.CurrentLayoutID)
+}
@@ -0,0 +1,189 @@
+package window
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourusername/grid-cli/internal/config"
+	"github.com/yourusername/grid-cli/internal/server"
+	"github.com/yourusername/grid-cli/internal/state"
+	"github.com/yourusername/grid-cli/internal/types"
+)
+
+func tileFixture() (*config.Config, *state.RuntimeState, server.DisplayInfo) {
+	cfg := &config.Config{
+		Layouts: []config.LayoutConfig{
+			{
+				ID:    "two-column",
+				Grid:  config.GridConfig{Columns: []string{"1fr", "1fr"}, Rows: []string{"1fr"}},
+				Areas: [][]string{{"left", "right"}},
+			},
+		},
+	}
+	rs := state.NewRuntimeState()
+	rs.GetSpace("1").AssignWindow(100, "main")
+	target := rs.GetSpace("5")
+	target.SetCurrentLayout("two-column", 0)
+	target.AssignWindow(1, "left")
+
+	bounds := types.Rect{X: 0, Y: 0, Width: 1000, Height: 800}
+	return cfg, rs, server.DisplayInfo{UUID: "main", Frame: bounds, VisibleFrame: bounds}
+}
+
+func TestTileIntoSpace_LeastPopulated(t *testing.T) {
+	cfg, rs, display := tileFixture()
+
+	cellID, placements, err := TileIntoSpace(cfg, rs, "5", display, 100, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cellID != "right" {
+		t.Errorf("expected least-populated cell right, got %s", cellID)
+	}
+	if got := rs.GetSpaceReadOnly("5").GetWindowCell(100); got != "right" {
+		t.Errorf("window tracked in %q on target space, want right", got)
+	}
+	if got := rs.GetSpaceReadOnly("1").GetWindowCell(100); got != "" {
+		t.Errorf("window still tracked in source cell %q", got)
+	}
+
+	if len(placements) != 1 || placements[0].WindowID != 100 {
+		t.Fatalf("expected a placement for window 100 only, got %+v", placements)
+	}
+	want := types.Rect{X: 500, Y: 0, Width: 500, Height: 800}
+	if placements[0].Bounds != want {
+		t.Errorf("frame = %+v, want %+v", placements[0].Bounds, want)
+	}
+}
+
+func TestTileIntoSpace_NamedCell(t *testing.T) {
+	cfg, rs, display := tileFixture()
+
+	cellID, placements, err := TileIntoSpace(cfg, rs, "5", display, 100, "left", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cellID != "left" {
+		t.Errorf("expected left, got %s", cellID)
+	}
+	// Both windows in the left cell are re-placed
+	if len(placements) != 2 {
+		t.Errorf("expected 2 placements for the left cell, got %+v", placements)
+	}
+
+	if _, _, err := TileIntoSpace(cfg, rs, "5", display, 100, "missing", nil); err == nil {
+		t.Error("expected error for unknown cell")
+	}
+	if _, _, err := TileIntoSpace(cfg, rs, "9", display, 100, "", nil); err == nil {
+		t.Error("expected error for space without layout")
+	}
+}
+
+func TestMoveWindowToSpace_ReflowsSourceCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("5").SetCurrentLayout("rows", 0)
+	c, fs := startFakeServer(t)
+
+	result, err := MoveWindowToSpace(context.Background(), c, snap, cfg, rs, 100, "5", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SourceReflowed != 1 {
+		t.Errorf("expected 1 window reflowed, got %d", result.SourceReflowed)
+	}
+
+	// The window left behind takes the whole top cell
+	got, ok := fs.frame(101)
+	if want := (types.Rect{X: 0, Y: 0, Width: 1920, Height: 540}); !ok || got != want {
+		t.Errorf("window 101 frame = %+v (set: %v), want %+v", got, ok, want)
+	}
+	if top := rs.GetSpaceReadOnly("1").Cells["top"].Windows; len(top) != 1 || top[0] != 101 {
+		t.Errorf("expected source top cell [101], got %v", top)
+	}
+}
+
+func TestMoveWindowToSpace_NoReflow(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("5").SetCurrentLayout("rows", 0)
+	c, fs := startFakeServer(t)
+
+	result, err := MoveWindowToSpace(context.Background(), c, snap, cfg, rs, 100, "5", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SourceReflowed != 0 {
+		t.Errorf("expected no reflow, got %d", result.SourceReflowed)
+	}
+	if _, ok := fs.frame(101); ok {
+		t.Error("window 101 should not be re-placed")
+	}
+}
+
+func TestMoveWindowToSpace_InvalidCellMovesNothing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	rs.GetSpace("5").SetCurrentLayout("rows", 0)
+	c, fs := startFakeServer(t)
+
+	if _, err := MoveWindowToSpace(context.Background(), c, snap, cfg, rs, 100, "5", "missing", false); err == nil {
+		t.Fatal("expected an error for a cell the layout doesn't define")
+	}
+	if _, err := MoveWindowToSpace(context.Background(), c, snap, cfg, rs, 100, "9", "", false); err == nil {
+		t.Fatal("expected an error for a space without a layout")
+	}
+	if fs.Called("updateWindow") {
+		t.Error("window should not be sent to the space when the target is invalid")
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(100); cell != "top" {
+		t.Errorf("window 100 should stay in top, got %q", cell)
+	}
+}
+
+func TestMoveWindow_CrossDisplayReflowsSourceCell(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	right := types.Rect{X: 1920, Y: 0, Width: 1920, Height: 1080}
+	snap.AllDisplays = append(snap.AllDisplays, server.DisplayInfo{
+		UUID: "right", CurrentSpaceID: 2, Frame: right, VisibleFrame: right,
+	})
+	rs.GetSpace("2").SetCurrentLayout("rows", 0)
+	c, fs := startFakeServer(t)
+
+	opts := MoveWindowOpts{WindowID: 100, Extend: true, ReflowSource: true}
+	result, err := MoveWindow(context.Background(), c, snap, cfg, rs, types.DirRight, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.CrossDisplay || result.SourceReflowed != 1 {
+		t.Fatalf("expected a cross-display move reflowing 1 window, got %+v", result)
+	}
+	got, ok := fs.frame(101)
+	if want := (types.Rect{X: 0, Y: 0, Width: 1920, Height: 540}); !ok || got != want {
+		t.Errorf("window 101 frame = %+v (set: %v), want %+v", got, ok, want)
+	}
+}
+
+func TestReflowAfterDeparture(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snap, cfg, rs := stackedCellFixture()
+	c, fs := startFakeServer(t)
+
+	// Untracked windows leave the space alone
+	if n := ReflowAfterDeparture(context.Background(), c, snap, cfg, rs, 999); n != 0 {
+		t.Errorf("expected no reflow for an untracked window, got %d", n)
+	}
+
+	if n := ReflowAfterDeparture(context.Background(), c, snap, cfg, rs, 101); n != 1 {
+		t.Fatalf("expected 1 window reflowed, got %d", n)
+	}
+	if cell := rs.GetSpaceReadOnly("1").GetWindowCell(101); cell != "" {
+		t.Errorf("departed window still tracked in %q", cell)
+	}
+	got, ok := fs.frame(100)
+	if want := (types.Rect{X: 0, Y: 0, Width: 1920, Height: 540}); !ok || got != want {
+		t.Errorf("window 100 frame = %+v (set: %v), want %+v", got, ok, want)
+	}
+}